@@ -23,6 +23,7 @@ const (
 	LVGKind                          = "LVG"
 	DriveKind                        = "Drive"
 	CSIBMNodeKind                    = "Node"
+	SnapshotKind                     = "Snapshot"
 
 	Version = "v1"
 	// TODO: change value, https://github.com/dell/csi-baremetal/issues/134
@@ -46,11 +47,34 @@ const (
 	DriveStatusOnline  = "ONLINE"
 	DriveStatusOffline = "OFFLINE"
 
+	// Drive operational status
+	DriveOpStatusOperative = "OPERATIVE"
+	DriveOpStatusReleasing = "RELEASING"
+	DriveOpStatusReleased  = "RELEASED"
+	DriveOpStatusFailed    = "FAILED"
+	DriveOpStatusRemoving  = "REMOVING"
+	DriveOpStatusRemoved   = "REMOVED"
+
+	// Drive LED state
+	LEDStateOff     = "OFF"
+	LEDStateLocate  = "LOCATE"
+	LEDStateFailure = "FAILURE"
+
+	// Drive event type, DriveService WatchDrives reports changes of drives with them
+	DriveEventAdded    = "ADDED"
+	DriveEventModified = "MODIFIED"
+	DriveEventRemoved  = "REMOVED"
+	// DriveWatchHeaderKey is a header which DriveService sends when WatchDrives stream is established
+	DriveWatchHeaderKey = "drive-watch"
+
 	// Drive type
 	DriveTypeHDD  = "HDD"
 	DriveTypeSSD  = "SSD"
 	DriveTypeNVMe = "NVME"
 
+	// DriveEnduranceUnknown is reported as drive endurance when drive doesn't provide percentage of life left
+	DriveEnduranceUnknown = -1
+
 	// Volume operational status
 	OperationalStatusOperative     = "OPERATIVE"
 	OperationalStatusInoperative   = "INOPERATIVE"
@@ -68,9 +92,14 @@ const (
 	ModeFS  = "FS"
 
 	// Volume location type
-	LocationTypeDrive = "DRIVE"
-	LocationTypeLVM   = "LVM"
-	LocationTypeNVMe  = "NVME"
+	LocationTypeDrive  = "DRIVE"
+	LocationTypeLVM    = "LVM"
+	LocationTypeNVMe   = "NVME"
+	LocationTypeMDRaid = "MDRAID"
+
+	// Volume content source type
+	ContentSourceSnapshot = "SNAPSHOT"
+	ContentSourceVolume   = "VOLUME"
 
 	// CSI StorageClass
 	StorageClassAny       = "ANY"
@@ -81,4 +110,48 @@ const (
 	StorageClassSSDLVG    = "SSDLVG"
 	StorageClassNVMeLVG   = "NVMELVG"
 	StorageClassSystemLVG = "SYSLVG"
+
+	// CSI StorageClass for thin volumes in thin pool LVG
+	StorageClassHDDLVGThin  = "HDDLVGTHIN"
+	StorageClassSSDLVGThin  = "SSDLVGTHIN"
+	StorageClassNVMeLVGThin = "NVMELVGTHIN"
+
+	// CSI StorageClass for volumes on MD RAID storage classes, each member of the array consumes the whole drive
+	StorageClassHDDRAID1   = "HDDRAID1"
+	StorageClassSSDRAID1   = "SSDRAID1"
+	StorageClassNVMeRAID1  = "NVMERAID1"
+	StorageClassHDDRAID10  = "HDDRAID10"
+	StorageClassSSDRAID10  = "SSDRAID10"
+	StorageClassNVMeRAID10 = "NVMERAID10"
+
+	// Type of the logical volume on LVG
+	LVTypeLinear  = "linear"
+	LVTypeStriped = "striped"
+	LVTypeRAID1   = "raid1"
+
+	// Cache mode of the cached logical volume
+	CacheModeWritethrough = "writethrough"
+	CacheModeWriteback    = "writeback"
+
+	// Drive replacement annotations
+	VolumeReleaseSupportAnnotationKey  = "volumerelease.csi-baremetal/support"
+	VolumeReleaseProcessAnnotationKey  = "volumerelease.csi-baremetal/process"
+	VolumeReleaseAnnotationKey         = "volumerelease.csi-baremetal/release"
+	VolumeReleaseRecoveryAnnotationKey = "volumerelease.csi-baremetal/recovery"
+	VolumeReleaseStatusAnnotationKey   = "volumerelease.csi-baremetal/status"
+	VolumeHealthAnnotationKey          = "volumehealth.csi-baremetal/health"
+	DriveRemovalAnnotationKey          = "driveremove.csi-baremetal/replacement"
+
+	// DriveLEDAnnotationKey is an annotation of Drive CR which holds desired LED state: off|locate|failure
+	DriveLEDAnnotationKey = "driveled.csi-baremetal/state"
+
+	// Drive replacement annotation values
+	VolumeReleaseSupported    = "yes"
+	VolumeReleaseProcessStart = "start"
+	VolumeReleaseProcessPause = "pause"
+	VolumeReleaseProcessStop  = "stop"
+	VolumeReleaseProcessing   = "processing"
+	VolumeReleaseCompleted    = "completed"
+	VolumeReleaseFailed       = "failed"
+	DriveRemovalReady         = "ready"
 )
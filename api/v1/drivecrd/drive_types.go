@@ -53,6 +53,10 @@ func (in *Drive) DeepCopyInto(out *Drive) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
+	if in.Spec.SMART != nil {
+		smart := *in.Spec.SMART
+		out.Spec.SMART = &smart
+	}
 }
 
 func init() {
@@ -68,5 +72,22 @@ func (in *Drive) Equals(drive *api.Drive) bool {
 		in.Spec.Health == drive.Health &&
 		in.Spec.Type == drive.Type &&
 		in.Spec.Size == drive.Size &&
-		in.Spec.Path == drive.Path
+		in.Spec.Path == drive.Path &&
+		in.Spec.Endurance == drive.Endurance &&
+		in.Spec.Enclosure == drive.Enclosure &&
+		in.Spec.Slot == drive.Slot &&
+		in.Spec.Bay == drive.Bay &&
+		smartCountersEqual(in.Spec.SMART, drive.SMART)
+}
+
+// smartCountersEqual compares SMART attributes which show degradation of the drive. Temperature and power-on hours
+// are changing constantly, they are refreshed in CR together with other fields only
+func smartCountersEqual(s1, s2 *api.SMARTInfo) bool {
+	if s1 == nil || s2 == nil {
+		return s1 == s2
+	}
+	return s1.ReallocatedSectors == s2.ReallocatedSectors &&
+		s1.PendingSectors == s2.PendingSectors &&
+		s1.MediaErrors == s2.MediaErrors &&
+		s1.PercentageUsed == s2.PercentageUsed
 }
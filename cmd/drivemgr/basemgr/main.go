@@ -25,6 +25,7 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/rpc"
 	"github.com/dell/csi-baremetal/pkg/drivemgr/basemgr"
+	"github.com/dell/csi-baremetal/pkg/drivemgr/healthpolicy"
 )
 
 var (
@@ -32,6 +33,8 @@ var (
 	logPath  = flag.String("logpath", "", "log path for DriveManager")
 	logLevel = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
+	healthPolicy = flag.String("healthpolicy", "",
+		"Path to YAML file with rules which derive drive health from SMART attributes, default rules are used if empty")
 )
 
 func main() {
@@ -49,6 +52,13 @@ func main() {
 	e.SetLogger(logger)
 
 	driveMgr := basemgr.New(e, logger)
+	if *healthPolicy != "" {
+		policy, err := healthpolicy.Load(*healthPolicy)
+		if err != nil {
+			logger.Fatalf("Failed to load health policy from %s: %v", *healthPolicy, err)
+		}
+		driveMgr.SetHealthPolicy(policy)
+	}
 
 	dmsetup.SetupAndRunDriveMgr(driveMgr, serverRunner, nil, logger)
 }
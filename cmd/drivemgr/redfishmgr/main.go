@@ -0,0 +1,85 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	dmsetup "github.com/dell/csi-baremetal/cmd/drivemgr"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ipmi"
+	"github.com/dell/csi-baremetal/pkg/base/rpc"
+	"github.com/dell/csi-baremetal/pkg/drivemgr/redfishmgr"
+)
+
+const (
+	// userEnv and passwordEnv hold names of environment variables with Redfish credentials
+	userEnv     = "REDFISH_USER"
+	passwordEnv = "REDFISH_PASSWORD"
+)
+
+var (
+	endpoint = flag.String("drivemgrendpoint", base.DefaultDriveMgrEndpoint, "DriveManager Endpoint")
+	redfish  = flag.String("redfishendpoint", "",
+		"Redfish service endpoint, e.g. https://10.10.10.10. BMC IP detected by ipmitool is used if empty")
+	caFile = flag.String("cafile", "", "path to PEM encoded CA certificates which are used to verify "+
+		"Redfish service certificate. System CA certificates are used if empty")
+	timeout  = flag.Duration("timeout", 10*time.Second, "timeout of each request to Redfish service")
+	logPath  = flag.String("logpath", "", "log path for DriveManager")
+	logLevel = flag.String("loglevel", base.InfoLevel,
+		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
+)
+
+func main() {
+	flag.Parse()
+
+	logger, err := base.InitLogger(*logPath, *logLevel)
+	if err != nil {
+		logger.Warnf("Can't set logger's output to %s. Using stdout instead.\n", *logPath)
+	}
+
+	// Server is insecure for now because credentials are nil
+	serverRunner := rpc.NewServerRunner(nil, *endpoint, logger)
+
+	if *redfish == "" {
+		e := &command.Executor{}
+		e.SetLogger(logger)
+		ip := ipmi.NewIPMI(e).GetBmcIP()
+		if ip == "" {
+			logger.Fatal("BMC IP is not found")
+		}
+		*redfish = "https://" + ip
+	}
+
+	var rootCAs *x509.CertPool
+	if *caFile != "" {
+		if rootCAs, err = redfishmgr.LoadCertPool(*caFile); err != nil {
+			logger.Fatalf("Unable to load CA certificates: %v", err)
+		}
+	}
+
+	driveMgr := redfishmgr.NewRedfishManager(logger, *redfish, os.Getenv(userEnv), os.Getenv(passwordEnv),
+		rootCAs, *timeout)
+	defer driveMgr.Close()
+
+	dmsetup.SetupAndRunDriveMgr(driveMgr, serverRunner, nil, logger)
+}
@@ -24,11 +24,14 @@ import (
 	"io/ioutil"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,6 +43,7 @@ import (
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
@@ -48,12 +52,20 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/csibmnode"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/lvg"
+	"github.com/dell/csi-baremetal/pkg/crcontrollers/snapshot"
 	"github.com/dell/csi-baremetal/pkg/events"
 	"github.com/dell/csi-baremetal/pkg/node"
 )
 
 const (
 	componentName = "baremetal-csi-node"
+
+	// discovering interval, health of arrays, thin pools, drive replacement and LEDs are checked with it
+	discoveringInterval = 30 * time.Second
+	// interval of full resync of drives when changes of drives are received through WatchDrives
+	drivesResyncInterval = 5 * time.Minute
+	// interval between attempts to re-establish watching of drives
+	watchingRetryInterval = 10 * time.Second
 )
 
 var (
@@ -70,6 +82,8 @@ var (
 		"Whether node svc should read id from node annotation and use it as id for all CRs or not")
 	logLevel = flag.String("loglevel", base.InfoLevel,
 		fmt.Sprintf("Log level, support values are %s, %s, %s", base.InfoLevel, base.DebugLevel, base.TraceLevel))
+	enduranceThresholds = flag.String("endurancethresholds", "20,10,5",
+		"Comma separated percentages of drive life left on which DriveEnduranceLow event is sent")
 )
 
 func main() {
@@ -115,12 +129,19 @@ func main() {
 
 	k8sClientForVolume := k8s.NewKubeClient(k8SClient, logger, *namespace)
 	k8sClientForLVG := k8s.NewKubeClient(k8SClient, logger, *namespace)
+	k8sClientForSnapshot := k8s.NewKubeClient(k8SClient, logger, *namespace)
 	csiNodeService := node.NewCSINodeService(
 		clientToDriveMgr, nodeID, logger, k8sClientForVolume, eventRecorder, featureConf)
+	thresholds, err := parseEnduranceThresholds(*enduranceThresholds)
+	if err != nil {
+		logger.Fatalf("fail to parse endurance thresholds: %v", err)
+	}
+	csiNodeService.SetEnduranceThresholds(thresholds)
 
 	mgr := prepareCRDControllerManagers(
 		csiNodeService,
 		lvg.NewController(k8sClientForLVG, nodeID, logger),
+		snapshot.NewController(k8sClientForSnapshot, nodeID, logger),
 		logger)
 
 	// register CSI calls handler
@@ -144,6 +165,10 @@ func main() {
 		}
 	}()
 	go Discovering(csiNodeService, logger)
+	go WatchingDrives(csiNodeService, logger)
+	if featureConf.IsEnabled(featureconfig.FeatureNodeIDFromAnnotation) {
+		go WatchingNodeID(k8SClient, nodeID, logger)
+	}
 
 	logger.Info("Starting handle CSI calls ...")
 	if err := csiUDSServer.RunServer(); err != nil && err != grpc.ErrServerStopped {
@@ -153,13 +178,21 @@ func main() {
 	logger.Info("Got SIGTERM signal")
 }
 
-// Discovering performs Discover method of the Node each 30 seconds
+// Discovering performs Discover method of the Node each 30 seconds. If changes of drives are received
+// through WatchDrives, drives are resynced each 5 minutes only and Maintain is performed in between
 func Discovering(c *node.CSINodeService, logger *logrus.Logger) {
-	var err error
+	var (
+		err        error
+		lastResync time.Time
+	)
 	discoveringWaitTime := 10 * time.Second
 	checker := c.GetLivenessHelper()
 	for {
 		time.Sleep(discoveringWaitTime)
+		if c.IsWatchingDrives() && time.Since(lastResync) < drivesResyncInterval {
+			c.Maintain()
+			continue
+		}
 		if err = c.Discover(); err != nil {
 			checker.Fail()
 			logger.Errorf("Discover finished with error: %v", err)
@@ -167,14 +200,66 @@ func Discovering(c *node.CSINodeService, logger *logrus.Logger) {
 			checker.OK()
 			logger.Info("Discover finished successful")
 			//Increase wait time, because we don't need to call API often after node initialization
-			discoveringWaitTime = 30 * time.Second
+			discoveringWaitTime = discoveringInterval
+			lastResync = time.Now()
+		}
+	}
+}
+
+// WatchingDrives applies changes of drives as soon as DriveManager reports them
+// Stops if DriveManager doesn't support watching of drives, Discovering is used in this case only
+func WatchingDrives(c *node.CSINodeService, logger *logrus.Logger) {
+	for {
+		err := c.WatchDrives(context.Background())
+		if status.Code(err) == codes.Unimplemented {
+			logger.Infof("DriveManager doesn't support watching of drives, drives are discovered each %s",
+				discoveringInterval)
+			return
+		}
+		logger.Errorf("Watching of drives is interrupted: %v", err)
+		time.Sleep(watchingRetryInterval)
+	}
+}
+
+// WatchingNodeID stops node service when id in the node annotation is changed (e.g. node replacement is performed),
+// node service is restarted then and uses new id for all CRs
+func WatchingNodeID(client k8sClient.Client, nodeID string, logger *logrus.Logger) {
+	for {
+		time.Sleep(discoveringInterval)
+		k8sNode := corev1.Node{}
+		if err := client.Get(context.Background(), k8sClient.ObjectKey{Name: *nodeName}, &k8sNode); err != nil {
+			logger.Errorf("Unable to read k8s node %s: %v", *nodeName, err)
+			continue
+		}
+		if val, ok := k8sNode.GetAnnotations()[csibmnode.NodeIDAnnotationKey]; ok && val != nodeID {
+			logger.Fatalf("Node id was changed from %s to %s, restarting", nodeID, val)
+		}
+	}
+}
+
+// parseEnduranceThresholds converts comma separated list of percentages to slice
+func parseEnduranceThresholds(value string) ([]int64, error) {
+	thresholds := make([]int64, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		threshold, err := strconv.ParseInt(item, 10, 64)
+		if err != nil {
+			return nil, err
 		}
+		if threshold <= 0 || threshold >= 100 {
+			return nil, fmt.Errorf("threshold %d is out of range (0, 100)", threshold)
+		}
+		thresholds = append(thresholds, threshold)
 	}
+	return thresholds, nil
 }
 
 // prepareCRDControllerManagers prepares CRD ControllerManagers to work with CSI custom resources
 func prepareCRDControllerManagers(volumeCtrl *node.CSINodeService, lvgCtrl *lvg.Controller,
-	logger *logrus.Logger) manager.Manager {
+	snapshotCtrl *snapshot.Controller, logger *logrus.Logger) manager.Manager {
 	var (
 		ll     = logger.WithField("method", "prepareCRDControllerManagers")
 		scheme = runtime.NewScheme()
@@ -192,6 +277,10 @@ func prepareCRDControllerManagers(volumeCtrl *node.CSINodeService, lvgCtrl *lvg.
 	if err = lvgcrd.AddToSchemeLVG(scheme); err != nil {
 		logrus.Fatal(err)
 	}
+	// register Snapshot crd
+	if err = snapshotcrd.AddToSchemeSnapshot(scheme); err != nil {
+		logger.Fatal(err)
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:    scheme,
@@ -211,6 +300,11 @@ func prepareCRDControllerManagers(volumeCtrl *node.CSINodeService, lvgCtrl *lvg.
 		logger.Fatalf("unable to create controller for LVG: %v", err)
 	}
 
+	// bind snapshot Controller to K8s Controller Manager as a controller for Snapshot CR
+	if err = snapshotCtrl.SetupWithManager(mgr); err != nil {
+		logger.Fatalf("unable to create controller for Snapshot: %v", err)
+	}
+
 	return mgr
 }
 
@@ -16,7 +16,15 @@ limitations under the License.
 
 package capacityplanner
 
-import "github.com/dell/csi-baremetal/pkg/base/util"
+import (
+	"math"
+	"sort"
+
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
+	v1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
 
 // AcSizeMinThresholdBytes means that if AC size becomes lower then AcSizeMinThresholdBytes that AC should be deleted
 const AcSizeMinThresholdBytes = int64(util.MBYTE) // 1MB
@@ -24,6 +32,15 @@ const AcSizeMinThresholdBytes = int64(util.MBYTE) // 1MB
 // LvgDefaultMetadataSize is additional cost for new VG we should consider.
 const LvgDefaultMetadataSize = int64(util.MBYTE) // 1MB
 
+// MDRaidMetadataSize is space which mdadm reserves on each member of the array for superblock and bitmap
+const MDRaidMetadataSize = 128 * int64(util.MBYTE) // 128MB
+
+// DefaultThinOvercommitRatio is overcommit ratio of AC for the new thin pool LVG if storage class doesn't set it
+const DefaultThinOvercommitRatio = 2.0
+
+// LuksHeaderSize is space which LUKS2 header takes at the beginning of the encrypted logical volume
+const LuksHeaderSize = 16 * int64(util.MBYTE) // 16MB
+
 // DefaultPESize is the default extent size we should align with
 // TODO: use non default PE size - https://github.com/dell/csi-baremetal/issues/85
 const DefaultPESize = 4 * int64(util.MBYTE)
@@ -38,3 +55,158 @@ func AlignSizeByPE(size int64) int64 {
 	}
 	return size + alignement
 }
+
+// GetLVSize returns size of the logical volume which is created for vol with provided size,
+// size of the striped logical volume is aligned with PEs of all its stripes
+func GetLVSize(vol *genV1.Volume, size int64) int64 {
+	size = AlignSizeByPE(size)
+	if vol.LVType == v1.LVTypeStriped && vol.Stripes > 0 {
+		stripesSize := DefaultPESize * int64(vol.Stripes)
+		if reminder := size % stripesSize; reminder != 0 {
+			size += stripesSize - reminder
+		}
+	}
+	return size
+}
+
+// GetLVFootprint returns space which the logical volume of vol with provided size consumes in VG,
+// each copy of the raid1 logical volume has its own metadata sub volume of one PE size
+func GetLVFootprint(vol *genV1.Volume, lvSize int64) int64 {
+	if vol.LVType == v1.LVTypeRAID1 {
+		return (lvSize + DefaultPESize) * int64(vol.Mirrors+1)
+	}
+	return lvSize
+}
+
+// GetCacheLVSize returns size of the cache LV of vol in LVG of faster drives,
+// one extra PE is reserved for metadata of the PV which is created on top of cache LV
+func GetCacheLVSize(vol *genV1.Volume) int64 {
+	return AlignSizeByPE(vol.CacheSize) + DefaultPESize
+}
+
+// GetACVirtualSize returns size which is available for volumes in AC,
+// it is bigger than AC size for thin pool LVG with overcommit ratio greater than 1
+func GetACVirtualSize(ac *accrd.AvailableCapacity) int64 {
+	if ac.Spec.OvercommitRatio > 1 {
+		return int64(float64(ac.Spec.Size) * ac.Spec.OvercommitRatio)
+	}
+	return ac.Spec.Size
+}
+
+// GetMaxVolumeSize returns size of the largest volume of storage class sc which could be allocated in ACs of one node,
+// volume is placed in a single AC, so capacity of different ACs isn't summed.
+// Drive ACs of the sub storage class are used for the new LVG with overcommitRatio for thin pool LVG
+// and they are combined into MD RAID array of sc. Volumes with cache in cacheSC couldn't be placed on the node
+// which has no capacity for the minimal cache
+func GetMaxVolumeSize(acs []accrd.AvailableCapacity, sc, cacheSC string, overcommitRatio float64) int64 {
+	if cacheSC != "" && !hasCacheCapacity(acs, cacheSC) {
+		return 0
+	}
+	if util.IsStorageClassRAID(sc) {
+		return getMaxRAIDArraySize(acs, sc)
+	}
+
+	var (
+		subSC   = util.GetSubStorageClass(sc)
+		maxSize int64
+	)
+	for i := range acs {
+		var (
+			ac   = &acs[i]
+			size int64
+		)
+		switch {
+		case sc == v1.StorageClassAny || ac.Spec.StorageClass == sc:
+			size = GetACVirtualSize(ac)
+		case subSC != "" && ac.Spec.StorageClass == subSC:
+			// the new LVG needs some extra space
+			size = ac.Spec.Size - LvgDefaultMetadataSize
+			if util.IsStorageClassLVGThin(sc) {
+				size = int64(float64(size) * overcommitRatio)
+			}
+		}
+		if size > maxSize {
+			maxSize = size
+		}
+	}
+	return maxSize
+}
+
+// getMaxRAIDArraySize returns size of the largest MD RAID array of sc which could be created on drive ACs,
+// the smallest of the largest drives limits size of the array
+func getMaxRAIDArraySize(acs []accrd.AvailableCapacity, sc string) int64 {
+	var (
+		count = util.GetRAIDDrivesCount(sc)
+		subSC = util.GetSubStorageClass(sc)
+		sizes []int64
+	)
+	for _, ac := range acs {
+		if ac.Spec.StorageClass == subSC {
+			sizes = append(sizes, ac.Spec.Size)
+		}
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] > sizes[j] })
+
+	if len(sizes) < count {
+		return 0
+	}
+	if memberSize := sizes[count-1] - MDRaidMetadataSize; memberSize > 0 {
+		return util.GetRAIDArraySize(sc, memberSize)
+	}
+	return 0
+}
+
+// hasCacheCapacity returns true if LVG of cacheSC or drive of its sub storage class fits the minimal cache LV
+func hasCacheCapacity(acs []accrd.AvailableCapacity, cacheSC string) bool {
+	var (
+		subSC   = util.GetSubStorageClass(cacheSC)
+		minSize = GetCacheLVSize(&genV1.Volume{CacheSize: util.MinCacheSize})
+	)
+	for _, ac := range acs {
+		switch ac.Spec.StorageClass {
+		case cacheSC:
+			if ac.Spec.Size >= minSize {
+				return true
+			}
+		case subSC:
+			if ac.Spec.Size >= minSize+LvgDefaultMetadataSize {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetThinPhysicalSize returns space of thin pool which is accounted for thin volume with provided size
+func GetThinPhysicalSize(size int64, overcommitRatio float64) int64 {
+	if overcommitRatio > 1 {
+		return int64(math.Ceil(float64(size) / overcommitRatio))
+	}
+	return size
+}
+
+// GetOvercommitRatio returns overcommit ratio of the new thin pool LVG for the volume
+func GetOvercommitRatio(vol *genV1.Volume) float64 {
+	if vol.GetOvercommitRatio() > 0 {
+		return vol.GetOvercommitRatio()
+	}
+	return DefaultThinOvercommitRatio
+}
+
+// GetVolumeConsumedSize returns space which the volume holds in AC of its LVG,
+// it's calculated with current overcommit ratio of AC only for volumes which have no consumed size recorded
+func GetVolumeConsumedSize(vol *genV1.Volume, ac *accrd.AvailableCapacity) int64 {
+	if vol.ConsumedSize > 0 {
+		return vol.ConsumedSize
+	}
+	return GetThinPhysicalSize(GetLVFootprint(vol, vol.Size), ac.Spec.OvercommitRatio)
+}
+
+// GetSnapshotConsumedSize returns space which the snapshot holds in AC of its LVG,
+// it's calculated with current overcommit ratio of AC only for snapshots which have no consumed size recorded
+func GetSnapshotConsumedSize(snapshot *genV1.Snapshot, ac *accrd.AvailableCapacity) int64 {
+	if snapshot.ConsumedSize > 0 {
+		return snapshot.ConsumedSize
+	}
+	return GetThinPhysicalSize(snapshot.Size, ac.Spec.OvercommitRatio)
+}
@@ -60,32 +60,45 @@ type ReservationHelper struct {
 }
 
 // CreateReservation create reservation
+// Cached volume has additional ACR for its cache with cache storage class and size of the cache LV
 func (rh *ReservationHelper) CreateReservation(ctx context.Context, placingPlan *VolumesPlacingPlan) error {
 	logger := util.AddCommonFields(ctx, rh.logger, "ReservationHelper.CreateReservation")
 
 	volToAC := placingPlan.GetACsForVolumes()
+	volToCacheAC := placingPlan.GetCacheACsForVolumes()
 
 	var (
 		createErr   error
-		createdACRs = make([]*acrcrd.AvailableCapacityReservation, 0, len(volToAC))
+		createdACRs = make([]*acrcrd.AvailableCapacityReservation, 0, len(volToAC)+len(volToCacheAC))
 	)
 
-	for v, acs := range volToAC {
+	createACR := func(v *genV1.Volume, sc string, size int64, acs []*accrd.AvailableCapacity) error {
 		acsNames := make([]string, len(acs))
 		for i := 0; i < len(acs); i++ {
 			acsNames[i] = acs[i].Name
 		}
 		acrCR := rh.client.ConstructACRCR(genV1.AvailableCapacityReservation{
 			Name:         uuid.New().String(),
-			StorageClass: v.StorageClass,
-			Size:         v.Size,
+			StorageClass: sc,
+			Size:         size,
 			Reservations: acsNames,
 		})
-		if createErr = rh.client.CreateCR(ctx, acrCR.Name, acrCR); createErr != nil {
-			createErr = fmt.Errorf("unable to create ACR CR %v for volume %v: %v", acrCR.Spec, v, createErr)
-			break
+		if err := rh.client.CreateCR(ctx, acrCR.Name, acrCR); err != nil {
+			return fmt.Errorf("unable to create ACR CR %v for volume %v: %v", acrCR.Spec, v, err)
 		}
 		createdACRs = append(createdACRs, acrCR)
+		return nil
+	}
+
+	for v, acs := range volToAC {
+		if createErr = createACR(v, v.StorageClass, v.Size, acs); createErr != nil {
+			break
+		}
+		if cacheACs, ok := volToCacheAC[v]; ok {
+			if createErr = createACR(v, v.CacheStorageClass, GetCacheLVSize(v), cacheACs); createErr != nil {
+				break
+			}
+		}
 	}
 	if createErr == nil {
 		return nil
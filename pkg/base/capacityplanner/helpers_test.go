@@ -45,6 +45,25 @@ func TestReservationHelper_CreateReservation(t *testing.T) {
 	}
 	assert.Len(t, acrList.Items, 1)
 	assert.Len(t, acrList.Items[0].Spec.Reservations, 2)
+
+	// cached volume reserves ACs for its cache as well
+	plan := getSimpleVolumePlacingPlan()
+	cacheAC := getTestAC(testNode1, testLargeSize, apiV1.StorageClassNVMeLVG)
+	for vol := range plan.plan[testNode1] {
+		vol.CacheStorageClass = apiV1.StorageClassNVMeLVG
+		vol.CacheSize = testSmallSize
+		plan.caches = VolumesPlanMap{testNode1: VolToACMap{vol: cacheAC}}
+	}
+	rh = createReservationHelper(t, logger, nil, nil, getKubeClient(t))
+	assert.Nil(t, rh.CreateReservation(ctx, plan))
+	assert.Nil(t, rh.client.ReadList(ctx, acrList))
+	assert.Len(t, acrList.Items, 2)
+	for _, acr := range acrList.Items {
+		if acr.Spec.StorageClass == apiV1.StorageClassNVMeLVG {
+			assert.Equal(t, []string{cacheAC.Name}, acr.Spec.Reservations)
+			assert.Equal(t, GetCacheLVSize(&genV1.Volume{CacheSize: testSmallSize}), acr.Spec.Size)
+		}
+	}
 }
 
 func TestReservationHelper_ReleaseReservation(t *testing.T) {
@@ -25,6 +25,8 @@ import (
 	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 )
 
 // CapacityReaderMock is a mock implementation of CapacityReader interface for test purposes
@@ -57,6 +59,36 @@ func (rrm *ReservationReaderMock) ReadReservations(ctx context.Context) ([]acrcr
 	return ret, args.Error(1)
 }
 
+// DriveReaderMock is the mock implementation of DriveReader interface for test purposes
+type DriveReaderMock struct {
+	mock.Mock
+}
+
+// ReadDrives is a mock implementation of ReadDrives
+func (drm *DriveReaderMock) ReadDrives(ctx context.Context) ([]drivecrd.Drive, error) {
+	args := drm.Mock.Called(ctx)
+	var ret []drivecrd.Drive
+	if args.Get(0) != nil {
+		ret = args.Get(0).([]drivecrd.Drive)
+	}
+	return ret, args.Error(1)
+}
+
+// LVGReaderMock is the mock implementation of LVGReader interface for test purposes
+type LVGReaderMock struct {
+	mock.Mock
+}
+
+// ReadLVGs is a mock implementation of ReadLVGs
+func (lrm *LVGReaderMock) ReadLVGs(ctx context.Context) ([]lvgcrd.LVG, error) {
+	args := lrm.Mock.Called(ctx)
+	var ret []lvgcrd.LVG
+	if args.Get(0) != nil {
+		ret = args.Get(0).([]lvgcrd.LVG)
+	}
+	return ret, args.Error(1)
+}
+
 // PlannerMock is a mock implementation of CapacityManager
 type PlannerMock struct {
 	mock.Mock
@@ -94,7 +126,8 @@ type MockCapacityManagerBuilder struct {
 }
 
 // GetCapacityManager returns mock implementation of CapacityManager
-func (mcb *MockCapacityManagerBuilder) GetCapacityManager(logger *logrus.Entry, capReader CapacityReader) CapacityPlaner {
+func (mcb *MockCapacityManagerBuilder) GetCapacityManager(logger *logrus.Entry, capReader CapacityReader,
+	driveReader DriveReader, lvgReader LVGReader) CapacityPlaner {
 	return mcb.Manager
 }
 
@@ -125,3 +158,25 @@ func getResReaderMock(acrList []*acrcrd.AvailableCapacityReservation, err error)
 		acrListV, err)
 	return resReaderMock
 }
+
+func getDriveReaderMock(driveList []*drivecrd.Drive, err error) *DriveReaderMock {
+	driveListV := make([]drivecrd.Drive, len(driveList))
+	for i := 0; i < len(driveList); i++ {
+		driveListV[i] = *driveList[i]
+	}
+	driveReaderMock := &DriveReaderMock{}
+	driveReaderMock.On("ReadDrives", mock.Anything).Return(
+		driveListV, err)
+	return driveReaderMock
+}
+
+func getLVGReaderMock(lvgList []*lvgcrd.LVG, err error) *LVGReaderMock {
+	lvgListV := make([]lvgcrd.LVG, len(lvgList))
+	for i := 0; i < len(lvgList); i++ {
+		lvgListV[i] = *lvgList[i]
+	}
+	lvgReaderMock := &LVGReaderMock{}
+	lvgReaderMock.On("ReadLVGs", mock.Anything).Return(
+		lvgListV, err)
+	return lvgReaderMock
+}
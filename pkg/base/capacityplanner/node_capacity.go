@@ -30,6 +30,10 @@ type nodeCapacity struct {
 	capacity ACMap
 	// store original versions of modified ACs
 	origAC ACMap
+	// drive UUID to drive endurance, used to prefer less worn drives
+	driveEndurance map[string]int64
+	// LVG name to number of its PVs, used to place striped and raid1 logical volumes
+	lvgPVs map[string]int
 }
 
 // registerAC register AC in internal cache
@@ -69,6 +73,9 @@ func (nc *nodeCapacity) removeAC(ac *accrd.AvailableCapacity) {
 // selectACForVolume select AC for volume
 // will modify nodeCapacity AC cache
 func (nc *nodeCapacity) selectACForVolume(vol *genV1.Volume) *accrd.AvailableCapacity {
+	if util.IsStorageClassLVGThin(vol.StorageClass) {
+		return nc.selectACForThinVolume(vol)
+	}
 	subSC := util.GetSubStorageClass(vol.StorageClass)
 	isLVM := util.IsStorageClassLVG(vol.StorageClass)
 
@@ -86,16 +93,16 @@ func (nc *nodeCapacity) selectACForVolume(vol *genV1.Volume) *accrd.AvailableCap
 		size = AlignSizeByPE(size)
 	}
 	var ac *accrd.AvailableCapacity
-	ac = searchACWithClosestSize(scM[vol.StorageClass], size)
+	ac = searchACWithClosestSize(nc.filterMultiPVLVGs(scM[vol.StorageClass]), size, nc.driveEndurance)
 	if ac == nil {
 		if isLVM {
 			// for the new lvg we need some extra space
 			size += LvgDefaultMetadataSize
 			// search AC in sub storage class
-			ac = searchACWithClosestSize(scM[subSC], size)
+			ac = searchACWithClosestSize(scM[subSC], size, nc.driveEndurance)
 		} else if vol.StorageClass == v1.StorageClassAny {
 			for _, acs := range scM {
-				ac = searchACWithClosestSize(acs, size)
+				ac = searchACWithClosestSize(nc.filterMultiPVLVGs(acs), size, nc.driveEndurance)
 				if ac != nil {
 					break
 				}
@@ -126,6 +133,155 @@ func (nc *nodeCapacity) selectACForVolume(vol *genV1.Volume) *accrd.AvailableCap
 	return nc.getOriginalAC(ac.Name)
 }
 
+// selectACForCache select AC of LVG in cache storage class of vol for its cache LV,
+// drive AC of the sub storage class is selected for the new LVG if there is no such LVG
+// will modify nodeCapacity AC cache
+func (nc *nodeCapacity) selectACForCache(vol *genV1.Volume) *accrd.AvailableCapacity {
+	return nc.selectACForVolume(&genV1.Volume{StorageClass: vol.CacheStorageClass, Size: GetCacheLVSize(vol)})
+}
+
+// selectACForThinVolume select AC for the thin volume, AC of thin pool LVG provides virtual size
+// which is overcommit ratio times bigger than its size, so only physical part of the volume is subtracted from it.
+// If there is no such AC, drive AC of the sub storage class is selected for the new thin pool LVG
+// with overcommit ratio of the volume
+// will modify nodeCapacity AC cache
+func (nc *nodeCapacity) selectACForThinVolume(vol *genV1.Volume) *accrd.AvailableCapacity {
+	var (
+		size = AlignSizeByPE(vol.GetSize())
+		scM  = nc.getStorageClassToACMapping()
+		ac   *accrd.AvailableCapacity
+	)
+
+	for _, lvgAC := range nc.filterMultiPVLVGs(scM[vol.StorageClass]) {
+		virtualSize := GetACVirtualSize(lvgAC)
+		if virtualSize < size || (ac != nil && virtualSize >= GetACVirtualSize(ac)) {
+			continue
+		}
+		ac = lvgAC
+	}
+	if ac != nil {
+		nc.saveOriginalAC(ac)
+		ac.Spec.Size -= GetThinPhysicalSize(size, ac.Spec.OvercommitRatio)
+		return nc.getOriginalAC(ac.Name)
+	}
+
+	ratio := GetOvercommitRatio(vol)
+	physicalSize := GetThinPhysicalSize(size, ratio)
+	ac = searchACWithClosestSize(scM[util.GetSubStorageClass(vol.StorageClass)],
+		physicalSize+LvgDefaultMetadataSize, nc.driveEndurance)
+	if ac == nil {
+		return nil
+	}
+	nc.saveOriginalAC(ac)
+	ac.Spec.StorageClass = vol.StorageClass
+	ac.Spec.OvercommitRatio = ratio
+	ac.Spec.Size -= physicalSize + LvgDefaultMetadataSize
+	return nc.getOriginalAC(ac.Name)
+}
+
+// selectACsForRAIDVolume select drive ACs of the sub storage class for all members of MD RAID array of the volume,
+// each member consumes the whole drive, ACs with the closest size are preferred
+// will modify nodeCapacity AC cache only if ACs for all members are found
+func (nc *nodeCapacity) selectACsForRAIDVolume(vol *genV1.Volume) []*accrd.AvailableCapacity {
+	var (
+		count    = util.GetRAIDDrivesCount(vol.StorageClass)
+		size     = util.GetRAIDMemberSize(vol.StorageClass, vol.GetSize()) + MDRaidMetadataSize
+		acs      = nc.getStorageClassToACMapping()[util.GetSubStorageClass(vol.StorageClass)]
+		selected = make([]*accrd.AvailableCapacity, 0, count)
+	)
+
+	for len(selected) < count {
+		ac := searchACWithClosestSize(acs, size, nc.driveEndurance)
+		if ac == nil {
+			return nil
+		}
+		delete(acs, ac.Name)
+		selected = append(selected, ac)
+	}
+
+	result := make([]*accrd.AvailableCapacity, 0, count)
+	for _, ac := range selected {
+		nc.saveOriginalAC(ac)
+		nc.removeAC(ac)
+		result = append(result, nc.getOriginalAC(ac.Name))
+	}
+	return result
+}
+
+// selectACsForMultiPVVolume select AC for the volume whose striped or raid1 logical volume is spread over several PVs.
+// Free space of LVG is aggregated across its PVs, so only LVGs whose PVs number is equal to the number of PVs
+// of the logical volume are considered: such logical volume consumes the same extents on each PV
+// and free extents remain spread evenly.
+// If there is no such LVG, drive ACs of the sub storage class are selected for the new LVG,
+// each drive should fit one stripe or one copy of the logical volume, AC of the first drive represents the new LVG
+// and provides only the size of the smallest drive on each PV.
+// will modify nodeCapacity AC cache only if ACs are found
+func (nc *nodeCapacity) selectACsForMultiPVVolume(vol *genV1.Volume) []*accrd.AvailableCapacity {
+	var (
+		pvs       = util.GetLVPVsCount(vol)
+		footprint = GetLVFootprint(vol, GetLVSize(vol, vol.GetSize()))
+		scM       = nc.getStorageClassToACMapping()
+		lvgACs    = ACMap{}
+	)
+
+	for name, ac := range scM[vol.StorageClass] {
+		if nc.lvgPVs[ac.Spec.Location] == pvs {
+			lvgACs[name] = ac
+		}
+	}
+	if ac := searchACWithClosestSize(lvgACs, footprint, nc.driveEndurance); ac != nil {
+		nc.saveOriginalAC(ac)
+		ac.Spec.Size -= footprint
+		return []*accrd.AvailableCapacity{nc.getOriginalAC(ac.Name)}
+	}
+
+	var (
+		sizePerPV = footprint/int64(pvs) + LvgDefaultMetadataSize
+		acs       = scM[util.GetSubStorageClass(vol.StorageClass)]
+		selected  = make([]*accrd.AvailableCapacity, 0, pvs)
+	)
+	for len(selected) < pvs {
+		ac := searchACWithClosestSize(acs, sizePerPV, nc.driveEndurance)
+		if ac == nil {
+			return nil
+		}
+		delete(acs, ac.Name)
+		selected = append(selected, ac)
+	}
+
+	var (
+		pvSize int64 = math.MaxInt64
+		result       = make([]*accrd.AvailableCapacity, 0, pvs)
+	)
+	for _, ac := range selected {
+		nc.saveOriginalAC(ac)
+		if ac.Spec.Size < pvSize {
+			pvSize = ac.Spec.Size
+		}
+		result = append(result, nc.getOriginalAC(ac.Name))
+	}
+	for _, ac := range selected[1:] {
+		nc.removeAC(ac)
+	}
+	lvgAC := selected[0]
+	lvgAC.Spec.StorageClass = vol.StorageClass
+	lvgAC.Spec.Size = int64(pvs)*(pvSize-LvgDefaultMetadataSize) - footprint
+	nc.lvgPVs[lvgAC.Spec.Location] = pvs
+	return result
+}
+
+// filterMultiPVLVGs returns ACs which don't point on LVG spread over several PVs. Such LVGs are used only
+// for striped and raid1 logical volumes spread over all their PVs, otherwise free extents become unevenly spread
+func (nc *nodeCapacity) filterMultiPVLVGs(acs ACMap) ACMap {
+	result := ACMap{}
+	for name, ac := range acs {
+		if nc.lvgPVs[ac.Spec.Location] <= 1 {
+			result[name] = ac
+		}
+	}
+	return result
+}
+
 func (nc *nodeCapacity) getStorageClassToACMapping() SCToACMap {
 	result := SCToACMap{}
 	for _, ac := range nc.capacity {
@@ -137,17 +293,37 @@ func (nc *nodeCapacity) getStorageClassToACMapping() SCToACMap {
 	return result
 }
 
-func searchACWithClosestSize(acs ACMap, size int64) *accrd.AvailableCapacity {
+// searchACWithClosestSize returns AC with the smallest size which is enough for the volume,
+// AC on the least worn drive is preferred between SSD or NVMe ACs of the same size
+func searchACWithClosestSize(acs ACMap, size int64, driveEndurance map[string]int64) *accrd.AvailableCapacity {
 	var (
 		maxSize  int64 = math.MaxInt64
 		pickedAC *accrd.AvailableCapacity
 	)
 
 	for _, ac := range acs {
-		if ac.Spec.Size >= size && ac.Spec.Size < maxSize {
+		if ac.Spec.Size < size {
+			continue
+		}
+		if ac.Spec.Size < maxSize ||
+			(ac.Spec.Size == maxSize && isLessWorn(ac, pickedAC, driveEndurance)) {
 			pickedAC = ac
 			maxSize = ac.Spec.Size
 		}
 	}
 	return pickedAC
 }
+
+// isLessWorn returns true if both ACs are SSD or NVMe, both drives report endurance
+// and drive of the first AC has bigger endurance
+func isLessWorn(ac, pickedAC *accrd.AvailableCapacity, driveEndurance map[string]int64) bool {
+	for _, c := range []*accrd.AvailableCapacity{ac, pickedAC} {
+		if c.Spec.StorageClass != v1.StorageClassSSD && c.Spec.StorageClass != v1.StorageClassNVMe {
+			return false
+		}
+		if endurance, ok := driveEndurance[c.Spec.Location]; !ok || endurance < 0 {
+			return false
+		}
+	}
+	return driveEndurance[ac.Spec.Location] > driveEndurance[pickedAC.Spec.Location]
+}
@@ -37,6 +37,10 @@ type VolumesPlacingPlan struct {
 	plan VolumesPlanMap
 	// capacity holds mapping between nodeID and ACMap
 	capacity NodeCapacityMap
+	// members holds mapping between nodeID and ACs of MD RAID array members
+	members VolumesMembersPlanMap
+	// caches holds mapping between nodeID and ACs of LVGs for caches of the volumes
+	caches VolumesPlanMap
 }
 
 // GetVolumesToACMapping returns volumes to AC mapping for node
@@ -79,6 +83,17 @@ func (vpp *VolumesPlacingPlan) GetACForVolume(node string, volume *genV1.Volume)
 	return ac
 }
 
+// GetMemberACsForVolume returns ACs selected for members of MD RAID array of the volume on node,
+// nil for volumes which don't relate to MD RAID
+func (vpp *VolumesPlacingPlan) GetMemberACsForVolume(node string, volume *genV1.Volume) []*accrd.AvailableCapacity {
+	return vpp.members[node][volume]
+}
+
+// GetCacheACForVolume returns AC selected for cache of the volume on node, nil for volumes which aren't cached
+func (vpp *VolumesPlacingPlan) GetCacheACForVolume(node string, volume *genV1.Volume) *accrd.AvailableCapacity {
+	return vpp.caches[node][volume]
+}
+
 // GetACsForVolumes returns mapping between volume and AC list
 // AC list consist of suitable ACs on all nodes
 func (vpp *VolumesPlacingPlan) GetACsForVolumes() VolToACListMap {
@@ -90,3 +105,14 @@ func (vpp *VolumesPlacingPlan) GetACsForVolumes() VolToACListMap {
 	}
 	return volToACListMap
 }
+
+// GetCacheACsForVolumes returns mapping between cached volume and list of ACs for its cache on all nodes
+func (vpp *VolumesPlacingPlan) GetCacheACsForVolumes() VolToACListMap {
+	volToACListMap := VolToACListMap{}
+	for _, volToACMap := range vpp.caches {
+		for vol, ac := range volToACMap {
+			volToACListMap[vol] = append(volToACListMap[vol], ac)
+		}
+	}
+	return volToACListMap
+}
@@ -25,6 +25,8 @@ import (
 	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 )
 
@@ -46,6 +48,9 @@ type VolToACListMap map[*genV1.Volume][]*accrd.AvailableCapacity
 // VolumesPlanMap NodeID to VolToACMap mapping
 type VolumesPlanMap map[string]VolToACMap
 
+// VolumesMembersPlanMap NodeID to VolToACListMap mapping, holds ACs of MD RAID array members
+type VolumesMembersPlanMap map[string]VolToACListMap
+
 // NodeCapacityMap NodeID to ACMap mapping
 type NodeCapacityMap map[string]ACMap
 
@@ -64,6 +69,18 @@ type ReservationReader interface {
 	ReadReservations(ctx context.Context) ([]acrcrd.AvailableCapacityReservation, error)
 }
 
+// DriveReader methods to read drives
+type DriveReader interface {
+	// ReadDrives read drives
+	ReadDrives(ctx context.Context) ([]drivecrd.Drive, error)
+}
+
+// LVGReader methods to read LVGs
+type LVGReader interface {
+	// ReadLVGs read LVGs
+	ReadLVGs(ctx context.Context) ([]lvgcrd.LVG, error)
+}
+
 // CapacityPlaner describes interface for volumes placing planing
 type CapacityPlaner interface {
 	// PlanVolumesPlacing plan volumes placing on nodes
@@ -73,7 +90,8 @@ type CapacityPlaner interface {
 // CapacityManagerBuilder interface for capacity managers creation
 type CapacityManagerBuilder interface {
 	// GetCapacityManager returns CapacityManager
-	GetCapacityManager(logger *logrus.Entry, capReader CapacityReader) CapacityPlaner
+	GetCapacityManager(logger *logrus.Entry, capReader CapacityReader,
+		driveReader DriveReader, lvgReader LVGReader) CapacityPlaner
 	// GetReservedCapacityManager returns ReservedCapacityManager
 	GetReservedCapacityManager(logger *logrus.Entry,
 		capReader CapacityReader, resReader ReservationReader) CapacityPlaner
@@ -84,8 +102,8 @@ type DefaultCapacityManagerBuilder struct{}
 
 // GetCapacityManager returns default implementation of CapacityManager
 func (dcmb *DefaultCapacityManagerBuilder) GetCapacityManager(
-	logger *logrus.Entry, capReader CapacityReader) CapacityPlaner {
-	return NewCapacityManager(logger, capReader)
+	logger *logrus.Entry, capReader CapacityReader, driveReader DriveReader, lvgReader LVGReader) CapacityPlaner {
+	return NewCapacityManager(logger, capReader, driveReader, lvgReader)
 }
 
 // GetReservedCapacityManager returns default implementation of ReservedCapacityManager
@@ -95,17 +113,29 @@ func (dcmb *DefaultCapacityManagerBuilder) GetReservedCapacityManager(
 }
 
 // NewCapacityManager return new instance of CapacityManager
-func NewCapacityManager(logger *logrus.Entry, capReader CapacityReader) *CapacityManager {
+// driveReader is optional, it is used to prefer less worn drives and could be nil
+// lvgReader is optional, it is used to place striped and raid1 logical volumes on existing LVGs and could be nil
+func NewCapacityManager(logger *logrus.Entry, capReader CapacityReader,
+	driveReader DriveReader, lvgReader LVGReader) *CapacityManager {
 	return &CapacityManager{
-		logger:    logger,
-		capReader: capReader,
+		logger:      logger,
+		capReader:   capReader,
+		driveReader: driveReader,
+		lvgReader:   lvgReader,
 	}
 }
 
 // CapacityManager provides placing plan for volumes
 type CapacityManager struct {
-	logger    *logrus.Entry
-	capReader CapacityReader
+	logger      *logrus.Entry
+	capReader   CapacityReader
+	driveReader DriveReader
+	lvgReader   LVGReader
+
+	// drive UUID to drive endurance
+	driveEndurance map[string]int64
+	// LVG name to number of its PVs
+	lvgPVs map[string]int
 
 	// nodeID to nodeCapacity
 	nodesCapacity map[string]*nodeCapacity
@@ -120,39 +150,83 @@ func (cm *CapacityManager) PlanVolumesPlacing(
 		return nil, fmt.Errorf("failed to update capacity data: %s", err.Error())
 	}
 	plan := VolumesPlanMap{}
+	members := VolumesMembersPlanMap{}
+	caches := VolumesPlanMap{}
 
 	for node := range cm.nodesCapacity {
-		volToACOnNode := cm.selectCapacityOnNode(ctx, node, volumes)
+		volToACOnNode, volToMembersOnNode, volToCacheOnNode := cm.selectCapacityOnNode(ctx, node, volumes)
 		if volToACOnNode == nil {
 			continue
 		}
 		plan[node] = volToACOnNode
+		members[node] = volToMembersOnNode
+		caches[node] = volToCacheOnNode
 	}
 	if len(plan) == 0 {
 		logger.Info("Required capacity for volumes not found")
 		return nil, nil
 	}
 	logger.Info("Capacity for all volumes found")
-	return NewVolumesPlacingPlan(plan, cm.convertCapacityToMap()), nil
+	placingPlan := NewVolumesPlacingPlan(plan, cm.convertCapacityToMap())
+	placingPlan.members = members
+	placingPlan.caches = caches
+	return placingPlan, nil
 }
 
-func (cm *CapacityManager) selectCapacityOnNode(ctx context.Context, node string, volumes []*genV1.Volume) VolToACMap {
+// selectCapacityOnNode returns AC for each volume and ACs of all array members for volumes on MD RAID
+// or ACs of all drives of the new LVG for striped and raid1 logical volumes,
+// for such volumes the first AC is used as AC of the volume. AC of LVG for the cache is returned for cached volumes
+func (cm *CapacityManager) selectCapacityOnNode(ctx context.Context, node string,
+	volumes []*genV1.Volume) (VolToACMap, VolToACListMap, VolToACMap) {
 	logger := util.AddCommonFields(ctx, cm.logger, "CapacityManager.selectCapacityOnNode")
 	nodeCap := cm.nodesCapacity[node]
 
 	result := VolToACMap{}
+	members := VolToACListMap{}
+	caches := VolToACMap{}
 
 	for _, vol := range volumes {
+		if util.IsStorageClassRAID(vol.StorageClass) {
+			acs := nodeCap.selectACsForRAIDVolume(vol)
+			if acs == nil {
+				logger.Tracef("ACs for MD RAID vol: %s not found on node %s", vol.Id, node)
+				return nil, nil, nil
+			}
+			logger.Tracef("ACs %v selected for MD RAID vol: %s found on node %s", acs, vol.Id, node)
+			result[vol] = acs[0]
+			members[vol] = acs
+			continue
+		}
+		if util.IsStorageClassLVG(vol.StorageClass) && util.GetLVPVsCount(vol) > 1 {
+			acs := nodeCap.selectACsForMultiPVVolume(vol)
+			if acs == nil {
+				logger.Tracef("ACs for %s LV vol: %s not found on node %s", vol.LVType, vol.Id, node)
+				return nil, nil, nil
+			}
+			logger.Tracef("ACs %v selected for %s LV vol: %s found on node %s", acs, vol.LVType, vol.Id, node)
+			result[vol] = acs[0]
+			members[vol] = acs
+			continue
+		}
 		ac := nodeCap.selectACForVolume(vol)
 		if ac == nil {
 			logger.Tracef("AC for vol: %s not found on node %s", vol.Id, node)
-			return nil
+			return nil, nil, nil
 		}
 		logger.Tracef("AC %v selected for vol: %s found on node %s", ac, vol.Id, node)
 		result[vol] = ac
+		if vol.CacheStorageClass != "" {
+			cacheAC := nodeCap.selectACForCache(vol)
+			if cacheAC == nil {
+				logger.Tracef("AC for cache of vol: %s not found on node %s", vol.Id, node)
+				return nil, nil, nil
+			}
+			logger.Tracef("AC %v selected for cache of vol: %s found on node %s", cacheAC, vol.Id, node)
+			caches[vol] = cacheAC
+		}
 	}
 	logger.Debugf("AC for all volumes found on node %s", node)
-	return result
+	return result, members, caches
 }
 
 func (cm *CapacityManager) update(ctx context.Context) error {
@@ -163,6 +237,28 @@ func (cm *CapacityManager) update(ctx context.Context) error {
 		logger.Errorf("Failed to read capacity: %s", err.Error())
 		return err
 	}
+	cm.driveEndurance = map[string]int64{}
+	if cm.driveReader != nil {
+		drives, err := cm.driveReader.ReadDrives(ctx)
+		if err != nil {
+			// endurance is used for choosing between equal ACs only, so planning is able to proceed without it
+			logger.Warningf("Failed to read drives: %s", err.Error())
+		}
+		for _, d := range drives {
+			cm.driveEndurance[d.Spec.UUID] = d.Spec.Endurance
+		}
+	}
+	cm.lvgPVs = map[string]int{}
+	if cm.lvgReader != nil {
+		lvgs, err := cm.lvgReader.ReadLVGs(ctx)
+		if err != nil {
+			// striped and raid1 logical volumes are placed on new LVGs only in that case
+			logger.Warningf("Failed to read LVGs: %s", err.Error())
+		}
+		for _, lvg := range lvgs {
+			cm.lvgPVs[lvg.Name] = len(lvg.Spec.Locations)
+		}
+	}
 	for _, c := range capacity {
 		c := c
 		nodeID := c.Spec.NodeId
@@ -182,7 +278,11 @@ func (cm *CapacityManager) convertCapacityToMap() NodeCapacityMap {
 
 func (cm *CapacityManager) registerNodeCapacity(node string, capacity *accrd.AvailableCapacity) {
 	if _, ok := cm.nodesCapacity[node]; !ok {
-		cm.nodesCapacity[node] = &nodeCapacity{capacity: ACMap{}}
+		cm.nodesCapacity[node] = &nodeCapacity{
+			capacity:       ACMap{},
+			driveEndurance: cm.driveEndurance,
+			lvgPVs:         cm.lvgPVs,
+		}
 	}
 	cm.nodesCapacity[node].registerAC(capacity)
 }
@@ -206,9 +306,15 @@ type ReservedCapacityManager struct {
 	nodeCapacityMap     NodeCapacityMap
 	acrMap              ACRMap
 	acNameToACRNamesMap ACNameToACRNamesMap
+
+	// reserved capacity for cache LV of the cached volume
+	cacheCapacityMap         NodeCapacityMap
+	cacheACRMap              ACRMap
+	cacheACNameToACRNamesMap ACNameToACRNamesMap
 }
 
-// PlanVolumesPlacing build placing plan for reserved volumes
+// PlanVolumesPlacing build placing plan for reserved volumes,
+// AC for cache of the cached volume is selected from cache reservations on the same node
 func (rcm *ReservedCapacityManager) PlanVolumesPlacing(
 	ctx context.Context, volumes []*genV1.Volume) (*VolumesPlacingPlan, error) {
 	logger := util.AddCommonFields(ctx, rcm.logger, "ReservedCapacityManager.PlanVolumesPlacing")
@@ -219,6 +325,12 @@ func (rcm *ReservedCapacityManager) PlanVolumesPlacing(
 		return nil, fmt.Errorf("plannning for multipile volumes not supported, volumes count: %d", len(volumes))
 	}
 	volume := volumes[0]
+	if util.IsStorageClassRAID(volume.StorageClass) || util.IsStorageClassLVGThin(volume.StorageClass) {
+		return nil, fmt.Errorf("storage class %s isn't supported with capacity reservation", volume.StorageClass)
+	}
+	if util.GetLVPVsCount(volume) > 1 {
+		return nil, fmt.Errorf("%s logical volumes aren't supported with capacity reservation", volume.LVType)
+	}
 	err := rcm.update(ctx, volume)
 	if err != nil {
 		return nil, err
@@ -229,14 +341,29 @@ func (rcm *ReservedCapacityManager) PlanVolumesPlacing(
 		return nil, nil
 	}
 	plan := VolumesPlanMap{}
+	caches := VolumesPlanMap{}
 	for node, acMap := range selectedACs {
+		if volume.CacheStorageClass != "" {
+			cacheAC, _ := choseACFromOldestACR(rcm.cacheCapacityMap[node], rcm.cacheACRMap, rcm.cacheACNameToACRNamesMap)
+			if cacheAC == nil {
+				logger.Infof("Reserved capacity for cache not found on node %s", node)
+				continue
+			}
+			caches[node] = VolToACMap{volume: cacheAC}
+		}
 		// we should have single value in acMap
 		for _, ac := range acMap {
 			plan[node] = VolToACMap{volume: ac}
 		}
 	}
+	if len(plan) == 0 {
+		logger.Info("Required capacity for volumes not found")
+		return nil, nil
+	}
 	logger.Info("Capacity for all volumes found")
-	return NewVolumesPlacingPlan(plan, rcm.nodeCapacityMap), nil
+	placingPlan := NewVolumesPlacingPlan(plan, rcm.nodeCapacityMap)
+	placingPlan.caches = caches
+	return placingPlan, nil
 }
 
 func (rcm *ReservedCapacityManager) update(ctx context.Context, volume *genV1.Volume) error {
@@ -252,15 +379,26 @@ func (rcm *ReservedCapacityManager) update(ctx context.Context, volume *genV1.Vo
 		logger.Errorf("failed to read ACR list: %s", err.Error())
 		return err
 	}
+	rcm.nodeCapacityMap, rcm.acrMap, rcm.acNameToACRNamesMap =
+		buildReservedCapacity(acList, acrList, volume.StorageClass, volume.Size)
+	if volume.CacheStorageClass != "" {
+		rcm.cacheCapacityMap, rcm.cacheACRMap, rcm.cacheACNameToACRNamesMap =
+			buildReservedCapacity(acList, acrList, volume.CacheStorageClass, GetCacheLVSize(volume))
+	}
+
+	return nil
+}
+
+// buildReservedCapacity returns ACs reserved by ACRs with provided storage class and size
+func buildReservedCapacity(acList []accrd.AvailableCapacity, acrList []acrcrd.AvailableCapacityReservation,
+	sc string, size int64) (NodeCapacityMap, ACRMap, ACNameToACRNamesMap) {
 	filteredACRs := FilterACRList(acrList, func(acr acrcrd.AvailableCapacityReservation) bool {
-		return acr.Spec.StorageClass == volume.StorageClass && acr.Spec.Size == volume.Size
+		return acr.Spec.StorageClass == sc && acr.Spec.Size == size
 	})
 	resFilter := NewReservationFilter()
 	reservedACs := resFilter.FilterByReservation(true, acList, filteredACRs)
-	rcm.nodeCapacityMap = buildNodeCapacityMap(reservedACs)
-	rcm.acrMap, rcm.acNameToACRNamesMap = buildACRMaps(filteredACRs)
-
-	return nil
+	acrMap, acNameToACRNamesMap := buildACRMaps(filteredACRs)
+	return buildNodeCapacityMap(reservedACs), acrMap, acNameToACRNamesMap
 }
 
 // selectBestACForNode select best AC for volume on node
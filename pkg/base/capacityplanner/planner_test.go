@@ -31,6 +31,8 @@ import (
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 )
 
 var (
@@ -64,6 +66,17 @@ func getTestAC(nodeID string, size int64, sc string) *accrd.AvailableCapacity {
 	}
 }
 
+func getTestDrive(uuid string, endurance int64) *drivecrd.Drive {
+	return &drivecrd.Drive{
+		TypeMeta:   k8smetav1.TypeMeta{Kind: "Drive", APIVersion: apiV1.APIV1Version},
+		ObjectMeta: k8smetav1.ObjectMeta{Name: uuid},
+		Spec: genV1.Drive{
+			UUID:      uuid,
+			Endurance: endurance,
+		},
+	}
+}
+
 func getTestACR(size int64, sc string,
 	acList []*accrd.AvailableCapacity) *acrcrd.AvailableCapacityReservation {
 	acNames := make([]string, len(acList))
@@ -87,11 +100,11 @@ func TestCapacityManager(t *testing.T) {
 	ctx := context.Background()
 
 	callPlanVolumesPlacing := func(capRead CapacityReader, volumes []*genV1.Volume) (*VolumesPlacingPlan, error) {
-		capManager := NewCapacityManager(logger, capRead)
+		capManager := NewCapacityManager(logger, capRead, nil, nil)
 		return capManager.PlanVolumesPlacing(ctx, volumes)
 	}
 	t.Run("Failed to read capacity", func(t *testing.T) {
-		capManager := NewCapacityManager(logger, getCapReaderMock(nil, testErr))
+		capManager := NewCapacityManager(logger, getCapReaderMock(nil, testErr), nil, nil)
 		plan, err := capManager.PlanVolumesPlacing(ctx,
 			[]*genV1.Volume{getTestVol(testNode1, testSmallSize, apiV1.StorageClassHDD)})
 		assert.Nil(t, plan)
@@ -255,6 +268,254 @@ func TestCapacityManager(t *testing.T) {
 			assert.Equal(t, testACS[0], plan.GetACForVolume(testNode1, testVols[1]))
 		}
 	})
+	t.Run("Least worn drive", func(t *testing.T) {
+		testVols := []*genV1.Volume{
+			getTestVol(testNode1, testSmallSize, apiV1.StorageClassSSD),
+		}
+		testACs := []*accrd.AvailableCapacity{
+			getTestAC(testNode1, testSmallSize, apiV1.StorageClassSSD),
+			getTestAC(testNode1, testSmallSize, apiV1.StorageClassSSD),
+			getTestAC(testNode1, testSmallSize, apiV1.StorageClassSSD),
+		}
+		testDrives := make([]*drivecrd.Drive, len(testACs))
+		for i, endurance := range []int64{40, 95, 70} {
+			testACs[i].Spec.Location = uuid.New().String()
+			testDrives[i] = getTestDrive(testACs[i].Spec.Location, endurance)
+		}
+		// map iteration order is random, check several times
+		for i := 0; i < 10; i++ {
+			capManager := NewCapacityManager(logger, getCapReaderMock(testACs, nil),
+				getDriveReaderMock(testDrives, nil), nil)
+			plan, err := capManager.PlanVolumesPlacing(ctx, testVols)
+			assert.Nil(t, err)
+			assert.NotNil(t, plan)
+			if plan != nil {
+				assert.Equal(t, testACs[1].Name, plan.GetACForVolume(testNode1, testVols[0]).Name)
+			}
+		}
+		// worn out drive isn't confused with drive which doesn't report endurance
+		driveEndurance := map[string]int64{
+			testACs[0].Spec.Location: 0,
+			testACs[1].Spec.Location: apiV1.DriveEnduranceUnknown,
+		}
+		assert.False(t, isLessWorn(testACs[0], testACs[1], driveEndurance))
+		assert.False(t, isLessWorn(testACs[1], testACs[0], driveEndurance))
+		assert.True(t, isLessWorn(testACs[2], testACs[0], map[string]int64{
+			testACs[0].Spec.Location: 0,
+			testACs[2].Spec.Location: 70,
+		}))
+		// planning works without drives
+		capManager := NewCapacityManager(logger, getCapReaderMock(testACs, nil), getDriveReaderMock(nil, testErr), nil)
+		plan, err := capManager.PlanVolumesPlacing(ctx, testVols)
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+	})
+	t.Run("MD RAID volume", func(t *testing.T) {
+		testVols := []*genV1.Volume{
+			getTestVol("", testSmallSize, apiV1.StorageClassHDDRAID10),
+		}
+		memberSize := testSmallSize/2 + MDRaidMetadataSize
+		testACs := []*accrd.AvailableCapacity{
+			getTestAC(testNode1, memberSize, apiV1.StorageClassHDD),
+			getTestAC(testNode1, testLargeSize, apiV1.StorageClassHDD),
+			getTestAC(testNode1, memberSize, apiV1.StorageClassHDD),
+			getTestAC(testNode1, memberSize, apiV1.StorageClassSSD),
+			getTestAC(testNode1, memberSize, apiV1.StorageClassHDD),
+			getTestAC(testNode2, memberSize, apiV1.StorageClassHDD),
+			getTestAC(testNode2, memberSize, apiV1.StorageClassHDD),
+		}
+		plan, err := callPlanVolumesPlacing(getCapReaderMock(testACs, nil), testVols)
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+		if plan != nil {
+			// there are only two HDD drives on the second node
+			assert.Nil(t, plan.GetACForVolume(testNode2, testVols[0]))
+			members := plan.GetMemberACsForVolume(testNode1, testVols[0])
+			assert.Len(t, members, 4)
+			assert.Equal(t, members[0], plan.GetACForVolume(testNode1, testVols[0]))
+			assert.NotContains(t, members, testACs[3])
+		}
+
+		// each volume needs its own drives
+		testVols = append(testVols, getTestVol("", testSmallSize, apiV1.StorageClassHDDRAID1))
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACs, nil), testVols)
+		assert.Nil(t, err)
+		assert.Nil(t, plan)
+
+		// member is too small
+		testVols = []*genV1.Volume{getTestVol("", testSmallSize, apiV1.StorageClassHDDRAID1)}
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACs[5:], nil), testVols)
+		assert.Nil(t, err)
+		assert.Nil(t, plan)
+
+		// capacity reservation isn't supported
+		capManager := NewReservedCapacityManager(logger, getCapReaderMock(testACs, nil), getResReaderMock(nil, nil))
+		_, err = capManager.PlanVolumesPlacing(ctx, testVols)
+		assert.Error(t, err)
+	})
+	t.Run("Striped and raid1 logical volumes", func(t *testing.T) {
+		raid1Vol := getTestVol("", testSmallSize, apiV1.StorageClassHDDLVG)
+		raid1Vol.LVType, raid1Vol.Mirrors = apiV1.LVTypeRAID1, 1
+		footprint := GetLVFootprint(raid1Vol, GetLVSize(raid1Vol, testSmallSize))
+		sizePerPV := footprint/2 + LvgDefaultMetadataSize
+
+		testACs := []*accrd.AvailableCapacity{
+			getTestAC(testNode1, sizePerPV, apiV1.StorageClassHDD),
+			getTestAC(testNode1, sizePerPV, apiV1.StorageClassSSD),
+			getTestAC(testNode1, sizePerPV, apiV1.StorageClassHDD),
+			getTestAC(testNode1, testLargeSize*2, apiV1.StorageClassHDDLVG),
+		}
+		for _, ac := range testACs {
+			ac.Spec.Location = uuid.New().String()
+		}
+		// LVG has enough free space in total, but has a single PV
+		testLVGs := []*lvgcrd.LVG{{
+			ObjectMeta: k8smetav1.ObjectMeta{Name: testACs[3].Spec.Location},
+			Spec:       genV1.LogicalVolumeGroup{Name: testACs[3].Spec.Location, Locations: []string{"drive"}},
+		}}
+		capManager := NewCapacityManager(logger, getCapReaderMock(testACs, nil), nil, getLVGReaderMock(testLVGs, nil))
+		plan, err := capManager.PlanVolumesPlacing(ctx, []*genV1.Volume{raid1Vol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+		if plan != nil {
+			// new LVG is created on two HDD drives
+			members := plan.GetMemberACsForVolume(testNode1, raid1Vol)
+			assert.ElementsMatch(t, []string{testACs[0].Name, testACs[2].Name},
+				[]string{members[0].Name, members[1].Name})
+			assert.Equal(t, members[0], plan.GetACForVolume(testNode1, raid1Vol))
+		}
+
+		// LVG has two PVs
+		testLVGs[0].Spec.Locations = []string{"drive-1", "drive-2"}
+		capManager = NewCapacityManager(logger, getCapReaderMock(testACs, nil), nil, getLVGReaderMock(testLVGs, nil))
+		plan, err = capManager.PlanVolumesPlacing(ctx, []*genV1.Volume{raid1Vol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+		if plan != nil {
+			assert.Equal(t, testACs[3].Name, plan.GetACForVolume(testNode1, raid1Vol).Name)
+		}
+
+		// linear logical volume isn't placed on LVG with two PVs
+		linearVol := getTestVol("", testSmallSize, apiV1.StorageClassHDDLVG)
+		capManager = NewCapacityManager(logger, getCapReaderMock(testACs, nil), nil, getLVGReaderMock(testLVGs, nil))
+		plan, err = capManager.PlanVolumesPlacing(ctx, []*genV1.Volume{linearVol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+		if plan != nil {
+			assert.NotEqual(t, testACs[3].Name, plan.GetACForVolume(testNode1, linearVol).Name)
+		}
+
+		// LVG has three PVs, raid1 logical volume on two of them spreads free extents unevenly
+		testLVGs[0].Spec.Locations = []string{"drive-1", "drive-2", "drive-3"}
+		capManager = NewCapacityManager(logger, getCapReaderMock(testACs, nil), nil, getLVGReaderMock(testLVGs, nil))
+		plan, err = capManager.PlanVolumesPlacing(ctx, []*genV1.Volume{raid1Vol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+		if plan != nil {
+			assert.Len(t, plan.GetMemberACsForVolume(testNode1, raid1Vol), 2)
+			assert.NotEqual(t, testACs[3].Name, plan.GetACForVolume(testNode1, raid1Vol).Name)
+		}
+
+		// there are only two HDD drives for three stripes, LVGs aren't read
+		stripedVol := getTestVol("", testSmallSize, apiV1.StorageClassHDDLVG)
+		stripedVol.LVType, stripedVol.Stripes = apiV1.LVTypeStriped, 3
+		capManager = NewCapacityManager(logger, getCapReaderMock(testACs, nil), nil, getLVGReaderMock(nil, testErr))
+		plan, err = capManager.PlanVolumesPlacing(ctx, []*genV1.Volume{stripedVol})
+		assert.Nil(t, err)
+		assert.Nil(t, plan)
+
+		// capacity reservation isn't supported
+		resManager := NewReservedCapacityManager(logger, getCapReaderMock(testACs, nil), getResReaderMock(nil, nil))
+		_, err = resManager.PlanVolumesPlacing(ctx, []*genV1.Volume{raid1Vol})
+		assert.Error(t, err)
+	})
+	t.Run("Thin volumes", func(t *testing.T) {
+		thinVol := getTestVol("", testLargeSize, apiV1.StorageClassHDDLVGThin)
+		physicalSize := GetThinPhysicalSize(AlignSizeByPE(testLargeSize), DefaultThinOvercommitRatio)
+
+		// the new thin pool LVG, drive is smaller than the volume
+		testACs := []*accrd.AvailableCapacity{
+			getTestAC(testNode1, physicalSize+LvgDefaultMetadataSize, apiV1.StorageClassHDD),
+		}
+		plan, err := callPlanVolumesPlacing(getCapReaderMock(testACs, nil), []*genV1.Volume{thinVol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+		if plan != nil {
+			assert.Equal(t, testACs[0].Name, plan.GetACForVolume(testNode1, thinVol).Name)
+		}
+
+		// storage class sets higher overcommit ratio of the new thin pool LVG
+		testACs = []*accrd.AvailableCapacity{
+			getTestAC(testNode1, physicalSize/2+LvgDefaultMetadataSize, apiV1.StorageClassHDD),
+		}
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACs, nil), []*genV1.Volume{thinVol})
+		assert.Nil(t, err)
+		assert.Nil(t, plan)
+		ratioVol := getTestVol("", testLargeSize, apiV1.StorageClassHDDLVGThin)
+		ratioVol.OvercommitRatio = 2 * DefaultThinOvercommitRatio
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACs, nil), []*genV1.Volume{ratioVol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+
+		// thin pool LVG provides virtual size
+		lvgAC := getTestAC(testNode1, physicalSize, apiV1.StorageClassHDDLVGThin)
+		lvgAC.Spec.OvercommitRatio = DefaultThinOvercommitRatio
+		testACs = []*accrd.AvailableCapacity{lvgAC}
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACs, nil), []*genV1.Volume{thinVol,
+			getTestVol("", testLargeSize, apiV1.StorageClassHDDLVGThin)})
+		assert.Nil(t, err)
+		assert.Nil(t, plan)
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACs, nil), []*genV1.Volume{thinVol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+
+		// there is no overcommit
+		lvgAC.Spec.OvercommitRatio = 0
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACs, nil), []*genV1.Volume{thinVol})
+		assert.Nil(t, err)
+		assert.Nil(t, plan)
+
+		// capacity reservation isn't supported
+		resManager := NewReservedCapacityManager(logger, getCapReaderMock(testACs, nil), getResReaderMock(nil, nil))
+		_, err = resManager.PlanVolumesPlacing(ctx, []*genV1.Volume{thinVol})
+		assert.Error(t, err)
+	})
+	t.Run("Cached volumes", func(t *testing.T) {
+		cachedVol := getTestVol("", testSmallSize, apiV1.StorageClassHDDLVG)
+		cachedVol.CacheStorageClass, cachedVol.CacheSize = apiV1.StorageClassNVMeLVG, testSmallSize/10
+
+		// there is no NVMe capacity for the cache
+		testACs := []*accrd.AvailableCapacity{
+			getTestAC(testNode1, testLargeSize, apiV1.StorageClassHDDLVG),
+			getTestAC(testNode1, testLargeSize, apiV1.StorageClassSSD),
+			getTestAC(testNode2, testLargeSize, apiV1.StorageClassHDD),
+			getTestAC(testNode2, GetCacheLVSize(cachedVol)-1, apiV1.StorageClassNVMeLVG),
+		}
+		plan, err := callPlanVolumesPlacing(getCapReaderMock(testACs, nil), []*genV1.Volume{cachedVol})
+		assert.Nil(t, err)
+		assert.Nil(t, plan)
+
+		// cache is placed on the new NVMe LVG of the same node
+		testACs = append(testACs, getTestAC(testNode1, testLargeSize, apiV1.StorageClassNVMe))
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACs, nil), []*genV1.Volume{cachedVol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+		if plan != nil {
+			assert.Equal(t, testACs[0].Name, plan.GetACForVolume(testNode1, cachedVol).Name)
+			assert.Equal(t, testACs[4].Name, plan.GetCacheACForVolume(testNode1, cachedVol).Name)
+			assert.Nil(t, plan.GetCacheACForVolume(testNode2, cachedVol))
+		}
+
+		// cache is placed on the existing NVMe LVG
+		testACs[3].Spec.Size = GetCacheLVSize(cachedVol)
+		plan, err = callPlanVolumesPlacing(getCapReaderMock(testACs[2:4], nil), []*genV1.Volume{cachedVol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+		if plan != nil {
+			assert.Equal(t, testACs[2].Name, plan.GetACForVolume(testNode2, cachedVol).Name)
+			assert.Equal(t, testACs[3].Name, plan.GetCacheACForVolume(testNode2, cachedVol).Name)
+		}
+	})
 }
 
 func TestReservedCapacityManager(t *testing.T) {
@@ -332,4 +593,39 @@ func TestReservedCapacityManager(t *testing.T) {
 			assert.Equal(t, testACS[0], plan.GetACForVolume(testNode1, testVols[0]))
 		}
 	})
+	t.Run("Cached volume", func(t *testing.T) {
+		testVol := getTestVol("", testSmallSize, apiV1.StorageClassHDDLVG)
+		testVol.CacheStorageClass = apiV1.StorageClassNVMeLVG
+		testVol.CacheSize = testSmallSize
+		cacheSize := GetCacheLVSize(testVol)
+		testACS := []*accrd.AvailableCapacity{
+			getTestAC(testNode1, testLargeSize, apiV1.StorageClassHDDLVG),
+			getTestAC(testNode2, testLargeSize, apiV1.StorageClassHDDLVG),
+			getTestAC(testNode1, testLargeSize, apiV1.StorageClassNVMeLVG),
+		}
+		testACRS := []*acrcrd.AvailableCapacityReservation{
+			getTestACR(testSmallSize, apiV1.StorageClassHDDLVG, testACS[:2]),
+			getTestACR(cacheSize, apiV1.StorageClassNVMeLVG, testACS[2:]),
+		}
+		plan, err := callPlanVolumesPlacing(
+			getCapReaderMock(testACS, nil),
+			getResReaderMock(testACRS, nil),
+			[]*genV1.Volume{testVol})
+		assert.Nil(t, err)
+		assert.NotNil(t, plan)
+		if plan != nil {
+			// cache isn't reserved on the second node
+			assert.Nil(t, plan.GetACForVolume(testNode2, testVol))
+			assert.Equal(t, testACS[0], plan.GetACForVolume(testNode1, testVol))
+			assert.Equal(t, testACS[2], plan.GetCacheACForVolume(testNode1, testVol))
+		}
+
+		// cache isn't reserved at all
+		plan, err = callPlanVolumesPlacing(
+			getCapReaderMock(testACS, nil),
+			getResReaderMock(testACRS[:1], nil),
+			[]*genV1.Volume{testVol})
+		assert.Nil(t, err)
+		assert.Nil(t, plan)
+	})
 }
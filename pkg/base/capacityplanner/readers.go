@@ -23,6 +23,8 @@ import (
 
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 )
@@ -137,3 +139,111 @@ func (rar *UnreservedACReader) ReadCapacity(ctx context.Context) ([]accrd.Availa
 	logger.Tracef("Read AvailableCapacity: %+v", reservedAC)
 	return reservedAC, nil
 }
+
+// NewLocationACReader returns instance of LocationACReader
+func NewLocationACReader(logger *logrus.Entry, capReader CapacityReader,
+	node, location string) *LocationACReader {
+	return &LocationACReader{
+		capReader: capReader,
+		node:      node,
+		location:  location,
+		logger:    logger,
+	}
+}
+
+// LocationACReader capReader which returns only ACs with provided location on provided node
+type LocationACReader struct {
+	capReader CapacityReader
+	node      string
+	location  string
+	logger    *logrus.Entry
+}
+
+// ReadCapacity returns ACs with provided location on provided node
+func (lar *LocationACReader) ReadCapacity(ctx context.Context) ([]accrd.AvailableCapacity, error) {
+	logger := util.AddCommonFields(ctx, lar.logger, "LocationACReader.ReadCapacity")
+
+	acList, err := lar.capReader.ReadCapacity(ctx)
+	if err != nil {
+		logger.Errorf("failed to read AC list: %s", err.Error())
+		return nil, err
+	}
+
+	locationAC := FilterACList(acList, func(ac accrd.AvailableCapacity) bool {
+		return ac.Spec.NodeId == lar.node && ac.Spec.Location == lar.location
+	})
+	logger.Tracef("Read AvailableCapacity: %+v", locationAC)
+	return locationAC, nil
+}
+
+// NewDriveReader returns instance of DriveReader
+func NewDriveReader(client *k8s.KubeClient, logger *logrus.Entry, cached bool) *DriveCRReader {
+	return &DriveCRReader{
+		client: client,
+		logger: logger,
+		cached: cached,
+	}
+}
+
+// DriveCRReader read Drives from kubernetes API
+type DriveCRReader struct {
+	client *k8s.KubeClient
+	logger *logrus.Entry
+	cached bool
+	cache  []drivecrd.Drive
+}
+
+// ReadDrives returns Drive list which was read from kubernetes API or from cache
+func (dr *DriveCRReader) ReadDrives(ctx context.Context) ([]drivecrd.Drive, error) {
+	logger := util.AddCommonFields(ctx, dr.logger, "DriveCRReader.ReadDrives")
+	if dr.cached && dr.cache != nil {
+		logger.Tracef("Read Drives from cache: %+v", dr.cache)
+		return dr.cache, nil
+	}
+	driveList := &drivecrd.DriveList{}
+	if err := dr.client.ReadList(ctx, driveList); err != nil {
+		logger.Errorf("failed to read Drive list: %s", err.Error())
+		return nil, err
+	}
+	logger.Tracef("Read Drives: %+v", driveList.Items)
+	if dr.cached {
+		dr.cache = driveList.Items
+	}
+	return driveList.Items, nil
+}
+
+// NewLVGReader returns instance of LVGReader
+func NewLVGReader(client *k8s.KubeClient, logger *logrus.Entry, cached bool) *LVGCRReader {
+	return &LVGCRReader{
+		client: client,
+		logger: logger,
+		cached: cached,
+	}
+}
+
+// LVGCRReader read LVGs from kubernetes API
+type LVGCRReader struct {
+	client *k8s.KubeClient
+	logger *logrus.Entry
+	cached bool
+	cache  []lvgcrd.LVG
+}
+
+// ReadLVGs returns LVG list which was read from kubernetes API or from cache
+func (lr *LVGCRReader) ReadLVGs(ctx context.Context) ([]lvgcrd.LVG, error) {
+	logger := util.AddCommonFields(ctx, lr.logger, "LVGCRReader.ReadLVGs")
+	if lr.cached && lr.cache != nil {
+		logger.Tracef("Read LVGs from cache: %+v", lr.cache)
+		return lr.cache, nil
+	}
+	lvgList := &lvgcrd.LVGList{}
+	if err := lr.client.ReadList(ctx, lvgList); err != nil {
+		logger.Errorf("failed to read LVG list: %s", err.Error())
+		return nil, err
+	}
+	logger.Tracef("Read LVGs: %+v", lvgList.Items)
+	if lr.cached {
+		lr.cache = lvgList.Items
+	}
+	return lvgList.Items, nil
+}
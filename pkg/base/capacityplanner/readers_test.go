@@ -20,11 +20,14 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	genV1 "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	acrcrd "github.com/dell/csi-baremetal/api/v1/acreservationcrd"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 )
 
 func TestACReader(t *testing.T) {
@@ -59,6 +62,37 @@ func TestACRReader(t *testing.T) {
 	assert.Len(t, resp, len(testACRs))
 }
 
+func TestDriveReader(t *testing.T) {
+	ctx := context.Background()
+	logger := testLogger.WithField("component", "test")
+	client := getKubeClient(t)
+	testDrives := []*drivecrd.Drive{
+		getTestDrive(uuid.New().String(), 90),
+		getTestDrive(uuid.New().String(), 0),
+	}
+	for _, d := range testDrives {
+		assert.Nil(t, client.CreateCR(ctx, d.Name, d))
+	}
+	reader := NewDriveReader(client, logger, true)
+	resp, err := reader.ReadDrives(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, resp, len(testDrives))
+}
+
+func TestLVGReader(t *testing.T) {
+	ctx := context.Background()
+	logger := testLogger.WithField("component", "test")
+	client := getKubeClient(t)
+	for _, name := range []string{uuid.New().String(), uuid.New().String()} {
+		lvg := client.ConstructLVGCR(name, genV1.LogicalVolumeGroup{Name: name, Locations: []string{"drive"}})
+		assert.Nil(t, client.CreateCR(ctx, name, lvg))
+	}
+	reader := NewLVGReader(client, logger, true)
+	resp, err := reader.ReadLVGs(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, resp, 2)
+}
+
 func TestUnreservedACReader(t *testing.T) {
 	ctx := context.Background()
 	logger := testLogger.WithField("component", "test")
@@ -82,3 +116,26 @@ func TestUnreservedACReader(t *testing.T) {
 	assert.Len(t, resp, 1)
 	assert.Equal(t, *testACs[2], resp[0])
 }
+
+func TestLocationACReader(t *testing.T) {
+	ctx := context.Background()
+	logger := testLogger.WithField("component", "test")
+	client := getKubeClient(t)
+	testACs := []*accrd.AvailableCapacity{
+		getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDDLVG),
+		getTestAC(testNode2, testLargeSize, apiV1.StorageClassHDDLVG),
+		getTestAC(testNode1, testSmallSize, apiV1.StorageClassHDD),
+	}
+	for i, ac := range testACs {
+		ac.Spec.Location = "location-1"
+		if i == 2 {
+			ac.Spec.Location = "location-2"
+		}
+	}
+	createACsInAPi(t, client, testACs)
+	reader := NewLocationACReader(logger, NewACReader(client, logger, true), testNode1, "location-1")
+	resp, err := reader.ReadCapacity(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, *testACs[0], resp[0])
+}
@@ -36,6 +36,9 @@ const (
 
 	// KubeletRootPath is the pods' path on the node
 	KubeletRootPath = "/var/lib/kubelet/pods"
+	// KubeletBlockPublishDir is the part of target path of the block volume, kubelet publishes it to
+	// <kubelet plugins path>/kubernetes.io/csi/volumeDevices/publish/<PV name>/<pod UID>
+	KubeletBlockPublishDir = "/volumeDevices/publish/"
 	// NonRotationalNum points on SSD drive
 	NonRotationalNum = "0"
 
@@ -55,4 +58,32 @@ const (
 	StorageTypeKey = "storageType"
 	// SizeKey key from volume_context in CreateVolumeRequest of NodePublishVolumeRequest
 	SizeKey = "size"
+	// MkfsOptionsKey key from StorageClass parameters with options that are passed to mkfs on file system creation
+	MkfsOptionsKey = "mkfsOptions"
+	// ReadBpsKey key from StorageClass parameters or volume_context with limit of read bytes per second
+	ReadBpsKey = "readBps"
+	// WriteBpsKey key from StorageClass parameters or volume_context with limit of write bytes per second
+	WriteBpsKey = "writeBps"
+	// ReadIopsKey key from StorageClass parameters or volume_context with limit of read operations per second
+	ReadIopsKey = "readIops"
+	// WriteIopsKey key from StorageClass parameters or volume_context with limit of write operations per second
+	WriteIopsKey = "writeIops"
+	// LVTypeKey key from StorageClass parameters with type of the logical volume on LVG: linear, striped or raid1
+	LVTypeKey = "lvType"
+	// StripesKey key from StorageClass parameters with number of stripes of the striped logical volume
+	StripesKey = "stripes"
+	// MirrorsKey key from StorageClass parameters with number of additional copies of the raid1 logical volume
+	MirrorsKey = "mirrors"
+	// EncryptedKey key from StorageClass parameters which enables LUKS encryption of the volume
+	EncryptedKey = "encrypted"
+	// PassphraseKey key of LUKS passphrase in node-stage secrets
+	PassphraseKey = "passphrase"
+	// CacheStorageTypeKey key from StorageClass parameters with storage type of LVG for the cache of the volume
+	CacheStorageTypeKey = "cacheStorageType"
+	// CacheSizeKey key from StorageClass parameters with size of the cache of the volume
+	CacheSizeKey = "cacheSize"
+	// CacheModeKey key from StorageClass parameters with cache mode of the volume: writethrough or writeback
+	CacheModeKey = "cacheMode"
+	// OvercommitRatioKey key from StorageClass parameters with overcommit ratio of the new thin pool LVG
+	OvercommitRatioKey = "overcommitRatio"
 )
@@ -38,6 +38,7 @@ import (
 	nodecrd "github.com/dell/csi-baremetal/api/v1/csibmnodecrd"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 )
 
@@ -211,6 +212,22 @@ func (k *KubeClient) ConstructVolumeCR(name string, apiVolume api.Volume) *volum
 	}
 }
 
+// ConstructSnapshotCR constructs Snapshot custom resource from api.Snapshot struct
+// Receives a name for k8s ObjectMeta and an instance of api.Snapshot struct
+// Returns an instance of Snapshot CR struct
+func (k *KubeClient) ConstructSnapshotCR(name string, apiSnapshot api.Snapshot) *snapshotcrd.Snapshot {
+	return &snapshotcrd.Snapshot{
+		TypeMeta: apisV1.TypeMeta{
+			Kind:       crdV1.SnapshotKind,
+			APIVersion: crdV1.APIV1Version,
+		},
+		ObjectMeta: apisV1.ObjectMeta{
+			Name: name,
+		},
+		Spec: apiSnapshot,
+	}
+}
+
 // ConstructDriveCR constructs Drive custom resource from api.Drive struct
 // Receives a name for k8s ObjectMeta and an instance of api.Drive struct
 // Returns an instance of Drive CR struct
@@ -406,6 +423,10 @@ func PrepareScheme() (*runtime.Scheme, error) {
 	if err := lvgcrd.AddToSchemeLVG(scheme); err != nil {
 		return nil, err
 	}
+	// register snapshot crd
+	if err := snapshotcrd.AddToSchemeSnapshot(scheme); err != nil {
+		return nil, err
+	}
 
 	// register csi node crd
 	if err := nodecrd.AddToSchemeCSIBMNode(scheme); err != nil {
@@ -0,0 +1,87 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package blockdev contains code for preparing block devices for physical removal through sysfs
+package blockdev
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultSysfsRoot is the path where sysfs is mounted
+	DefaultSysfsRoot = "/sys"
+
+	// scsiDeleteFile detaches SCSI device (SAS/SATA drive) from the kernel, cache of the drive is synchronized before
+	scsiDeleteFile = "device/delete"
+	// pciRemoveFile detaches PCI function of NVMe controller from the kernel
+	pciRemoveFile = "device/device/remove"
+)
+
+// WrapBlockDev is an interface that encapsulates operations with block devices through sysfs
+type WrapBlockDev interface {
+	SafeRemove(device string) error
+}
+
+// BlockDev is implementation for WrapBlockDev interface
+type BlockDev struct {
+	root string
+	log  *logrus.Entry
+}
+
+// NewBlockDev is a constructor for BlockDev struct
+func NewBlockDev(logger *logrus.Logger) *BlockDev {
+	return &BlockDev{
+		root: DefaultSysfsRoot,
+		log:  logger.WithField("component", "BlockDev"),
+	}
+}
+
+// SafeRemove detaches the drive from the kernel, so it could be pulled out without data loss
+// Receives path of the block device, e.g. /dev/sda
+// Returns error if device doesn't support removal or wasn't detached,
+// device which is already detached or isn't attached to any bus (loop device) isn't an error
+func (b *BlockDev) SafeRemove(device string) error {
+	ll := b.log.WithField("method", "SafeRemove")
+
+	blockPath := filepath.Join(b.root, "block", filepath.Base(device))
+	if _, err := os.Stat(blockPath); os.IsNotExist(err) {
+		ll.Infof("Device %s is already detached", device)
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(blockPath, "device")); os.IsNotExist(err) {
+		ll.Infof("Device %s isn't attached to any bus, nothing to detach", device)
+		return nil
+	}
+
+	for _, file := range []string{scsiDeleteFile, pciRemoveFile} {
+		path := filepath.Join(blockPath, file)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		ll.Infof("Detaching device %s through %s", device, path)
+		if err := ioutil.WriteFile(path, []byte("1"), 0200); err != nil {
+			return fmt.Errorf("unable to detach device %s: %v", device, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("device %s doesn't support safe removal", device)
+}
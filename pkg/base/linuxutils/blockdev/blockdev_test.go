@@ -0,0 +1,75 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blockdev
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockDev_SafeRemove(t *testing.T) {
+	b, root := setupBlockDevTest(t)
+
+	// SCSI drive
+	deleteFile := filepath.Join(root, "block/sda", scsiDeleteFile)
+	createFile(t, deleteFile)
+	assert.Nil(t, b.SafeRemove("/dev/sda"))
+	assert.Equal(t, "1", readFile(t, deleteFile))
+
+	// NVMe drive
+	removeFile := filepath.Join(root, "block/nvme0n1", pciRemoveFile)
+	createFile(t, removeFile)
+	assert.Nil(t, b.SafeRemove("/dev/nvme0n1"))
+	assert.Equal(t, "1", readFile(t, removeFile))
+
+	// device is already detached
+	assert.Nil(t, b.SafeRemove("/dev/sdb"))
+
+	// loop device isn't attached to any bus
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "block/loop0"), 0755))
+	assert.Nil(t, b.SafeRemove("/dev/loop0"))
+
+	// device doesn't support removal
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, "block/vda/device"), 0755))
+	assert.NotNil(t, b.SafeRemove("/dev/vda"))
+}
+
+func setupBlockDevTest(t *testing.T) (*BlockDev, string) {
+	root, err := ioutil.TempDir("", "sysfs")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(root) })
+
+	b := NewBlockDev(logrus.New())
+	b.root = root
+	return b, root
+}
+
+func createFile(t *testing.T, path string) {
+	assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.Nil(t, ioutil.WriteFile(path, nil, 0644))
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	return string(data)
+}
@@ -0,0 +1,240 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cgroup contains code for managing I/O limits of the pod in cgroup blkio (v1) or io (v2) controller
+package cgroup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCgroupRoot is the path where cgroup file systems are mounted
+	DefaultCgroupRoot = "/sys/fs/cgroup"
+	// DefaultSysfsRoot is the path where sysfs is mounted
+	DefaultSysfsRoot = "/sys"
+	// sysfsDevBlock is the directory of sysfs with links to block devices by major:minor
+	sysfsDevBlock = "dev/block"
+	// cgroupV2ControllersFile exists only in the root of cgroup v2 unified hierarchy
+	cgroupV2ControllersFile = "cgroup.controllers"
+	// blkioController is the name of cgroup v1 controller which throttles I/O
+	blkioController = "blkio"
+
+	// ReadBpsFile cgroup v1 file for read bytes per second limit
+	ReadBpsFile = "blkio.throttle.read_bps_device"
+	// WriteBpsFile cgroup v1 file for write bytes per second limit
+	WriteBpsFile = "blkio.throttle.write_bps_device"
+	// ReadIopsFile cgroup v1 file for read operations per second limit
+	ReadIopsFile = "blkio.throttle.read_iops_device"
+	// WriteIopsFile cgroup v1 file for write operations per second limit
+	WriteIopsFile = "blkio.throttle.write_iops_device"
+	// IOMaxFile cgroup v2 file for all I/O limits
+	IOMaxFile = "io.max"
+	// ioMaxTmpl is the line of io.max file, add device major:minor and limits
+	ioMaxTmpl = "%s rbps=%s wbps=%s riops=%s wiops=%s"
+	// ioMaxUnlimited is the io.max value which means no limit
+	ioMaxUnlimited = "max"
+)
+
+// podCgroupPatterns are the paths of pod cgroup relative to the controller root for cgroupfs and systemd drivers,
+// add pod UID (systemd driver replaces "-" with "_" in pod UID)
+var podCgroupPatterns = []string{
+	"kubepods/pod%s",
+	"kubepods/*/pod%s",
+	"kubepods.slice/kubepods-pod%s.slice",
+	"kubepods.slice/*/kubepods-*-pod%s.slice",
+}
+
+// IOLimits holds I/O limits for the block device, zero value means that there is no limit
+type IOLimits struct {
+	ReadBps   int64
+	WriteBps  int64
+	ReadIops  int64
+	WriteIops int64
+}
+
+// IsEmpty returns true if none of the limits is set
+func (l IOLimits) IsEmpty() bool {
+	return l == IOLimits{}
+}
+
+// WrapCgroup is an interface that encapsulates operations with I/O limits of the pod cgroup
+type WrapCgroup interface {
+	SetIOLimits(podUID, device string, limits IOLimits) error
+	ClearIOLimits(podUID, device string) error
+}
+
+// Cgroup is implementation for WrapCgroup interface
+type Cgroup struct {
+	root      string
+	sysfsRoot string
+	log       *logrus.Entry
+}
+
+// NewCgroup is a constructor for Cgroup struct
+func NewCgroup(logger *logrus.Logger) *Cgroup {
+	return &Cgroup{
+		root:      DefaultCgroupRoot,
+		sysfsRoot: DefaultSysfsRoot,
+		log:       logger.WithField("component", "Cgroup"),
+	}
+}
+
+// SetIOLimits writes I/O limits for the device into the cgroup of the pod
+// Receives UID of the pod, path of the block device and limits
+// Returns error if pod cgroup wasn't found or limits weren't written
+func (c *Cgroup) SetIOLimits(podUID, device string, limits IOLimits) error {
+	devNum, err := c.deviceNumber(device)
+	if err != nil {
+		return err
+	}
+	podPath, err := c.findPodCgroup(podUID)
+	if err != nil {
+		return err
+	}
+	if podPath == "" {
+		return fmt.Errorf("cgroup of pod %s isn't found", podUID)
+	}
+
+	c.log.WithField("method", "SetIOLimits").
+		Infof("Setting I/O limits %+v for device %s (%s) in %s", limits, device, devNum, podPath)
+	return c.writeLimits(podPath, devNum, limits)
+}
+
+// ClearIOLimits removes I/O limits for the device from the cgroup of the pod
+// Receives UID of the pod and path of the block device
+// Returns error if limits weren't removed, missing pod cgroup isn't an error because pod could be already removed
+func (c *Cgroup) ClearIOLimits(podUID, device string) error {
+	devNum, err := c.deviceNumber(device)
+	if err != nil {
+		return err
+	}
+	podPath, err := c.findPodCgroup(podUID)
+	if err != nil || podPath == "" {
+		return err
+	}
+
+	c.log.WithField("method", "ClearIOLimits").Infof("Clearing I/O limits for device %s in %s", devNum, podPath)
+	return c.writeLimits(podPath, devNum, IOLimits{})
+}
+
+// isV2 returns true if cgroup v2 unified hierarchy is mounted to the root
+func (c *Cgroup) isV2() bool {
+	_, err := os.Stat(filepath.Join(c.root, cgroupV2ControllersFile))
+	return err == nil
+}
+
+// findPodCgroup returns path of the pod cgroup in I/O controller hierarchy or empty string if it wasn't found
+func (c *Cgroup) findPodCgroup(podUID string) (string, error) {
+	controllerRoot := c.root
+	if !c.isV2() {
+		controllerRoot = filepath.Join(c.root, blkioController)
+	}
+
+	for _, uid := range []string{podUID, strings.ReplaceAll(podUID, "-", "_")} {
+		for _, pattern := range podCgroupPatterns {
+			matches, err := filepath.Glob(filepath.Join(controllerRoot, fmt.Sprintf(pattern, uid)))
+			if err != nil {
+				return "", err
+			}
+			if len(matches) > 0 {
+				return matches[0], nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// writeLimits writes limits for the device number to the cgroup v1 blkio files or cgroup v2 io.max file,
+// zero limit removes the rule in cgroup v1 and is written as "max" in cgroup v2
+func (c *Cgroup) writeLimits(podPath, devNum string, limits IOLimits) error {
+	if c.isV2() {
+		ioMaxValue := func(v int64) string {
+			if v == 0 {
+				return ioMaxUnlimited
+			}
+			return strconv.FormatInt(v, 10)
+		}
+		line := fmt.Sprintf(ioMaxTmpl, devNum, ioMaxValue(limits.ReadBps), ioMaxValue(limits.WriteBps),
+			ioMaxValue(limits.ReadIops), ioMaxValue(limits.WriteIops))
+		return writeFile(filepath.Join(podPath, IOMaxFile), line)
+	}
+
+	for file, value := range map[string]int64{
+		ReadBpsFile:   limits.ReadBps,
+		WriteBpsFile:  limits.WriteBps,
+		ReadIopsFile:  limits.ReadIops,
+		WriteIopsFile: limits.WriteIops,
+	} {
+		if err := writeFile(filepath.Join(podPath, file), fmt.Sprintf("%s %d", devNum, value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path, value string) error {
+	if err := ioutil.WriteFile(path, []byte(value), 0644); err != nil {
+		return fmt.Errorf("unable to write %q to %s: %v", value, path, err)
+	}
+	return nil
+}
+
+// deviceNumber returns major:minor of the block device, symlinks (e.g. /dev/<vg>/<lv>) are followed.
+// Kernel throttles I/O only for whole disks, so major:minor of the parent disk is returned for partition
+func (c *Cgroup) deviceNumber(device string) (string, error) {
+	var st syscall.Stat_t
+	if err := syscall.Stat(device, &st); err != nil {
+		return "", fmt.Errorf("unable to stat device %s: %v", device, err)
+	}
+	if st.Mode&syscall.S_IFMT != syscall.S_IFBLK && st.Mode&syscall.S_IFMT != syscall.S_IFCHR {
+		return "", fmt.Errorf("%s isn't a device", device)
+	}
+
+	dev := st.Rdev
+	major := ((dev >> 8) & 0xfff) | ((dev >> 32) & 0xfffff000)
+	minor := (dev & 0xff) | ((dev >> 12) & 0xffffff00)
+	return c.diskNumber(fmt.Sprintf("%d:%d", major, minor))
+}
+
+// diskNumber returns major:minor of the parent disk if devNum is a partition, otherwise returns devNum as is
+func (c *Cgroup) diskNumber(devNum string) (string, error) {
+	devPath, err := filepath.EvalSymlinks(filepath.Join(c.sysfsRoot, sysfsDevBlock, devNum))
+	if err != nil {
+		if os.IsNotExist(err) {
+			// not a block device
+			return devNum, nil
+		}
+		return "", fmt.Errorf("unable to resolve device %s in sysfs: %v", devNum, err)
+	}
+	if _, err = os.Stat(filepath.Join(devPath, "partition")); err != nil {
+		return devNum, nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(filepath.Dir(devPath), "dev"))
+	if err != nil {
+		return "", fmt.Errorf("unable to read parent disk of partition %s: %v", devNum, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
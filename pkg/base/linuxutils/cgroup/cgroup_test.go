@@ -0,0 +1,125 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testPodUID = "5e1ef0c3-a3bf-4a4f-8a0e-3a2b2c1d0e9f"
+	// /dev/null is used as a device, it's present on any linux system
+	testDevice    = "/dev/null"
+	testDeviceNum = "1:3"
+	testLimits    = IOLimits{ReadBps: 1048576, WriteIops: 100}
+)
+
+func TestCgroup_V1(t *testing.T) {
+	c, root := setupCgroupTest(t)
+	podPath := filepath.Join(root, blkioController, "kubepods/burstable/pod"+testPodUID)
+	assert.Nil(t, os.MkdirAll(podPath, 0755))
+
+	assert.Nil(t, c.SetIOLimits(testPodUID, testDevice, testLimits))
+	assert.Equal(t, testDeviceNum+" 1048576", readFile(t, filepath.Join(podPath, ReadBpsFile)))
+	assert.Equal(t, testDeviceNum+" 0", readFile(t, filepath.Join(podPath, WriteBpsFile)))
+	assert.Equal(t, testDeviceNum+" 100", readFile(t, filepath.Join(podPath, WriteIopsFile)))
+
+	assert.Nil(t, c.ClearIOLimits(testPodUID, testDevice))
+	assert.Equal(t, testDeviceNum+" 0", readFile(t, filepath.Join(podPath, ReadBpsFile)))
+	assert.Equal(t, testDeviceNum+" 0", readFile(t, filepath.Join(podPath, WriteIopsFile)))
+}
+
+func TestCgroup_V2Systemd(t *testing.T) {
+	c, root := setupCgroupTest(t)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(root, cgroupV2ControllersFile), []byte("io"), 0644))
+	podPath := filepath.Join(root, "kubepods.slice/kubepods-besteffort.slice",
+		"kubepods-besteffort-pod5e1ef0c3_a3bf_4a4f_8a0e_3a2b2c1d0e9f.slice")
+	assert.Nil(t, os.MkdirAll(podPath, 0755))
+
+	assert.Nil(t, c.SetIOLimits(testPodUID, testDevice, testLimits))
+	assert.Equal(t, testDeviceNum+" rbps=1048576 wbps=max riops=max wiops=100",
+		readFile(t, filepath.Join(podPath, IOMaxFile)))
+
+	assert.Nil(t, c.ClearIOLimits(testPodUID, testDevice))
+	assert.Equal(t, testDeviceNum+" rbps=max wbps=max riops=max wiops=max",
+		readFile(t, filepath.Join(podPath, IOMaxFile)))
+}
+
+func TestCgroup_Partition(t *testing.T) {
+	c, root := setupCgroupTest(t)
+	podPath := filepath.Join(root, blkioController, "kubepods/pod"+testPodUID)
+	assert.Nil(t, os.MkdirAll(podPath, 0755))
+
+	// test device is a partition of the disk 1:0 in sysfs
+	diskPath := filepath.Join(c.sysfsRoot, "devices/virtual/block/disk")
+	partPath := filepath.Join(diskPath, "disk1")
+	assert.Nil(t, os.MkdirAll(partPath, 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(diskPath, "dev"), []byte("1:0\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(partPath, "dev"), []byte(testDeviceNum+"\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(partPath, "partition"), []byte("1\n"), 0644))
+	assert.Nil(t, os.MkdirAll(filepath.Join(c.sysfsRoot, sysfsDevBlock), 0755))
+	assert.Nil(t, os.Symlink(partPath, filepath.Join(c.sysfsRoot, sysfsDevBlock, testDeviceNum)))
+
+	assert.Nil(t, c.SetIOLimits(testPodUID, testDevice, testLimits))
+	assert.Equal(t, "1:0 1048576", readFile(t, filepath.Join(podPath, ReadBpsFile)))
+	assert.Equal(t, "1:0 100", readFile(t, filepath.Join(podPath, WriteIopsFile)))
+
+	// parent disk isn't readable
+	assert.Nil(t, os.Remove(filepath.Join(diskPath, "dev")))
+	assert.NotNil(t, c.ClearIOLimits(testPodUID, testDevice))
+}
+
+func TestCgroup_Fail(t *testing.T) {
+	c, _ := setupCgroupTest(t)
+
+	// pod cgroup doesn't exist
+	assert.NotNil(t, c.SetIOLimits(testPodUID, testDevice, testLimits))
+	assert.Nil(t, c.ClearIOLimits(testPodUID, testDevice))
+
+	// device doesn't exist
+	assert.NotNil(t, c.SetIOLimits(testPodUID, "/dev/not-existing-device", testLimits))
+	// not a device
+	assert.NotNil(t, c.ClearIOLimits(testPodUID, os.TempDir()))
+}
+
+func TestIOLimits_IsEmpty(t *testing.T) {
+	assert.True(t, IOLimits{}.IsEmpty())
+	assert.False(t, testLimits.IsEmpty())
+}
+
+func setupCgroupTest(t *testing.T) (*Cgroup, string) {
+	root, err := ioutil.TempDir("", "cgroup")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(root) })
+
+	c := NewCgroup(logrus.New())
+	c.root = root
+	c.sysfsRoot = filepath.Join(root, "sys")
+	return c, root
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	return string(data)
+}
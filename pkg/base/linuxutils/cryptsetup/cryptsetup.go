@@ -0,0 +1,138 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cryptsetup contains code for running system cryptsetup util
+// which is used for managing of dm-crypt mappings of LUKS encrypted devices
+package cryptsetup
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base/command"
+)
+
+const (
+	// LuksFormatCmdTmpl formats device with LUKS, passphrase is read from stdin
+	LuksFormatCmdTmpl = "cryptsetup luksFormat --batch-mode --type luks2 --key-file - %s" // add device
+	// LuksOpenCmdTmpl opens LUKS device as dm-crypt mapping, passphrase is read from stdin.
+	// Volume key is kept in dm-crypt table instead of kernel keyring, so the mapping can be resized without passphrase
+	LuksOpenCmdTmpl = "cryptsetup luksOpen --disable-keyring --key-file - %s %s" // add device and mapping name
+	// LuksCloseCmdTmpl removes dm-crypt mapping
+	LuksCloseCmdTmpl = "cryptsetup luksClose %s" // add mapping name
+	// LuksResizeCmdTmpl resizes active dm-crypt mapping up to the size of underlying device
+	LuksResizeCmdTmpl = "cryptsetup resize %s" // add mapping name
+	// LuksEraseCmdTmpl wipes all key slots of LUKS device, data can't be decrypted after that
+	LuksEraseCmdTmpl = "cryptsetup luksErase --batch-mode %s" // add device
+	// StatusCmdTmpl shows status of dm-crypt mapping, exits with error if mapping isn't active
+	StatusCmdTmpl = "cryptsetup status %s" // add mapping name
+	// IsLuksCmdTmpl exits with error if device isn't formatted with LUKS
+	IsLuksCmdTmpl = "cryptsetup isLuks %s" // add device
+
+	// LuksSignature is a type of LUKS device signature which is reported by wipefs
+	LuksSignature = "crypto_LUKS"
+
+	// MapperDir is a directory where device mapper creates device files of the mappings
+	MapperDir = "/dev/mapper"
+)
+
+// WrapCryptsetup is an interface that encapsulates operation with system cryptsetup util
+type WrapCryptsetup interface {
+	LuksFormat(device, passphrase string) error
+	LuksOpen(device, name, passphrase string) error
+	LuksClose(name string) error
+	LuksResize(name string) error
+	LuksErase(device string) error
+	IsOpened(name string) bool
+	IsLuks(device string) bool
+}
+
+// Cryptsetup is a wrap for system cryptsetup util
+type Cryptsetup struct {
+	e   command.CmdExecutor
+	log *logrus.Entry
+}
+
+// NewCryptsetup is a constructor for Cryptsetup
+func NewCryptsetup(e command.CmdExecutor, logger *logrus.Logger) *Cryptsetup {
+	return &Cryptsetup{
+		e:   e,
+		log: logger.WithField("component", "Cryptsetup"),
+	}
+}
+
+// GetMappingPath returns full path of the named dm-crypt mapping: /dev/mapper/NAME
+func GetMappingPath(name string) string {
+	return filepath.Join(MapperDir, name)
+}
+
+// LuksFormat formats device with LUKS using passphrase, all data on the device is lost
+func (c *Cryptsetup) LuksFormat(device, passphrase string) error {
+	return c.run("LuksFormat", fmt.Sprintf(LuksFormatCmdTmpl, device), passphrase)
+}
+
+// LuksOpen opens LUKS device using passphrase, decrypted device is available by GetMappingPath(name)
+func (c *Cryptsetup) LuksOpen(device, name, passphrase string) error {
+	return c.run("LuksOpen", fmt.Sprintf(LuksOpenCmdTmpl, device, name), passphrase)
+}
+
+// LuksClose removes named dm-crypt mapping, device has to be unmounted before
+func (c *Cryptsetup) LuksClose(name string) error {
+	return c.run("LuksClose", fmt.Sprintf(LuksCloseCmdTmpl, name), "")
+}
+
+// LuksResize grows named dm-crypt mapping after its underlying device was expanded,
+// passphrase isn't required because the mapping is opened by LuksOpen with volume key out of kernel keyring
+func (c *Cryptsetup) LuksResize(name string) error {
+	return c.run("LuksResize", fmt.Sprintf(LuksResizeCmdTmpl, name), "")
+}
+
+// LuksErase destroys all key slots of LUKS device, so the data can't be decrypted anymore
+func (c *Cryptsetup) LuksErase(device string) error {
+	return c.run("LuksErase", fmt.Sprintf(LuksEraseCmdTmpl, device), "")
+}
+
+// IsOpened returns true if named dm-crypt mapping is active
+func (c *Cryptsetup) IsOpened(name string) bool {
+	_, _, err := c.e.RunCmd(fmt.Sprintf(StatusCmdTmpl, name))
+	return err == nil
+}
+
+// IsLuks returns true if device is formatted with LUKS
+func (c *Cryptsetup) IsLuks(device string) bool {
+	_, _, err := c.e.RunCmd(fmt.Sprintf(IsLuksCmdTmpl, device))
+	return err == nil
+}
+
+// run runs cryptsetup command, passphrase is passed through stdin to keep it out of the process arguments and logs
+func (c *Cryptsetup) run(method, cmd, passphrase string) error {
+	var cmdToRun interface{} = cmd
+	if passphrase != "" {
+		fields := strings.Fields(cmd)
+		cmdObj := exec.Command(fields[0], fields[1:]...)
+		cmdObj.Stdin = strings.NewReader(passphrase)
+		cmdToRun = cmdObj
+	}
+	if _, stderr, err := c.e.RunCmd(cmdToRun); err != nil {
+		c.log.WithField("method", method).Errorf("%s failed, stderr: %s, error: %v", cmd, stderr, err)
+		return err
+	}
+	return nil
+}
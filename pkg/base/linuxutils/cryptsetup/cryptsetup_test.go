@@ -0,0 +1,131 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cryptsetup
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+var (
+	testLogger = logrus.New()
+	testErr    = errors.New("error")
+)
+
+const (
+	testDevice     = "/dev/sdb1"
+	testName       = "luks-volume"
+	testPassphrase = "secret"
+)
+
+func TestCryptsetup_LuksFormat(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	c := NewCryptsetup(e, testLogger)
+
+	cmd := fmt.Sprintf(LuksFormatCmdTmpl, testDevice)
+	assert.NotContains(t, cmd, testPassphrase)
+	e.OnCommand(cmd).Return("", "", nil).Once()
+	assert.Nil(t, c.LuksFormat(testDevice, testPassphrase))
+
+	e.OnCommand(cmd).Return("", "error", testErr).Once()
+	assert.Equal(t, testErr, c.LuksFormat(testDevice, testPassphrase))
+}
+
+func TestCryptsetup_LuksOpen(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	c := NewCryptsetup(e, testLogger)
+
+	cmd := fmt.Sprintf(LuksOpenCmdTmpl, testDevice, testName)
+	// volume key has to be kept out of kernel keyring to resize the mapping without passphrase
+	assert.Contains(t, cmd, "--disable-keyring")
+	e.OnCommand(cmd).Return("", "", nil).Once()
+	assert.Nil(t, c.LuksOpen(testDevice, testName, testPassphrase))
+
+	e.OnCommand(cmd).Return("", "No key available with this passphrase.", testErr).Once()
+	assert.Equal(t, testErr, c.LuksOpen(testDevice, testName, testPassphrase))
+}
+
+func TestCryptsetup_LuksClose(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	c := NewCryptsetup(e, testLogger)
+
+	cmd := fmt.Sprintf(LuksCloseCmdTmpl, testName)
+	e.OnCommand(cmd).Return("", "", nil).Once()
+	assert.Nil(t, c.LuksClose(testName))
+
+	e.OnCommand(cmd).Return("", "error", testErr).Once()
+	assert.Equal(t, testErr, c.LuksClose(testName))
+}
+
+func TestCryptsetup_LuksResize(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	c := NewCryptsetup(e, testLogger)
+
+	cmd := fmt.Sprintf(LuksResizeCmdTmpl, testName)
+	assert.NotContains(t, cmd, "--key-file")
+	e.OnCommand(cmd).Return("", "", nil).Once()
+	assert.Nil(t, c.LuksResize(testName))
+
+	e.OnCommand(cmd).Return("", "No known cipher specification pattern detected.", testErr).Once()
+	assert.Equal(t, testErr, c.LuksResize(testName))
+}
+
+func TestCryptsetup_LuksErase(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	c := NewCryptsetup(e, testLogger)
+
+	cmd := fmt.Sprintf(LuksEraseCmdTmpl, testDevice)
+	e.OnCommand(cmd).Return("", "", nil).Once()
+	assert.Nil(t, c.LuksErase(testDevice))
+
+	e.OnCommand(cmd).Return("", "error", testErr).Once()
+	assert.Equal(t, testErr, c.LuksErase(testDevice))
+}
+
+func TestCryptsetup_IsOpened(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	c := NewCryptsetup(e, testLogger)
+
+	cmd := fmt.Sprintf(StatusCmdTmpl, testName)
+	e.OnCommand(cmd).Return("/dev/mapper/luks-volume is active.", "", nil).Once()
+	assert.True(t, c.IsOpened(testName))
+
+	e.OnCommand(cmd).Return("/dev/mapper/luks-volume is inactive.", "", testErr).Once()
+	assert.False(t, c.IsOpened(testName))
+}
+
+func TestCryptsetup_IsLuks(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	c := NewCryptsetup(e, testLogger)
+
+	cmd := fmt.Sprintf(IsLuksCmdTmpl, testDevice)
+	e.OnCommand(cmd).Return("", "", nil).Once()
+	assert.True(t, c.IsLuks(testDevice))
+
+	e.OnCommand(cmd).Return("", "", testErr).Once()
+	assert.False(t, c.IsLuks(testDevice))
+}
+
+func TestGetMappingPath(t *testing.T) {
+	assert.Equal(t, "/dev/mapper/luks-volume", GetMappingPath(testName))
+}
@@ -19,8 +19,10 @@ package fs
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/dell/csi-baremetal/pkg/base/command"
@@ -37,17 +39,37 @@ const (
 	EXT4 FileSystem = "ext4"
 	// EXT3 file system
 	EXT3 FileSystem = "ext3"
+	// BTRFS file system
+	BTRFS FileSystem = "btrfs"
 
 	// wipefs is a system utility
 	wipefs = "wipefs "
 	// CheckSpaceCmdImpl cmd for getting space on the mounted FS, produce output in megabytes (--block-size=M)
 	CheckSpaceCmdImpl = "df %s --output=target,avail --block-size=M" // add mounted fs part
+	// GetDeviceSizeCmdTmpl cmd for getting size of the block device in bytes
+	GetDeviceSizeCmdTmpl = "blockdev --getsize64 %s" // add device
 	// MkFSCmdTmpl mkfs command template
 	MkFSCmdTmpl = "mkfs.%s %s" // add fs type and device/path
 	// SpeedUpFsCreationOpts options that could be used for speeds up creation of ext3 and ext4 FS
 	SpeedUpFsCreationOpts = " -E lazy_journal_init=1,lazy_itable_init=1,discard"
+	// BtrfsCreationOpts options for btrfs FS creation, force overwrite of the device with existing FS signature
+	BtrfsCreationOpts = " -f"
+	// ResizeExtFSCmdTmpl cmd for growing ext3 and ext4 FS up to the size of the device
+	ResizeExtFSCmdTmpl = "resize2fs %s" // add device
+	// GrowXFSCmdTmpl cmd for growing xfs FS up to the size of the device, xfs could be grown only being mounted
+	GrowXFSCmdTmpl = "xfs_growfs %s" // add mount point
+	// GrowBtrfsCmdTmpl cmd for growing btrfs FS up to the size of the device, btrfs could be grown only being mounted
+	GrowBtrfsCmdTmpl = "btrfs filesystem resize max %s" // add mount point
+	// CopyDeviceCmdTmpl cmd for copying the whole content of one block device to another
+	CopyDeviceCmdTmpl = "dd if=%s of=%s bs=4M conv=fsync" // add source and destination devices
+	// RegenerateXFSUUIDCmdTmpl cmd for generating new UUID for xfs FS, xfs refuses to mount FS with duplicated UUID
+	RegenerateXFSUUIDCmdTmpl = "xfs_admin -U generate %s" // add device
+	// RegenerateBtrfsUUIDCmdTmpl cmd for generating new UUID for btrfs FS, btrfs confuses devices with duplicated UUID
+	RegenerateBtrfsUUIDCmdTmpl = "btrfstune -f -u %s" // add device
 	// MkDirCmdTmpl mkdir template
 	MkDirCmdTmpl = "mkdir -p %s"
+	// MkFileCmdTmpl touch template
+	MkFileCmdTmpl = "touch %s"
 	// RmDirCmdTmpl rm template
 	RmDirCmdTmpl = "rm -rf %s"
 	// WipeFSCmdTmpl cmd for wiping FS on device
@@ -64,14 +86,34 @@ const (
 	UnmountCmdTmpl = "umount %s"
 	// BindOption option for mount operation
 	BindOption = "--bind"
+	// RemountOption option for changing mount options of the already mounted FS, e.g. for making bind mount read-only
+	RemountOption = "remount"
+	// ReadOnlyOption option for read-only mount
+	ReadOnlyOption = "ro"
 )
 
+// FSStats contains capacity and inodes usage of the mounted file system
+type FSStats struct {
+	TotalBytes     int64
+	AvailableBytes int64
+	UsedBytes      int64
+	TotalInodes    int64
+	FreeInodes     int64
+	UsedInodes     int64
+}
+
 // WrapFS is an interface that encapsulates operation with file systems
 type WrapFS interface {
 	GetFSSpace(src string) (int64, error)
+	GetFSStats(path string) (*FSStats, error)
+	GetDeviceSize(device string) (int64, error)
 	MkDir(src string) error
+	MkFile(src string) error
 	RmDir(src string) error
-	CreateFS(fsType FileSystem, device string) error
+	CreateFS(fsType FileSystem, device string, opts ...string) error
+	GrowFS(fsType FileSystem, device, mountPoint string) error
+	CopyDevice(src, dst string) error
+	RegenerateFSUUID(fsType FileSystem, device string) error
 	WipeFS(device string) error
 	GetFSType(device string) (FileSystem, error)
 	// Mount operations
@@ -125,6 +167,40 @@ func (h *WrapFSImpl) GetFSSpace(src string) (int64, error) {
 	return 0, fmt.Errorf("wrong df output %s", stdout)
 }
 
+// GetFSStats calls statfs for the provided path and returns capacity and inodes usage of the file system
+// Receives path on the mounted file system
+// Returns FSStats or error if something went wrong
+func (h *WrapFSImpl) GetFSStats(path string) (*FSStats, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return nil, fmt.Errorf("failed to get stats for %s: %v", path, err)
+	}
+
+	return &FSStats{
+		TotalBytes:     int64(st.Blocks) * st.Bsize,
+		AvailableBytes: int64(st.Bavail) * st.Bsize,
+		UsedBytes:      (int64(st.Blocks) - int64(st.Bfree)) * st.Bsize,
+		TotalInodes:    int64(st.Files),
+		FreeInodes:     int64(st.Ffree),
+		UsedInodes:     int64(st.Files) - int64(st.Ffree),
+	}, nil
+}
+
+// GetDeviceSize calls blockdev command and returns size of the provided block device
+// Receives path of the device
+// Returns size in bytes or error if something went wrong
+func (h *WrapFSImpl) GetDeviceSize(device string) (int64, error) {
+	stdout, _, err := h.e.RunCmd(fmt.Sprintf(GetDeviceSizeCmdTmpl, device))
+	if err != nil {
+		return 0, err
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse size of device %s from %s: %v", device, stdout, err)
+	}
+	return size, nil
+}
+
 // MkDir creates specified path using mkdir if it doesn't exist
 // Receives directory path to create as a string
 // Returns error if something went wrong
@@ -137,6 +213,18 @@ func (h *WrapFSImpl) MkDir(src string) error {
 	return nil
 }
 
+// MkFile creates file on specified path using touch
+// Receives file path to create as a string
+// Returns error if something went wrong
+func (h *WrapFSImpl) MkFile(src string) error {
+	cmd := fmt.Sprintf(MkFileCmdTmpl, src)
+
+	if _, _, err := h.e.RunCmd(cmd); err != nil {
+		return fmt.Errorf("failed to create file %s: %v", src, err)
+	}
+	return nil
+}
+
 // RmDir removes specified path using rm
 // Receives directory of file path to delete as a string
 // Returns error if something went wrong
@@ -150,18 +238,24 @@ func (h *WrapFSImpl) RmDir(src string) error {
 }
 
 // CreateFS creates specified file system on the provided device using mkfs
-// Receives file system as a var of FileSystem type and path of the device as a string
+// Receives file system as a var of FileSystem type, path of the device as a string
+// and optional mkfs options which are appended to the command
 // Returns error if something went wrong
-func (h *WrapFSImpl) CreateFS(fsType FileSystem, device string) error {
+func (h *WrapFSImpl) CreateFS(fsType FileSystem, device string, opts ...string) error {
 	var cmd string
 	switch fsType {
 	case XFS:
 		cmd = fmt.Sprintf(MkFSCmdTmpl, fsType, device)
 	case EXT3, EXT4:
 		cmd = fmt.Sprintf(MkFSCmdTmpl, fsType, device) + SpeedUpFsCreationOpts
+	case BTRFS:
+		cmd = fmt.Sprintf(MkFSCmdTmpl, fsType, device) + BtrfsCreationOpts
 	default:
 		return fmt.Errorf("unsupported file system %v", fsType)
 	}
+	if len(opts) > 0 {
+		cmd += " " + strings.Join(opts, " ")
+	}
 
 	if _, _, err := h.e.RunCmd(cmd); err != nil {
 		return fmt.Errorf("failed to create file system on %s: %v", device, err)
@@ -169,6 +263,62 @@ func (h *WrapFSImpl) CreateFS(fsType FileSystem, device string) error {
 	return nil
 }
 
+// GrowFS grows file system on the provided device up to the size of the device
+// ext3 and ext4 are grown using resize2fs, xfs and btrfs are grown being mounted to mountPoint
+// Receives file system as a var of FileSystem type, path of the device and mount point as a strings
+// Returns error if something went wrong
+func (h *WrapFSImpl) GrowFS(fsType FileSystem, device, mountPoint string) error {
+	var cmd string
+	switch fsType {
+	case XFS:
+		cmd = fmt.Sprintf(GrowXFSCmdTmpl, mountPoint)
+	case BTRFS:
+		cmd = fmt.Sprintf(GrowBtrfsCmdTmpl, mountPoint)
+	case EXT3, EXT4:
+		cmd = fmt.Sprintf(ResizeExtFSCmdTmpl, device)
+	default:
+		return fmt.Errorf("unsupported file system %v", fsType)
+	}
+
+	if _, _, err := h.e.RunCmd(cmd); err != nil {
+		return fmt.Errorf("failed to grow file system on %s: %v", device, err)
+	}
+	return nil
+}
+
+// CopyDevice copies the whole content of the src block device to the dst block device using dd
+// Receives file paths of the source and destination devices as a strings
+// Returns error if something went wrong
+func (h *WrapFSImpl) CopyDevice(src, dst string) error {
+	cmd := fmt.Sprintf(CopyDeviceCmdTmpl, src, dst)
+
+	if _, _, err := h.e.RunCmd(cmd); err != nil {
+		return fmt.Errorf("failed to copy device %s to %s: %v", src, dst, err)
+	}
+	return nil
+}
+
+// RegenerateFSUUID generates new UUID for file system on the device which was copied from another device.
+// Only xfs and btrfs require unique UUID, for other file systems method does nothing
+// Receives file system type and file path of the device as a string
+// Returns error if something went wrong
+func (h *WrapFSImpl) RegenerateFSUUID(fsType FileSystem, device string) error {
+	var cmd string
+	switch fsType {
+	case XFS:
+		cmd = fmt.Sprintf(RegenerateXFSUUIDCmdTmpl, device)
+	case BTRFS:
+		cmd = fmt.Sprintf(RegenerateBtrfsUUIDCmdTmpl, device)
+	default:
+		return nil
+	}
+
+	if _, _, err := h.e.RunCmd(cmd); err != nil {
+		return fmt.Errorf("failed to regenerate UUID of file system on %s: %v", device, err)
+	}
+	return nil
+}
+
 // WipeFS deletes file system from the provided device using wipefs
 // Receives file path of the device as a string
 // Returns error if something went wrong
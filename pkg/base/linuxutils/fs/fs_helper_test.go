@@ -106,6 +106,42 @@ func TestGetFSSpace_Success(t *testing.T) {
 	assert.Equal(t, expectedRes, freeBytes)
 }
 
+func TestGetFSStats(t *testing.T) {
+	fh := NewFSImpl(&mocks.GoMockExecutor{})
+
+	stats, err := fh.GetFSStats("/")
+	assert.Nil(t, err)
+	assert.True(t, stats.TotalBytes > 0)
+	assert.Equal(t, stats.TotalInodes-stats.FreeInodes, stats.UsedInodes)
+
+	_, err = fh.GetFSStats("/some/not/existing/path")
+	assert.NotNil(t, err)
+}
+
+func TestGetDeviceSize(t *testing.T) {
+	var (
+		e      = &mocks.GoMockExecutor{}
+		fh     = NewFSImpl(e)
+		device = "/dev/sda"
+		cmd    = fmt.Sprintf(GetDeviceSizeCmdTmpl, device)
+	)
+
+	e.OnCommand(cmd).Return("1073741824\n", "", nil).Times(1)
+	size, err := fh.GetDeviceSize(device)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1073741824), size)
+
+	// wrong output
+	e.OnCommand(cmd).Return("size", "", nil).Times(1)
+	_, err = fh.GetDeviceSize(device)
+	assert.NotNil(t, err)
+
+	// cmd failed
+	e.OnCommand(cmd).Return("", "", testError).Times(1)
+	_, err = fh.GetDeviceSize(device)
+	assert.NotNil(t, err)
+}
+
 func TestMkDir(t *testing.T) {
 	var (
 		e   = &mocks.GoMockExecutor{}
@@ -125,6 +161,25 @@ func TestMkDir(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestMkFile(t *testing.T) {
+	var (
+		e   = &mocks.GoMockExecutor{}
+		fh  = NewFSImpl(e)
+		src = "/dev/mnt/file"
+		cmd = fmt.Sprintf(MkFileCmdTmpl, src)
+		err error
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	err = fh.MkFile(src)
+	assert.Nil(t, err)
+
+	// cmd failed
+	e.OnCommand(cmd).Return("", "", testError).Times(1)
+	err = fh.MkFile(src)
+	assert.NotNil(t, err)
+}
+
 func TestRmDir(t *testing.T) {
 	var (
 		e   = &mocks.GoMockExecutor{}
@@ -163,12 +218,99 @@ func TestCreateFS(t *testing.T) {
 	err = fh.CreateFS(fsType, device)
 	assert.NotNil(t, err)
 
+	// btrfs with mkfs options
+	btrfsCmd := fmt.Sprintf(MkFSCmdTmpl, BTRFS, device) + BtrfsCreationOpts + " -m single -d single"
+	e.OnCommand(btrfsCmd).Return("", "", nil).Times(1)
+	err = fh.CreateFS(BTRFS, device, "-m", "single", "-d", "single")
+	assert.Nil(t, err)
+
 	// unsupported FS
 	err = fh.CreateFS("anotherFS", device)
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "unsupported file system")
 }
 
+func TestGrowFS(t *testing.T) {
+	var (
+		e          = &mocks.GoMockExecutor{}
+		fh         = NewFSImpl(e)
+		device     = "/dev/sda1"
+		mountPoint = "/mnt/pod1"
+		xfsCmd     = fmt.Sprintf(GrowXFSCmdTmpl, mountPoint)
+		extCmd     = fmt.Sprintf(ResizeExtFSCmdTmpl, device)
+		err        error
+	)
+
+	e.OnCommand(xfsCmd).Return("", "", nil).Times(1)
+	err = fh.GrowFS(XFS, device, mountPoint)
+	assert.Nil(t, err)
+
+	e.OnCommand(extCmd).Return("", "", nil).Times(1)
+	err = fh.GrowFS(EXT4, device, mountPoint)
+	assert.Nil(t, err)
+
+	e.OnCommand(fmt.Sprintf(GrowBtrfsCmdTmpl, mountPoint)).Return("", "", nil).Times(1)
+	err = fh.GrowFS(BTRFS, device, mountPoint)
+	assert.Nil(t, err)
+
+	// cmd failed
+	e.OnCommand(xfsCmd).Return("", "", testError).Times(1)
+	err = fh.GrowFS(XFS, device, mountPoint)
+	assert.NotNil(t, err)
+
+	// unsupported FS
+	err = fh.GrowFS("anotherFS", device, mountPoint)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unsupported file system")
+}
+
+func TestCopyDevice(t *testing.T) {
+	var (
+		e   = &mocks.GoMockExecutor{}
+		fh  = NewFSImpl(e)
+		src = "/dev/vg/lv-1"
+		dst = "/dev/vg/lv-2"
+		cmd = fmt.Sprintf(CopyDeviceCmdTmpl, src, dst)
+		err error
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	err = fh.CopyDevice(src, dst)
+	assert.Nil(t, err)
+
+	// cmd failed
+	e.OnCommand(cmd).Return("", "", testError).Times(1)
+	err = fh.CopyDevice(src, dst)
+	assert.NotNil(t, err)
+}
+
+func TestRegenerateFSUUID(t *testing.T) {
+	var (
+		e      = &mocks.GoMockExecutor{}
+		fh     = NewFSImpl(e)
+		device = "/dev/vg/lv-2"
+		cmd    = fmt.Sprintf(RegenerateXFSUUIDCmdTmpl, device)
+		err    error
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	err = fh.RegenerateFSUUID(XFS, device)
+	assert.Nil(t, err)
+
+	e.OnCommand(fmt.Sprintf(RegenerateBtrfsUUIDCmdTmpl, device)).Return("", "", nil).Times(1)
+	err = fh.RegenerateFSUUID(BTRFS, device)
+	assert.Nil(t, err)
+
+	// nothing to do for ext4
+	err = fh.RegenerateFSUUID(EXT4, device)
+	assert.Nil(t, err)
+
+	// cmd failed
+	e.OnCommand(cmd).Return("", "", testError).Times(1)
+	err = fh.RegenerateFSUUID(XFS, device)
+	assert.NotNil(t, err)
+}
+
 func TestWipeFS(t *testing.T) {
 	var (
 		e      = &mocks.GoMockExecutor{}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MountOptionsSeparator separates mount options in the value of "-o" flag of mount command
+const MountOptionsSeparator = ","
+
+// commonMountOptions are the mount options which are handled by VFS and supported for any file system
+var commonMountOptions = map[string]bool{
+	"ro": true, "rw": true, "noatime": true, "nodiratime": true, "relatime": true, "strictatime": true,
+	"lazytime": true, "nodev": true, "nosuid": true, "noexec": true, "sync": true, "discard": true,
+}
+
+// fsMountOptions are the file system specific mount options which are allowed to be passed from StorageClass,
+// options with value (e.g. "commit=30") are matched by the name before "="
+var fsMountOptions = map[FileSystem]map[string]bool{
+	XFS: {
+		"prjquota": true, "pquota": true, "uquota": true, "gquota": true, "usrquota": true, "grpquota": true,
+		"noquota": true, "nouuid": true, "inode64": true, "largeio": true, "logbsize": true, "allocsize": true,
+	},
+	EXT4: {
+		"data": true, "barrier": true, "nobarrier": true, "errors": true, "commit": true, "usrquota": true,
+		"grpquota": true, "prjquota": true, "dioread_nolock": true, "nodelalloc": true,
+	},
+	EXT3: {
+		"data": true, "barrier": true, "errors": true, "commit": true, "usrquota": true, "grpquota": true,
+	},
+	BTRFS: {
+		"compress": true, "compress-force": true, "space_cache": true, "autodefrag": true, "noautodefrag": true,
+		"ssd": true, "nossd": true, "commit": true, "datacow": true, "nodatacow": true, "subvol": true,
+	},
+}
+
+// ValidateMountOptions checks that each of the mount options is allowed for the provided file system
+// Receives file system as a var of FileSystem type and slice of mount options (e.g. "noatime", "commit=30")
+// Returns error if file system isn't supported or some of options isn't allowed, empty options are always valid
+func ValidateMountOptions(fsType FileSystem, opts []string) error {
+	if len(opts) == 0 {
+		return nil
+	}
+
+	allowed, ok := fsMountOptions[fsType]
+	if !ok {
+		return fmt.Errorf("unsupported file system %v", fsType)
+	}
+
+	for _, opt := range opts {
+		name := strings.SplitN(opt, "=", 2)[0]
+		if !commonMountOptions[name] && !allowed[name] {
+			return fmt.Errorf("mount option %s isn't allowed for file system %v", opt, fsType)
+		}
+	}
+	return nil
+}
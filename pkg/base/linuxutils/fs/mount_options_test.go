@@ -0,0 +1,41 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMountOptions(t *testing.T) {
+	assert.Nil(t, ValidateMountOptions(XFS, nil))
+	assert.Nil(t, ValidateMountOptions("", nil))
+	assert.Nil(t, ValidateMountOptions(XFS, []string{"noatime", "discard", "prjquota"}))
+	assert.Nil(t, ValidateMountOptions(EXT4, []string{"nodev", "commit=30", "data=ordered"}))
+	assert.Nil(t, ValidateMountOptions(BTRFS, []string{"compress=zstd", "ssd", "noatime"}))
+
+	// option of another file system
+	err := ValidateMountOptions(EXT4, []string{"noatime", "compress=zstd"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "compress=zstd")
+
+	// unsupported FS
+	err = ValidateMountOptions("anotherFS", []string{"noatime"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unsupported file system")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledctl
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+)
+
+const (
+	//LedctlCmdTmpl is a CMD to set LED pattern of the device through enclosure services (SES-2, SGPIO, VMD)
+	LedctlCmdTmpl = "ledctl %s=%s"
+
+	//NormalPattern turns off locate and failure LEDs
+	NormalPattern = "normal"
+	//LocatePattern blinks locate LED
+	LocatePattern = "locate"
+	//FailurePattern lits failure LED
+	FailurePattern = "failure"
+)
+
+// WrapLedctl is an interface that encapsulates operation with system ledctl util
+type WrapLedctl interface {
+	SetLEDState(device, state string) error
+}
+
+// LEDCTL is a wrap for system ledctl util
+type LEDCTL struct {
+	e   command.CmdExecutor
+	log *logrus.Entry
+}
+
+// NewLEDCTL is a constructor for LEDCTL
+func NewLEDCTL(e command.CmdExecutor, logger *logrus.Logger) *LEDCTL {
+	return &LEDCTL{e: e, log: logger.WithField("component", "LEDCTL")}
+}
+
+// SetLEDState sets LED of the device to the state (one of apiV1.LEDState*) using ledctl util
+func (l *LEDCTL) SetLEDState(device, state string) error {
+	var pattern string
+	switch state {
+	case apiV1.LEDStateOff:
+		pattern = NormalPattern
+	case apiV1.LEDStateLocate:
+		pattern = LocatePattern
+	case apiV1.LEDStateFailure:
+		pattern = FailurePattern
+	default:
+		return fmt.Errorf("unsupported LED state %s", state)
+	}
+
+	cmd := fmt.Sprintf(LedctlCmdTmpl, pattern, device)
+	if _, stderr, err := l.e.RunCmd(cmd); err != nil {
+		l.log.WithField("method", "SetLEDState").Errorf("%s failed, stderr: %s, error: %v", cmd, stderr, err)
+		return err
+	}
+	return nil
+}
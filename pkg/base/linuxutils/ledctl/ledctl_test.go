@@ -0,0 +1,45 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ledctl
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+var testLogger = logrus.New()
+
+func TestLEDCTL_SetLEDState(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	l := NewLEDCTL(e, testLogger)
+
+	e.On("RunCmd", fmt.Sprintf(LedctlCmdTmpl, LocatePattern, "/dev/sda")).Return("", "", nil)
+	e.On("RunCmd", fmt.Sprintf(LedctlCmdTmpl, NormalPattern, "/dev/sda")).Return("", "", nil)
+	e.On("RunCmd", fmt.Sprintf(LedctlCmdTmpl, FailurePattern, "/dev/sdb")).Return("", "error", errors.New("error"))
+
+	assert.Nil(t, l.SetLEDState("/dev/sda", apiV1.LEDStateLocate))
+	assert.Nil(t, l.SetLEDState("/dev/sda", apiV1.LEDStateOff))
+	assert.NotNil(t, l.SetLEDState("/dev/sdb", apiV1.LEDStateFailure))
+	assert.NotNil(t, l.SetLEDState("/dev/sda", "BLINK"))
+}
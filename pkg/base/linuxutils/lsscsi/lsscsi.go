@@ -60,6 +60,15 @@ type SCSIDevice struct {
 	Firmware string
 }
 
+//Address parses ID of the device in [host:channel:target:lun] format
+func (d *SCSIDevice) Address() (host, channel, target, lun int, err error) {
+	_, err = fmt.Sscanf(d.ID, "[%d:%d:%d:%d]", &host, &channel, &target, &lun)
+	if err != nil {
+		err = fmt.Errorf("unable to parse SCSI address %s: %v", d.ID, err)
+	}
+	return
+}
+
 //NewLSSCSI is a constructor for LSSCSI
 func NewLSSCSI(e command.CmdExecutor, logger *logrus.Logger) *LSSCSI {
 	return &LSSCSI{e: e, log: logger.WithField("component", "LSSCSI")}
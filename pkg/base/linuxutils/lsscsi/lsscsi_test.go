@@ -171,3 +171,14 @@ func TestLSSCSI_GetSCSIDevicesSizeCMDFail(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(devs))
 }
+
+func TestSCSIDevice_Address(t *testing.T) {
+	device := &SCSIDevice{ID: "[0:2:3:1]"}
+	host, channel, target, lun, err := device.Address()
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 2, 3, 1}, []int{host, channel, target, lun})
+
+	device.ID = "0:2:3"
+	_, _, _, _, err = device.Address()
+	assert.NotNil(t, err)
+}
@@ -15,12 +15,13 @@ limitations under the License.
 */
 
 // Package lvm contains code for running and interpreting output of system logical volume manager utils
-// such as: pvcreate/pvremove, vgcreate/vgremove, lvcreate/lvremove
+// such as: pvcreate/pvremove, vgcreate/vgremove, lvcreate/lvremove, lvcreate --snapshot
 package lvm
 
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -34,14 +35,26 @@ const (
 	EmptyName = " "
 	// lvmPath is a path in the system to the lvm util
 	lvmPath = "/sbin/lvm "
+	// ThinPoolName is a name of the thin pool LV in the thin pool VG
+	ThinPoolName = "thinpool"
 	// PVCreateCmdTmpl create PV cmd
 	PVCreateCmdTmpl = lvmPath + "pvcreate --yes %s" // add PV name
 	// PVRemoveCmdTmpl remove PV cmd
 	PVRemoveCmdTmpl = lvmPath + "pvremove --yes %s" // add PV name
+	// PVDisableAllocationCmdTmpl forbid allocation of new extents on PV cmd
+	PVDisableAllocationCmdTmpl = lvmPath + "pvchange --yes --allocatable n %s" // add PV name
 	// PVsInVGCmdTmpl print PVs in VG cmd
 	PVsInVGCmdTmpl = lvmPath + "pvs --select vg_name=%s -o pv_name --noheadings" // add VG name
+	// PVsFreeSpaceCmdTmpl print attributes and free space of PVs in VG cmd
+	PVsFreeSpaceCmdTmpl = lvmPath + "pvs --select vg_name=%s --options pv_attr,pv_free --units b --noheadings" // add VG name
 	// VGCreateCmdTmpl create VG on provided PVs cmd
 	VGCreateCmdTmpl = lvmPath + "vgcreate --yes %s %s" // add VG name and PV names
+	// VGExtendCmdTmpl add PV to VG cmd
+	VGExtendCmdTmpl = lvmPath + "vgextend --yes %s %s" // add VG name and PV name
+	// VGReduceCmdTmpl remove PV from VG cmd
+	VGReduceCmdTmpl = lvmPath + "vgreduce --yes %s %s" // add VG name and PV name
+	// VGActivateCmdTmpl activate all LVs in VG cmd
+	VGActivateCmdTmpl = lvmPath + "vgchange --activate y %s" // add VG name
 	// VGRemoveCmdTmpl remove VG cmd
 	VGRemoveCmdTmpl = lvmPath + "vgremove --yes %s" // add VG name
 	// VGByLVCmdTmpl find VG by LV cmd
@@ -50,6 +63,28 @@ const (
 	VGFreeSpaceCmdTmpl = "vgs %s --options vg_free --units b --noheadings" // add VG name
 	// LVCreateCmdTmpl create LV on provided VG cmd
 	LVCreateCmdTmpl = lvmPath + "lvcreate --yes --name %s --size %s %s" // add LV name, size and VG name
+	// LVCreateStripedCmdTmpl create striped LV on provided VG cmd
+	LVCreateStripedCmdTmpl = lvmPath + "lvcreate --yes --name %s --size %s --stripes %d %s" // add LV name, size, stripes and VG name
+	// LVCreateRAID1CmdTmpl create raid1 LV on provided VG cmd
+	LVCreateRAID1CmdTmpl = lvmPath + "lvcreate --yes --type raid1 --mirrors %d --name %s --size %s %s" // add mirrors, LV name, size and VG name
+	// ThinPoolCreateCmdTmpl create thin pool on all free space of provided VG cmd
+	ThinPoolCreateCmdTmpl = lvmPath + "lvcreate --yes --type thin-pool --extents 100%%FREE --name %s %s" // add pool name and VG name
+	// ThinLVCreateCmdTmpl create thin LV in thin pool of provided VG cmd
+	ThinLVCreateCmdTmpl = lvmPath + "lvcreate --yes --type thin --virtualsize %s --thinpool %s --name %s %s" // add size, pool name, LV name and VG name
+	// ThinPoolUsageCmdTmpl print data and metadata usage of thin pool cmd
+	ThinPoolUsageCmdTmpl = lvmPath + "lvs --options data_percent,metadata_percent --noheadings %s/%s" // add VG name and pool name
+	// CachePoolCreateCmdTmpl create cache pool on all extents of provided PV of VG cmd
+	CachePoolCreateCmdTmpl = lvmPath + "lvcreate --yes --type cache-pool --poolmetadataspare n --extents 100%%PVS --name %s %s %s" // add pool name, VG name and PV name
+	// LVCacheAttachCmdTmpl attach cache pool to LV cmd
+	LVCacheAttachCmdTmpl = lvmPath + "lvconvert --yes --type cache --cachepool %s --cachemode %s %s" // add full pool name, cache mode and full LV name
+	// LVResizeCmdTmpl resize LV cmd
+	LVResizeCmdTmpl = lvmPath + "lvresize --yes --size %s %s" // add size and full LV name
+	// LVSnapshotCreateCmdTmpl create snapshot of LV cmd
+	LVSnapshotCreateCmdTmpl = lvmPath + "lvcreate --yes --snapshot --name %s --size %s %s" // add snapshot name, size and full LV name
+	// LVThinSnapshotCreateCmdTmpl create thin snapshot of thin LV in the same thin pool cmd, snapshot is activated as usual LV
+	LVThinSnapshotCreateCmdTmpl = lvmPath + "lvcreate --yes --snapshot --setactivationskip n --name %s %s" // add snapshot name and full LV name
+	// LVSnapshotRemoveCmdTmpl remove snapshot of LV cmd
+	LVSnapshotRemoveCmdTmpl = lvmPath + "lvremove --yes %s" // add full snapshot name
 	// LVRemoveCmdTmpl remove LV cmd
 	LVRemoveCmdTmpl = lvmPath + "lvremove --yes %s" // add full LV name
 	// LVsInVGCmdTmpl print LVs in VG cmd
@@ -60,14 +95,30 @@ const (
 type WrapLVM interface {
 	PVCreate(dev string) error
 	PVRemove(name string) error
+	PVDisableAllocation(name string) error
 	VGCreate(name string, pvs ...string) error
+	VGExtend(name, pv string) error
+	VGReduce(name, pv string) error
 	VGRemove(name string) error
+	VGActivate(name string) error
 	LVCreate(name, size, vgName string) error
+	LVCreateStriped(name, size, vgName string, stripes int32) error
+	LVCreateRAID1(name, size, vgName string, mirrors int32) error
+	ThinPoolCreate(vgName, poolName string) error
+	ThinLVCreate(name, size, vgName, poolName string) error
+	GetThinPoolUsage(vgName, poolName string) (float64, float64, error)
+	CachePoolCreate(name, vgName, pv string) error
+	LVCacheAttach(fullLVName, fullPoolName, mode string) error
 	LVRemove(fullLVName string) error
+	LVResize(fullLVName, size string) error
+	LVSnapshotCreate(name, size, fullLVName string) error
+	LVThinSnapshotCreate(name, fullLVName string) error
+	LVSnapshotRemove(fullSnapshotName string) error
 	IsVGContainsLVs(vgName string) bool
 	RemoveOrphanPVs() error
 	FindVgNameByLvName(lvName string) (string, error)
 	GetVgFreeSpace(vgName string) (int64, error)
+	GetPVsFreeSpace(vgName string) ([]int64, error)
 	IsLVGExists(lvName string) (bool, error)
 	GetLVsInVG(vgName string) ([]string, error)
 }
@@ -107,6 +158,15 @@ func (l *LVM) PVRemove(name string) error {
 	return err
 }
 
+// PVDisableAllocation forbids allocation of extents on the physical volume, already allocated extents are kept
+// Receives PV name
+// Returns error if something went wrong
+func (l *LVM) PVDisableAllocation(name string) error {
+	cmd := fmt.Sprintf(PVDisableAllocationCmdTmpl, name)
+	_, _, err := l.e.RunCmd(cmd)
+	return err
+}
+
 // VGCreate creates volume group and based on provided physical volumes (pvs). Ignore error if VG already exists
 // Receives name of VG to create and names of physical volumes which VG should based on
 // Returns error if something went wrong
@@ -119,6 +179,31 @@ func (l *LVM) VGCreate(name string, pvs ...string) error {
 	return err
 }
 
+// VGExtend adds physical volume to the volume group, ignore error if PV is already in that VG
+// Receives name of VG and name of physical volume
+// Returns error if something went wrong
+func (l *LVM) VGExtend(name, pv string) error {
+	cmd := fmt.Sprintf(VGExtendCmdTmpl, name, pv)
+	_, stdErr, err := l.e.RunCmd(cmd)
+	if err != nil && strings.Contains(stdErr, "is already in volume group") {
+		return nil
+	}
+	return err
+}
+
+// VGReduce removes physical volume from the volume group, ignore error if PV doesn't exist or isn't in that VG
+// Receives name of VG and name of physical volume
+// Returns error if something went wrong
+func (l *LVM) VGReduce(name, pv string) error {
+	cmd := fmt.Sprintf(VGReduceCmdTmpl, name, pv)
+	_, stdErr, err := l.e.RunCmd(cmd)
+	if err != nil && (strings.Contains(stdErr, "Failed to find physical volume") ||
+		strings.Contains(stdErr, "not found in Volume Group")) {
+		return nil
+	}
+	return err
+}
+
 // VGRemove removes volume group, ignore error if VG doesn't exist
 // Receives name of VG to remove
 // Returns error if something went wrong
@@ -131,11 +216,100 @@ func (l *LVM) VGRemove(name string) error {
 	return err
 }
 
+// VGActivate activates all logical volumes of the volume group, already active LVs are kept untouched
+// Receives name of VG
+// Returns error if something went wrong
+func (l *LVM) VGActivate(name string) error {
+	cmd := fmt.Sprintf(VGActivateCmdTmpl, name)
+	_, _, err := l.e.RunCmd(cmd)
+	return err
+}
+
 // LVCreate created logical volume in volume group, ignore error if LV already exists
 // Receives name of created LV, size which is a string like 1.2G, 100M and name of VG which LV should be based on
 // Returns error if something went wrong
 func (l *LVM) LVCreate(name, size, vgName string) error {
-	cmd := fmt.Sprintf(LVCreateCmdTmpl, name, size, vgName)
+	return l.lvCreate(fmt.Sprintf(LVCreateCmdTmpl, name, size, vgName))
+}
+
+// LVCreateStriped creates logical volume which is striped across stripes PVs of the volume group,
+// ignore error if LV already exists
+// Receives name of created LV, size which is a string like 1.2G, 100M, name of VG and number of stripes
+// Returns error if something went wrong
+func (l *LVM) LVCreateStriped(name, size, vgName string, stripes int32) error {
+	return l.lvCreate(fmt.Sprintf(LVCreateStripedCmdTmpl, name, size, stripes, vgName))
+}
+
+// LVCreateRAID1 creates raid1 logical volume with mirrors additional copies on different PVs of the volume group,
+// ignore error if LV already exists
+// Receives name of created LV, size which is a string like 1.2G, 100M, name of VG and number of mirrors
+// Returns error if something went wrong
+func (l *LVM) LVCreateRAID1(name, size, vgName string, mirrors int32) error {
+	return l.lvCreate(fmt.Sprintf(LVCreateRAID1CmdTmpl, mirrors, name, size, vgName))
+}
+
+// ThinPoolCreate creates thin pool which occupies all free space of the volume group,
+// ignore error if thin pool already exists
+// Receives name of VG and name of created thin pool
+// Returns error if something went wrong
+func (l *LVM) ThinPoolCreate(vgName, poolName string) error {
+	return l.lvCreate(fmt.Sprintf(ThinPoolCreateCmdTmpl, poolName, vgName))
+}
+
+// ThinLVCreate creates thin logical volume in the thin pool of the volume group, ignore error if LV already exists
+// Receives name of created LV, virtual size which is a string like 1.2G, 100M, name of VG and name of thin pool
+// Returns error if something went wrong
+func (l *LVM) ThinLVCreate(name, size, vgName, poolName string) error {
+	return l.lvCreate(fmt.Sprintf(ThinLVCreateCmdTmpl, size, poolName, name, vgName))
+}
+
+// GetThinPoolUsage returns data and metadata usage of the thin pool in percents
+// Receives name of VG and name of thin pool
+// Returns data usage, metadata usage or error if something went wrong
+func (l *LVM) GetThinPoolUsage(vgName, poolName string) (float64, float64, error) {
+	/*
+		Example of output:
+		root@provo-goop:~# lvs --options data_percent,metadata_percent --noheadings vg/thinpool
+		  12.50  3.27
+	*/
+	cmd := fmt.Sprintf(ThinPoolUsageCmdTmpl, vgName, poolName)
+	stdout, _, err := l.e.RunCmd(cmd)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	fields := strings.Fields(stdout)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unable to parse usage of thin pool %s/%s: %q", vgName, poolName, stdout)
+	}
+	usage := make([]float64, len(fields))
+	for i, field := range fields {
+		if usage[i], err = strconv.ParseFloat(field, 64); err != nil {
+			return 0, 0, fmt.Errorf("unable to parse usage of thin pool %s/%s: %v", vgName, poolName, err)
+		}
+	}
+	return usage[0], usage[1], nil
+}
+
+// CachePoolCreate creates cache pool which occupies all extents of the physical volume of the volume group,
+// spare metadata LV isn't created to keep other PVs of VG untouched. Ignore error if cache pool already exists
+// Receives name of created cache pool, name of VG and name of PV
+// Returns error if something went wrong
+func (l *LVM) CachePoolCreate(name, vgName, pv string) error {
+	return l.lvCreate(fmt.Sprintf(CachePoolCreateCmdTmpl, name, vgName, pv))
+}
+
+// LVCacheAttach attaches cache pool to the logical volume, both of them should be placed in the same VG
+// Receives fullLVName that is a path to LV, fullPoolName that is a path to cache pool
+// and cache mode: writethrough or writeback
+// Returns error if something went wrong
+func (l *LVM) LVCacheAttach(fullLVName, fullPoolName, mode string) error {
+	cmd := fmt.Sprintf(LVCacheAttachCmdTmpl, fullPoolName, mode, fullLVName)
+	_, _, err := l.e.RunCmd(cmd)
+	return err
+}
+
+func (l *LVM) lvCreate(cmd string) error {
 	_, stdErr, err := l.e.RunCmd(cmd)
 	if err != nil && strings.Contains(stdErr, "already exists") {
 		return nil
@@ -155,6 +329,56 @@ func (l *LVM) LVRemove(fullLVName string) error {
 	return err
 }
 
+// LVResize changes size of logical volume, ignore error if LV already has provided size
+// Receives fullLVName that is a path to LV and size which is a string like 1.2G, 100M
+// Returns error if something went wrong
+func (l *LVM) LVResize(fullLVName, size string) error {
+	cmd := fmt.Sprintf(LVResizeCmdTmpl, size, fullLVName)
+	_, stdErr, err := l.e.RunCmd(cmd)
+	if err != nil && strings.Contains(stdErr, "matches existing size") {
+		return nil
+	}
+	return err
+}
+
+// LVSnapshotCreate creates copy-on-write snapshot of logical volume, ignore error if snapshot already exists
+// Receives name of the snapshot, size of the snapshot which is a string like 1.2G, 100M and
+// fullLVName that is a path to the origin LV
+// Returns error if something went wrong
+func (l *LVM) LVSnapshotCreate(name, size, fullLVName string) error {
+	cmd := fmt.Sprintf(LVSnapshotCreateCmdTmpl, name, size, fullLVName)
+	_, stdErr, err := l.e.RunCmd(cmd)
+	if err != nil && strings.Contains(stdErr, "already exists") {
+		return nil
+	}
+	return err
+}
+
+// LVThinSnapshotCreate creates thin snapshot of thin logical volume which shares thin pool with origin LV,
+// ignore error if snapshot already exists
+// Receives name of the snapshot and fullLVName that is a path to the origin LV
+// Returns error if something went wrong
+func (l *LVM) LVThinSnapshotCreate(name, fullLVName string) error {
+	cmd := fmt.Sprintf(LVThinSnapshotCreateCmdTmpl, name, fullLVName)
+	_, stdErr, err := l.e.RunCmd(cmd)
+	if err != nil && strings.Contains(stdErr, "already exists") {
+		return nil
+	}
+	return err
+}
+
+// LVSnapshotRemove removes snapshot of logical volume, ignore error if snapshot doesn't exist
+// Receives fullSnapshotName that is a path to snapshot LV
+// Returns error if something went wrong
+func (l *LVM) LVSnapshotRemove(fullSnapshotName string) error {
+	cmd := fmt.Sprintf(LVSnapshotRemoveCmdTmpl, fullSnapshotName)
+	_, stdErr, err := l.e.RunCmd(cmd)
+	if err != nil && strings.Contains(stdErr, "Failed to find logical volume") {
+		return nil
+	}
+	return err
+}
+
 // IsVGContainsLVs checks whether VG vgName contains any LVs or no
 // Receives Volume Group name to check
 // Returns true in case of error to prevent mistaken VG remove
@@ -255,6 +479,39 @@ func (l *LVM) GetVgFreeSpace(vgName string) (int64, error) {
 	return bytes, nil
 }
 
+// GetPVsFreeSpace returns free space in bytes of each allocatable PV of VG,
+// PVs with disabled allocation (e.g. PVs of cache pools) are skipped
+// Receives VG name
+// Returns slice of PVs free space or error if something went wrong
+func (l *LVM) GetPVsFreeSpace(vgName string) ([]int64, error) {
+	/*
+		Example of output:
+		root@provo-goop:~# pvs --select vg_name=vg-1 --options pv_attr,pv_free --units b --noheadings
+			  a--  1000B
+			  a--  2000B
+			  ---     0B
+	*/
+	cmd := fmt.Sprintf(PVsFreeSpaceCmdTmpl, vgName)
+	strOut, _, err := l.e.RunCmd(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make([]int64, 0)
+	for _, line := range strings.Split(strOut, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !strings.HasPrefix(fields[0], "a") {
+			continue
+		}
+		size, err := util.StrToBytes(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
 // IsLVGExists try to get vg group from lvName, if there is no such group then lvg is not exists
 // Receives lvName string
 // Returns true if lvg exists, else false; error
@@ -96,6 +96,46 @@ func TestLinuxUtils_VGCreate(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 }
 
+func TestLinuxUtils_VGExtend(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		pv          = "/dev/cache-lvg/cache-lv"
+		cmd         = fmt.Sprintf(VGExtendCmdTmpl, vg, pv)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.VGExtend(vg, pv))
+
+	e.OnCommand(cmd).Return("", "is already in volume group", expectedErr).Times(1)
+	assert.Nil(t, l.VGExtend(vg, pv))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.VGExtend(vg, pv))
+}
+
+func TestLinuxUtils_VGReduce(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		pv          = "/dev/cache-lvg/cache-lv"
+		cmd         = fmt.Sprintf(VGReduceCmdTmpl, vg, pv)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.VGReduce(vg, pv))
+
+	e.OnCommand(cmd).Return("", "Failed to find physical volume", expectedErr).Times(1)
+	assert.Nil(t, l.VGReduce(vg, pv))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.VGReduce(vg, pv))
+}
+
 func TestLinuxUtils_VGRemove(t *testing.T) {
 	var (
 		e           = &mocks.GoMockExecutor{}
@@ -144,6 +184,175 @@ func TestLinuxUtils_LVCreate(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 }
 
+func TestLinuxUtils_LVCreateStriped(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		lv          = "test-lv"
+		size        = "9g"
+		vg          = "test-lvg"
+		cmd         = fmt.Sprintf(LVCreateStripedCmdTmpl, lv, size, 3, vg)
+		err         error
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	err = l.LVCreateStriped(lv, size, vg, 3)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	err = l.LVCreateStriped(lv, size, vg, 3)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestLinuxUtils_LVCreateRAID1(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		lv          = "test-lv"
+		size        = "9g"
+		vg          = "test-lvg"
+		cmd         = fmt.Sprintf(LVCreateRAID1CmdTmpl, 1, lv, size, vg)
+		err         error
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "already exists", expectedErr).Times(1)
+	err = l.LVCreateRAID1(lv, size, vg, 1)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "Insufficient suitable allocatable extents", expectedErr).Times(1)
+	err = l.LVCreateRAID1(lv, size, vg, 1)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestLinuxUtils_ThinPoolCreate(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		cmd         = fmt.Sprintf(ThinPoolCreateCmdTmpl, ThinPoolName, vg)
+		expectedErr = errors.New("error")
+	)
+	assert.Contains(t, cmd, "100%FREE")
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.ThinPoolCreate(vg, ThinPoolName))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.ThinPoolCreate(vg, ThinPoolName))
+}
+
+func TestLinuxUtils_CachePoolCreate(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		pool        = "test-cpool"
+		pv          = "/dev/cache-lvg/cache-lv"
+		cmd         = fmt.Sprintf(CachePoolCreateCmdTmpl, pool, vg, pv)
+		expectedErr = errors.New("error")
+	)
+	assert.Contains(t, cmd, "100%PVS")
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.CachePoolCreate(pool, vg, pv))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.CachePoolCreate(pool, vg, pv))
+}
+
+func TestLinuxUtils_PVDisableAllocation(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		pv          = "/dev/cache-lvg/cache-lv"
+		cmd         = fmt.Sprintf(PVDisableAllocationCmdTmpl, pv)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.PVDisableAllocation(pv))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.PVDisableAllocation(pv))
+}
+
+func TestLinuxUtils_VGActivate(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		cmd         = fmt.Sprintf(VGActivateCmdTmpl, vg)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.VGActivate(vg))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.VGActivate(vg))
+}
+
+func TestLinuxUtils_LVCacheAttach(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		lv          = "/dev/test-lvg/test-lv"
+		pool        = "/dev/test-lvg/test-cpool"
+		cmd         = fmt.Sprintf(LVCacheAttachCmdTmpl, pool, "writethrough", lv)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	assert.Nil(t, l.LVCacheAttach(lv, pool, "writethrough"))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.LVCacheAttach(lv, pool, "writethrough"))
+}
+
+func TestLinuxUtils_ThinLVCreate(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		lv          = "test-lv"
+		size        = "9g"
+		vg          = "test-lvg"
+		cmd         = fmt.Sprintf(ThinLVCreateCmdTmpl, size, ThinPoolName, lv, vg)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "already exists", expectedErr).Times(1)
+	assert.Nil(t, l.ThinLVCreate(lv, size, vg, ThinPoolName))
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	assert.Equal(t, expectedErr, l.ThinLVCreate(lv, size, vg, ThinPoolName))
+}
+
+func TestLinuxUtils_GetThinPoolUsage(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vg          = "test-lvg"
+		cmd         = fmt.Sprintf(ThinPoolUsageCmdTmpl, vg, ThinPoolName)
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("  12.50  3.27\n", "", nil).Times(1)
+	data, metadata, err := l.GetThinPoolUsage(vg, ThinPoolName)
+	assert.Nil(t, err)
+	assert.Equal(t, 12.5, data)
+	assert.Equal(t, 3.27, metadata)
+
+	e.OnCommand(cmd).Return("  12.50\n", "", nil).Times(1)
+	_, _, err = l.GetThinPoolUsage(vg, ThinPoolName)
+	assert.NotNil(t, err)
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	_, _, err = l.GetThinPoolUsage(vg, ThinPoolName)
+	assert.Equal(t, expectedErr, err)
+}
+
 func TestLinuxUtils_LVRemove(t *testing.T) {
 	var (
 		e           = &mocks.GoMockExecutor{}
@@ -169,6 +378,105 @@ func TestLinuxUtils_LVRemove(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 }
 
+func TestLinuxUtils_LVResize(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		fullLVName  = "/dev/test-lvg/test-lv"
+		size        = "200m"
+		cmd         = fmt.Sprintf(LVResizeCmdTmpl, size, fullLVName)
+		err         error
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	err = l.LVResize(fullLVName, size)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "New size (50 extents) matches existing size (50 extents).", expectedErr).Times(1)
+	err = l.LVResize(fullLVName, size)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	err = l.LVResize(fullLVName, size)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestLinuxUtils_LVSnapshotCreate(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		snapName    = "test-snap"
+		fullLVName  = "/dev/test-lvg/test-lv"
+		size        = "200m"
+		cmd         = fmt.Sprintf(LVSnapshotCreateCmdTmpl, snapName, size, fullLVName)
+		err         error
+		expectedErr = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	err = l.LVSnapshotCreate(snapName, size, fullLVName)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "Logical volume \"test-snap\" already exists", expectedErr).Times(1)
+	err = l.LVSnapshotCreate(snapName, size, fullLVName)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	err = l.LVSnapshotCreate(snapName, size, fullLVName)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestLinuxUtils_LVThinSnapshotCreate(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		snapName    = "test-snap"
+		fullLVName  = "/dev/test-lvg/test-lv"
+		cmd         = fmt.Sprintf(LVThinSnapshotCreateCmdTmpl, snapName, fullLVName)
+		err         error
+		expectedErr = errors.New("error")
+	)
+
+	// thin snapshot is allocated in thin pool, so size isn't set
+	assert.NotContains(t, cmd, "--size")
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	err = l.LVThinSnapshotCreate(snapName, fullLVName)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "Logical volume \"test-snap\" already exists", expectedErr).Times(1)
+	err = l.LVThinSnapshotCreate(snapName, fullLVName)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	err = l.LVThinSnapshotCreate(snapName, fullLVName)
+	assert.Equal(t, expectedErr, err)
+}
+
+func TestLinuxUtils_LVSnapshotRemove(t *testing.T) {
+	var (
+		e            = &mocks.GoMockExecutor{}
+		l            = NewLVM(e, testLogger)
+		fullSnapName = "/dev/test-lvg/test-snap"
+		cmd          = fmt.Sprintf(LVSnapshotRemoveCmdTmpl, fullSnapName)
+		err          error
+		expectedErr  = errors.New("error")
+	)
+
+	e.OnCommand(cmd).Return("", "", nil).Times(1)
+	err = l.LVSnapshotRemove(fullSnapName)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "Failed to find logical volume", expectedErr).Times(1)
+	err = l.LVSnapshotRemove(fullSnapName)
+	assert.Nil(t, err)
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	err = l.LVSnapshotRemove(fullSnapName)
+	assert.Equal(t, expectedErr, err)
+}
+
 func TestLinuxUtilsIs_VGContainsLVs(t *testing.T) {
 	var (
 		e           = &mocks.GoMockExecutor{}
@@ -343,3 +651,26 @@ func TestLinuxUtils_GetVgFreeSpace(t *testing.T) {
 	assert.Equal(t, int64(-1), currentSize)
 	assert.Contains(t, err.Error(), "unknown size unit")
 }
+
+func TestLinuxUtils_GetPVsFreeSpace(t *testing.T) {
+	var (
+		e           = &mocks.GoMockExecutor{}
+		l           = NewLVM(e, testLogger)
+		vgName      = "vg-1"
+		cmd         = fmt.Sprintf(PVsFreeSpaceCmdTmpl, vgName)
+		expectedErr = errors.New("error here")
+	)
+
+	e.OnCommand(cmd).Return("  a--  1000B\n  a--  2000B\n  ---  0B\n", "", nil).Times(1)
+	sizes, err := l.GetPVsFreeSpace(vgName)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1000, 2000}, sizes)
+
+	e.OnCommand(cmd).Return("", "", expectedErr).Times(1)
+	_, err = l.GetPVsFreeSpace(vgName)
+	assert.Equal(t, expectedErr, err)
+
+	e.OnCommand(cmd).Return("  a--  1000\n", "", nil).Times(1)
+	_, err = l.GetPVsFreeSpace(vgName)
+	assert.Contains(t, err.Error(), "unknown size unit")
+}
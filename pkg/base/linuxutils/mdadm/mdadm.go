@@ -0,0 +1,232 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mdadm contains code for running system mdadm util and interpreting /proc/mdstat
+// which are used for managing of Linux software RAID (MD) arrays
+package mdadm
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dell/csi-baremetal/pkg/base/command"
+)
+
+const (
+	// MDADMCreateCmdTmpl creates MD array with metadata 1.2, --run suppresses confirmation questions,
+	// --homehost=any keeps array name without hostname, so the array is assembled with the same name on any host
+	MDADMCreateCmdTmpl = "mdadm --create %s --run --homehost=any --metadata=1.2 --level=%s --raid-devices=%d %s" // add array path, level, devices count and devices
+	// MDADMAssembleCmdTmpl assembles MD array from provided members, --run starts the array even if it is degraded
+	MDADMAssembleCmdTmpl = "mdadm --assemble %s --run --homehost=any %s" // add array path and devices
+	// MDADMStopCmdTmpl stops MD array and releases its members
+	MDADMStopCmdTmpl = "mdadm --stop %s" // add array path
+	// MDADMZeroSuperblockCmdTmpl removes MD superblock from the former member of the array
+	MDADMZeroSuperblockCmdTmpl = "mdadm --zero-superblock %s" // add device
+
+	// ArrayDir is a directory where udev creates symlinks on the named MD arrays
+	ArrayDir = "/dev/md"
+	// MDStatPath is a path of the file which represents state of all MD arrays on the node
+	MDStatPath = "/proc/mdstat"
+	// ArrayStateActive is a state of the running array
+	ArrayStateActive = "active"
+)
+
+var (
+	// md127 : active raid1 sdc[1] sdb[0](F)
+	arrayRegexp = regexp.MustCompile(`^(md\S+)\s+:\s+(\S+)\s*(.*)$`)
+	// 1046528 blocks super 1.2 [2/1] [U_]
+	disksRegexp = regexp.MustCompile(`\[(\d+)/(\d+)\]`)
+	// [=>...................]  recovery =  8.6% (90112/1046528) finish=0.5min speed=30037K/sec
+	recoveryRegexp = regexp.MustCompile(`(recovery|resync|reshape)\s+=`)
+)
+
+// Array represents state of MD array in /proc/mdstat
+type Array struct {
+	// kernel name of the array, such as md127
+	Name string
+	// active or inactive
+	State string
+	// RAID personality, such as raid1, empty for inactive arrays
+	Level string
+	// kernel names of the member devices which are in use
+	Devices []string
+	// kernel names of the member devices which are marked as faulty
+	FailedDevices []string
+	// amount of devices that array should consist of
+	RaidDisks int
+	// amount of devices that are in sync
+	ActiveDisks int
+	// whether array is rebuilding or resyncing
+	Recovering bool
+}
+
+// IsDegraded returns true if some members of the array are missed or failed
+func (a *Array) IsDegraded() bool {
+	return a.ActiveDisks < a.RaidDisks || len(a.FailedDevices) > 0
+}
+
+// WrapMDADM is an interface that encapsulates operation with system mdadm util and /proc/mdstat
+type WrapMDADM interface {
+	CreateArray(name, level string, devices []string) error
+	AssembleArray(name string, devices []string) error
+	StopArray(name string) error
+	ZeroSuperblock(device string) error
+	GetArray(name string) (*Array, error)
+}
+
+// MDADM is a wrap for system mdadm util
+type MDADM struct {
+	e      command.CmdExecutor
+	mdDir  string
+	mdstat string
+	log    *logrus.Entry
+}
+
+// NewMDADM is a constructor for MDADM
+func NewMDADM(e command.CmdExecutor, logger *logrus.Logger) *MDADM {
+	return &MDADM{
+		e:      e,
+		mdDir:  ArrayDir,
+		mdstat: MDStatPath,
+		log:    logger.WithField("component", "MDADM"),
+	}
+}
+
+// GetArrayPath returns full path of the named array: /dev/md/NAME
+func GetArrayPath(name string) string {
+	return filepath.Join(ArrayDir, name)
+}
+
+// CreateArray creates and starts named MD array of the level on provided devices
+func (m *MDADM) CreateArray(name, level string, devices []string) error {
+	cmd := fmt.Sprintf(MDADMCreateCmdTmpl, filepath.Join(m.mdDir, name), level, len(devices),
+		strings.Join(devices, " "))
+	if _, stderr, err := m.e.RunCmd(cmd); err != nil {
+		m.log.WithField("method", "CreateArray").Errorf("%s failed, stderr: %s, error: %v", cmd, stderr, err)
+		return err
+	}
+	return nil
+}
+
+// AssembleArray assembles and starts named MD array from provided member devices,
+// arrays aren't listed in mdadm.conf, so they should be assembled explicitly after node reboot
+func (m *MDADM) AssembleArray(name string, devices []string) error {
+	cmd := fmt.Sprintf(MDADMAssembleCmdTmpl, filepath.Join(m.mdDir, name), strings.Join(devices, " "))
+	if _, stderr, err := m.e.RunCmd(cmd); err != nil {
+		m.log.WithField("method", "AssembleArray").Errorf("%s failed, stderr: %s, error: %v", cmd, stderr, err)
+		return err
+	}
+	return nil
+}
+
+// StopArray stops named MD array, members of the stopped array keep their superblocks
+func (m *MDADM) StopArray(name string) error {
+	cmd := fmt.Sprintf(MDADMStopCmdTmpl, filepath.Join(m.mdDir, name))
+	if _, stderr, err := m.e.RunCmd(cmd); err != nil {
+		m.log.WithField("method", "StopArray").Errorf("%s failed, stderr: %s, error: %v", cmd, stderr, err)
+		return err
+	}
+	return nil
+}
+
+// ZeroSuperblock removes MD superblock from the device, so it isn't assembled into the array anymore
+func (m *MDADM) ZeroSuperblock(device string) error {
+	cmd := fmt.Sprintf(MDADMZeroSuperblockCmdTmpl, device)
+	if _, stderr, err := m.e.RunCmd(cmd); err != nil {
+		m.log.WithField("method", "ZeroSuperblock").Errorf("%s failed, stderr: %s, error: %v", cmd, stderr, err)
+		return err
+	}
+	return nil
+}
+
+// GetArray resolves named array to the kernel name and returns its state from /proc/mdstat
+func (m *MDADM) GetArray(name string) (*Array, error) {
+	device, err := filepath.EvalSymlinks(filepath.Join(m.mdDir, name))
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve array %s: %v", name, err)
+	}
+
+	data, err := ioutil.ReadFile(m.mdstat)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %v", m.mdstat, err)
+	}
+
+	for _, array := range parseMDStat(string(data)) {
+		if array.Name == filepath.Base(device) {
+			return array, nil
+		}
+	}
+	return nil, fmt.Errorf("array %s (%s) isn't found in %s", name, device, m.mdstat)
+}
+
+// parseMDStat parses content of /proc/mdstat, each array is described by the header line
+// followed by the indented lines with its status
+func parseMDStat(data string) []*Array {
+	var (
+		arrays  []*Array
+		current *Array
+		scanner = bufio.NewScanner(strings.NewReader(data))
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if matches := arrayRegexp.FindStringSubmatch(line); matches != nil {
+			current = parseArrayLine(matches[1], matches[2], matches[3])
+			arrays = append(arrays, current)
+			continue
+		}
+		if current == nil || !strings.HasPrefix(line, " ") {
+			current = nil
+			continue
+		}
+		if matches := disksRegexp.FindStringSubmatch(line); matches != nil {
+			current.RaidDisks, _ = strconv.Atoi(matches[1])
+			current.ActiveDisks, _ = strconv.Atoi(matches[2])
+		}
+		if recoveryRegexp.MatchString(line) {
+			current.Recovering = true
+		}
+	}
+	return arrays
+}
+
+// parseArrayLine parses rest of the header line such as "(auto-read-only) raid1 sdc[1] sdb[0](F)"
+func parseArrayLine(name, state, rest string) *Array {
+	array := &Array{Name: name, State: state}
+	for _, field := range strings.Fields(rest) {
+		switch {
+		case strings.HasPrefix(field, "("):
+			// read-only flags of the array
+		case strings.Contains(field, "["):
+			device := field[:strings.Index(field, "[")]
+			if strings.HasSuffix(field, "(F)") {
+				array.FailedDevices = append(array.FailedDevices, device)
+			} else {
+				array.Devices = append(array.Devices, device)
+			}
+		default:
+			array.Level = field
+		}
+	}
+	return array
+}
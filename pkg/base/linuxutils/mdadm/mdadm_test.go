@@ -0,0 +1,151 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mdadm
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+var testLogger = logrus.New()
+
+const testMDStat = `Personalities : [raid1] [raid10]
+md126 : active raid10 sde[3] sdd[2] sdc[1](F) sdb[0]
+      2093056 blocks super 1.2 512K chunks 2 near-copies [4/3] [UU_U]
+      [=>...................]  recovery =  8.6% (90112/1046528) finish=0.5min speed=30037K/sec
+
+md127 : active (auto-read-only) raid1 sdg[1] sdf[0]
+      1046528 blocks super 1.2 [2/2] [UU]
+
+md125 : inactive sdh[0](S)
+      1046528 blocks super 1.2
+
+unused devices: <none>
+`
+
+func TestMDADM_CreateArray(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	m := NewMDADM(e, testLogger)
+
+	cmd := fmt.Sprintf(MDADMCreateCmdTmpl, "/dev/md/vol", "1", 2, "/dev/sdb /dev/sdc")
+	e.On("RunCmd", cmd).Return("", "", nil).Once()
+	assert.Nil(t, m.CreateArray("vol", "1", []string{"/dev/sdb", "/dev/sdc"}))
+
+	e.On("RunCmd", cmd).Return("", "error", errors.New("error")).Once()
+	assert.NotNil(t, m.CreateArray("vol", "1", []string{"/dev/sdb", "/dev/sdc"}))
+}
+
+func TestMDADM_AssembleArray(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	m := NewMDADM(e, testLogger)
+
+	cmd := fmt.Sprintf(MDADMAssembleCmdTmpl, "/dev/md/vol", "/dev/sdb /dev/sdc")
+	e.On("RunCmd", cmd).Return("", "", nil).Once()
+	assert.Nil(t, m.AssembleArray("vol", []string{"/dev/sdb", "/dev/sdc"}))
+
+	e.On("RunCmd", cmd).Return("", "error", errors.New("error")).Once()
+	assert.NotNil(t, m.AssembleArray("vol", []string{"/dev/sdb", "/dev/sdc"}))
+}
+
+func TestMDADM_StopArrayAndZeroSuperblock(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	m := NewMDADM(e, testLogger)
+
+	e.On("RunCmd", fmt.Sprintf(MDADMStopCmdTmpl, "/dev/md/vol")).Return("", "", nil)
+	e.On("RunCmd", fmt.Sprintf(MDADMZeroSuperblockCmdTmpl, "/dev/sdb")).Return("", "", nil)
+	e.On("RunCmd", fmt.Sprintf(MDADMZeroSuperblockCmdTmpl, "/dev/sdc")).Return("", "error", errors.New("error"))
+
+	assert.Nil(t, m.StopArray("vol"))
+	assert.Nil(t, m.ZeroSuperblock("/dev/sdb"))
+	assert.NotNil(t, m.ZeroSuperblock("/dev/sdc"))
+}
+
+func TestMDADM_GetArray(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mdadm")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	m := NewMDADM(&mocks.GoMockExecutor{}, testLogger)
+	m.mdDir = filepath.Join(dir, "md")
+	m.mdstat = filepath.Join(dir, "mdstat")
+	assert.Nil(t, os.Mkdir(m.mdDir, 0755))
+	assert.Nil(t, os.Symlink("../md126", filepath.Join(m.mdDir, "vol")))
+	assert.Nil(t, os.Symlink("../md124", filepath.Join(m.mdDir, "missed")))
+	for _, dev := range []string{"md126", "md124"} {
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, dev), nil, 0644))
+	}
+
+	// mdstat doesn't exist
+	_, err = m.GetArray("vol")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, ioutil.WriteFile(m.mdstat, []byte(testMDStat), 0644))
+	array, err := m.GetArray("vol")
+	assert.Nil(t, err)
+	assert.Equal(t, "md126", array.Name)
+	assert.Equal(t, "raid10", array.Level)
+	assert.True(t, array.IsDegraded())
+
+	// array isn't in mdstat
+	_, err = m.GetArray("missed")
+	assert.NotNil(t, err)
+
+	// symlink doesn't exist
+	_, err = m.GetArray("unknown")
+	assert.NotNil(t, err)
+}
+
+func Test_parseMDStat(t *testing.T) {
+	arrays := parseMDStat(testMDStat)
+	assert.Len(t, arrays, 3)
+
+	assert.Equal(t, &Array{
+		Name:          "md126",
+		State:         ArrayStateActive,
+		Level:         "raid10",
+		Devices:       []string{"sde", "sdd", "sdb"},
+		FailedDevices: []string{"sdc"},
+		RaidDisks:     4,
+		ActiveDisks:   3,
+		Recovering:    true,
+	}, arrays[0])
+
+	assert.Equal(t, &Array{
+		Name:        "md127",
+		State:       ArrayStateActive,
+		Level:       "raid1",
+		Devices:     []string{"sdg", "sdf"},
+		RaidDisks:   2,
+		ActiveDisks: 2,
+	}, arrays[1])
+	assert.False(t, arrays[1].IsDegraded())
+
+	assert.Equal(t, "inactive", arrays[2].State)
+	assert.Equal(t, "", arrays[2].Level)
+	assert.Equal(t, []string{"sdh"}, arrays[2].Devices)
+
+	assert.Len(t, parseMDStat(""), 0)
+}
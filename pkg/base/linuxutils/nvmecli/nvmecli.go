@@ -22,6 +22,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 )
@@ -37,6 +38,8 @@ const (
 	NVMeVendorCmdImpl = NVMCliCmdImpl + " id-ctrl %s --output-format=json"
 	//DevicesKey is the key to find NVMe devices in nvme json output
 	DevicesKey = "Devices"
+	//kelvinOffset is used to convert temperature reported by smart-log from Kelvin to Celsius
+	kelvinOffset = 273
 )
 
 //WrapNvmecli is an interface that encapsulates operation with system nvme util
@@ -54,11 +57,34 @@ type NVMDevice struct {
 	//Can VID be string for nvme?
 	Vendor int `json:"vid,omitempty"`
 	Health string
+	SMART  *api.SMARTInfo `json:"-"`
 }
 
 //SMARTLog represents SMART information for NVMe devices
 type SMARTLog struct {
-	CriticalWarning int `json:"critical_warning,omitempty"`
+	CriticalWarning int   `json:"critical_warning,omitempty"`
+	Temperature     int64 `json:"temperature,omitempty"`
+	PercentUsed     int64 `json:"percent_used,omitempty"`
+	// newer nvme-cli versions use percentage_used key
+	PercentageUsed int64 `json:"percentage_used,omitempty"`
+	PowerOnHours   int64 `json:"power_on_hours,omitempty"`
+	MediaErrors    int64 `json:"media_errors,omitempty"`
+}
+
+//GetSMARTInfo converts SMARTLog to api.SMARTInfo
+func (s *SMARTLog) GetSMARTInfo() *api.SMARTInfo {
+	info := &api.SMARTInfo{
+		MediaErrors:    s.MediaErrors,
+		PowerOnHours:   s.PowerOnHours,
+		PercentageUsed: s.PercentUsed,
+	}
+	if s.PercentageUsed > info.PercentageUsed {
+		info.PercentageUsed = s.PercentageUsed
+	}
+	if s.Temperature > kelvinOffset {
+		info.Temperature = s.Temperature - kelvinOffset
+	}
+	return info
 }
 
 //NVMECLI is a wrap for system nvem_cli util
@@ -93,7 +119,14 @@ func (na *NVMECLI) GetNVMDevices() ([]NVMDevice, error) {
 		return nil, fmt.Errorf("unexpected nvme list output format")
 	}
 	for i, d := range devs {
-		devs[i].Health = na.getNVMDeviceHealth(d.DevicePath)
+		smartLog, err := na.getSMARTLog(d.DevicePath)
+		if err != nil {
+			ll.Errorf("%v, set health as %s", err, apiV1.HealthUnknown)
+			devs[i].Health = apiV1.HealthUnknown
+		} else {
+			devs[i].Health = na.getHealthFromSMARTLog(smartLog)
+			devs[i].SMART = smartLog.GetSMARTInfo()
+		}
 		na.fillNVMDeviceVendor(&devs[i])
 	}
 	return devs, nil
@@ -102,18 +135,31 @@ func (na *NVMECLI) GetNVMDevices() ([]NVMDevice, error) {
 //getNVMDeviceHealth gets information about device health based on critical_warning SMART attribute using nvme_cli smart-log util
 func (na *NVMECLI) getNVMDeviceHealth(path string) string {
 	ll := na.log.WithField("method", "getNVMDeviceHealth")
+	smartLog, err := na.getSMARTLog(path)
+	if err != nil {
+		ll.Errorf("%v, set health as %s", err, apiV1.HealthUnknown)
+		return apiV1.HealthUnknown
+	}
+	return na.getHealthFromSMARTLog(smartLog)
+}
+
+//getSMARTLog reads SMART log of NVMe device using nvme_cli smart-log util
+func (na *NVMECLI) getSMARTLog(path string) (*SMARTLog, error) {
 	cmd := fmt.Sprintf(NVMeHealthCmdImpl, path)
 	strOut, _, err := na.e.RunCmd(cmd)
 	if err != nil {
-		ll.Errorf("%s failed, set health as %s", cmd, apiV1.HealthUnknown)
-		return apiV1.HealthUnknown
+		return nil, fmt.Errorf("%s failed: %v", cmd, err)
 	}
 	smartLog := &SMARTLog{}
 	err = json.Unmarshal([]byte(strOut), &smartLog)
 	if err != nil {
-		ll.Errorf("unable to unmarshal output to SMARTLog, set health as %s", apiV1.HealthUnknown)
-		return apiV1.HealthUnknown
+		return nil, fmt.Errorf("unable to unmarshal output to SMARTLog: %v", err)
 	}
+	return smartLog, nil
+}
+
+//getHealthFromSMARTLog converts critical_warning SMART attribute to health
+func (na *NVMECLI) getHealthFromSMARTLog(smartLog *SMARTLog) string {
 	health := smartLog.CriticalWarning
 	if na.isOneOfBitsSet(uint64(health), 0, 3) {
 		return apiV1.HealthSuspect
@@ -23,6 +23,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
+	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/pkg/mocks"
 )
@@ -55,8 +56,10 @@ func TestNVMECLI_GetNVMDevicesSuccess(t *testing.T) {
  		"temperature" : 302,
   		"avail_spare" : 100,
   		"spare_thresh" : 10,
-  		"percent_used" : 0,
-  		"data_units_read" : 97704077
+  		"percent_used" : 7,
+  		"data_units_read" : 97704077,
+  		"power_on_hours" : 1520,
+  		"media_errors" : 2
 	}
 `
 	vendor := `{
@@ -88,6 +91,32 @@ func TestNVMECLI_GetNVMDevicesSuccess(t *testing.T) {
 	assert.Equal(t, "Dell Express Flash NVMe P4510 4TB SFF", devices[0].ModelNumber)
 	assert.Equal(t, apiV1.HealthGood, devices[0].Health)
 	assert.Equal(t, 32902, devices[0].Vendor)
+	assert.Equal(t, &api.SMARTInfo{
+		MediaErrors:    2,
+		Temperature:    29,
+		PowerOnHours:   1520,
+		PercentageUsed: 7,
+	}, devices[0].SMART)
+}
+
+func TestNVMECLI_GetNVMDevicesSMARTLogFail(t *testing.T) {
+	output := `{"Devices" : [{"DevicePath" : "/dev/nvme9n1", "SerialNumber" : "PHLJ9135027L4P0DGN"}]}`
+	e := &mocks.GoMockExecutor{}
+	l := NewNVMECLI(e, testLogger)
+
+	e.On("RunCmd", NVMeDeviceCmdImpl).Return(output, "", nil)
+	e.On("RunCmd", fmt.Sprintf(NVMeHealthCmdImpl, "/dev/nvme9n1")).Return("", "", fmt.Errorf("error"))
+	e.On("RunCmd", fmt.Sprintf(NVMeVendorCmdImpl, "/dev/nvme9n1")).Return("", "", fmt.Errorf("error"))
+	devices, err := l.GetNVMDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(devices))
+	assert.Equal(t, apiV1.HealthUnknown, devices[0].Health)
+	assert.Nil(t, devices[0].SMART)
+}
+
+func TestSMARTLog_GetSMARTInfo(t *testing.T) {
+	smartLog := &SMARTLog{PercentageUsed: 12}
+	assert.Equal(t, &api.SMARTInfo{PercentageUsed: 12}, smartLog.GetSMARTInfo())
 }
 
 func TestNVMECLI_GetNVMDevicesFails(t *testing.T) {
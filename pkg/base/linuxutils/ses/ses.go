@@ -0,0 +1,300 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ses contains code for mapping block devices to slots of enclosures through SCSI enclosure services
+package ses
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+)
+
+const (
+	// DefaultSysfsRoot is the path where sysfs is mounted
+	DefaultSysfsRoot = "/sys"
+	// SgSesAESCmdTmpl is a CMD to read Additional Element Status diagnostic page of the enclosure
+	SgSesAESCmdTmpl = "sg_ses --page=aes %s"
+	// SgSesSetCmdTmpl is a CMD to set or clear element of the enclosure slot with provided device slot number
+	SgSesSetCmdTmpl = "sg_ses --dev-slot-num=%s --%s=%s %s"
+
+	// enclosureClass contains enclosures registered by ses kernel module, each of them has directory per component
+	enclosureClass = "class/enclosure"
+	// scsiGenericClass contains SCSI generic devices, sg_ses works with them
+	scsiGenericClass = "class/scsi_generic"
+	// scsiTypeEnclosure is a SCSI peripheral device type of enclosure services device
+	scsiTypeEnclosure = "13"
+	// identElement and faultElement are sg_ses acronyms of locate and failure LEDs of the slot
+	identElement = "ident"
+	faultElement = "fault"
+)
+
+var (
+	slotNumberRegexp = regexp.MustCompile(`device slot number:\s*(\d+)`)
+	sasAddressRegexp = regexp.MustCompile(`^\s*SAS address:\s*(0x[0-9a-fA-F]+)`)
+)
+
+// Location represents position of the drive in the enclosure
+type Location struct {
+	Enclosure string
+	Slot      string
+	Bay       string
+}
+
+// WrapSES is an interface that encapsulates discovering of drive locations in enclosures
+type WrapSES interface {
+	GetLocations() (map[string]*Location, error)
+	SetLEDState(device, state string) error
+}
+
+// SES reads drive locations from sysfs enclosure class or with sg_ses util
+type SES struct {
+	e    command.CmdExecutor
+	root string
+	log  *logrus.Entry
+}
+
+// NewSES is a constructor for SES
+func NewSES(e command.CmdExecutor, logger *logrus.Logger) *SES {
+	return &SES{
+		e:    e,
+		root: DefaultSysfsRoot,
+		log:  logger.WithField("component", "SES"),
+	}
+}
+
+// GetLocations returns locations of block devices in enclosures, key is a name of block device such as sda or nvme0n1
+// Enclosures which are registered in sysfs by ses kernel module are used, sg_ses util is used when there are no them
+func (s *SES) GetLocations() (map[string]*Location, error) {
+	ll := s.log.WithField("method", "GetLocations")
+	locations, err := s.getLocationsFromSysfs()
+	if err != nil {
+		ll.Warnf("Unable to read enclosures from sysfs: %v", err)
+	}
+	if len(locations) > 0 {
+		return locations, nil
+	}
+	return s.getLocationsFromSgSes()
+}
+
+// SetLEDState sets LED of the slot which contains block device (such as sda) to the state (one of apiV1.LEDState*)
+// using sg_ses util, it is used for enclosures which aren't supported by ledctl
+func (s *SES) SetLEDState(device, state string) error {
+	ll := s.log.WithField("method", "SetLEDState")
+	var actions [][2]string
+	switch state {
+	case apiV1.LEDStateOff:
+		actions = [][2]string{{"clear", identElement}, {"clear", faultElement}}
+	case apiV1.LEDStateLocate:
+		actions = [][2]string{{"set", identElement}}
+	case apiV1.LEDStateFailure:
+		actions = [][2]string{{"set", faultElement}}
+	default:
+		return fmt.Errorf("unsupported LED state %s", state)
+	}
+
+	enclosure, slot, err := s.findSlot(device)
+	if err != nil {
+		return err
+	}
+	for _, action := range actions {
+		cmd := fmt.Sprintf(SgSesSetCmdTmpl, slot, action[0], action[1], filepath.Join("/dev", enclosure))
+		if _, stderr, err := s.e.RunCmd(cmd); err != nil {
+			ll.Errorf("%s failed, stderr: %s, error: %v", cmd, stderr, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// findSlot returns SCSI generic device of the enclosure and number of the slot which contains block device
+func (s *SES) findSlot(device string) (string, string, error) {
+	ll := s.log.WithField("method", "findSlot")
+	address := strings.ToLower(readAttribute(filepath.Join(s.root, "block", device, "device", "sas_address")))
+	if address == "" {
+		return "", "", fmt.Errorf("unable to read SAS address of device %s", device)
+	}
+	for _, enclosure := range s.getEnclosureSgDevices() {
+		cmd := fmt.Sprintf(SgSesAESCmdTmpl, filepath.Join("/dev", enclosure))
+		stdout, stderr, err := s.e.RunCmd(cmd)
+		if err != nil {
+			ll.Errorf("%s failed, stderr: %s, error: %v", cmd, stderr, err)
+			continue
+		}
+		if slot, ok := parseAES(stdout)[address]; ok {
+			return enclosure, slot, nil
+		}
+	}
+	return "", "", fmt.Errorf("device %s isn't found in enclosures", device)
+}
+
+// getLocationsFromSysfs reads /sys/class/enclosure/<enclosure>/<component>/device links
+func (s *SES) getLocationsFromSysfs() (map[string]*Location, error) {
+	locations := make(map[string]*Location)
+	enclosures, err := ioutil.ReadDir(filepath.Join(s.root, enclosureClass))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return locations, nil
+		}
+		return nil, err
+	}
+	for _, enclosure := range enclosures {
+		enclosurePath := filepath.Join(s.root, enclosureClass, enclosure.Name())
+		enclosureID := readAttribute(filepath.Join(enclosurePath, "id"))
+		if enclosureID == "" {
+			enclosureID = enclosure.Name()
+		}
+		components, err := ioutil.ReadDir(enclosurePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, component := range components {
+			componentPath := filepath.Join(enclosurePath, component.Name())
+			// component is a directory with device link when there is a drive in it
+			if _, err := os.Stat(filepath.Join(componentPath, "device")); err != nil {
+				continue
+			}
+			slot := readAttribute(filepath.Join(componentPath, "slot"))
+			if slot == "" {
+				slot = component.Name()
+			}
+			for _, device := range blockDevices(filepath.Join(componentPath, "device")) {
+				locations[device] = &Location{Enclosure: enclosureID, Slot: slot, Bay: component.Name()}
+			}
+		}
+	}
+	return locations, nil
+}
+
+// getLocationsFromSgSes reads Additional Element Status page of each enclosure with sg_ses and matches
+// SAS addresses of the slots with SAS addresses of block devices
+func (s *SES) getLocationsFromSgSes() (map[string]*Location, error) {
+	ll := s.log.WithField("method", "getLocationsFromSgSes")
+	locations := make(map[string]*Location)
+
+	addresses := s.getSASAddresses()
+	if len(addresses) == 0 {
+		return locations, nil
+	}
+	for _, enclosure := range s.getEnclosureSgDevices() {
+		cmd := fmt.Sprintf(SgSesAESCmdTmpl, filepath.Join("/dev", enclosure))
+		stdout, stderr, err := s.e.RunCmd(cmd)
+		if err != nil {
+			ll.Errorf("%s failed, stderr: %s, error: %v", cmd, stderr, err)
+			continue
+		}
+		enclosureID := enclosure
+		if target, err := filepath.EvalSymlinks(filepath.Join(s.root, scsiGenericClass, enclosure, "device")); err == nil {
+			enclosureID = filepath.Base(target)
+		}
+		for address, slot := range parseAES(stdout) {
+			if device, ok := addresses[address]; ok {
+				locations[device] = &Location{Enclosure: enclosureID, Slot: slot}
+			}
+		}
+	}
+	return locations, nil
+}
+
+// getEnclosureSgDevices returns names of SCSI generic devices of enclosures
+func (s *SES) getEnclosureSgDevices() []string {
+	devices := make([]string, 0)
+	sgDevices, err := ioutil.ReadDir(filepath.Join(s.root, scsiGenericClass))
+	if err != nil {
+		return devices
+	}
+	for _, sg := range sgDevices {
+		if readAttribute(filepath.Join(s.root, scsiGenericClass, sg.Name(), "device", "type")) == scsiTypeEnclosure {
+			devices = append(devices, sg.Name())
+		}
+	}
+	return devices
+}
+
+// getSASAddresses returns map of SAS address to block device name
+func (s *SES) getSASAddresses() map[string]string {
+	addresses := make(map[string]string)
+	devices, err := ioutil.ReadDir(filepath.Join(s.root, "block"))
+	if err != nil {
+		return addresses
+	}
+	for _, device := range devices {
+		address := readAttribute(filepath.Join(s.root, "block", device.Name(), "device", "sas_address"))
+		if address != "" {
+			addresses[strings.ToLower(address)] = device.Name()
+		}
+	}
+	return addresses
+}
+
+// parseAES parses output of sg_ses Additional Element Status page and returns map of SAS address to slot number
+func parseAES(output string) map[string]string {
+	slots := make(map[string]string)
+	slot := ""
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.Contains(line, "Element index:") {
+			slot = ""
+			continue
+		}
+		if match := slotNumberRegexp.FindStringSubmatch(line); match != nil {
+			slot = match[1]
+			continue
+		}
+		// attached SAS address belongs to the expander, it doesn't match the regexp
+		if match := sasAddressRegexp.FindStringSubmatch(line); match != nil && slot != "" {
+			slots[strings.ToLower(match[1])] = slot
+		}
+	}
+	return slots
+}
+
+// blockDevices returns names of block devices of the SCSI or NVMe device
+func blockDevices(devicePath string) []string {
+	devices := make([]string, 0)
+	// SCSI device has block/<name> directory
+	if entries, err := ioutil.ReadDir(filepath.Join(devicePath, "block")); err == nil {
+		for _, entry := range entries {
+			devices = append(devices, entry.Name())
+		}
+	}
+	// NVMe controller has nvme/<controller>/<namespace> directories
+	if matches, err := filepath.Glob(filepath.Join(devicePath, "nvme", "nvme*", "nvme*n*")); err == nil {
+		for _, match := range matches {
+			devices = append(devices, filepath.Base(match))
+		}
+	}
+	return devices
+}
+
+// readAttribute returns trimmed content of sysfs attribute or empty string if it can't be read
+func readAttribute(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ses
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+var testLogger = logrus.New()
+
+const testAES = `  HGST   H4060-J   3010
+  Primary enclosure logical identifier (hex): 5000cca04a000000
+Additional element status diagnostic page:
+  generation code: 0x0
+  additional element status descriptor list
+    Element type: Array device slot, subenclosure id: 0 [ti=0]
+      Element index: 0  eiioe=1
+        Transport protocol: SAS
+        number of phys: 1, not all phys: 0, device slot number: 0
+        phy index: 0
+          SAS device type: end device
+          attached SAS address: 0x5000cca04a00003f
+          SAS address: 0x5000CCA2531A2B2D
+          phy identifier: 0x0
+      Element index: 1  eiioe=1
+        Transport protocol: SAS
+        number of phys: 1, not all phys: 0, device slot number: 7
+        phy index: 0
+          SAS device type: end device
+          attached SAS address: 0x5000cca04a00003f
+          SAS address: 0x5000cca2531a2b99
+          phy identifier: 0x0
+      Element index: 2  eiioe=1
+        Transport protocol: SAS
+        number of phys: 1, not all phys: 0, device slot number: 8
+        phy index: 0
+          SAS device type: no device attached
+          attached SAS address: 0x5000cca04a00003f
+          SAS address: 0x0
+`
+
+func setupSESTest(t *testing.T) (*SES, *mocks.GoMockExecutor, string) {
+	root, err := ioutil.TempDir("", "ses")
+	assert.Nil(t, err)
+	e := &mocks.GoMockExecutor{}
+	s := NewSES(e, testLogger)
+	s.root = root
+	return s, e, root
+}
+
+func writeAttribute(t *testing.T, path, value string) {
+	assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.Nil(t, ioutil.WriteFile(path, []byte(value+"\n"), 0644))
+}
+
+func TestSES_GetLocationsSysfs(t *testing.T) {
+	s, _, root := setupSESTest(t)
+	defer os.RemoveAll(root)
+
+	// SAS drive in slot 7, NVMe drive in slot 2, empty slot 8
+	scsiDevice := filepath.Join(root, "devices/pci0000:00/host0/target0:0:1/0:0:1:0")
+	nvmeDevice := filepath.Join(root, "devices/pci0000:00/0000:00:01.0")
+	assert.Nil(t, os.MkdirAll(filepath.Join(scsiDevice, "block", "sdb"), 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(nvmeDevice, "nvme", "nvme0", "nvme0n1"), 0755))
+
+	enclosure := filepath.Join(root, enclosureClass, "0:0:8:0")
+	writeAttribute(t, filepath.Join(enclosure, "id"), "0x5000cca04a000000")
+	writeAttribute(t, filepath.Join(enclosure, "Slot07", "slot"), "7")
+	assert.Nil(t, os.Symlink(scsiDevice, filepath.Join(enclosure, "Slot07", "device")))
+	assert.Nil(t, os.MkdirAll(filepath.Join(enclosure, "Slot08"), 0755))
+	assert.Nil(t, os.MkdirAll(filepath.Join(enclosure, "Disk02"), 0755))
+	assert.Nil(t, os.Symlink(nvmeDevice, filepath.Join(enclosure, "Disk02", "device")))
+
+	locations, err := s.GetLocations()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*Location{
+		"sdb":     {Enclosure: "0x5000cca04a000000", Slot: "7", Bay: "Slot07"},
+		"nvme0n1": {Enclosure: "0x5000cca04a000000", Slot: "Disk02", Bay: "Disk02"},
+	}, locations)
+}
+
+func TestSES_GetLocationsSgSes(t *testing.T) {
+	s, e, root := setupSESTest(t)
+	defer os.RemoveAll(root)
+
+	writeAttribute(t, filepath.Join(root, "block", "sda", "device", "sas_address"), "0x5000cca2531a2b2d")
+	writeAttribute(t, filepath.Join(root, "block", "sdb", "device", "sas_address"), "0x5000cca2531a2b99")
+	writeAttribute(t, filepath.Join(root, "block", "sdc", "device", "sas_address"), "0x5000cca2531a2bff")
+	enclosureDevice := filepath.Join(root, "devices", "0:0:8:0")
+	writeAttribute(t, filepath.Join(enclosureDevice, "type"), scsiTypeEnclosure)
+	assert.Nil(t, os.MkdirAll(filepath.Join(root, scsiGenericClass, "sg3"), 0755))
+	assert.Nil(t, os.Symlink(enclosureDevice, filepath.Join(root, scsiGenericClass, "sg3", "device")))
+	writeAttribute(t, filepath.Join(root, scsiGenericClass, "sg1", "device", "type"), "0")
+
+	e.On("RunCmd", fmt.Sprintf(SgSesAESCmdTmpl, "/dev/sg3")).Return(testAES, "", nil)
+	locations, err := s.GetLocations()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]*Location{
+		"sda": {Enclosure: "0:0:8:0", Slot: "0"},
+		"sdb": {Enclosure: "0:0:8:0", Slot: "7"},
+	}, locations)
+}
+
+func TestSES_GetLocationsSgSesFail(t *testing.T) {
+	s, e, root := setupSESTest(t)
+	defer os.RemoveAll(root)
+
+	writeAttribute(t, filepath.Join(root, "block", "sda", "device", "sas_address"), "0x5000cca2531a2b2d")
+	writeAttribute(t, filepath.Join(root, scsiGenericClass, "sg3", "device", "type"), scsiTypeEnclosure)
+	e.On("RunCmd", fmt.Sprintf(SgSesAESCmdTmpl, "/dev/sg3")).Return("", "error", fmt.Errorf("error"))
+
+	locations, err := s.GetLocations()
+	assert.Nil(t, err)
+	assert.Empty(t, locations)
+}
+
+func TestSES_GetLocationsNoEnclosures(t *testing.T) {
+	s, _, root := setupSESTest(t)
+	defer os.RemoveAll(root)
+
+	locations, err := s.GetLocations()
+	assert.Nil(t, err)
+	assert.Empty(t, locations)
+}
+
+func TestSES_SetLEDState(t *testing.T) {
+	s, e, root := setupSESTest(t)
+	defer os.RemoveAll(root)
+
+	writeAttribute(t, filepath.Join(root, "block", "sdb", "device", "sas_address"), "0x5000cca2531a2b99")
+	writeAttribute(t, filepath.Join(root, "block", "sdc", "device", "sas_address"), "0x5000cca2531a2bff")
+	writeAttribute(t, filepath.Join(root, scsiGenericClass, "sg3", "device", "type"), scsiTypeEnclosure)
+	e.On("RunCmd", fmt.Sprintf(SgSesAESCmdTmpl, "/dev/sg3")).Return(testAES, "", nil)
+	e.On("RunCmd", fmt.Sprintf(SgSesSetCmdTmpl, "7", "set", identElement, "/dev/sg3")).Return("", "", nil).Once()
+	e.On("RunCmd", fmt.Sprintf(SgSesSetCmdTmpl, "7", "clear", identElement, "/dev/sg3")).Return("", "", nil).Once()
+	e.On("RunCmd", fmt.Sprintf(SgSesSetCmdTmpl, "7", "clear", faultElement, "/dev/sg3")).
+		Return("", "error", fmt.Errorf("error")).Once()
+
+	assert.Nil(t, s.SetLEDState("sdb", apiV1.LEDStateLocate))
+	assert.NotNil(t, s.SetLEDState("sdb", apiV1.LEDStateOff))
+	assert.NotNil(t, s.SetLEDState("sdb", "unknown"))
+	// device isn't in the enclosure or has no SAS address
+	assert.NotNil(t, s.SetLEDState("sdc", apiV1.LEDStateLocate))
+	assert.NotNil(t, s.SetLEDState("nvme0n1", apiV1.LEDStateLocate))
+	e.AssertExpectations(t)
+}
@@ -19,7 +19,9 @@ package smartctl
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 
+	api "github.com/dell/csi-baremetal/api/generated/v1"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 )
 
@@ -30,11 +32,50 @@ const (
 	SmartctlDeviceInfoCmdImpl = SmartctlCmdImpl + " --info --json %s"
 	//SmartctlHealthCmdImpl is a CMD to get  SMART status of device in JSON format
 	SmartctlHealthCmdImpl = SmartctlCmdImpl + " --health --json %s"
+	//SmartctlAttributesCmdImpl is a CMD to get SMART attributes (ATA) or error counters (SCSI) of device in JSON format
+	SmartctlAttributesCmdImpl = SmartctlCmdImpl + " --attributes --log=error --json %s"
+	//SmartctlRAIDDeviceTmpl is used instead of device path in smartctl commands to address physical drive behind
+	//RAID controller, it contains controller type, device ID of the drive and path of any device of the controller
+	SmartctlRAIDDeviceTmpl = "--device=%s,%d %s"
+
+	//RAIDControllerMegaRAID is a type of LSI MegaRAID and Dell PERC controllers
+	RAIDControllerMegaRAID = "megaraid"
+	//RAIDControllerCCISS is a type of HP Smart Array controllers
+	RAIDControllerCCISS = "cciss"
+
+	// ATA SMART attributes IDs
+	reallocatedSectorsID = 5
+	reportedUncorrectID  = 187
+	pendingSectorsID     = 197
+	offlineUncorrectID   = 198
+	// ATA wear-level attributes IDs, normalized value of them shows percentage of SSD life left
+	wearLevelingCountID     = 177
+	percentLifetimeRemainID = 202
+	ssdLifeLeftID           = 231
+	mediaWearoutIndicatorID = 233
+
+	// bits of smartctl exit status which mean that command failed, other bits describe state of the device
+	exitStatusFailureMask = 0x3
 )
 
+// raidControllerRegexp matches smartctl hint which is printed when device is behind RAID controller
+var raidControllerRegexp = regexp.MustCompile(`-d (` + RAIDControllerMegaRAID + `|` + RAIDControllerCCISS + `),N`)
+
 //WrapSmartctl is an interface that encapsulates operation with system smartctl util
 type WrapSmartctl interface {
 	GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error)
+	GetRAIDDriveInfo(path, controllerType string, deviceID int) (*DeviceSMARTInfo, error)
+}
+
+//RAIDControllerError is returned by GetDriveInfoByPath when device is behind RAID controller and SMART information
+//of the physical drive should be read with GetRAIDDriveInfo
+type RAIDControllerError struct {
+	ControllerType string
+}
+
+//Error returns message of RAIDControllerError
+func (e *RAIDControllerError) Error() string {
+	return fmt.Sprintf("device is behind %s RAID controller", e.ControllerType)
 }
 
 //DeviceSMARTInfo represents SMART information about device
@@ -42,6 +83,44 @@ type DeviceSMARTInfo struct {
 	SerialNumber string          `json:"serial_number"`
 	SmartStatus  map[string]bool `json:"smart_status"`
 	Rotation     int             `json:"rotation_rate"`
+	Vendor       string          `json:"vendor"`
+	ModelName    string          `json:"model_name"`
+
+	Smartctl struct {
+		ExitStatus int `json:"exit_status"`
+		Messages   []struct {
+			String string `json:"string"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	Temperature struct {
+		Current int64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours int64 `json:"hours"`
+	} `json:"power_on_time"`
+	// ATA devices
+	ATAAttributes struct {
+		Table []ATAAttribute `json:"table"`
+	} `json:"ata_smart_attributes"`
+	// SCSI devices
+	SCSIGrownDefectList int64                       `json:"scsi_grown_defect_list"`
+	SCSIErrorCounterLog map[string]SCSIErrorCounter `json:"scsi_error_counter_log"`
+	SCSIPercentageUsed  *int64                      `json:"scsi_percentage_used_endurance_indicator"`
+}
+
+//ATAAttribute represents row of ATA SMART attributes table
+type ATAAttribute struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Value int64  `json:"value"`
+	Raw   struct {
+		Value int64 `json:"value"`
+	} `json:"raw"`
+}
+
+//SCSIErrorCounter represents read, write or verify errors counter of SCSI device
+type SCSIErrorCounter struct {
+	TotalUncorrectedErrors int64 `json:"total_uncorrected_errors"`
 }
 
 //SMARTCTL is a wrap for system smartctl util
@@ -58,6 +137,9 @@ func NewSMARTCTL(e command.CmdExecutor) *SMARTCTL {
 func (sa *SMARTCTL) GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error) {
 	strOut, _, err := sa.e.RunCmd(fmt.Sprintf(SmartctlDeviceInfoCmdImpl, path))
 	if err != nil {
+		if controllerType := getRAIDControllerType(strOut); controllerType != "" {
+			return nil, &RAIDControllerError{ControllerType: controllerType}
+		}
 		return nil, err
 	}
 	var deviceInfo = &DeviceSMARTInfo{}
@@ -70,9 +152,33 @@ func (sa *SMARTCTL) GetDriveInfoByPath(path string) (*DeviceSMARTInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to get SMART status for device %s, error: %v", path, err)
 	}
+	err = sa.fillSmartAttributes(deviceInfo, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get SMART attributes for device %s, error: %v", path, err)
+	}
 	return deviceInfo, nil
 }
 
+//GetRAIDDriveInfo gets SMART information about physical drive with deviceID behind RAID controller of controllerType,
+//path is a path of any block device of this controller
+func (sa *SMARTCTL) GetRAIDDriveInfo(path, controllerType string, deviceID int) (*DeviceSMARTInfo, error) {
+	return sa.GetDriveInfoByPath(fmt.Sprintf(SmartctlRAIDDeviceTmpl, controllerType, deviceID, path))
+}
+
+//getRAIDControllerType returns type of RAID controller from smartctl JSON output or empty string if there is no hint
+func getRAIDControllerType(output string) string {
+	deviceInfo := &DeviceSMARTInfo{}
+	if err := json.Unmarshal([]byte(output), deviceInfo); err != nil {
+		return ""
+	}
+	for _, message := range deviceInfo.Smartctl.Messages {
+		if match := raidControllerRegexp.FindStringSubmatch(message.String); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
 //fillSmartStatus fill smart_status field in DeviceSMARTInfo using smartctl command
 func (sa *SMARTCTL) fillSmartStatus(dev *DeviceSMARTInfo, path string) error {
 	strOut, _, err := sa.e.RunCmd(fmt.Sprintf(SmartctlHealthCmdImpl, path))
@@ -86,3 +192,80 @@ func (sa *SMARTCTL) fillSmartStatus(dev *DeviceSMARTInfo, path string) error {
 	}
 	return nil
 }
+
+//fillSmartAttributes fills ATA SMART attributes or SCSI error counters, temperature and power-on time in DeviceSMARTInfo
+//smartctl exits with non zero status if device is failing or attributes are exceeded thresholds, output is used
+//in this case and error is returned only if smartctl wasn't able to read the device
+func (sa *SMARTCTL) fillSmartAttributes(dev *DeviceSMARTInfo, path string) error {
+	strOut, _, err := sa.e.RunCmd(fmt.Sprintf(SmartctlAttributesCmdImpl, path))
+	if err != nil && strOut == "" {
+		return err
+	}
+	if unmarshalErr := json.Unmarshal([]byte(strOut), dev); unmarshalErr != nil {
+		return fmt.Errorf("unable to unmarshal output to DeviceSMARTInfo instance, error: %v", unmarshalErr)
+	}
+	if err != nil && dev.Smartctl.ExitStatus&exitStatusFailureMask != 0 {
+		return err
+	}
+	return nil
+}
+
+//GetSMARTInfo converts SMART attributes of ATA or SCSI device into api.SMARTInfo
+func (dev *DeviceSMARTInfo) GetSMARTInfo() *api.SMARTInfo {
+	info := &api.SMARTInfo{
+		Temperature:  dev.Temperature.Current,
+		PowerOnHours: dev.PowerOnTime.Hours,
+	}
+	if len(dev.ATAAttributes.Table) > 0 {
+		for _, attr := range dev.ATAAttributes.Table {
+			switch attr.ID {
+			case reallocatedSectorsID:
+				info.ReallocatedSectors = attr.Raw.Value
+			case pendingSectorsID:
+				info.PendingSectors = attr.Raw.Value
+			case reportedUncorrectID, offlineUncorrectID:
+				info.MediaErrors += attr.Raw.Value
+			}
+		}
+		if endurance, ok := dev.GetEndurance(); ok {
+			info.PercentageUsed = 100 - endurance
+		}
+		return info
+	}
+	info.ReallocatedSectors = dev.SCSIGrownDefectList
+	if dev.SCSIPercentageUsed != nil {
+		info.PercentageUsed = *dev.SCSIPercentageUsed
+	}
+	for _, counter := range dev.SCSIErrorCounterLog {
+		info.MediaErrors += counter.TotalUncorrectedErrors
+	}
+	return info
+}
+
+//GetEndurance returns percentage of SSD life left based on wear-level attributes of ATA device or percentage used
+//endurance indicator of SCSI device. Second value is false if device doesn't report any of them
+func (dev *DeviceSMARTInfo) GetEndurance() (int64, bool) {
+	if dev.SCSIPercentageUsed != nil {
+		return clampPercentage(100 - *dev.SCSIPercentageUsed), true
+	}
+	// attributes are listed in order of preference
+	for _, id := range []int{mediaWearoutIndicatorID, ssdLifeLeftID, percentLifetimeRemainID, wearLevelingCountID} {
+		for _, attr := range dev.ATAAttributes.Table {
+			if attr.ID == id {
+				return clampPercentage(attr.Value), true
+			}
+		}
+	}
+	return 0, false
+}
+
+//clampPercentage limits value to [0, 100] range
+func clampPercentage(value int64) int64 {
+	if value < 0 {
+		return 0
+	}
+	if value > 100 {
+		return 100
+	}
+	return value
+}
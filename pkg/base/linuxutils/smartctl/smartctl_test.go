@@ -17,11 +17,13 @@ limitations under the License.
 package smartctl
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	api "github.com/dell/csi-baremetal/api/generated/v1"
 	"github.com/dell/csi-baremetal/pkg/mocks"
 )
 
@@ -38,19 +40,42 @@ func TestSMARCTL_GetDriveInfoByPath(t *testing.T) {
     "smart_status": {
         "passed": true
     }}`
+	outputAttributes := `{
+    "smartctl": {"exit_status": 0},
+    "ata_smart_attributes": {
+        "table": [
+            {"id": 5, "name": "Reallocated_Sector_Ct", "value": 100, "raw": {"value": 8}},
+            {"id": 9, "name": "Power_On_Hours", "value": 97, "raw": {"value": 2785}},
+            {"id": 187, "name": "Reported_Uncorrect", "value": 100, "raw": {"value": 1}},
+            {"id": 197, "name": "Current_Pending_Sector", "value": 100, "raw": {"value": 2}},
+            {"id": 198, "name": "Offline_Uncorrectable", "value": 100, "raw": {"value": 3}}
+        ]
+    },
+    "power_on_time": {"hours": 2785},
+    "temperature": {"current": 31}
+    }`
 	cmd := fmt.Sprintf(SmartctlDeviceInfoCmdImpl, "/dev/sdd")
 	cmdHealth := fmt.Sprintf(SmartctlHealthCmdImpl, "/dev/sdd")
+	cmdAttributes := fmt.Sprintf(SmartctlAttributesCmdImpl, "/dev/sdd")
 	e := &mocks.GoMockExecutor{}
 	l := NewSMARTCTL(e)
 
 	e.On("RunCmd", cmd).Return(output, "", nil)
 	e.On("RunCmd", cmdHealth).Return(outputHealth, "", nil)
+	e.On("RunCmd", cmdAttributes).Return(outputAttributes, "", nil)
 	smartInfo, err := l.GetDriveInfoByPath("/dev/sdd")
 	assert.Nil(t, err)
 
 	assert.Equal(t, smartInfo.SerialNumber, "29P4K65PF9NF")
 	assert.Equal(t, smartInfo.Rotation, 7200)
 	assert.Equal(t, smartInfo.SmartStatus, map[string]bool{"passed": true})
+	assert.Equal(t, &api.SMARTInfo{
+		ReallocatedSectors: 8,
+		PendingSectors:     2,
+		MediaErrors:        4,
+		Temperature:        31,
+		PowerOnHours:       2785,
+	}, smartInfo.GetSMARTInfo())
 }
 
 func TestSMARCTL_GetDriveInfoByPathFails(t *testing.T) {
@@ -112,3 +137,124 @@ func TestSMARCTL_fillSmartStatusUnmarshallError(t *testing.T) {
 	err := l.fillSmartStatus(&DeviceSMARTInfo{}, "/dev/sdd")
 	assert.NotNil(t, err)
 }
+
+func TestSMARCTL_fillSmartAttributesSCSI(t *testing.T) {
+	output := `{
+    "smartctl": {"exit_status": 0},
+    "temperature": {"current": 40},
+    "power_on_time": {"hours": 11015},
+    "scsi_grown_defect_list": 12,
+    "scsi_percentage_used_endurance_indicator": 3,
+    "scsi_error_counter_log": {
+        "read": {"total_uncorrected_errors": 2},
+        "write": {"total_uncorrected_errors": 1},
+        "verify": {"total_uncorrected_errors": 0}
+    }}`
+	cmd := fmt.Sprintf(SmartctlAttributesCmdImpl, "/dev/sdd")
+	e := &mocks.GoMockExecutor{}
+	l := NewSMARTCTL(e)
+
+	e.On("RunCmd", cmd).Return(output, "", nil)
+	dev := &DeviceSMARTInfo{}
+	assert.Nil(t, l.fillSmartAttributes(dev, "/dev/sdd"))
+	assert.Equal(t, &api.SMARTInfo{
+		ReallocatedSectors: 12,
+		MediaErrors:        3,
+		Temperature:        40,
+		PowerOnHours:       11015,
+		PercentageUsed:     3,
+	}, dev.GetSMARTInfo())
+}
+
+func TestSMARCTL_fillSmartAttributesExitStatus(t *testing.T) {
+	cmd := fmt.Sprintf(SmartctlAttributesCmdImpl, "/dev/sdd")
+	e := &mocks.GoMockExecutor{}
+	l := NewSMARTCTL(e)
+
+	// device is failing, but attributes are read
+	e.On("RunCmd", cmd).Return(`{"smartctl": {"exit_status": 8}, "temperature": {"current": 50}}`, "",
+		fmt.Errorf("exit status 8")).Once()
+	dev := &DeviceSMARTInfo{}
+	assert.Nil(t, l.fillSmartAttributes(dev, "/dev/sdd"))
+	assert.Equal(t, int64(50), dev.Temperature.Current)
+
+	// device open failed
+	e.On("RunCmd", cmd).Return(`{"smartctl": {"exit_status": 2}}`, "", fmt.Errorf("exit status 2")).Once()
+	assert.NotNil(t, l.fillSmartAttributes(&DeviceSMARTInfo{}, "/dev/sdd"))
+
+	e.On("RunCmd", cmd).Return("", "", fmt.Errorf("error")).Once()
+	assert.NotNil(t, l.fillSmartAttributes(&DeviceSMARTInfo{}, "/dev/sdd"))
+}
+
+func TestDeviceSMARTInfo_GetEndurance(t *testing.T) {
+	dev := &DeviceSMARTInfo{}
+	_, ok := dev.GetEndurance()
+	assert.False(t, ok)
+
+	// ATA SSD with several wear-level attributes
+	err := json.Unmarshal([]byte(`{"ata_smart_attributes": {"table": [
+		{"id": 177, "name": "Wear_Leveling_Count", "value": 99, "raw": {"value": 12}},
+		{"id": 233, "name": "Media_Wearout_Indicator", "value": 97, "raw": {"value": 0}}
+	]}}`), dev)
+	assert.Nil(t, err)
+	endurance, ok := dev.GetEndurance()
+	assert.True(t, ok)
+	assert.Equal(t, int64(97), endurance)
+	assert.Equal(t, int64(3), dev.GetSMARTInfo().PercentageUsed)
+
+	// SCSI SSD, endurance indicator could exceed 100 percent
+	dev = &DeviceSMARTInfo{}
+	err = json.Unmarshal([]byte(`{"scsi_percentage_used_endurance_indicator": 120}`), dev)
+	assert.Nil(t, err)
+	endurance, ok = dev.GetEndurance()
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), endurance)
+}
+
+func TestSMARCTL_GetDriveInfoByPathRAIDController(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	l := NewSMARTCTL(e)
+
+	output := `{"smartctl": {"exit_status": 2, "messages": [{"string": "Smartctl open device: /dev/sda failed: ` +
+		`DELL or MegaRaid controller, please try adding '-d megaraid,N'", "severity": "error"}]}}`
+	e.On("RunCmd", fmt.Sprintf(SmartctlDeviceInfoCmdImpl, "/dev/sda")).
+		Return(output, "", fmt.Errorf("exit status 2"))
+	_, err := l.GetDriveInfoByPath("/dev/sda")
+	assert.Equal(t, &RAIDControllerError{ControllerType: RAIDControllerMegaRAID}, err)
+
+	output = `{"smartctl": {"exit_status": 2, "messages": [{"string": "/dev/sdb: requires option '-d cciss,N'"}]}}`
+	e.On("RunCmd", fmt.Sprintf(SmartctlDeviceInfoCmdImpl, "/dev/sdb")).
+		Return(output, "", fmt.Errorf("exit status 2"))
+	_, err = l.GetDriveInfoByPath("/dev/sdb")
+	assert.Equal(t, &RAIDControllerError{ControllerType: RAIDControllerCCISS}, err)
+
+	output = `{"smartctl": {"exit_status": 2, "messages": [{"string": "No such device"}]}}`
+	e.On("RunCmd", fmt.Sprintf(SmartctlDeviceInfoCmdImpl, "/dev/sdc")).
+		Return(output, "", fmt.Errorf("exit status 2"))
+	_, err = l.GetDriveInfoByPath("/dev/sdc")
+	assert.NotNil(t, err)
+	_, ok := err.(*RAIDControllerError)
+	assert.False(t, ok)
+}
+
+func TestSMARCTL_GetRAIDDriveInfo(t *testing.T) {
+	e := &mocks.GoMockExecutor{}
+	l := NewSMARTCTL(e)
+
+	device := fmt.Sprintf(SmartctlRAIDDeviceTmpl, RAIDControllerMegaRAID, 130, "/dev/sda")
+	assert.Equal(t, "--device=megaraid,130 /dev/sda", device)
+	e.On("RunCmd", fmt.Sprintf(SmartctlDeviceInfoCmdImpl, device)).
+		Return(`{"serial_number": "PHYSICAL_SN", "model_name": "ST4000NM0023", "rotation_rate": 7200}`, "", nil)
+	e.On("RunCmd", fmt.Sprintf(SmartctlHealthCmdImpl, device)).
+		Return(`{"smart_status": {"passed": true}}`, "", nil)
+	e.On("RunCmd", fmt.Sprintf(SmartctlAttributesCmdImpl, device)).
+		Return(`{"smartctl": {"exit_status": 0}, "temperature": {"current": 30}}`, "", nil)
+
+	smartInfo, err := l.GetRAIDDriveInfo("/dev/sda", RAIDControllerMegaRAID, 130)
+	assert.Nil(t, err)
+	assert.Equal(t, "PHYSICAL_SN", smartInfo.SerialNumber)
+	assert.Equal(t, "ST4000NM0023", smartInfo.ModelName)
+	assert.Equal(t, 7200, smartInfo.Rotation)
+	assert.True(t, smartInfo.SmartStatus["passed"])
+	assert.Equal(t, int64(30), smartInfo.Temperature.Current)
+}
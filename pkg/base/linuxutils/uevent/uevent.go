@@ -0,0 +1,134 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package uevent contains code for receiving kernel uevents through netlink socket
+package uevent
+
+import (
+	"bytes"
+	"fmt"
+	"syscall"
+	"time"
+)
+
+const (
+	// ActionAdd is an action of uevent which kernel sends when device appears
+	ActionAdd = "add"
+	// ActionRemove is an action of uevent which kernel sends when device disappears
+	ActionRemove = "remove"
+	// ActionChange is an action of uevent which kernel sends when device state is changed (e.g. media or size)
+	ActionChange = "change"
+
+	// SubsystemBlock is a subsystem of block devices
+	SubsystemBlock = "block"
+	// DevTypeDisk is a type of whole block device in opposite to partition
+	DevTypeDisk = "disk"
+
+	// kernel multicast group, udevd uses group 2 for rebroadcasting of processed events
+	kernelGroup = 1
+	// receive buffer is large enough for single uevent
+	bufferSize = 64 * 1024
+)
+
+// Event is a kernel uevent
+type Event struct {
+	Action string
+	// environment of the event such as DEVNAME, DEVTYPE, SUBSYSTEM and so on
+	Env map[string]string
+}
+
+// Reader is an interface that encapsulates reading of kernel uevents
+type Reader interface {
+	// returns nil event if there were no events during read timeout
+	ReadEvent() (*Event, error)
+	Close() error
+}
+
+// Listener is a Reader based on NETLINK_KOBJECT_UEVENT socket
+type Listener struct {
+	fd  int
+	buf []byte
+}
+
+// NewListener opens netlink socket and subscribes on kernel uevents
+// Receives timeout of the single read, it allows caller to check cancellation periodically
+// Returns an instance of Listener or error if something went wrong
+func NewListener(readTimeout time.Duration) (*Listener, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC,
+		syscall.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create netlink socket: %v", err)
+	}
+
+	tv := syscall.NsecToTimeval(readTimeout.Nanoseconds())
+	if err = syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		_ = syscall.Close(fd)
+		return nil, fmt.Errorf("unable to set read timeout: %v", err)
+	}
+	addr := &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Pid: 0, Groups: kernelGroup}
+	if err = syscall.Bind(fd, addr); err != nil {
+		_ = syscall.Close(fd)
+		return nil, fmt.Errorf("unable to bind netlink socket: %v", err)
+	}
+	return &Listener{fd: fd, buf: make([]byte, bufferSize)}, nil
+}
+
+// ReadEvent reads next uevent from the socket
+// Returns nil event if read timeout is expired
+func (l *Listener) ReadEvent() (*Event, error) {
+	n, _, err := syscall.Recvfrom(l.fd, l.buf, 0)
+	switch {
+	case err == syscall.EAGAIN || err == syscall.EINTR:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return Parse(l.buf[:n])
+}
+
+// Close closes netlink socket
+func (l *Listener) Close() error {
+	return syscall.Close(l.fd)
+}
+
+// Parse parses kernel uevent message, for example:
+// "add@/devices/.../block/sdb\x00ACTION=add\x00DEVPATH=/devices/.../block/sdb\x00SUBSYSTEM=block\x00DEVNAME=sdb\x00"
+// Returns Event or error if message has unexpected format
+func Parse(msg []byte) (*Event, error) {
+	fields := bytes.Split(bytes.TrimRight(msg, "\x00"), []byte{0})
+	if len(fields) < 2 || !bytes.Contains(fields[0], []byte("@")) {
+		return nil, fmt.Errorf("unexpected uevent format: %q", msg)
+	}
+
+	event := &Event{Env: make(map[string]string, len(fields)-1)}
+	for _, f := range fields[1:] {
+		kv := bytes.SplitN(f, []byte("="), 2)
+		if len(kv) != 2 {
+			continue
+		}
+		event.Env[string(kv[0])] = string(kv[1])
+	}
+	event.Action = event.Env["ACTION"]
+	if event.Action == "" {
+		event.Action = string(bytes.SplitN(fields[0], []byte("@"), 2)[0])
+	}
+	return event, nil
+}
+
+// IsDisk checks whether event is related to whole block device
+func (e *Event) IsDisk() bool {
+	return e.Env["SUBSYSTEM"] == SubsystemBlock && e.Env["DEVTYPE"] == DevTypeDisk
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uevent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	msg := "add@/devices/pci0000:00/0000:00:17.0/ata1/host0/target0:0:0/0:0:0:0/block/sdb\x00" +
+		"ACTION=add\x00DEVPATH=/devices/pci0000:00/0000:00:17.0/ata1/host0/target0:0:0/0:0:0:0/block/sdb\x00" +
+		"SUBSYSTEM=block\x00MAJOR=8\x00MINOR=16\x00DEVNAME=sdb\x00DEVTYPE=disk\x00SEQNUM=4312\x00"
+	event, err := Parse([]byte(msg))
+	assert.Nil(t, err)
+	assert.Equal(t, ActionAdd, event.Action)
+	assert.Equal(t, "sdb", event.Env["DEVNAME"])
+	assert.True(t, event.IsDisk())
+
+	msg = "remove@/devices/virtual/block/sdb/sdb1\x00ACTION=remove\x00SUBSYSTEM=block\x00DEVTYPE=partition\x00"
+	event, err = Parse([]byte(msg))
+	assert.Nil(t, err)
+	assert.Equal(t, ActionRemove, event.Action)
+	assert.False(t, event.IsDisk())
+
+	// message of udevd
+	_, err = Parse([]byte("libudev\x00\xfe\xed\xca\xfe"))
+	assert.NotNil(t, err)
+}
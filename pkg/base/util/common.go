@@ -68,6 +68,15 @@ func ConvertStorageClass(strSC string) string {
 		api.StorageClassSSDLVG,
 		api.StorageClassNVMeLVG,
 		api.StorageClassSystemLVG,
+		api.StorageClassHDDLVGThin,
+		api.StorageClassSSDLVGThin,
+		api.StorageClassNVMeLVGThin,
+		api.StorageClassHDDRAID1,
+		api.StorageClassSSDRAID1,
+		api.StorageClassNVMeRAID1,
+		api.StorageClassHDDRAID10,
+		api.StorageClassSSDRAID10,
+		api.StorageClassNVMeRAID10,
 		api.StorageClassAny:
 		return sc
 	}
@@ -92,14 +101,14 @@ func ConvertDriveTypeToStorageClass(driveType string) string {
 }
 
 // GetSubStorageClass return appropriate underlying storage class for
-// storage classes that are based on LVM, or empty string
+// storage classes that are based on LVM or MD RAID, or empty string
 func GetSubStorageClass(sc string) string {
 	switch sc {
-	case api.StorageClassHDDLVG:
+	case api.StorageClassHDDLVG, api.StorageClassHDDLVGThin, api.StorageClassHDDRAID1, api.StorageClassHDDRAID10:
 		return api.StorageClassHDD
-	case api.StorageClassSSDLVG:
+	case api.StorageClassSSDLVG, api.StorageClassSSDLVGThin, api.StorageClassSSDRAID1, api.StorageClassSSDRAID10:
 		return api.StorageClassSSD
-	case api.StorageClassNVMeLVG:
+	case api.StorageClassNVMeLVG, api.StorageClassNVMeLVGThin, api.StorageClassNVMeRAID1, api.StorageClassNVMeRAID10:
 		return api.StorageClassNVMe
 	default:
 		return ""
@@ -111,7 +120,72 @@ func IsStorageClassLVG(sc string) bool {
 	return sc == api.StorageClassHDDLVG ||
 		sc == api.StorageClassSSDLVG ||
 		sc == api.StorageClassNVMeLVG ||
-		sc == api.StorageClassSystemLVG
+		sc == api.StorageClassSystemLVG ||
+		IsStorageClassLVGThin(sc)
+}
+
+// IsStorageClassLVGThin returns whether provided sc relates to thin pool LVG or no
+func IsStorageClassLVGThin(sc string) bool {
+	return sc == api.StorageClassHDDLVGThin ||
+		sc == api.StorageClassSSDLVGThin ||
+		sc == api.StorageClassNVMeLVGThin
+}
+
+// IsStorageClassRAID returns whether provided sc relates to MD RAID or no
+func IsStorageClassRAID(sc string) bool {
+	return GetRAIDLevel(sc) != ""
+}
+
+// GetRAIDLevel returns MD RAID level (in terms of mdadm --level) for provided sc, or empty string
+func GetRAIDLevel(sc string) string {
+	switch sc {
+	case api.StorageClassHDDRAID1, api.StorageClassSSDRAID1, api.StorageClassNVMeRAID1:
+		return "1"
+	case api.StorageClassHDDRAID10, api.StorageClassSSDRAID10, api.StorageClassNVMeRAID10:
+		return "10"
+	default:
+		return ""
+	}
+}
+
+// GetRAIDDrivesCount returns amount of drives that MD RAID array of provided sc consists of, or 0
+func GetRAIDDrivesCount(sc string) int {
+	switch GetRAIDLevel(sc) {
+	case "1":
+		return 2
+	case "10":
+		return 4
+	default:
+		return 0
+	}
+}
+
+// GetRAIDMemberSize returns size that each member of MD RAID array of provided sc has to provide
+// for the array of the size bytes, or 0 if sc doesn't relate to MD RAID
+func GetRAIDMemberSize(sc string, size int64) int64 {
+	switch GetRAIDLevel(sc) {
+	case "1":
+		// each member holds full copy of the data
+		return size
+	case "10":
+		// data is striped between two mirrors
+		return size/2 + size%2
+	default:
+		return 0
+	}
+}
+
+// GetRAIDArraySize returns size of MD RAID array of provided sc which members have memberSize bytes each,
+// or 0 if sc doesn't relate to MD RAID
+func GetRAIDArraySize(sc string, memberSize int64) int64 {
+	switch GetRAIDLevel(sc) {
+	case "1":
+		return memberSize
+	case "10":
+		return memberSize * 2
+	default:
+		return 0
+	}
 }
 
 // ContainsString return true if slice contains string str
@@ -109,6 +109,10 @@ var strToSC = []struct {
 	{"ssdlvg", api.StorageClassSSDLVG},
 	{"nvmelvg", api.StorageClassNVMeLVG},
 	{"syslVg", api.StorageClassSystemLVG},
+	{"hddlvgthin", api.StorageClassHDDLVGThin},
+	{"nvmelvgthin", api.StorageClassNVMeLVGThin},
+	{"hddraid1", api.StorageClassHDDRAID1},
+	{"ssdraid10", api.StorageClassSSDRAID10},
 	{"any", api.StorageClassAny},
 	{"random", api.StorageClassAny},
 }
@@ -123,6 +127,37 @@ func TestConvertStorageClass(t *testing.T) {
 	}
 }
 
+func TestThinStorageClass(t *testing.T) {
+	assert.True(t, IsStorageClassLVGThin(api.StorageClassSSDLVGThin))
+	assert.True(t, IsStorageClassLVG(api.StorageClassSSDLVGThin))
+	assert.False(t, IsStorageClassLVGThin(api.StorageClassSSDLVG))
+	assert.Equal(t, api.StorageClassHDD, GetSubStorageClass(api.StorageClassHDDLVGThin))
+}
+
+func TestRAIDStorageClass(t *testing.T) {
+	assert.True(t, IsStorageClassRAID(api.StorageClassHDDRAID1))
+	assert.False(t, IsStorageClassRAID(api.StorageClassHDDLVG))
+
+	assert.Equal(t, api.StorageClassSSD, GetSubStorageClass(api.StorageClassSSDRAID10))
+	assert.Equal(t, api.StorageClassNVMe, GetSubStorageClass(api.StorageClassNVMeRAID1))
+
+	assert.Equal(t, "1", GetRAIDLevel(api.StorageClassNVMeRAID1))
+	assert.Equal(t, "10", GetRAIDLevel(api.StorageClassHDDRAID10))
+	assert.Equal(t, "", GetRAIDLevel(api.StorageClassHDD))
+
+	assert.Equal(t, 2, GetRAIDDrivesCount(api.StorageClassSSDRAID1))
+	assert.Equal(t, 4, GetRAIDDrivesCount(api.StorageClassSSDRAID10))
+	assert.Equal(t, 0, GetRAIDDrivesCount(api.StorageClassSSD))
+
+	assert.Equal(t, int64(101), GetRAIDMemberSize(api.StorageClassHDDRAID1, 101))
+	assert.Equal(t, int64(51), GetRAIDMemberSize(api.StorageClassHDDRAID10, 101))
+	assert.Equal(t, int64(0), GetRAIDMemberSize(api.StorageClassHDD, 101))
+
+	assert.Equal(t, int64(100), GetRAIDArraySize(api.StorageClassHDDRAID1, 100))
+	assert.Equal(t, int64(200), GetRAIDArraySize(api.StorageClassHDDRAID10, 100))
+	assert.Equal(t, int64(0), GetRAIDArraySize(api.StorageClassHDD, 100))
+}
+
 var driveTypeToSC = []struct {
 	driveType string
 	check     string
@@ -19,10 +19,33 @@ package util
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base"
+)
+
+const (
+	prefix = "pvc-"
+
+	// DefaultCachePercent is a size of the cache in percents of the volume size if cacheSize parameter isn't set
+	DefaultCachePercent = 10
+	// MinCacheSize is the minimal size of the cache of the volume
+	MinCacheSize = 64 * int64(MBYTE)
 )
 
-const prefix = "pvc-"
+// supportedAccessModes are the access modes of volume which is bound to a single node
+var supportedAccessModes = map[csi.VolumeCapability_AccessMode_Mode]bool{
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER:        true,
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY:   true,
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER: true,
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER:  true,
+}
 
 // GetVolumeUUID extracts UUID from volume ID: pvc-<UUID>
 // Method will remove prefix `pvc-` and return UUID
@@ -41,3 +64,214 @@ func GetVolumeUUID(volumeID string) (string, error) {
 	// is PV UUID RFC 4122 compatible?
 	return uuid, nil
 }
+
+// GetVolumeCondition returns abnormal condition if volume has BAD health or MISSING operational status
+func GetVolumeCondition(vol *api.Volume) *csi.VolumeCondition {
+	switch {
+	case vol.Health == apiV1.HealthBad:
+		return &csi.VolumeCondition{Abnormal: true, Message: "volume health is " + vol.Health}
+	case vol.OperationalStatus == apiV1.OperationalStatusMissing:
+		return &csi.VolumeCondition{Abnormal: true, Message: "volume is missing"}
+	default:
+		return &csi.VolumeCondition{Abnormal: false, Message: "volume is operating normally"}
+	}
+}
+
+// IsAccessModeSupported returns true if volume could be published with provided access mode,
+// several pods on the same node could use volume, but volume couldn't be published on several nodes
+func IsAccessModeSupported(mode *csi.VolumeCapability_AccessMode) bool {
+	return supportedAccessModes[mode.GetMode()]
+}
+
+// IsReadOnlyAccessMode returns true if volume with provided access mode should be published as read-only
+func IsReadOnlyAccessMode(mode *csi.VolumeCapability_AccessMode) bool {
+	return mode.GetMode() == csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY
+}
+
+// FillIOLimits sets I/O limits of the volume from StorageClass parameters or volume context,
+// bytes per second limits could be provided with size literal (e.g. "10Mi"), operations per second - as integers
+// Receives volume to fill and parameters with keys: readBps, writeBps, readIops and writeIops
+// Returns error if some of the limits couldn't be parsed
+func FillIOLimits(vol *api.Volume, params map[string]string) error {
+	parseBps := func(v string) (int64, error) {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n, nil
+		}
+		return StrToBytes(v)
+	}
+	parseIops := func(v string) (int64, error) {
+		return strconv.ParseInt(v, 10, 64)
+	}
+
+	for _, limit := range []struct {
+		key   string
+		field *int64
+		parse func(string) (int64, error)
+	}{
+		{base.ReadBpsKey, &vol.ReadBps, parseBps},
+		{base.WriteBpsKey, &vol.WriteBps, parseBps},
+		{base.ReadIopsKey, &vol.ReadIops, parseIops},
+		{base.WriteIopsKey, &vol.WriteIops, parseIops},
+	} {
+		value, ok := params[limit.key]
+		if !ok {
+			continue
+		}
+		n, err := limit.parse(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid value %q of %s parameter", value, limit.key)
+		}
+		*limit.field = n
+	}
+	return nil
+}
+
+// FillLVType sets type of the logical volume and its stripes or mirrors from StorageClass parameters,
+// striped logical volume has 2 stripes and raid1 logical volume has 1 additional copy by default
+// Receives volume to fill and parameters with keys: lvType, stripes and mirrors
+// Returns error if parameters are invalid or storage class of the volume doesn't support logical volume type
+func FillLVType(vol *api.Volume, params map[string]string) error {
+	lvType, ok := params[base.LVTypeKey]
+	if !ok || lvType == apiV1.LVTypeLinear {
+		return nil
+	}
+
+	var (
+		key   string
+		field *int32
+	)
+	switch lvType {
+	case apiV1.LVTypeStriped:
+		key, field, vol.Stripes = base.StripesKey, &vol.Stripes, 2
+	case apiV1.LVTypeRAID1:
+		key, field, vol.Mirrors = base.MirrorsKey, &vol.Mirrors, 1
+	default:
+		return fmt.Errorf("invalid value %q of %s parameter", lvType, base.LVTypeKey)
+	}
+	// system LVG is placed on the single drive, thin LVs are always placed in thin pool
+	if !IsStorageClassLVG(vol.StorageClass) || vol.StorageClass == apiV1.StorageClassSystemLVG ||
+		IsStorageClassLVGThin(vol.StorageClass) {
+		return fmt.Errorf("%s parameter isn't supported for storage class %s", base.LVTypeKey, vol.StorageClass)
+	}
+	vol.LVType = lvType
+
+	if value, ok := params[key]; ok {
+		n, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || n < int64(*field) {
+			return fmt.Errorf("invalid value %q of %s parameter", value, key)
+		}
+		*field = int32(n)
+	}
+	return nil
+}
+
+// FillEncryption enables LUKS encryption of the volume from StorageClass parameters,
+// passphrase is provided to the node in node stage secrets
+// Receives volume to fill and parameters with key encrypted
+// Returns error if parameters are invalid or volume can't be encrypted
+func FillEncryption(vol *api.Volume, params map[string]string) error {
+	value, ok := params[base.EncryptedKey]
+	if !ok {
+		return nil
+	}
+	encrypted, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid value %q of %s parameter", value, base.EncryptedKey)
+	}
+	if !encrypted {
+		return nil
+	}
+	// MD RAID array consumes several drives, content of the source volume or snapshot isn't encrypted
+	if IsStorageClassRAID(vol.StorageClass) {
+		return fmt.Errorf("%s parameter isn't supported for storage class %s", base.EncryptedKey, vol.StorageClass)
+	}
+	if vol.ContentSourceId != "" {
+		return fmt.Errorf("encrypted volume can't be created from %s", vol.ContentSourceType)
+	}
+
+	vol.Encrypted = true
+	return nil
+}
+
+// FillCache enables caching of the logical volume on LVG of faster drives of the same node from StorageClass parameters,
+// cache takes DefaultCachePercent of the volume size and works in writethrough mode by default
+// Receives volume to fill and parameters with keys: cacheStorageType, cacheSize and cacheMode
+// Returns error if parameters are invalid or volume can't be cached
+func FillCache(vol *api.Volume, params map[string]string) error {
+	value, ok := params[base.CacheStorageTypeKey]
+	if !ok {
+		return nil
+	}
+	cacheSC := ConvertStorageClass(value)
+	if cacheSC != apiV1.StorageClassSSDLVG && cacheSC != apiV1.StorageClassNVMeLVG {
+		return fmt.Errorf("invalid value %q of %s parameter, %s or %s is expected",
+			value, base.CacheStorageTypeKey, apiV1.StorageClassSSDLVG, apiV1.StorageClassNVMeLVG)
+	}
+	// cache is attached to the linear logical volume on HDD LVG, content of the source is placed on its LVG
+	if vol.StorageClass != apiV1.StorageClassHDDLVG {
+		return fmt.Errorf("%s parameter isn't supported for storage class %s", base.CacheStorageTypeKey, vol.StorageClass)
+	}
+	if GetLVPVsCount(vol) > 1 {
+		return fmt.Errorf("%s parameter isn't supported for %s logical volumes", base.CacheStorageTypeKey, vol.LVType)
+	}
+	if vol.ContentSourceId != "" {
+		return fmt.Errorf("cached volume can't be created from %s", vol.ContentSourceType)
+	}
+
+	mode := apiV1.CacheModeWritethrough
+	if value, ok := params[base.CacheModeKey]; ok {
+		if value != apiV1.CacheModeWritethrough && value != apiV1.CacheModeWriteback {
+			return fmt.Errorf("invalid value %q of %s parameter", value, base.CacheModeKey)
+		}
+		mode = value
+	}
+
+	size := vol.Size * DefaultCachePercent / 100
+	if value, ok := params[base.CacheSizeKey]; ok {
+		n, err := StrToBytes(value)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid value %q of %s parameter", value, base.CacheSizeKey)
+		}
+		size = n
+	}
+	if size < MinCacheSize {
+		size = MinCacheSize
+	}
+
+	vol.CacheStorageClass, vol.CacheSize, vol.CacheMode = cacheSC, size, mode
+	return nil
+}
+
+// FillOvercommitRatio sets overcommit ratio of the thin pool LVG which is created for the thin volume
+// from StorageClass parameters, existing thin pool LVGs keep the ratio they were created with
+// Receives volume to fill and parameters with key overcommitRatio
+// Returns error if ratio is less than 1 or storage class of the volume isn't thin
+func FillOvercommitRatio(vol *api.Volume, params map[string]string) error {
+	value, ok := params[base.OvercommitRatioKey]
+	if !ok {
+		return nil
+	}
+	if !IsStorageClassLVGThin(vol.StorageClass) {
+		return fmt.Errorf("%s parameter isn't supported for storage class %s", base.OvercommitRatioKey, vol.StorageClass)
+	}
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil || ratio < 1 {
+		return fmt.Errorf("invalid value %q of %s parameter", value, base.OvercommitRatioKey)
+	}
+
+	vol.OvercommitRatio = ratio
+	return nil
+}
+
+// GetLVPVsCount returns number of PVs which are required for the logical volume of the volume:
+// number of stripes for striped LV, number of copies for raid1 LV and 1 for linear LV
+func GetLVPVsCount(vol *api.Volume) int {
+	switch vol.LVType {
+	case apiV1.LVTypeStriped:
+		return int(vol.Stripes)
+	case apiV1.LVTypeRAID1:
+		return int(vol.Mirrors) + 1
+	default:
+		return 1
+	}
+}
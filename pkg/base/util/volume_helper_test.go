@@ -19,6 +19,12 @@ package util
 import (
 	"gotest.tools/assert"
 	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base"
 )
 
 func Test_GetVolumeUUID(t *testing.T) {
@@ -41,3 +47,161 @@ func Test_GetEmptyVolumeUUID(t *testing.T) {
 	_, err := GetVolumeUUID(volumeID)
 	assert.Error(t, err, "volume UUID is empty")
 }
+
+func Test_GetVolumeCondition(t *testing.T) {
+	vol := &api.Volume{Health: apiV1.HealthGood, OperationalStatus: apiV1.OperationalStatusOperative}
+	assert.Equal(t, false, GetVolumeCondition(vol).Abnormal)
+
+	vol.OperationalStatus = apiV1.OperationalStatusMissing
+	assert.Equal(t, true, GetVolumeCondition(vol).Abnormal)
+
+	vol.Health = apiV1.HealthBad
+	assert.Equal(t, "volume health is "+apiV1.HealthBad, GetVolumeCondition(vol).Message)
+}
+
+func Test_IsAccessModeSupported(t *testing.T) {
+	mode := &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER}
+	assert.Equal(t, true, IsAccessModeSupported(mode))
+	assert.Equal(t, false, IsReadOnlyAccessMode(mode))
+
+	mode.Mode = csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER
+	assert.Equal(t, true, IsAccessModeSupported(mode))
+	assert.Equal(t, false, IsReadOnlyAccessMode(mode))
+
+	mode.Mode = csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER
+	assert.Equal(t, true, IsAccessModeSupported(mode))
+
+	mode.Mode = csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY
+	assert.Equal(t, true, IsAccessModeSupported(mode))
+	assert.Equal(t, true, IsReadOnlyAccessMode(mode))
+
+	mode.Mode = csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER
+	assert.Equal(t, false, IsAccessModeSupported(mode))
+	assert.Equal(t, false, IsAccessModeSupported(nil))
+}
+
+func Test_FillIOLimits(t *testing.T) {
+	vol := &api.Volume{}
+	err := FillIOLimits(vol, map[string]string{
+		base.ReadBpsKey: "10Mi", base.WriteBpsKey: "1048576", base.WriteIopsKey: "100"})
+	assert.NilError(t, err)
+	assert.Equal(t, int64(10*MBYTE), vol.ReadBps)
+	assert.Equal(t, int64(MBYTE), vol.WriteBps)
+	assert.Equal(t, int64(0), vol.ReadIops)
+	assert.Equal(t, int64(100), vol.WriteIops)
+
+	err = FillIOLimits(vol, map[string]string{base.ReadIopsKey: "10Mi"})
+	assert.ErrorContains(t, err, base.ReadIopsKey)
+
+	err = FillIOLimits(vol, map[string]string{base.WriteIopsKey: "-1"})
+	assert.ErrorContains(t, err, base.WriteIopsKey)
+}
+
+func Test_FillLVType(t *testing.T) {
+	vol := &api.Volume{StorageClass: apiV1.StorageClassHDDLVG}
+	err := FillLVType(vol, map[string]string{})
+	assert.NilError(t, err)
+	assert.Equal(t, "", vol.LVType)
+	assert.Equal(t, 1, GetLVPVsCount(vol))
+
+	err = FillLVType(vol, map[string]string{base.LVTypeKey: apiV1.LVTypeStriped})
+	assert.NilError(t, err)
+	assert.Equal(t, apiV1.LVTypeStriped, vol.LVType)
+	assert.Equal(t, int32(2), vol.Stripes)
+	assert.Equal(t, 2, GetLVPVsCount(vol))
+
+	vol = &api.Volume{StorageClass: apiV1.StorageClassSSDLVG}
+	err = FillLVType(vol, map[string]string{base.LVTypeKey: apiV1.LVTypeRAID1, base.MirrorsKey: "2"})
+	assert.NilError(t, err)
+	assert.Equal(t, apiV1.LVTypeRAID1, vol.LVType)
+	assert.Equal(t, int32(2), vol.Mirrors)
+	assert.Equal(t, 3, GetLVPVsCount(vol))
+
+	err = FillLVType(vol, map[string]string{base.LVTypeKey: apiV1.LVTypeStriped, base.StripesKey: "1"})
+	assert.ErrorContains(t, err, base.StripesKey)
+
+	err = FillLVType(vol, map[string]string{base.LVTypeKey: "mirror"})
+	assert.ErrorContains(t, err, base.LVTypeKey)
+
+	vol = &api.Volume{StorageClass: apiV1.StorageClassHDD}
+	err = FillLVType(vol, map[string]string{base.LVTypeKey: apiV1.LVTypeRAID1})
+	assert.ErrorContains(t, err, apiV1.StorageClassHDD)
+}
+
+func Test_FillEncryption(t *testing.T) {
+	vol := &api.Volume{StorageClass: apiV1.StorageClassHDD}
+	err := FillEncryption(vol, map[string]string{base.EncryptedKey: "false"})
+	assert.NilError(t, err)
+	assert.Equal(t, false, vol.Encrypted)
+
+	err = FillEncryption(vol, map[string]string{base.EncryptedKey: "true"})
+	assert.NilError(t, err)
+	assert.Equal(t, true, vol.Encrypted)
+
+	vol = &api.Volume{StorageClass: apiV1.StorageClassSSDLVG}
+	err = FillEncryption(vol, map[string]string{base.EncryptedKey: "yes"})
+	assert.ErrorContains(t, err, base.EncryptedKey)
+
+	vol = &api.Volume{StorageClass: apiV1.StorageClassHDDRAID1}
+	err = FillEncryption(vol, map[string]string{base.EncryptedKey: "true"})
+	assert.ErrorContains(t, err, apiV1.StorageClassHDDRAID1)
+}
+
+func Test_FillCache(t *testing.T) {
+	vol := &api.Volume{StorageClass: apiV1.StorageClassHDDLVG, Size: 10 * int64(GBYTE)}
+	err := FillCache(vol, map[string]string{})
+	assert.NilError(t, err)
+	assert.Equal(t, "", vol.CacheStorageClass)
+
+	err = FillCache(vol, map[string]string{base.CacheStorageTypeKey: "nvmelvg"})
+	assert.NilError(t, err)
+	assert.Equal(t, apiV1.StorageClassNVMeLVG, vol.CacheStorageClass)
+	assert.Equal(t, int64(GBYTE), vol.CacheSize)
+	assert.Equal(t, apiV1.CacheModeWritethrough, vol.CacheMode)
+
+	err = FillCache(vol, map[string]string{base.CacheStorageTypeKey: apiV1.StorageClassSSDLVG,
+		base.CacheSizeKey: "2Gi", base.CacheModeKey: apiV1.CacheModeWriteback})
+	assert.NilError(t, err)
+	assert.Equal(t, 2*int64(GBYTE), vol.CacheSize)
+	assert.Equal(t, apiV1.CacheModeWriteback, vol.CacheMode)
+
+	vol = &api.Volume{StorageClass: apiV1.StorageClassHDDLVG, Size: int64(MBYTE)}
+	err = FillCache(vol, map[string]string{base.CacheStorageTypeKey: apiV1.StorageClassNVMeLVG})
+	assert.NilError(t, err)
+	assert.Equal(t, MinCacheSize, vol.CacheSize)
+
+	err = FillCache(vol, map[string]string{base.CacheStorageTypeKey: apiV1.StorageClassHDD})
+	assert.ErrorContains(t, err, base.CacheStorageTypeKey)
+
+	err = FillCache(vol, map[string]string{base.CacheStorageTypeKey: apiV1.StorageClassNVMeLVG, base.CacheModeKey: "writearound"})
+	assert.ErrorContains(t, err, base.CacheModeKey)
+
+	err = FillCache(vol, map[string]string{base.CacheStorageTypeKey: apiV1.StorageClassNVMeLVG, base.CacheSizeKey: "big"})
+	assert.ErrorContains(t, err, base.CacheSizeKey)
+
+	vol = &api.Volume{StorageClass: apiV1.StorageClassHDDLVG, LVType: apiV1.LVTypeStriped, Stripes: 2}
+	err = FillCache(vol, map[string]string{base.CacheStorageTypeKey: apiV1.StorageClassNVMeLVG})
+	assert.ErrorContains(t, err, apiV1.LVTypeStriped)
+
+	vol = &api.Volume{StorageClass: apiV1.StorageClassSSDLVG}
+	err = FillCache(vol, map[string]string{base.CacheStorageTypeKey: apiV1.StorageClassNVMeLVG})
+	assert.ErrorContains(t, err, apiV1.StorageClassSSDLVG)
+}
+
+func Test_FillOvercommitRatio(t *testing.T) {
+	vol := &api.Volume{StorageClass: apiV1.StorageClassHDDLVGThin}
+	err := FillOvercommitRatio(vol, map[string]string{})
+	assert.NilError(t, err)
+	assert.Equal(t, float64(0), vol.OvercommitRatio)
+
+	err = FillOvercommitRatio(vol, map[string]string{base.OvercommitRatioKey: "3.5"})
+	assert.NilError(t, err)
+	assert.Equal(t, 3.5, vol.OvercommitRatio)
+
+	err = FillOvercommitRatio(vol, map[string]string{base.OvercommitRatioKey: "0.5"})
+	assert.ErrorContains(t, err, base.OvercommitRatioKey)
+
+	vol = &api.Volume{StorageClass: apiV1.StorageClassHDDLVG}
+	err = FillOvercommitRatio(vol, map[string]string{base.OvercommitRatioKey: "2"})
+	assert.ErrorContains(t, err, apiV1.StorageClassHDDLVG)
+}
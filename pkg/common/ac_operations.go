@@ -27,11 +27,13 @@ import (
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
 )
 
 // AvailableCapacityOperations is the interface for interact with AvailableCapacity CRs from Controller
 type AvailableCapacityOperations interface {
-	RecreateACToLVGSC(ctx context.Context, sc string, acs ...accrd.AvailableCapacity) *accrd.AvailableCapacity
+	RecreateACToLVGSC(ctx context.Context, sc string, overcommitRatio float64,
+		acs ...accrd.AvailableCapacity) *accrd.AvailableCapacity
 }
 
 // ACOperationsImpl is the basic implementation of AvailableCapacityOperations interface
@@ -51,9 +53,12 @@ func NewACOperationsImpl(k8sClient *k8s.KubeClient, l *logrus.Logger) *ACOperati
 }
 
 // RecreateACToLVGSC creates LVG(based on ACs) creates AC based on that LVG and set sise of provided ACs to 0.
-// Receives newSC as string (e.g. HDDLVG) and AvailableCapacities where LVG should be based
+// Receives newSC as string (e.g. HDDLVG), overcommit ratio of AC if newSC is thin
+// and AvailableCapacities where LVG should be based. Logical volumes of LVG on several drives are spread evenly
+// over all of them, so AC of such LVG provides the size of the smallest drive on each drive
 // Returns created AC or nil
-func (a *ACOperationsImpl) RecreateACToLVGSC(ctx context.Context, newSC string, acs ...accrd.AvailableCapacity) *accrd.AvailableCapacity {
+func (a *ACOperationsImpl) RecreateACToLVGSC(ctx context.Context, newSC string, overcommitRatio float64,
+	acs ...accrd.AvailableCapacity) *accrd.AvailableCapacity {
 	ll := a.log.WithFields(logrus.Fields{
 		"method":   "RecreateACToLVGSC",
 		"volumeID": ctx.Value(k8s.RequestUUID),
@@ -66,10 +71,17 @@ func (a *ACOperationsImpl) RecreateACToLVGSC(ctx context.Context, newSC string,
 	ll.Debugf("Recreating ACs %v with SC %s to SC %s", acs[0], acs[0].Spec.StorageClass, newSC)
 
 	lvgLocations := make([]string, len(acs))
-	var lvgSize int64
+	var lvgSize, minSize int64
 	for i, ac := range acs {
 		lvgLocations[i] = ac.Spec.Location
 		lvgSize += ac.Spec.Size
+		if i == 0 || ac.Spec.Size < minSize {
+			minSize = ac.Spec.Size
+		}
+	}
+	acSize := lvgSize
+	if len(acs) > 1 {
+		acSize = int64(len(acs)) * minSize
 	}
 
 	var (
@@ -81,8 +93,13 @@ func (a *ACOperationsImpl) RecreateACToLVGSC(ctx context.Context, newSC string,
 			Locations: lvgLocations,
 			Size:      lvgSize,
 			Status:    apiV1.Creating,
+			Health:    apiV1.HealthGood,
+			ThinPool:  util.IsStorageClassLVGThin(newSC),
 		}
 	)
+	if !apiLVG.ThinPool {
+		overcommitRatio = 0
+	}
 
 	// set size ACs to 0 to avoid allocations
 	for _, ac := range acs {
@@ -104,10 +121,11 @@ func (a *ACOperationsImpl) RecreateACToLVGSC(ctx context.Context, newSC string,
 	// create new AC
 	newACCRName := uuid.New().String()
 	newACCR := a.k8sClient.ConstructACCR(newACCRName, api.AvailableCapacity{
-		Location:     lvg.Name,
-		NodeId:       acs[0].Spec.NodeId,
-		StorageClass: newSC,
-		Size:         lvgSize,
+		Location:        lvg.Name,
+		NodeId:          acs[0].Spec.NodeId,
+		StorageClass:    newSC,
+		Size:            acSize,
+		OvercommitRatio: overcommitRatio,
 	})
 	if err = a.k8sClient.CreateCR(ctx, newACCRName, newACCR); err != nil {
 		ll.Errorf("Unable to create AC %v, error: %v", newACCRName, err)
@@ -39,7 +39,7 @@ func Test_recreateACToLVGSC_Success(t *testing.T) {
 	)
 
 	// there are no acs are provided
-	newAC = acOp.RecreateACToLVGSC(testCtx, apiV1.StorageClassHDDLVG)
+	newAC = acOp.RecreateACToLVGSC(testCtx, apiV1.StorageClassHDDLVG, 0)
 	assert.Nil(t, newAC)
 
 	// ensure that there are 2 ACs
@@ -50,7 +50,7 @@ func Test_recreateACToLVGSC_Success(t *testing.T) {
 
 	// expect that AC with SC HDDLVG will be created and will be size of 1Tb + 100Gb
 	// testAC2 and testAC3 should be removed
-	newAC = acOp.RecreateACToLVGSC(testCtx, apiV1.StorageClassHDDLVG, testAC2, testAC3)
+	newAC = acOp.RecreateACToLVGSC(testCtx, apiV1.StorageClassHDDLVG, 0, testAC2, testAC3)
 
 	// check that LVG is in creating state
 	lvgList := lvgcrd.LVGList{}
@@ -76,6 +76,8 @@ func Test_recreateACToLVGSC_Success(t *testing.T) {
 	assert.Equal(t, apiV1.StorageClassHDD, acList.Items[1].Spec.StorageClass)
 	assert.Equal(t, int64(0), acList.Items[1].Spec.Size)
 	assert.Equal(t, apiV1.StorageClassHDDLVG, acList.Items[2].Spec.StorageClass)
+	// logical volumes are spread evenly over drives of LVG, so only the smallest drive is usable on each of them
+	assert.Equal(t, 2*testAC2.Spec.Size, newAC.Spec.Size)
 }
 
 
@@ -0,0 +1,250 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+// SnapshotOperations is the interface that unites common Snapshot CRs operations
+type SnapshotOperations interface {
+	CreateSnapshot(ctx context.Context, s api.Snapshot) (*api.Snapshot, error)
+	DeleteSnapshot(ctx context.Context, snapshotID string) error
+	UpdateCRsAfterSnapshotDeletion(ctx context.Context, snapshotID string)
+	WaitStatus(ctx context.Context, snapshotID string, statuses ...string) error
+}
+
+// SnapshotOperationsImpl is the basic implementation of SnapshotOperations interface
+type SnapshotOperationsImpl struct {
+	k8sClient *k8s.KubeClient
+	crHelper  *k8s.CRHelper
+	log       *logrus.Entry
+}
+
+// NewSnapshotOperationsImpl is the constructor for SnapshotOperationsImpl struct
+// Receives an instance of base.KubeClient and logrus logger
+// Returns an instance of SnapshotOperationsImpl
+func NewSnapshotOperationsImpl(k8sClient *k8s.KubeClient, logger *logrus.Logger) *SnapshotOperationsImpl {
+	return &SnapshotOperationsImpl{
+		k8sClient: k8sClient,
+		crHelper:  k8s.NewCRHelper(k8sClient, logger),
+		log:       logger.WithField("component", "SnapshotOperationsImpl"),
+	}
+}
+
+// CreateSnapshot creates Snapshot CR for LVM volume and decreases size of the corresponding LVG AC CR
+// or returns existed Snapshot CR. Snapshot LV is created on the node by reconciling of Snapshot CR
+// Receives golang context and api.Snapshot which is Spec of Snapshot CR to create (Id and VolumeId are required)
+// Returns api.Snapshot of created or existed Snapshot CR or error if something went wrong
+func (so *SnapshotOperationsImpl) CreateSnapshot(ctx context.Context, s api.Snapshot) (*api.Snapshot, error) {
+	ll := so.log.WithFields(logrus.Fields{
+		"method":     "CreateSnapshot",
+		"snapshotID": s.Id,
+	})
+	ll.Infof("Creating snapshot %v", s)
+
+	var (
+		ctxWithID  = context.WithValue(context.Background(), k8s.RequestUUID, s.Id)
+		snapshotCR = &snapshotcrd.Snapshot{}
+		volumeCR   = &volumecrd.Volume{}
+		err        error
+	)
+
+	// at first check whether snapshot CR exist or no
+	err = so.k8sClient.ReadCR(ctx, s.Id, snapshotCR)
+	switch {
+	case err == nil:
+		ll.Infof("Snapshot exists, current status: %s.", snapshotCR.Spec.CSIStatus)
+		if snapshotCR.Spec.VolumeId != s.VolumeId {
+			return nil, status.Errorf(codes.AlreadyExists,
+				"snapshot %s already exists for another volume %s", s.Id, snapshotCR.Spec.VolumeId)
+		}
+		if snapshotCR.Spec.CSIStatus == apiV1.Failed {
+			return nil, fmt.Errorf("corresponding snapshot CR %s has failed status", s.Id)
+		}
+		return &snapshotCR.Spec, nil
+	case !k8sError.IsNotFound(err):
+		ll.Errorf("Unable to read snapshot CR: %v", err)
+		return nil, status.Error(codes.Aborted, "unable to check snapshot existence")
+	}
+
+	if err = so.k8sClient.ReadCR(ctx, s.VolumeId, volumeCR); err != nil {
+		if k8sError.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "source volume %s isn't found", s.VolumeId)
+		}
+		ll.Errorf("Unable to read volume CR: %v", err)
+		return nil, status.Error(codes.Aborted, "unable to read source volume CR")
+	}
+
+	if volumeCR.Spec.LocationType != apiV1.LocationTypeLVM {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"snapshots are supported only for volumes on LVG, volume %s has location type %s",
+			s.VolumeId, volumeCR.Spec.LocationType)
+	}
+
+	// snapshot has the same size as origin LV, so it couldn't be overflowed by changes in origin LV,
+	// snapshot of the thin LV is the thin LV in the same thin pool which is accounted with overcommit
+	size := volumeCR.Spec.Size
+	ac := so.crHelper.GetACByLocation(volumeCR.Spec.Location)
+	if ac == nil || ac.Spec.Size < capacityplanner.GetThinPhysicalSize(size, ac.Spec.OvercommitRatio) {
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"there is no enough capacity in LVG %s for snapshot of volume %s", volumeCR.Spec.Location, s.VolumeId)
+	}
+
+	apiSnapshot := api.Snapshot{
+		Id:           s.Id,
+		VolumeId:     s.VolumeId,
+		NodeId:       volumeCR.Spec.NodeId,
+		Location:     volumeCR.Spec.Location,
+		Size:         size,
+		CSIStatus:    apiV1.Creating,
+		CreationTime: time.Now().Unix(),
+		ConsumedSize: capacityplanner.GetThinPhysicalSize(size, ac.Spec.OvercommitRatio),
+	}
+	snapshotCR = so.k8sClient.ConstructSnapshotCR(s.Id, apiSnapshot)
+
+	if err = so.k8sClient.CreateCR(ctxWithID, s.Id, snapshotCR); err != nil {
+		ll.Errorf("Unable to create CR, error: %v", err)
+		return nil, status.Errorf(codes.Internal, "unable to create snapshot CR")
+	}
+
+	// decrease AC size
+	ac.Spec.Size -= apiSnapshot.ConsumedSize
+	if err = so.k8sClient.UpdateCRWithAttempts(ctxWithID, ac, 5); err != nil {
+		ll.Errorf("Unable to set size for AC %s to %d, error: %v", ac.Name, ac.Spec.Size, err)
+	}
+
+	return &snapshotCR.Spec, nil
+}
+
+// DeleteSnapshot changes snapshot CR state to Removing and updates it,
+// if snapshot CR doesn't exists return Not found error and that error should be handled by caller.
+// Receives golang context and a snapshot ID to delete
+// Returns error if something went wrong or Snapshot with snapshotID wasn't found
+func (so *SnapshotOperationsImpl) DeleteSnapshot(ctx context.Context, snapshotID string) error {
+	ll := so.log.WithFields(logrus.Fields{
+		"method":     "DeleteSnapshot",
+		"snapshotID": snapshotID,
+	})
+	ll.Info("Processing")
+
+	snapshotCR := &snapshotcrd.Snapshot{}
+	if err := so.k8sClient.ReadCR(ctx, snapshotID, snapshotCR); err != nil {
+		return err
+	}
+
+	switch snapshotCR.Spec.CSIStatus {
+	case apiV1.Created, apiV1.Failed:
+	case apiV1.Removed, apiV1.Removing:
+		ll.Debugf("Snapshot has %s status", snapshotCR.Spec.CSIStatus)
+		return nil
+	default:
+		return status.Errorf(codes.FailedPrecondition,
+			"Snapshot CR status hadn't been set to %s, current status - %s, expected - %s",
+			apiV1.Removing, snapshotCR.Spec.CSIStatus, apiV1.Created)
+	}
+
+	snapshotCR.Spec.CSIStatus = apiV1.Removing
+	return so.k8sClient.UpdateCR(ctx, snapshotCR)
+}
+
+// UpdateCRsAfterSnapshotDeletion should considered as a second step in DeleteSnapshot,
+// remove Snapshot CR and increase size of the corresponding LVG AC CR
+// does not return anything because that method does not change real LVG on the node
+func (so *SnapshotOperationsImpl) UpdateCRsAfterSnapshotDeletion(ctx context.Context, snapshotID string) {
+	ll := so.log.WithFields(logrus.Fields{
+		"method":     "UpdateCRsAfterSnapshotDeletion",
+		"snapshotID": snapshotID,
+	})
+
+	snapshotCR := &snapshotcrd.Snapshot{}
+	if err := so.k8sClient.ReadCR(ctx, snapshotID, snapshotCR); err != nil {
+		if !k8sError.IsNotFound(err) {
+			ll.Errorf("Unable to read snapshot CR %s: %v. Snapshot CR will not be removed", snapshotID, err)
+		}
+		return
+	}
+
+	if err := so.k8sClient.DeleteCR(ctx, snapshotCR); err != nil {
+		ll.Errorf("Unable to delete snapshot CR %s: %v", snapshotID, err)
+		return
+	}
+
+	ac := so.crHelper.GetACByLocation(snapshotCR.Spec.Location)
+	if ac == nil {
+		ll.Errorf("Unable to find available capacity resource for LVG %s", snapshotCR.Spec.Location)
+		return
+	}
+	ac.Spec.Size += capacityplanner.GetSnapshotConsumedSize(&snapshotCR.Spec, ac)
+	if err := so.k8sClient.UpdateCRWithAttempts(ctx, ac, 5); err != nil {
+		ll.Errorf("Unable to update AC %s size: %v", ac.Name, err)
+	}
+}
+
+// WaitStatus check snapshot status until it will be reached one of the statuses
+// return error if context is done or snapshot reaches failed status, return nil if reached status != failed
+func (so *SnapshotOperationsImpl) WaitStatus(ctx context.Context, snapshotID string, statuses ...string) error {
+	ll := so.log.WithFields(logrus.Fields{
+		"method":     "WaitStatus",
+		"snapshotID": snapshotID,
+	})
+
+	ll.Infof("Pulling snapshot status")
+
+	var (
+		s                   = &snapshotcrd.Snapshot{}
+		timeoutBetweenCheck = time.Second
+		err                 error
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			ll.Warnf("Context is done but snapshot still not reach one of the expected status: %v", statuses)
+			return fmt.Errorf("snapshot context is done")
+		case <-time.After(timeoutBetweenCheck):
+			if err = so.k8sClient.ReadCR(ctx, snapshotID, s); err != nil {
+				ll.Errorf("Unable to read snapshot CR: %v", err)
+				if k8sError.IsNotFound(err) {
+					return fmt.Errorf("snapshot isn't found")
+				}
+				continue
+			}
+			for _, st := range statuses {
+				if s.Spec.CSIStatus == st {
+					if st == apiV1.Failed {
+						return fmt.Errorf("snapshot has reached Failed status")
+					}
+					return nil
+				}
+			}
+		}
+	}
+}
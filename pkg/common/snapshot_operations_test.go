@@ -0,0 +1,185 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+)
+
+var testSnapshotName = "snapshot-1"
+
+func TestSnapshotOperationsImpl_CreateSnapshot(t *testing.T) {
+	svc := setupSnapshotOperationsTest(t)
+
+	// source volume doesn't exist
+	_, err := svc.CreateSnapshot(testCtx, api.Snapshot{Id: testSnapshotName, VolumeId: testVolume1Name})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// source volume isn't on LVG
+	v := testVolume1
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testVolume1Name, &v))
+	_, err = svc.CreateSnapshot(testCtx, api.Snapshot{Id: testSnapshotName, VolumeId: testVolume1Name})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// there is no enough capacity in LVG
+	v.Spec.LocationType = apiV1.LocationTypeLVM
+	v.Spec.Location = testLVGName
+	v.Spec.Size = testAC4.Spec.Size + 1
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, &v))
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC4Name, &testAC4))
+	_, err = svc.CreateSnapshot(testCtx, api.Snapshot{Id: testSnapshotName, VolumeId: testVolume1Name})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// snapshot CR is created and AC is decreased
+	v.Spec.Size = testAC4.Spec.Size / 2
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, &v))
+	snapshot, err := svc.CreateSnapshot(testCtx, api.Snapshot{Id: testSnapshotName, VolumeId: testVolume1Name})
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.Creating, snapshot.CSIStatus)
+	assert.Equal(t, v.Spec.Size, snapshot.Size)
+	assert.Equal(t, testLVGName, snapshot.Location)
+	assert.Equal(t, v.Spec.NodeId, snapshot.NodeId)
+
+	ac := &accrd.AvailableCapacity{}
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testAC4Name, ac))
+	assert.Equal(t, testAC4.Spec.Size-v.Spec.Size, ac.Spec.Size)
+
+	// snapshot exists
+	existed, err := svc.CreateSnapshot(testCtx, api.Snapshot{Id: testSnapshotName, VolumeId: testVolume1Name})
+	assert.Nil(t, err)
+	assert.Equal(t, snapshot.CreationTime, existed.CreationTime)
+
+	// snapshot exists for another volume
+	_, err = svc.CreateSnapshot(testCtx, api.Snapshot{Id: testSnapshotName, VolumeId: "another-volume"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestSnapshotOperationsImpl_CreateAndDeleteThinSnapshot(t *testing.T) {
+	var (
+		svc = setupSnapshotOperationsTest(t)
+		v   = testVolume1
+		ac  = testAC4
+	)
+	// thin pool LVG with overcommit
+	ac.Spec.OvercommitRatio = 2
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC4Name, &ac))
+	v.Spec.LocationType = apiV1.LocationTypeLVM
+	v.Spec.Location = testLVGName
+	v.Spec.Size = ac.Spec.Size
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testVolume1Name, &v))
+
+	snapshot, err := svc.CreateSnapshot(testCtx, api.Snapshot{Id: testSnapshotName, VolumeId: testVolume1Name})
+	assert.Nil(t, err)
+	assert.Equal(t, v.Spec.Size, snapshot.Size)
+	assert.Equal(t, v.Spec.Size/2, snapshot.ConsumedSize)
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testAC4Name, &ac))
+	assert.Equal(t, testAC4.Spec.Size-v.Spec.Size/2, ac.Spec.Size)
+
+	// overcommit ratio of AC is edited, snapshot releases space which it consumed
+	ac.Spec.OvercommitRatio = 4
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, &ac))
+	svc.UpdateCRsAfterSnapshotDeletion(testCtx, testSnapshotName)
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testAC4Name, &ac))
+	assert.Equal(t, testAC4.Spec.Size, ac.Spec.Size)
+}
+
+func TestSnapshotOperationsImpl_DeleteSnapshot(t *testing.T) {
+	svc := setupSnapshotOperationsTest(t)
+
+	// snapshot doesn't exist
+	err := svc.DeleteSnapshot(testCtx, testSnapshotName)
+	assert.True(t, k8sError.IsNotFound(err))
+
+	s := createTestSnapshotCR(t, svc, apiV1.Creating)
+	err = svc.DeleteSnapshot(testCtx, testSnapshotName)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	s.Spec.CSIStatus = apiV1.Created
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, s))
+	assert.Nil(t, svc.DeleteSnapshot(testCtx, testSnapshotName))
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testSnapshotName, s))
+	assert.Equal(t, apiV1.Removing, s.Spec.CSIStatus)
+
+	// already removing
+	assert.Nil(t, svc.DeleteSnapshot(testCtx, testSnapshotName))
+}
+
+func TestSnapshotOperationsImpl_UpdateCRsAfterSnapshotDeletion(t *testing.T) {
+	svc := setupSnapshotOperationsTest(t)
+
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC4Name, &testAC4))
+	s := createTestSnapshotCR(t, svc, apiV1.Removed)
+
+	svc.UpdateCRsAfterSnapshotDeletion(testCtx, testSnapshotName)
+
+	err := svc.k8sClient.ReadCR(testCtx, testSnapshotName, &snapshotcrd.Snapshot{})
+	assert.True(t, k8sError.IsNotFound(err))
+	ac := &accrd.AvailableCapacity{}
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testAC4Name, ac))
+	assert.Equal(t, testAC4.Spec.Size+s.Spec.Size, ac.Spec.Size)
+}
+
+func TestSnapshotOperationsImpl_WaitStatus(t *testing.T) {
+	svc := setupSnapshotOperationsTest(t)
+
+	// snapshot CR wasn't found
+	assert.NotNil(t, svc.WaitStatus(testCtx, testSnapshotName, apiV1.Created))
+
+	createTestSnapshotCR(t, svc, apiV1.Created)
+	ctx, closeFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer closeFn()
+	assert.Nil(t, svc.WaitStatus(ctx, testSnapshotName, apiV1.Failed, apiV1.Created))
+
+	// ctx is done
+	doneCtx, doneFn := context.WithCancel(context.Background())
+	doneFn()
+	assert.NotNil(t, svc.WaitStatus(doneCtx, testSnapshotName, apiV1.Removed))
+}
+
+func setupSnapshotOperationsTest(t *testing.T) *SnapshotOperationsImpl {
+	k8sClient, err := k8s.GetFakeKubeClient(testNS, testLogger)
+	assert.Nil(t, err)
+	assert.NotNil(t, k8sClient)
+
+	return NewSnapshotOperationsImpl(k8sClient, testLogger)
+}
+
+func createTestSnapshotCR(t *testing.T, svc *SnapshotOperationsImpl, csiStatus string) *snapshotcrd.Snapshot {
+	s := svc.k8sClient.ConstructSnapshotCR(testSnapshotName, api.Snapshot{
+		Id:        testSnapshotName,
+		VolumeId:  testVolume1Name,
+		NodeId:    testNode1Name,
+		Location:  testLVGName,
+		Size:      testAC4.Spec.Size / 2,
+		CSIStatus: csiStatus,
+	})
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testSnapshotName, s))
+	return s
+}
@@ -32,6 +32,7 @@ import (
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
@@ -47,12 +48,14 @@ type VolumeOperations interface {
 	DeleteVolume(ctx context.Context, volumeID string) error
 	UpdateCRsAfterVolumeDeletion(ctx context.Context, volumeID string)
 	WaitStatus(ctx context.Context, volumeID string, statuses ...string) error
+	ExpandVolume(ctx context.Context, volumeID string, requiredBytes int64) (*api.Volume, error)
 }
 
 // VolumeOperationsImpl is the basic implementation of VolumeOperations interface
 type VolumeOperationsImpl struct {
 	acProvider             AvailableCapacityOperations
 	k8sClient              *k8s.KubeClient
+	crHelper               *k8s.CRHelper
 	capacityManagerBuilder capacityplanner.CapacityManagerBuilder
 
 	featureChecker fc.FeatureChecker
@@ -66,6 +69,7 @@ func NewVolumeOperationsImpl(k8sClient *k8s.KubeClient, logger *logrus.Logger,
 	featureConf fc.FeatureChecker) *VolumeOperationsImpl {
 	return &VolumeOperationsImpl{
 		k8sClient:              k8sClient,
+		crHelper:               k8s.NewCRHelper(k8sClient, logger),
 		acProvider:             NewACOperationsImpl(k8sClient, logger),
 		log:                    logger.WithField("component", "VolumeOperationsImpl"),
 		featureChecker:         featureConf,
@@ -74,6 +78,7 @@ func NewVolumeOperationsImpl(k8sClient *k8s.KubeClient, logger *logrus.Logger,
 }
 
 // CreateVolume searches AC and creates volume CR or returns existed volume CR
+// If v has content source, volume is placed on the same node and LVG as the source snapshot or volume
 // Receives golang context and api.Volume which is Spec of Volume CR to create
 // Returns api.Volume instance that took the place of chosen by SearchAC method AvailableCapacity CR
 func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume) (*api.Volume, error) {
@@ -108,12 +113,28 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 		ll.Errorf("Unable to read volume CR: %v", err)
 		return nil, status.Error(codes.Aborted, "unable to check volume existence")
 	default:
+		var sourceLocation string
+		if v.ContentSourceId != "" {
+			if sourceLocation, err = vo.fillVolumeFromContentSource(ctx, &v); err != nil {
+				ll.Errorf("Unable to use %s %s as a content source: %v", v.ContentSourceType, v.ContentSourceId, err)
+				return nil, err
+			}
+		}
+
+		// LUKS header is placed on the encrypted logical volume before the data
+		if v.Encrypted && util.IsStorageClassLVG(v.StorageClass) {
+			v.Size += capacityplanner.LuksHeaderSize
+		}
+
 		// create volume
 		var (
 			ac             *accrd.AvailableCapacity
+			members        []*accrd.AvailableCapacity
+			locations      []string
 			sc             string
 			requiredBytes  = v.Size
 			allocatedBytes int64
+			consumedBytes  int64 // raid1 logical volume consumes more than its size
 			locationType   string
 			csiStatus      = apiV1.Creating
 		)
@@ -122,9 +143,14 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 			requiredBytes = capacityplanner.AlignSizeByPE(requiredBytes)
 		}
 
-		capReader := capacityplanner.NewACReader(vo.k8sClient, vo.log, true)
+		var capReader capacityplanner.CapacityReader = capacityplanner.NewACReader(vo.k8sClient, vo.log, true)
 		resReader := capacityplanner.NewACRReader(vo.k8sClient, vo.log, true)
 
+		if v.ContentSourceId != "" {
+			// volume has to be placed on the same LVG as a content source
+			capReader = capacityplanner.NewLocationACReader(vo.log, capReader, v.NodeId, sourceLocation)
+		}
+
 		capacityManager := vo.createCapacityManager(capReader, resReader)
 		plan, err := capacityManager.PlanVolumesPlacing(ctxWithID, []*api.Volume{&v})
 		if err != nil {
@@ -145,23 +171,69 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 		}
 		origAC := ac
 		if ac.Spec.StorageClass != v.StorageClass && util.IsStorageClassLVG(v.StorageClass) {
-			// AC needs to be converted to LVG AC, LVG doesn't exist yet
-			if ac = vo.acProvider.RecreateACToLVGSC(ctxWithID, v.StorageClass, *ac); ac == nil {
+			// AC needs to be converted to LVG AC, LVG doesn't exist yet,
+			// LVG for striped or raid1 logical volume spans drives of all selected ACs
+			lvgACs := []accrd.AvailableCapacity{*ac}
+			if util.GetLVPVsCount(&v) > 1 {
+				lvgACs = lvgACs[:0]
+				for _, member := range plan.GetMemberACsForVolume(v.NodeId, &v) {
+					lvgACs = append(lvgACs, *member)
+				}
+			}
+			if ac = vo.acProvider.RecreateACToLVGSC(ctxWithID, v.StorageClass,
+				capacityplanner.GetOvercommitRatio(&v), lvgACs...); ac == nil {
 				return nil, status.Errorf(codes.Internal,
 					"unable to prepare underlying storage for storage class %s", v.StorageClass)
 			}
 		}
 		ll.Infof("AC %v was selected", ac)
 
+		// cache LV of the volume is placed on LVG of faster drives on the same node
+		var cacheAC, origCacheAC *accrd.AvailableCapacity
+		if v.CacheStorageClass != "" {
+			if cacheAC = plan.GetCacheACForVolume(v.NodeId, &v); cacheAC == nil {
+				return nil, status.Error(codes.ResourceExhausted, noResourceMsg)
+			}
+			origCacheAC = cacheAC
+			if cacheAC.Spec.StorageClass != v.CacheStorageClass {
+				if cacheAC = vo.acProvider.RecreateACToLVGSC(ctxWithID, v.CacheStorageClass, 0, *cacheAC); cacheAC == nil {
+					return nil, status.Errorf(codes.Internal,
+						"unable to prepare underlying storage for storage class %s", v.CacheStorageClass)
+				}
+			}
+			ll.Infof("AC %v was selected for cache", cacheAC)
+		}
+
 		// if sc was parsed as an ANY then we can choose AC with any storage class and then
 		// volume should be created with that particular SC
 		sc = ac.Spec.StorageClass
 
-		if util.IsStorageClassLVG(sc) {
+		switch {
+		case util.IsStorageClassLVG(sc):
 			allocatedBytes = requiredBytes
+			if util.GetLVPVsCount(&v) > 1 {
+				allocatedBytes = capacityplanner.GetLVSize(&v, requiredBytes)
+			}
+			// thin volume consumes only part of its size in thin pool LVG with overcommit
+			consumedBytes = capacityplanner.GetThinPhysicalSize(
+				capacityplanner.GetLVFootprint(&v, allocatedBytes), ac.Spec.OvercommitRatio)
 			locationType = apiV1.LocationTypeLVM
-		} else {
+		case util.IsStorageClassRAID(v.StorageClass):
+			// ac is the first member of MD RAID array, array size is defined by the smallest member
+			sc = v.StorageClass
+			members = plan.GetMemberACsForVolume(v.NodeId, &v)
+			memberSize := ac.Spec.Size
+			for _, member := range members {
+				locations = append(locations, member.Spec.Location)
+				if member.Spec.Size < memberSize {
+					memberSize = member.Spec.Size
+				}
+			}
+			allocatedBytes = util.GetRAIDArraySize(sc, memberSize-capacityplanner.MDRaidMetadataSize)
+			locationType = apiV1.LocationTypeMDRaid
+		default:
 			allocatedBytes = ac.Spec.Size
+			consumedBytes = allocatedBytes
 			locationType = apiV1.LocationTypeDrive
 		}
 
@@ -171,6 +243,7 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 			NodeId:            ac.Spec.NodeId,
 			Size:              allocatedBytes,
 			Location:          ac.Spec.Location,
+			Locations:         locations,
 			CSIStatus:         csiStatus,
 			StorageClass:      sc,
 			Ephemeral:         v.Ephemeral,
@@ -179,6 +252,25 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 			OperationalStatus: apiV1.OperationalStatusOperative,
 			Mode:              v.Mode,
 			Type:              v.Type,
+			ContentSourceType: v.ContentSourceType,
+			ContentSourceId:   v.ContentSourceId,
+			MkfsOptions:       v.MkfsOptions,
+			ReadBps:           v.ReadBps,
+			WriteBps:          v.WriteBps,
+			ReadIops:          v.ReadIops,
+			WriteIops:         v.WriteIops,
+			LVType:            v.LVType,
+			Stripes:           v.Stripes,
+			Mirrors:           v.Mirrors,
+			Encrypted:         v.Encrypted,
+			CacheStorageClass: v.CacheStorageClass,
+			CacheSize:         v.CacheSize,
+			CacheMode:         v.CacheMode,
+			ConsumedSize:      consumedBytes,
+			OvercommitRatio:   ac.Spec.OvercommitRatio,
+		}
+		if cacheAC != nil {
+			apiVolume.CacheLocation = cacheAC.Spec.Location
 		}
 		volumeCR = vo.k8sClient.ConstructVolumeCR(v.Id, apiVolume)
 
@@ -187,27 +279,149 @@ func (vo *VolumeOperationsImpl) CreateVolume(ctx context.Context, v api.Volume)
 			return nil, status.Errorf(codes.Internal, "unable to create volume CR")
 		}
 
-		// decrease AC size
-		ac.Spec.Size -= allocatedBytes
-		if err = vo.k8sClient.UpdateCRWithAttempts(ctxWithID, ac, 5); err != nil {
-			ll.Errorf("Unable to set size for AC %s to %d, error: %v", ac.Name, ac.Spec.Size, err)
+		if len(members) > 0 {
+			// each member of MD RAID array consumes the whole drive
+			for _, member := range members {
+				member.Spec.Size = 0
+				if err = vo.k8sClient.UpdateCRWithAttempts(ctxWithID, member, 5); err != nil {
+					ll.Errorf("Unable to set size for AC %s to %d, error: %v", member.Name, member.Spec.Size, err)
+				}
+			}
+		} else {
+			// decrease AC size
+			ac.Spec.Size -= consumedBytes
+			if err = vo.k8sClient.UpdateCRWithAttempts(ctxWithID, ac, 5); err != nil {
+				ll.Errorf("Unable to set size for AC %s to %d, error: %v", ac.Name, ac.Spec.Size, err)
+			}
+		}
+		if cacheAC != nil {
+			cacheAC.Spec.Size -= capacityplanner.GetCacheLVSize(&v)
+			if err = vo.k8sClient.UpdateCRWithAttempts(ctxWithID, cacheAC, 5); err != nil {
+				ll.Errorf("Unable to set size for AC %s to %d, error: %v", cacheAC.Name, cacheAC.Spec.Size, err)
+			}
 		}
 		if vo.featureChecker.IsEnabled(fc.FeatureACReservation) {
 			resHelper := capacityplanner.NewReservationHelper(vo.log, vo.k8sClient, capReader, resReader)
 			if err = resHelper.ReleaseReservation(ctxWithID, &v, origAC, ac); err != nil {
 				ll.Errorf("Unable to remove ACR reservation for AC %s, error: %v", ac.Name, err)
 			}
+			if cacheAC != nil {
+				// cache is reserved with cache storage class and size of the cache LV
+				cacheVol := &api.Volume{StorageClass: v.CacheStorageClass, Size: capacityplanner.GetCacheLVSize(&v)}
+				if err = resHelper.ReleaseReservation(ctxWithID, cacheVol, origCacheAC, cacheAC); err != nil {
+					ll.Errorf("Unable to remove ACR reservation for AC %s, error: %v", cacheAC.Name, err)
+				}
+			}
 		}
 	}
 	return &volumeCR.Spec, nil
 }
 
+// checkSourceFormat returns error if mode or FS type of v doesn't match the source volume,
+// content of the source is copied as is, so v gets the same FS
+func checkSourceFormat(v, source *api.Volume) error {
+	if v.Mode != source.Mode {
+		return status.Errorf(codes.InvalidArgument, "volume mode %s doesn't match mode %s of the source %s",
+			v.Mode, source.Mode, v.ContentSourceId)
+	}
+	sourceType := source.Type
+	if sourceType == "" {
+		sourceType = base.DefaultFsType
+	}
+	if v.Mode != apiV1.ModeRAW && v.Type != sourceType {
+		return status.Errorf(codes.InvalidArgument, "file system %s doesn't match file system %s of the source %s",
+			v.Type, sourceType, v.ContentSourceId)
+	}
+	return nil
+}
+
+// fillVolumeFromContentSource checks that content source of v could be used for volume creation and sets
+// node, storage class and size (if it wasn't provided) of v according to the source
+// Returns location (LVG CR name) of the source or error if source couldn't be used
+func (vo *VolumeOperationsImpl) fillVolumeFromContentSource(ctx context.Context, v *api.Volume) (string, error) {
+	var (
+		node     string
+		location string
+		size     int64
+	)
+
+	switch v.ContentSourceType {
+	case apiV1.ContentSourceSnapshot:
+		snapshot := &snapshotcrd.Snapshot{}
+		if err := vo.k8sClient.ReadCR(ctx, v.ContentSourceId, snapshot); err != nil {
+			if k8sError.IsNotFound(err) {
+				return "", status.Errorf(codes.NotFound, "snapshot %s isn't found", v.ContentSourceId)
+			}
+			return "", status.Error(codes.Aborted, "unable to read source snapshot CR")
+		}
+		if snapshot.Spec.CSIStatus != apiV1.Created {
+			return "", status.Errorf(codes.FailedPrecondition, "snapshot %s isn't ready, current status - %s",
+				v.ContentSourceId, snapshot.Spec.CSIStatus)
+		}
+		// volume with snapshots can't be deleted, so snapshot has the same format as its volume
+		volume := &volumecrd.Volume{}
+		if err := vo.k8sClient.ReadCR(ctx, snapshot.Spec.VolumeId, volume); err == nil {
+			if err = checkSourceFormat(v, &volume.Spec); err != nil {
+				return "", err
+			}
+		}
+		node, location, size = snapshot.Spec.NodeId, snapshot.Spec.Location, snapshot.Spec.Size
+	case apiV1.ContentSourceVolume:
+		volume := &volumecrd.Volume{}
+		if err := vo.k8sClient.ReadCR(ctx, v.ContentSourceId, volume); err != nil {
+			if k8sError.IsNotFound(err) {
+				return "", status.Errorf(codes.NotFound, "volume %s isn't found", v.ContentSourceId)
+			}
+			return "", status.Error(codes.Aborted, "unable to read source volume CR")
+		}
+		if volume.Spec.LocationType != apiV1.LocationTypeLVM {
+			return "", status.Errorf(codes.InvalidArgument,
+				"only volumes on LVG could be cloned, volume %s has location type %s",
+				v.ContentSourceId, volume.Spec.LocationType)
+		}
+		// published volume is copied from the temporary snapshot on the node, so it's consistent
+		if err := checkSourceFormat(v, &volume.Spec); err != nil {
+			return "", err
+		}
+		node, location, size = volume.Spec.NodeId, volume.Spec.Location, volume.Spec.Size
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unknown content source type %s", v.ContentSourceType)
+	}
+
+	if v.NodeId != "" && v.NodeId != node {
+		return "", status.Errorf(codes.ResourceExhausted, "source %s is placed on node %s, but node %s is required",
+			v.ContentSourceId, node, v.NodeId)
+	}
+	switch {
+	case v.Size == 0:
+		v.Size = size
+	case v.Size < size:
+		return "", status.Errorf(codes.OutOfRange, "required size %d is less than size %d of the source %s",
+			v.Size, size, v.ContentSourceId)
+	}
+
+	ac := vo.crHelper.GetACByLocation(location)
+	if ac == nil {
+		return "", status.Errorf(codes.ResourceExhausted, "there is no AC for LVG %s", location)
+	}
+	if v.StorageClass != apiV1.StorageClassAny && v.StorageClass != ac.Spec.StorageClass {
+		return "", status.Errorf(codes.InvalidArgument, "storage class %s doesn't match storage class %s of the source",
+			v.StorageClass, ac.Spec.StorageClass)
+	}
+
+	v.NodeId = node
+	v.StorageClass = ac.Spec.StorageClass
+	return location, nil
+}
+
 func (vo *VolumeOperationsImpl) createCapacityManager(capReader capacityplanner.CapacityReader,
 	resReader capacityplanner.ReservationReader) capacityplanner.CapacityPlaner {
 	if vo.featureChecker.IsEnabled(fc.FeatureACReservation) {
 		return vo.capacityManagerBuilder.GetReservedCapacityManager(vo.log, capReader, resReader)
 	}
-	return vo.capacityManagerBuilder.GetCapacityManager(vo.log, capReader)
+	return vo.capacityManagerBuilder.GetCapacityManager(vo.log, capReader,
+		capacityplanner.NewDriveReader(vo.k8sClient, vo.log, false),
+		capacityplanner.NewLVGReader(vo.k8sClient, vo.log, false))
 }
 
 // DeleteVolume changes volume CR state and updates it,
@@ -252,6 +466,20 @@ func (vo *VolumeOperationsImpl) DeleteVolume(ctx context.Context, volumeID strin
 			apiV1.Removing, volumeCR.Spec.CSIStatus, apiV1.Published)
 	}
 
+	// snapshot LVs are removed together with origin LV, so volume with snapshots can't be deleted
+	if volumeCR.Spec.LocationType == apiV1.LocationTypeLVM {
+		snapList := &snapshotcrd.SnapshotList{}
+		if err = vo.k8sClient.ReadList(ctx, snapList); err != nil {
+			ll.Errorf("Unable to read snapshots list: %v", err)
+			return status.Error(codes.Aborted, "unable to check snapshots of volume")
+		}
+		for _, snapshot := range snapList.Items {
+			if snapshot.Spec.VolumeId == volumeID {
+				return status.Errorf(codes.FailedPrecondition, "volume has snapshot %s", snapshot.Spec.Id)
+			}
+		}
+	}
+
 	volumeCR.Spec.CSIStatus = apiV1.Removing
 	return vo.k8sClient.UpdateCR(ctx, volumeCR)
 }
@@ -288,45 +516,183 @@ func (vo *VolumeOperationsImpl) UpdateCRsAfterVolumeDeletion(ctx context.Context
 			volumeCR.Spec.StorageClass, err)
 	}
 
+	if volumeCR.Spec.LocationType == apiV1.LocationTypeMDRaid {
+		vo.restoreMDRaidMembersACs(ctx, &volumeCR, acList.Items)
+		return
+	}
+
+	vo.restoreAC(ctx, &volumeCR, volumeCR.Spec.StorageClass, volumeCR.Spec.Location, acList.Items,
+		func(ac *accrd.AvailableCapacity) int64 {
+			// striped or raid1 logical volume consumes more space in LVG,
+			// thin volume consumes less space in thin pool LVG with overcommit
+			return capacityplanner.GetVolumeConsumedSize(&volumeCR.Spec, ac)
+		})
+	if volumeCR.Spec.CacheLocation != "" {
+		vo.restoreAC(ctx, &volumeCR, volumeCR.Spec.CacheStorageClass, volumeCR.Spec.CacheLocation, acList.Items,
+			func(*accrd.AvailableCapacity) int64 {
+				return capacityplanner.GetCacheLVSize(&volumeCR.Spec)
+			})
+	}
+}
+
+// restoreAC returns space which was consumed by the volume to AC with provided location and storage class,
+// consumedBytes calculates that space for AC. For LVG storage classes reference to the volume is removed from LVG,
+// LVG and its AC are deleted when no volumes remain
+func (vo *VolumeOperationsImpl) restoreAC(ctx context.Context, volumeCR *volumecrd.Volume, sc, location string,
+	acs []accrd.AvailableCapacity, consumedBytes func(ac *accrd.AvailableCapacity) int64) {
+	ll := vo.log.WithFields(logrus.Fields{
+		"method":   "restoreAC",
+		"volumeID": volumeCR.Name,
+	})
+
 	// search for AC
 	acCR := accrd.AvailableCapacity{}
-	for _, a := range acList.Items {
-		if a.Spec.Location == volumeCR.Spec.Location {
+	for _, a := range acs {
+		if a.Spec.Location == location {
 			acCR = a
 			break
 		}
 	}
 	// AC CR must exist
 	if acCR.Name == "" {
-		ll.Errorf("Unable to find available capacity resource for volume %s", volumeID)
+		ll.Errorf("Unable to find available capacity resource with location %s", location)
 		return
 	}
 
 	// for LVG SCs we need to delete AC CR when no volumes remain to avoid new allocations since
 	// underlying LVG CR is destroying. For other SC just to increase size
-	isDeleted := false
-	lvg := &lvgcrd.LVG{}
-	if util.IsStorageClassLVG(volumeCR.Spec.StorageClass) {
-		if err = vo.k8sClient.ReadCR(context.Background(), volumeCR.Spec.Location, lvg); err != nil {
-			ll.Errorf("Unable to get LVG %s: %v", volumeCR.Spec.Location, err)
+	var (
+		isDeleted bool
+		err       error
+		lvg       = &lvgcrd.LVG{}
+	)
+	if util.IsStorageClassLVG(sc) {
+		if err = vo.k8sClient.ReadCR(context.Background(), location, lvg); err != nil {
+			ll.Errorf("Unable to get LVG %s: %v", location, err)
 			return
 		}
 
 		if isDeleted, err = vo.deleteLVGIfVolumesNotExistOrUpdate(lvg, volumeCR.Name, &acCR); err != nil {
-			ll.Errorf("Unable to remove volume reference from LVG %s: %v", volumeCR.Spec.Location, err)
+			ll.Errorf("Unable to remove volume reference from LVG %s: %v", location, err)
 		}
 	}
 
-	// if LVG wasn't deleted increase AC size
-	if !isDeleted {
-		// Increase size of AC using volume size
-		acCR.Spec.Size += volumeCR.Spec.Size
+	// if LVG wasn't deleted increase AC size, AC of unhealthy LVG remains zeroed to avoid new allocations
+	if !isDeleted && (lvg.Spec.Health == "" || lvg.Spec.Health == apiV1.HealthGood) {
+		acCR.Spec.Size += consumedBytes(&acCR)
 		if err = vo.k8sClient.UpdateCRWithAttempts(ctx, &acCR, 5); err != nil {
 			ll.Errorf("Unable to update AC %s size: %v", acCR.Name, err)
 		}
 	}
 }
 
+// restoreMDRaidMembersACs returns size of the drives to ACs of MD RAID array members of volumeCR,
+// ACs of the unhealthy drives are removed by the node and are not restored
+func (vo *VolumeOperationsImpl) restoreMDRaidMembersACs(ctx context.Context, volumeCR *volumecrd.Volume,
+	acs []accrd.AvailableCapacity) {
+	ll := vo.log.WithFields(logrus.Fields{
+		"method":   "restoreMDRaidMembersACs",
+		"volumeID": volumeCR.Name,
+	})
+
+	for i := range acs {
+		ac := &acs[i]
+		if !util.ContainsString(volumeCR.Spec.Locations, ac.Spec.Location) {
+			continue
+		}
+		drive := vo.crHelper.GetDriveCRByUUID(ac.Spec.Location)
+		if drive == nil {
+			ll.Errorf("Unable to find drive %s of AC %s", ac.Spec.Location, ac.Name)
+			continue
+		}
+		ac.Spec.Size = drive.Spec.Size
+		if err := vo.k8sClient.UpdateCRWithAttempts(ctx, ac, 5); err != nil {
+			ll.Errorf("Unable to update AC %s size: %v", ac.Name, err)
+		}
+	}
+}
+
+// ExpandVolume increases size of the LVM volume CR up to requiredBytes and decreases size of the corresponding AC CR,
+// real expansion of the logical volume and file system is performed on the node side
+// Receives golang context, a volume ID to expand and a new size of the volume
+// Returns api.Volume with updated size or error if something went wrong
+func (vo *VolumeOperationsImpl) ExpandVolume(ctx context.Context, volumeID string,
+	requiredBytes int64) (*api.Volume, error) {
+	ll := vo.log.WithFields(logrus.Fields{
+		"method":   "ExpandVolume",
+		"volumeID": volumeID,
+	})
+	ll.Infof("Expanding volume up to %d bytes", requiredBytes)
+
+	var (
+		ctxWithID = context.WithValue(ctx, k8s.RequestUUID, volumeID)
+		volumeCR  = &volumecrd.Volume{}
+		err       error
+	)
+
+	if err = vo.k8sClient.ReadCR(ctx, volumeID, volumeCR); err != nil {
+		if k8sError.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "volume %s isn't found", volumeID)
+		}
+		ll.Errorf("Unable to read volume CR: %v", err)
+		return nil, status.Error(codes.Aborted, "unable to read volume CR")
+	}
+
+	if volumeCR.Spec.Encrypted && volumeCR.Spec.LocationType == apiV1.LocationTypeLVM {
+		requiredBytes += capacityplanner.LuksHeaderSize
+	}
+	if requiredBytes <= volumeCR.Spec.Size {
+		ll.Infof("Volume already has size %d, nothing to do", volumeCR.Spec.Size)
+		return &volumeCR.Spec, nil
+	}
+
+	if volumeCR.Spec.LocationType != apiV1.LocationTypeLVM {
+		return nil, status.Errorf(codes.OutOfRange,
+			"volume with location type %s can't be expanded", volumeCR.Spec.LocationType)
+	}
+	// logical volume with attached cache pool can't be resized
+	if volumeCR.Spec.CacheLocation != "" {
+		return nil, status.Error(codes.OutOfRange, "cached volume can't be expanded")
+	}
+
+	ac := vo.crHelper.GetACByLocation(volumeCR.Spec.Location)
+	if ac == nil {
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"there is no enough capacity in LVG %s to expand volume %s", volumeCR.Spec.Location, volumeID)
+	}
+
+	// only added part of the volume is accounted with current overcommit ratio of AC
+	requiredBytes = capacityplanner.GetLVSize(&volumeCR.Spec, requiredBytes)
+	consumedBytes := capacityplanner.GetVolumeConsumedSize(&volumeCR.Spec, ac)
+	deltaBytes := capacityplanner.GetThinPhysicalSize(capacityplanner.GetLVFootprint(&volumeCR.Spec, requiredBytes)-
+		capacityplanner.GetLVFootprint(&volumeCR.Spec, volumeCR.Spec.Size), ac.Spec.OvercommitRatio)
+
+	if ac.Spec.Size < deltaBytes {
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"there is no enough capacity in LVG %s to expand volume %s", volumeCR.Spec.Location, volumeID)
+	}
+
+	// decrease AC size
+	ac.Spec.Size -= deltaBytes
+	if err = vo.k8sClient.UpdateCRWithAttempts(ctxWithID, ac, 5); err != nil {
+		ll.Errorf("Unable to set size for AC %s to %d, error: %v", ac.Name, ac.Spec.Size, err)
+		return nil, status.Error(codes.Internal, "unable to update available capacity")
+	}
+
+	volumeCR.Spec.Size, volumeCR.Spec.ConsumedSize = requiredBytes, consumedBytes+deltaBytes
+	if err = vo.k8sClient.UpdateCRWithAttempts(ctxWithID, volumeCR, 5); err != nil {
+		ll.Errorf("Unable to set size for volume to %d, error: %v", requiredBytes, err)
+		// return capacity back to AC
+		ac.Spec.Size += deltaBytes
+		if err = vo.k8sClient.UpdateCRWithAttempts(ctxWithID, ac, 5); err != nil {
+			ll.Errorf("Unable to restore size for AC %s, error: %v", ac.Name, err)
+		}
+		return nil, status.Error(codes.Internal, "unable to update volume CR")
+	}
+
+	return &volumeCR.Spec, nil
+}
+
 // WaitStatus check volume status until it will be reached one of the statuses
 // return error if context is done or volume reaches failed status, return nil if reached status != failed
 func (vo *VolumeOperationsImpl) WaitStatus(ctx context.Context, volumeID string, statuses ...string) error {
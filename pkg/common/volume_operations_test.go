@@ -83,6 +83,7 @@ func TestVolumeOperationsImpl_CreateVolume_HDDVolumeCreated(t *testing.T) {
 			Health:            apiV1.HealthGood,
 			LocationType:      apiV1.LocationTypeDrive,
 			OperationalStatus: apiV1.OperationalStatusOperative,
+			ConsumedSize:      expectedAC.Spec.Size,
 		}
 	)
 
@@ -129,6 +130,7 @@ func TestVolumeOperationsImpl_CreateVolume_HDDLVGVolumeCreated(t *testing.T) {
 			Health:            apiV1.HealthGood,
 			LocationType:      apiV1.LocationTypeLVM,
 			OperationalStatus: apiV1.OperationalStatusOperative,
+			ConsumedSize:      requiredBytes,
 		}
 		createdVolume *api.Volume
 		err           error
@@ -143,7 +145,7 @@ func TestVolumeOperationsImpl_CreateVolume_HDDLVGVolumeCreated(t *testing.T) {
 	svc.capacityManagerBuilder = capMBuilder
 	capMMock.On("PlanVolumesPlacing", ctxWithID, mock.Anything).
 		Return(buildVolumePlacingPlan(testNode1Name, &expectedVolume, &acToReturn), nil).Times(1)
-	acProvider.On("RecreateACToLVGSC", ctxWithID, requiredSC, &acToReturn).
+	acProvider.On("RecreateACToLVGSC", ctxWithID, requiredSC, capacityplanner.DefaultThinOvercommitRatio, &acToReturn).
 		Return(&recreatedAC).Times(1)
 
 	createdVolume, err = svc.CreateVolume(testCtx, api.Volume{
@@ -157,6 +159,368 @@ func TestVolumeOperationsImpl_CreateVolume_HDDLVGVolumeCreated(t *testing.T) {
 	assert.Equal(t, expectedVolume, *createdVolume)
 }
 
+// Volume CR on MD RAID array was successfully created and removed, HDDRAID1 SC
+func TestVolumeOperationsImpl_CreateVolume_HDDRAID1VolumeCreated(t *testing.T) {
+	var (
+		svc      = setupVOOperationsTest(t)
+		volumeID = "pvc-aaaa-bbbb"
+		ac       = &accrd.AvailableCapacity{}
+	)
+	for _, acCR := range []accrd.AvailableCapacity{testAC2, testAC3} {
+		acCR := acCR
+		assert.Nil(t, svc.k8sClient.CreateCR(testCtx, acCR.Name, &acCR))
+		drive := svc.k8sClient.ConstructDriveCR(acCR.Spec.Location, api.Drive{
+			UUID:   acCR.Spec.Location,
+			NodeId: acCR.Spec.NodeId,
+			Size:   acCR.Spec.Size,
+		})
+		assert.Nil(t, svc.k8sClient.CreateCR(testCtx, drive.Name, drive))
+	}
+
+	createdVolume, err := svc.CreateVolume(testCtx, api.Volume{
+		Id:           volumeID,
+		StorageClass: apiV1.StorageClassHDDRAID1,
+		Size:         int64(util.GBYTE),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, testNode2Name, createdVolume.NodeId)
+	assert.Equal(t, apiV1.StorageClassHDDRAID1, createdVolume.StorageClass)
+	assert.Equal(t, apiV1.LocationTypeMDRaid, createdVolume.LocationType)
+	assert.ElementsMatch(t, []string{testDrive2UUID, testDrive3UUID}, createdVolume.Locations)
+	assert.Equal(t, createdVolume.Locations[0], createdVolume.Location)
+	// array size is defined by the smallest drive
+	assert.Equal(t, testAC2.Spec.Size-capacityplanner.MDRaidMetadataSize, createdVolume.Size)
+	for _, name := range []string{testAC2Name, testAC3Name} {
+		assert.Nil(t, svc.k8sClient.ReadCR(testCtx, name, ac))
+		assert.Equal(t, int64(0), ac.Spec.Size)
+	}
+
+	// there are no free drives for the second volume
+	_, err = svc.CreateVolume(testCtx, api.Volume{
+		Id:           "pvc-cccc-dddd",
+		StorageClass: apiV1.StorageClassHDDRAID1,
+		Size:         int64(util.GBYTE),
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// drives of the array members are returned to ACs
+	svc.UpdateCRsAfterVolumeDeletion(testCtx, volumeID)
+	assert.True(t, k8sError.IsNotFound(svc.k8sClient.ReadCR(testCtx, volumeID, &volumecrd.Volume{})))
+	for _, acCR := range []accrd.AvailableCapacity{testAC2, testAC3} {
+		assert.Nil(t, svc.k8sClient.ReadCR(testCtx, acCR.Name, ac))
+		assert.Equal(t, acCR.Spec.Size, ac.Spec.Size)
+	}
+}
+
+// Volume CR with raid1 logical volume was successfully created on the new LVG which spans two drives
+func TestVolumeOperationsImpl_CreateVolume_RAID1LVVolumeCreated(t *testing.T) {
+	var (
+		svc      = setupVOOperationsTest(t)
+		volumeID = "pvc-aaaa-bbbb"
+		lvg      = &lvgcrd.LVG{}
+	)
+	for _, acCR := range []accrd.AvailableCapacity{testAC2, testAC3} {
+		acCR := acCR
+		assert.Nil(t, svc.k8sClient.CreateCR(testCtx, acCR.Name, &acCR))
+	}
+
+	createdVolume, err := svc.CreateVolume(testCtx, api.Volume{
+		Id:           volumeID,
+		StorageClass: apiV1.StorageClassHDDLVG,
+		Size:         int64(util.GBYTE) + 1,
+		LVType:       apiV1.LVTypeRAID1,
+		Mirrors:      1,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, testNode2Name, createdVolume.NodeId)
+	assert.Equal(t, apiV1.LocationTypeLVM, createdVolume.LocationType)
+	assert.Equal(t, apiV1.LVTypeRAID1, createdVolume.LVType)
+	assert.Equal(t, int32(1), createdVolume.Mirrors)
+	assert.Equal(t, capacityplanner.AlignSizeByPE(int64(util.GBYTE)+1), createdVolume.Size)
+
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, createdVolume.Location, lvg))
+	assert.ElementsMatch(t, []string{testDrive2UUID, testDrive3UUID}, lvg.Spec.Locations)
+	// each copy is placed on its own drive, only the smallest drive is usable on each of them
+	ac := svc.crHelper.GetACByLocation(lvg.Name)
+	assert.NotNil(t, ac)
+	assert.Equal(t, 2*testAC2.Spec.Size-(createdVolume.Size+capacityplanner.DefaultPESize)*2, ac.Spec.Size)
+}
+
+func TestVolumeOperationsImpl_CreateVolume_ThinVolumeCreated(t *testing.T) {
+	var (
+		svc      = setupVOOperationsTest(t)
+		volumeID = "pvc-aaaa-bbbb"
+		lvg      = &lvgcrd.LVG{}
+		// volume is bigger than the drive
+		size = testAC2.Spec.Size * 3 / 2
+	)
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC2Name, &testAC2))
+
+	createdVolume, err := svc.CreateVolume(testCtx, api.Volume{
+		Id:           volumeID,
+		StorageClass: apiV1.StorageClassHDDLVGThin,
+		Size:         size,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.LocationTypeLVM, createdVolume.LocationType)
+	assert.Equal(t, apiV1.StorageClassHDDLVGThin, createdVolume.StorageClass)
+	assert.Equal(t, size, createdVolume.Size)
+
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, createdVolume.Location, lvg))
+	assert.True(t, lvg.Spec.ThinPool)
+	ac := svc.crHelper.GetACByLocation(lvg.Name)
+	assert.NotNil(t, ac)
+	assert.Equal(t, capacityplanner.DefaultThinOvercommitRatio, ac.Spec.OvercommitRatio)
+	assert.Equal(t, lvg.Spec.Size-size/2, ac.Spec.Size)
+	assert.Equal(t, size/2, createdVolume.ConsumedSize)
+}
+
+// Thin volume is accounted with consumed size which was recorded in volume CR, not with current ratio of AC
+func TestVolumeOperationsImpl_ExpandVolume_ThinVolume(t *testing.T) {
+	var (
+		svc      = setupVOOperationsTest(t)
+		volumeID = "pvc-aaaa-bbbb"
+		lvg      = &lvgcrd.LVG{}
+		size     = capacityplanner.AlignSizeByPE(testAC2.Spec.Size * 3 / 2)
+	)
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC2Name, &testAC2))
+
+	createdVolume, err := svc.CreateVolume(testCtx, api.Volume{
+		Id:              volumeID,
+		StorageClass:    apiV1.StorageClassHDDLVGThin,
+		Size:            size,
+		OvercommitRatio: 3,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, float64(3), createdVolume.OvercommitRatio)
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, createdVolume.Location, lvg))
+	ac := svc.crHelper.GetACByLocation(lvg.Name)
+	assert.NotNil(t, ac)
+	assert.Equal(t, float64(3), ac.Spec.OvercommitRatio)
+	assert.Equal(t, lvg.Spec.Size-createdVolume.ConsumedSize, ac.Spec.Size)
+
+	// overcommit ratio of AC is edited, only added part of the volume is accounted with the new ratio
+	ac.Spec.OvercommitRatio = 1.5
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, ac))
+	expandedVolume, err := svc.ExpandVolume(testCtx, volumeID, size+3*capacityplanner.DefaultPESize)
+	assert.Nil(t, err)
+	assert.Equal(t, createdVolume.ConsumedSize+2*capacityplanner.DefaultPESize, expandedVolume.ConsumedSize)
+	ac = svc.crHelper.GetACByLocation(lvg.Name)
+	assert.NotNil(t, ac)
+	assert.Equal(t, lvg.Spec.Size-expandedVolume.ConsumedSize, ac.Spec.Size)
+}
+
+// Encrypted volume CR was successfully created on LVG with space for LUKS header and expanded
+func TestVolumeOperationsImpl_CreateVolume_EncryptedVolumeCreated(t *testing.T) {
+	var (
+		svc      = setupVOOperationsTest(t)
+		volumeID = "pvc-aaaa-bbbb"
+		size     = int64(util.GBYTE)
+	)
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC2Name, &testAC2))
+
+	createdVolume, err := svc.CreateVolume(testCtx, api.Volume{
+		Id:           volumeID,
+		StorageClass: apiV1.StorageClassHDDLVG,
+		Size:         size,
+		Encrypted:    true,
+	})
+	assert.Nil(t, err)
+	assert.True(t, createdVolume.Encrypted)
+	assert.Equal(t, size+capacityplanner.LuksHeaderSize, createdVolume.Size)
+	ac := svc.crHelper.GetACByLocation(createdVolume.Location)
+	assert.NotNil(t, ac)
+	acSize := ac.Spec.Size
+
+	// volume already has required size for data
+	vol, err := svc.ExpandVolume(testCtx, volumeID, size)
+	assert.Nil(t, err)
+	assert.Equal(t, size+capacityplanner.LuksHeaderSize, vol.Size)
+
+	vol, err = svc.ExpandVolume(testCtx, volumeID, size*2)
+	assert.Nil(t, err)
+	assert.Equal(t, size*2+capacityplanner.LuksHeaderSize, vol.Size)
+	ac = svc.crHelper.GetACByLocation(createdVolume.Location)
+	assert.Equal(t, acSize-size, ac.Spec.Size)
+}
+
+// Cached volume CR was successfully created on HDD LVG with cache on the new NVMe LVG and removed
+func TestVolumeOperationsImpl_CreateVolume_CachedVolumeCreated(t *testing.T) {
+	var (
+		svc      = setupVOOperationsTest(t)
+		volumeID = "pvc-aaaa-bbbb"
+		size     = int64(util.GBYTE)
+		hddLVG   = testLVG
+		nvmeAC   = testAC3
+		lvg      = &lvgcrd.LVG{}
+		ac       = &accrd.AvailableCapacity{}
+	)
+	hddLVG.Spec.Status = apiV1.Created
+	nvmeAC.Spec.StorageClass = apiV1.StorageClassNVMe
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC4Name, &testAC4))
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testLVGName, &hddLVG))
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC3Name, &nvmeAC))
+
+	createdVolume, err := svc.CreateVolume(testCtx, api.Volume{
+		Id:                volumeID,
+		StorageClass:      apiV1.StorageClassHDDLVG,
+		Size:              size,
+		CacheStorageClass: apiV1.StorageClassNVMeLVG,
+		CacheSize:         size / 10,
+		CacheMode:         apiV1.CacheModeWriteback,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, testLVGName, createdVolume.Location)
+	assert.Equal(t, apiV1.CacheModeWriteback, createdVolume.CacheMode)
+	assert.NotEqual(t, "", createdVolume.CacheLocation)
+	cacheLVSize := capacityplanner.GetCacheLVSize(createdVolume)
+
+	// both ACs are decreased
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testAC4Name, ac))
+	assert.Equal(t, testAC4.Spec.Size-size, ac.Spec.Size)
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, createdVolume.CacheLocation, lvg))
+	assert.Equal(t, []string{testDrive3UUID}, lvg.Spec.Locations)
+	cacheAC := svc.crHelper.GetACByLocation(lvg.Name)
+	assert.NotNil(t, cacheAC)
+	assert.Equal(t, apiV1.StorageClassNVMeLVG, cacheAC.Spec.StorageClass)
+	assert.Equal(t, lvg.Spec.Size-cacheLVSize, cacheAC.Spec.Size)
+
+	// volume references are added by the node
+	hddLVG.Spec.VolumeRefs = []string{volumeID, "pvc-cccc-dddd"}
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, &hddLVG))
+	lvg.Spec.VolumeRefs = []string{volumeID}
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, lvg))
+
+	// HDD LVG AC is restored, NVMe LVG without volumes is removed along with its AC
+	svc.UpdateCRsAfterVolumeDeletion(testCtx, volumeID)
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testAC4Name, ac))
+	assert.Equal(t, testAC4.Spec.Size, ac.Spec.Size)
+	assert.True(t, k8sError.IsNotFound(svc.k8sClient.ReadCR(testCtx, lvg.Name, &lvgcrd.LVG{})))
+	assert.True(t, k8sError.IsNotFound(svc.k8sClient.ReadCR(testCtx, cacheAC.Name, &accrd.AvailableCapacity{})))
+}
+
+// Volume CR was successfully created from snapshot on the same node and LVG as the snapshot
+func TestVolumeOperationsImpl_CreateVolume_FromContentSource(t *testing.T) {
+	var (
+		svc        = setupVOOperationsTest(t)
+		volumeID   = "pvc-aaaa-bbbb"
+		snapshotID = "snapshot-1"
+		// AC with the same SC on another node
+		anotherAC = accrd.AvailableCapacity{
+			TypeMeta:   testAC4.TypeMeta,
+			ObjectMeta: v1.ObjectMeta{Name: "another-ac", Namespace: testNS},
+			Spec: api.AvailableCapacity{
+				Size:         testAC4.Spec.Size * 2,
+				StorageClass: apiV1.StorageClassHDDLVG,
+				Location:     "another-lvg",
+				NodeId:       testNode1Name,
+			},
+		}
+		snapshot = api.Snapshot{
+			Id:        snapshotID,
+			VolumeId:  testVolume1Name,
+			NodeId:    testNode2Name,
+			Location:  testLVGName,
+			Size:      int64(util.GBYTE),
+			CSIStatus: apiV1.Created,
+		}
+	)
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC4Name, &testAC4))
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, anotherAC.Name, &anotherAC))
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, snapshotID, svc.k8sClient.ConstructSnapshotCR(snapshotID, snapshot)))
+
+	createdVolume, err := svc.CreateVolume(testCtx, api.Volume{
+		Id:                volumeID,
+		StorageClass:      apiV1.StorageClassAny,
+		ContentSourceType: apiV1.ContentSourceSnapshot,
+		ContentSourceId:   snapshotID,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, testNode2Name, createdVolume.NodeId)
+	assert.Equal(t, testLVGName, createdVolume.Location)
+	assert.Equal(t, apiV1.StorageClassHDDLVG, createdVolume.StorageClass)
+	assert.Equal(t, snapshot.Size, createdVolume.Size)
+	assert.Equal(t, apiV1.ContentSourceSnapshot, createdVolume.ContentSourceType)
+	assert.Equal(t, snapshotID, createdVolume.ContentSourceId)
+
+	// snapshot on another node
+	_, err = svc.CreateVolume(testCtx, api.Volume{
+		Id:                "pvc-cccc-dddd",
+		NodeId:            testNode1Name,
+		StorageClass:      apiV1.StorageClassAny,
+		ContentSourceType: apiV1.ContentSourceSnapshot,
+		ContentSourceId:   snapshotID,
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// required size less than source size
+	_, err = svc.CreateVolume(testCtx, api.Volume{
+		Id:                "pvc-cccc-dddd",
+		Size:              snapshot.Size / 2,
+		StorageClass:      apiV1.StorageClassAny,
+		ContentSourceType: apiV1.ContentSourceSnapshot,
+		ContentSourceId:   snapshotID,
+	})
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+
+	// source volume isn't on LVG
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testVolume1Name, &testVolume1))
+	_, err = svc.CreateVolume(testCtx, api.Volume{
+		Id:                "pvc-cccc-dddd",
+		StorageClass:      apiV1.StorageClassAny,
+		ContentSourceType: apiV1.ContentSourceVolume,
+		ContentSourceId:   testVolume1Name,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// source volume is published, it's copied from the temporary snapshot
+	publishedVolume := testVolume1
+	publishedVolume.Name, publishedVolume.Spec.Id = "published-volume", "published-volume"
+	publishedVolume.Spec.LocationType = apiV1.LocationTypeLVM
+	publishedVolume.Spec.NodeId, publishedVolume.Spec.Location = testNode2Name, testLVGName
+	publishedVolume.Spec.Mode, publishedVolume.Spec.Type = apiV1.ModeFS, "xfs"
+	publishedVolume.Spec.CSIStatus = apiV1.Published
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, publishedVolume.Name, &publishedVolume))
+	createdVolume, err = svc.CreateVolume(testCtx, api.Volume{
+		Id:                "pvc-cccc-dddd",
+		StorageClass:      apiV1.StorageClassAny,
+		Mode:              apiV1.ModeFS,
+		Type:              "xfs",
+		ContentSourceType: apiV1.ContentSourceVolume,
+		ContentSourceId:   publishedVolume.Name,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, publishedVolume.Name, createdVolume.ContentSourceId)
+
+	// mode or file system doesn't match the source
+	_, err = svc.CreateVolume(testCtx, api.Volume{
+		Id:                "pvc-eeee-ffff",
+		StorageClass:      apiV1.StorageClassAny,
+		Mode:              apiV1.ModeRAW,
+		ContentSourceType: apiV1.ContentSourceVolume,
+		ContentSourceId:   publishedVolume.Name,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = svc.CreateVolume(testCtx, api.Volume{
+		Id:                "pvc-eeee-ffff",
+		StorageClass:      apiV1.StorageClassAny,
+		Mode:              apiV1.ModeFS,
+		Type:              "ext4",
+		ContentSourceType: apiV1.ContentSourceVolume,
+		ContentSourceId:   publishedVolume.Name,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// source doesn't exist
+	_, err = svc.CreateVolume(testCtx, api.Volume{
+		Id:                "pvc-gggg-hhhh",
+		StorageClass:      apiV1.StorageClassAny,
+		ContentSourceType: apiV1.ContentSourceVolume,
+		ContentSourceId:   "unknown",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
 // Volume CR exists and has "failed" CSIStatus
 func TestVolumeOperationsImpl_CreateVolume_FaileCauseExist(t *testing.T) {
 	svc := setupVOOperationsTest(t)
@@ -242,7 +606,7 @@ func TestVolumeOperationsImpl_CreateVolume_FailRecreateAC(t *testing.T) {
 	svc.capacityManagerBuilder = capMBuilder
 	capMMock.On("PlanVolumesPlacing", ctxWithID, mock.Anything).
 		Return(buildVolumePlacingPlan(testNode1Name, &expectedVolume, &acToReturn), nil).Times(1)
-	acProvider.On("RecreateACToLVGSC", ctxWithID, requiredSC, mock.Anything).
+	acProvider.On("RecreateACToLVGSC", ctxWithID, requiredSC, capacityplanner.DefaultThinOvercommitRatio, mock.Anything).
 		Return(nil).Times(1)
 
 	createdVolume, err := svc.CreateVolume(testCtx, api.Volume{
@@ -350,6 +714,36 @@ func TestVolumeOperationsImpl_DeleteVolume_SetStatus(t *testing.T) {
 	assert.Equal(t, apiV1.Removing, updatedVol.Spec.CSIStatus)
 }
 
+func TestVolumeOperationsImpl_DeleteVolume_HasSnapshots(t *testing.T) {
+	var (
+		svc        = setupVOOperationsTest(t)
+		v          = testVolume1
+		snapshotID = "snapshot-1"
+		updatedVol = volumecrd.Volume{}
+	)
+
+	v.Spec.CSIStatus = apiV1.Created
+	v.Spec.LocationType = apiV1.LocationTypeLVM
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testVolume1Name, &v))
+	snapshotCR := svc.k8sClient.ConstructSnapshotCR(snapshotID, api.Snapshot{
+		Id:        snapshotID,
+		VolumeId:  testVolume1Name,
+		CSIStatus: apiV1.Created,
+	})
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, snapshotID, snapshotCR))
+
+	err := svc.DeleteVolume(testCtx, testVolume1Name)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testVolume1Name, &updatedVol))
+	assert.Equal(t, apiV1.Created, updatedVol.Spec.CSIStatus)
+
+	// snapshot was deleted
+	assert.Nil(t, svc.k8sClient.DeleteCR(testCtx, snapshotCR))
+	assert.Nil(t, svc.DeleteVolume(testCtx, testVolume1Name))
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testVolume1Name, &updatedVol))
+	assert.Equal(t, apiV1.Removing, updatedVol.Spec.CSIStatus)
+}
+
 func TestVolumeOperationsImpl_WaitStatus_Success(t *testing.T) {
 	svc := setupVOOperationsTest(t)
 
@@ -421,6 +815,79 @@ func TestVolumeOperationsImpl_UpdateCRsAfterVolumeDeletion(t *testing.T) {
 	err = svc1.k8sClient.ReadCR(testCtx, testAC4Name, updatedAC)
 	assert.Nil(t, err)
 	assert.Equal(t, testAC4.Spec.Size+v1.Spec.Size, updatedAC.Spec.Size)
+
+	// 2. LVG is unhealthy, AC size should not be increased
+	lvg := &lvgcrd.LVG{}
+	assert.Nil(t, svc1.k8sClient.ReadCR(testCtx, testLVGName, lvg))
+	lvg.Spec.Health = apiV1.HealthBad
+	assert.Nil(t, svc1.k8sClient.UpdateCR(testCtx, lvg))
+	v2 := testVolume1
+	v2.Spec.StorageClass = apiV1.StorageClassHDDLVG
+	v2.Spec.Location = testLVGName
+	assert.Nil(t, svc1.k8sClient.CreateCR(testCtx, testVolume1Name, &v2))
+
+	svc1.UpdateCRsAfterVolumeDeletion(testCtx, testVolume1Name)
+	acSize := updatedAC.Spec.Size
+	assert.Nil(t, svc1.k8sClient.ReadCR(testCtx, testAC4Name, updatedAC))
+	assert.Equal(t, acSize, updatedAC.Spec.Size)
+}
+
+func TestVolumeOperationsImpl_ExpandVolume(t *testing.T) {
+	var (
+		svc           = setupVOOperationsTest(t)
+		requiredBytes = int64(util.GBYTE) * 2
+		v             = testVolume1
+		err           error
+	)
+
+	// volume CR wasn't found
+	_, err = svc.ExpandVolume(testCtx, testVolume1Name, requiredBytes)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// volume isn't on LVG
+	v.Spec.CSIStatus = apiV1.Published
+	v.Spec.LocationType = apiV1.LocationTypeDrive
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testVolume1Name, &v))
+	_, err = svc.ExpandVolume(testCtx, testVolume1Name, requiredBytes)
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+
+	// required size is less than current
+	vol, err := svc.ExpandVolume(testCtx, testVolume1Name, v.Spec.Size)
+	assert.Nil(t, err)
+	assert.Equal(t, v.Spec.Size, vol.Size)
+
+	// there is no AC for LVG
+	v.Spec.LocationType = apiV1.LocationTypeLVM
+	v.Spec.StorageClass = apiV1.StorageClassHDDLVG
+	v.Spec.Location = testLVGName
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, &v))
+	_, err = svc.ExpandVolume(testCtx, testVolume1Name, requiredBytes)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// not enough capacity in LVG
+	ac := testAC4
+	assert.Nil(t, svc.k8sClient.CreateCR(testCtx, testAC4Name, &ac))
+	_, err = svc.ExpandVolume(testCtx, testVolume1Name, testAC4.Spec.Size*2)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// success
+	vol, err = svc.ExpandVolume(testCtx, testVolume1Name, requiredBytes)
+	assert.Nil(t, err)
+	assert.Equal(t, capacityplanner.AlignSizeByPE(requiredBytes), vol.Size)
+
+	var updatedAC = &accrd.AvailableCapacity{}
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testAC4Name, updatedAC))
+	assert.Equal(t, testAC4.Spec.Size-(vol.Size-v.Spec.Size), updatedAC.Spec.Size)
+
+	var updatedVolume = &volumecrd.Volume{}
+	assert.Nil(t, svc.k8sClient.ReadCR(testCtx, testVolume1Name, updatedVolume))
+	assert.Equal(t, vol.Size, updatedVolume.Spec.Size)
+
+	// cached volume can't be expanded
+	updatedVolume.Spec.CacheLocation = "lvg-2"
+	assert.Nil(t, svc.k8sClient.UpdateCR(testCtx, updatedVolume))
+	_, err = svc.ExpandVolume(testCtx, testVolume1Name, requiredBytes*2)
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
 }
 
 func TestVolumeOperationsImpl_deleteLVGIfVolumesNotExistOrUpdate(t *testing.T) {
@@ -19,10 +19,14 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
@@ -32,10 +36,14 @@ import (
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/common"
 	"github.com/dell/csi-baremetal/pkg/controller/node"
@@ -53,7 +61,9 @@ type CSIControllerService struct {
 	reqMu sync.Mutex
 	log   *logrus.Entry
 
-	svc common.VolumeOperations
+	svc            common.VolumeOperations
+	snapSvc        common.SnapshotOperations
+	featureChecker featureconfig.FeatureChecker
 
 	// to track node health status
 	nodeServicesStateMonitor *node.ServicesStateMonitor
@@ -73,6 +83,8 @@ func NewControllerService(k8sClient *k8s.KubeClient, logger *logrus.Logger,
 		k8sclient:                k8sClient,
 		log:                      logger.WithField("component", "CSIControllerService"),
 		svc:                      common.NewVolumeOperationsImpl(k8sClient, logger, featureConf),
+		snapSvc:                  common.NewSnapshotOperationsImpl(k8sClient, logger),
+		featureChecker:           featureConf,
 		nodeServicesStateMonitor: node.NewNodeServicesStateMonitor(k8sClient, logger),
 		IdentityServer:           NewIdentityServer(base.PluginName, base.PluginVersion),
 	}
@@ -149,6 +161,12 @@ func (c *CSIControllerService) CreateVolume(ctx context.Context, req *csi.Create
 	if req.GetVolumeCapabilities() == nil || len(req.GetVolumeCapabilities()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume capabilities missing in request")
 	}
+	for _, vc := range req.GetVolumeCapabilities() {
+		if !util.IsAccessModeSupported(vc.GetAccessMode()) {
+			return nil, status.Errorf(codes.InvalidArgument, "Access mode %s isn't supported",
+				vc.GetAccessMode().GetMode())
+		}
+	}
 
 	preferredNode := ""
 	if req.GetAccessibilityRequirements() != nil && len(req.GetAccessibilityRequirements().Preferred) > 0 {
@@ -163,22 +181,66 @@ func (c *CSIControllerService) CreateVolume(ctx context.Context, req *csi.Create
 		vol    *api.Volume
 	)
 
-	if accessType, ok := req.GetVolumeCapabilities()[0].AccessType.(*csi.VolumeCapability_Mount); ok {
-		fsType = strings.ToLower(accessType.Mount.FsType) // ext4 by default (from request)
+	switch accessType := req.GetVolumeCapabilities()[0].AccessType.(type) {
+	case *csi.VolumeCapability_Mount:
+		fsType = strings.ToLower(accessType.Mount.FsType)
+		// FS type is stored in volume CR, so node creates FS and validates mount options of the same type
+		if fsType == "" {
+			fsType = base.DefaultFsType
+		}
 		mode = apiV1.ModeFS
-	} else {
-		return nil, status.Error(codes.Unimplemented, "Block mode is unimplemented")
+		if err = fs.ValidateMountOptions(fs.FileSystem(fsType), accessType.Mount.GetMountFlags()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	case *csi.VolumeCapability_Block:
+		mode = apiV1.ModeRAW
+	default:
+		return nil, status.Error(codes.InvalidArgument, "Unknown access type")
+	}
+
+	var contentSourceType, contentSourceID string
+	if src := req.GetVolumeContentSource(); src != nil {
+		switch {
+		case src.GetSnapshot() != nil:
+			contentSourceType, contentSourceID = apiV1.ContentSourceSnapshot, src.GetSnapshot().GetSnapshotId()
+		case src.GetVolume() != nil:
+			contentSourceType, contentSourceID = apiV1.ContentSourceVolume, src.GetVolume().GetVolumeId()
+		}
+		if contentSourceID == "" {
+			return nil, status.Error(codes.InvalidArgument, "Volume content source is incorrect")
+		}
+		ll.Infof("Volume will be created from %s %s", contentSourceType, contentSourceID)
+	}
+
+	volume := api.Volume{
+		Id:                req.Name,
+		StorageClass:      util.ConvertStorageClass(req.Parameters[base.StorageTypeKey]),
+		NodeId:            preferredNode,
+		Size:              req.GetCapacityRange().GetRequiredBytes(),
+		Mode:              mode,
+		Type:              fsType,
+		ContentSourceType: contentSourceType,
+		ContentSourceId:   contentSourceID,
+		MkfsOptions:       req.Parameters[base.MkfsOptionsKey],
+	}
+	if err = util.FillIOLimits(&volume, req.GetParameters()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = util.FillLVType(&volume, req.GetParameters()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = util.FillEncryption(&volume, req.GetParameters()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = util.FillCache(&volume, req.GetParameters()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err = util.FillOvercommitRatio(&volume, req.GetParameters()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	c.reqMu.Lock()
-	vol, err = c.svc.CreateVolume(ctx, api.Volume{
-		Id:           req.Name,
-		StorageClass: util.ConvertStorageClass(req.Parameters[base.StorageTypeKey]),
-		NodeId:       preferredNode,
-		Size:         req.GetCapacityRange().GetRequiredBytes(),
-		Mode:         mode,
-		Type:         fsType,
-	})
+	vol, err = c.svc.CreateVolume(ctx, volume)
 	c.reqMu.Unlock()
 
 	if err != nil {
@@ -202,6 +264,7 @@ func (c *CSIControllerService) CreateVolume(ctx context.Context, req *csi.Create
 			VolumeId:           req.Name,
 			CapacityBytes:      vol.Size,
 			VolumeContext:      req.GetParameters(),
+			ContentSource:      req.GetVolumeContentSource(),
 			AccessibleTopology: topologyList,
 		},
 	}, nil
@@ -307,23 +370,209 @@ func (c *CSIControllerService) ControllerUnpublishVolume(ctx context.Context,
 	return &csi.ControllerUnpublishVolumeResponse{}, nil
 }
 
-// ValidateVolumeCapabilities is not implemented yet
-func (c *CSIControllerService) ValidateVolumeCapabilities(context.Context, *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "not implemented yet")
+// ValidateVolumeCapabilities is the implementation of CSI Spec ValidateVolumeCapabilities. This method checks that
+// volume supports each of requested capabilities: access mode should be bound to a single node,
+// access type should match volume mode and mount flags should be allowed for file system of the volume
+// Receives golang context and CSI Spec ValidateVolumeCapabilitiesRequest
+// Returns CSI Spec ValidateVolumeCapabilitiesResponse with confirmed capabilities or with message why
+// they aren't supported, or error if request is incorrect or Volume CR wasn't found
+func (c *CSIControllerService) ValidateVolumeCapabilities(ctx context.Context,
+	req *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":   "ValidateVolumeCapabilities",
+		"volumeID": req.GetVolumeId(),
+	})
+
+	if req.GetVolumeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID must be provided")
+	}
+	if len(req.GetVolumeCapabilities()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume capabilities must be provided")
+	}
+
+	vol := &volumecrd.Volume{}
+	if err := c.k8sclient.ReadCR(ctx, req.GetVolumeId(), vol); err != nil {
+		if k8sError.IsNotFound(err) {
+			return nil, status.Error(codes.NotFound, "Volume is not found")
+		}
+		ll.Errorf("k8s client can't read volume CR: %v", err)
+		return nil, status.Error(codes.Unavailable, "Something went wrong with k8s client")
+	}
+
+	for _, vc := range req.GetVolumeCapabilities() {
+		if msg := validateVolumeCapability(&vol.Spec, vc); msg != "" {
+			ll.Infof("Volume capability %v isn't supported: %s", vc, msg)
+			return &csi.ValidateVolumeCapabilitiesResponse{Message: msg}, nil
+		}
+	}
+
+	return &csi.ValidateVolumeCapabilitiesResponse{
+		Confirmed: &csi.ValidateVolumeCapabilitiesResponse_Confirmed{
+			VolumeContext:      req.GetVolumeContext(),
+			VolumeCapabilities: req.GetVolumeCapabilities(),
+			Parameters:         req.GetParameters(),
+		},
+	}, nil
 }
 
-// ListVolumes is not implemented yet
-func (c *CSIControllerService) ListVolumes(context.Context, *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "not implemented yet")
+// validateVolumeCapability returns the reason why volume doesn't support capability or empty string if it does
+func validateVolumeCapability(vol *api.Volume, vc *csi.VolumeCapability) string {
+	if !util.IsAccessModeSupported(vc.GetAccessMode()) {
+		return fmt.Sprintf("access mode %s isn't supported", vc.GetAccessMode().GetMode())
+	}
+
+	switch accessType := vc.GetAccessType().(type) {
+	case *csi.VolumeCapability_Block:
+		if vol.Mode != apiV1.ModeRAW {
+			return fmt.Sprintf("volume has %s mode and couldn't be used as a block device", vol.Mode)
+		}
+	case *csi.VolumeCapability_Mount:
+		if vol.Mode != apiV1.ModeFS {
+			return fmt.Sprintf("volume has %s mode and couldn't be mounted", vol.Mode)
+		}
+		fsType := strings.ToLower(accessType.Mount.GetFsType())
+		if fsType != "" && vol.Type != "" && fsType != vol.Type {
+			return fmt.Sprintf("volume has %s file system, requested - %s", vol.Type, fsType)
+		}
+		if err := fs.ValidateMountOptions(fs.FileSystem(vol.Type), accessType.Mount.GetMountFlags()); err != nil {
+			return err.Error()
+		}
+	default:
+		return "unknown access type"
+	}
+	return ""
 }
 
-// GetCapacity is not implemented yet
-func (c *CSIControllerService) GetCapacity(context.Context, *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "not implemented yet")
+// ListVolumes is the implementation of CSI Spec ListVolumes. This method lists Volume CRs with their capacity,
+// topology, published node and condition. StartingToken is an index of the first entry in the sorted list of volumes
+// Receives golang context and CSI Spec ListVolumesRequest
+// Returns CSI Spec ListVolumesResponse or error if something went wrong
+func (c *CSIControllerService) ListVolumes(ctx context.Context,
+	req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method": "ListVolumes",
+	})
+	ll.Infof("Processing request: %v", req)
+
+	volList := &volumecrd.VolumeList{}
+	if err := c.k8sclient.ReadList(ctx, volList); err != nil {
+		ll.Errorf("Unable to read volumes list: %v", err)
+		return nil, status.Error(codes.Internal, "unable to read volumes list")
+	}
+
+	volumes := make([]api.Volume, 0, len(volList.Items))
+	for _, v := range volList.Items {
+		if v.Spec.CSIStatus == apiV1.Removing || v.Spec.CSIStatus == apiV1.Removed {
+			continue
+		}
+		volumes = append(volumes, v.Spec)
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Id < volumes[j].Id })
+
+	start, end, err := paginate(len(volumes), req.GetStartingToken(), req.GetMaxEntries())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &csi.ListVolumesResponse{
+		Entries: make([]*csi.ListVolumesResponse_Entry, 0, end-start),
+	}
+	for i := start; i < end; i++ {
+		vol := &volumes[i]
+		entry := &csi.ListVolumesResponse_Entry{
+			Volume: &csi.Volume{
+				VolumeId:      vol.Id,
+				CapacityBytes: vol.Size,
+				AccessibleTopology: []*csi.Topology{
+					{Segments: map[string]string{csibmnode.NodeIDAnnotationKey: vol.NodeId}},
+				},
+			},
+			Status: &csi.ListVolumesResponse_VolumeStatus{
+				VolumeCondition: util.GetVolumeCondition(vol),
+			},
+		}
+		if vol.CSIStatus == apiV1.Published {
+			entry.Status.PublishedNodeIds = []string{vol.NodeId}
+		}
+		resp.Entries = append(resp.Entries, entry)
+	}
+	if end < len(volumes) {
+		resp.NextToken = strconv.Itoa(end)
+	}
+
+	return resp, nil
+}
+
+// GetCapacity is the implementation of CSI Spec GetCapacity. This method returns size of the largest volume
+// with storage type from request parameters which could be allocated in one AvailableCapacity CR
+// on the node from request topology (or on any node if topology isn't provided).
+// Size is counted per node: drives are combined into MD RAID array, thin pool LVG provides overcommitted size
+// and cached volumes are available only on nodes with capacity for the cache.
+// If ACReservation feature is enabled then reserved ACs are not taken into account.
+// Receives golang context and CSI Spec GetCapacityRequest
+// Returns CSI Spec GetCapacityResponse or error if something went wrong
+func (c *CSIControllerService) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method": "GetCapacity",
+	})
+
+	ll.Infof("Processing request: %v", req)
+
+	var (
+		sc        = util.ConvertStorageClass(req.GetParameters()[base.StorageTypeKey])
+		cacheSC   string
+		nodeID    = req.GetAccessibleTopology().GetSegments()[csibmnode.NodeIDAnnotationKey]
+		capReader capacityplanner.CapacityReader
+		// overcommit ratio of the new thin pool LVG
+		ratioVol = &api.Volume{StorageClass: sc}
+	)
+
+	if value, ok := req.GetParameters()[base.CacheStorageTypeKey]; ok {
+		cacheSC = util.ConvertStorageClass(value)
+	}
+	if err := util.FillOvercommitRatio(ratioVol, req.GetParameters()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	capReader = capacityplanner.NewACReader(c.k8sclient, c.log, false)
+	if c.featureChecker.IsEnabled(featureconfig.FeatureACReservation) {
+		resReader := capacityplanner.NewACRReader(c.k8sclient, c.log, false)
+		capReader = capacityplanner.NewUnreservedACReader(c.log, capReader, resReader)
+	}
+
+	acs, err := capReader.ReadCapacity(ctx)
+	if err != nil {
+		ll.Errorf("Unable to read available capacity: %v", err)
+		return nil, status.Error(codes.Internal, "unable to read available capacity")
+	}
+
+	acs = capacityplanner.FilterACList(acs, func(ac accrd.AvailableCapacity) bool {
+		return nodeID == "" || ac.Spec.NodeId == nodeID
+	})
+
+	// MD RAID arrays and cache of the volume are placed on the same node as the volume,
+	// the largest volume which could be created is reported, it isn't spread over several ACs or nodes
+	nodeACs := make(map[string][]accrd.AvailableCapacity)
+	for _, ac := range acs {
+		nodeACs[ac.Spec.NodeId] = append(nodeACs[ac.Spec.NodeId], ac)
+	}
+	var capacity int64
+	for _, acs := range nodeACs {
+		size := capacityplanner.GetMaxVolumeSize(acs, sc, cacheSC, capacityplanner.GetOvercommitRatio(ratioVol))
+		if size > capacity {
+			capacity = size
+		}
+	}
+
+	ll.Infof("Available capacity for storage class %s on node %q is %d bytes", sc, nodeID, capacity)
+
+	return &csi.GetCapacityResponse{AvailableCapacity: capacity}, nil
 }
 
 // ControllerGetCapabilities is the implementation of CSI Spec ControllerGetCapabilities.
-// Provides Controller capabilities of CSI driver to k8s CREATE/DELETE Volume and PUBLISH/UNPUBLISH Volume for now.
+// Provides Controller capabilities of CSI driver to k8s: CREATE/DELETE Volume, PUBLISH/UNPUBLISH Volume,
+// EXPAND Volume, GET_CAPACITY, CREATE/DELETE Snapshot, LIST Snapshots, CLONE Volume, LIST Volumes
+// with published nodes, volume condition and SINGLE_NODE_MULTI_WRITER access mode.
 // Receives golang context and CSI Spec ControllerGetCapabilitiesRequest
 // Returns CSI Spec ControllerGetCapabilitiesResponse and nil error
 func (c *CSIControllerService) ControllerGetCapabilities(context.Context, *csi.ControllerGetCapabilitiesRequest) (*csi.ControllerGetCapabilitiesResponse, error) {
@@ -345,6 +594,15 @@ func (c *CSIControllerService) ControllerGetCapabilities(context.Context, *csi.C
 	for _, c := range []csi.ControllerServiceCapability_RPC_Type{
 		csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
 		csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+		csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
+		csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 	} {
 		caps = append(caps, newCap(c))
 	}
@@ -358,22 +616,210 @@ func (c *CSIControllerService) ControllerGetCapabilities(context.Context, *csi.C
 	return resp, nil
 }
 
-// CreateSnapshot is not implemented yet
-func (c *CSIControllerService) CreateSnapshot(context.Context, *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "not implemented yet")
+// CreateSnapshot is the implementation of CSI Spec CreateSnapshot. This method creates Snapshot CR for the source
+// volume and waits until snapshot LV will be created by Reconcile loop of appropriate Node.
+// Only volumes on LVG could be snapshotted.
+// Receives golang context and CSI Spec CreateSnapshotRequest
+// Returns CSI Spec CreateSnapshotResponse or error if something went wrong
+func (c *CSIControllerService) CreateSnapshot(ctx context.Context,
+	req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":     "CreateSnapshot",
+		"snapshotID": req.GetName(),
+	})
+	ll.Infof("Processing request: %v", req)
+
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot name missing in request")
+	}
+	if req.GetSourceVolumeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Source volume ID missing in request")
+	}
+
+	c.reqMu.Lock()
+	snap, err := c.snapSvc.CreateSnapshot(ctx, api.Snapshot{
+		Id:       req.GetName(),
+		VolumeId: req.GetSourceVolumeId(),
+	})
+	c.reqMu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if snap.CSIStatus == apiV1.Creating {
+		ll.Infof("Waiting until snapshot will reach Created status. Current status - %s", snap.CSIStatus)
+		if err = c.snapSvc.WaitStatus(ctx, snap.Id, apiV1.Failed, apiV1.Created); err != nil {
+			return nil, status.Error(codes.Internal, "Unable to create snapshot")
+		}
+	}
+
+	return &csi.CreateSnapshotResponse{Snapshot: snapshotToCSI(snap, true)}, nil
 }
 
-// DeleteSnapshot is not implemented yet
-func (c *CSIControllerService) DeleteSnapshot(context.Context, *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "not implemented yet")
+// DeleteSnapshot is the implementation of CSI Spec DeleteSnapshot. This method sets Snapshot CR's Spec.CSIStatus
+// to Removing and waits for snapshot LV to be removed by Reconcile loop of appropriate Node.
+// Receives golang context and CSI Spec DeleteSnapshotRequest
+// Returns CSI Spec DeleteSnapshotResponse or error if something went wrong
+func (c *CSIControllerService) DeleteSnapshot(ctx context.Context,
+	req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":     "DeleteSnapshot",
+		"snapshotID": req.GetSnapshotId(),
+	})
+	ll.Infof("Processing request: %v", req)
+
+	if req.GetSnapshotId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Snapshot ID must be provided")
+	}
+	ctxWithID := context.WithValue(context.Background(), k8s.RequestUUID, req.GetSnapshotId())
+
+	c.reqMu.Lock()
+	err := c.snapSvc.DeleteSnapshot(ctxWithID, req.GetSnapshotId())
+	c.reqMu.Unlock()
+
+	if err != nil {
+		if k8sError.IsNotFound(err) {
+			ll.Infof("Snapshot doesn't exist")
+			return &csi.DeleteSnapshotResponse{}, nil
+		}
+		ll.Errorf("Unable to delete snapshot: %v", err)
+		return nil, err
+	}
+	if err = c.snapSvc.WaitStatus(ctx, req.GetSnapshotId(), apiV1.Failed, apiV1.Removed); err != nil {
+		return nil, status.Error(codes.Internal, "Unable to delete snapshot")
+	}
+
+	c.reqMu.Lock()
+	c.snapSvc.UpdateCRsAfterSnapshotDeletion(ctxWithID, req.GetSnapshotId())
+	c.reqMu.Unlock()
+
+	ll.Debug("Snapshot was successfully deleted")
+
+	return &csi.DeleteSnapshotResponse{}, nil
 }
 
-// ListSnapshots is not implemented yet
-func (c *CSIControllerService) ListSnapshots(context.Context, *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
-	return nil, status.Error(codes.Unimplemented, "not implemented yet")
+// ListSnapshots is the implementation of CSI Spec ListSnapshots. This method lists Snapshot CRs filtered by
+// snapshot ID or source volume ID. StartingToken is an index of the first entry in the sorted list of snapshots
+// Receives golang context and CSI Spec ListSnapshotsRequest
+// Returns CSI Spec ListSnapshotsResponse or error if something went wrong
+func (c *CSIControllerService) ListSnapshots(ctx context.Context,
+	req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method": "ListSnapshots",
+	})
+	ll.Infof("Processing request: %v", req)
+
+	snapList := &snapshotcrd.SnapshotList{}
+	if err := c.k8sclient.ReadList(ctx, snapList); err != nil {
+		ll.Errorf("Unable to read snapshots list: %v", err)
+		return nil, status.Error(codes.Internal, "unable to read snapshots list")
+	}
+
+	snapshots := make([]api.Snapshot, 0, len(snapList.Items))
+	for _, s := range snapList.Items {
+		if req.GetSnapshotId() != "" && s.Spec.Id != req.GetSnapshotId() {
+			continue
+		}
+		if req.GetSourceVolumeId() != "" && s.Spec.VolumeId != req.GetSourceVolumeId() {
+			continue
+		}
+		if s.Spec.CSIStatus == apiV1.Removing || s.Spec.CSIStatus == apiV1.Removed {
+			continue
+		}
+		snapshots = append(snapshots, s.Spec)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Id < snapshots[j].Id })
+
+	start, end, err := paginate(len(snapshots), req.GetStartingToken(), req.GetMaxEntries())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &csi.ListSnapshotsResponse{
+		Entries: make([]*csi.ListSnapshotsResponse_Entry, 0, end-start),
+	}
+	for i := start; i < end; i++ {
+		resp.Entries = append(resp.Entries, &csi.ListSnapshotsResponse_Entry{
+			Snapshot: snapshotToCSI(&snapshots[i], snapshots[i].CSIStatus == apiV1.Created),
+		})
+	}
+	if end < len(snapshots) {
+		resp.NextToken = strconv.Itoa(end)
+	}
+
+	return resp, nil
+}
+
+// paginate returns bounds [start, end) of the page in the sorted list of total entries,
+// startingToken is an index of the first entry of the page, maxEntries equal to 0 means no limit
+// Returns Aborted error if startingToken is invalid
+func paginate(total int, startingToken string, maxEntries int32) (int, int, error) {
+	start := 0
+	if startingToken != "" {
+		var err error
+		start, err = strconv.Atoi(startingToken)
+		if err != nil || start < 0 || start > total {
+			return 0, 0, status.Errorf(codes.Aborted, "invalid starting token %s", startingToken)
+		}
+	}
+	end := total
+	if maxEntries > 0 && start+int(maxEntries) < end {
+		end = start + int(maxEntries)
+	}
+	return start, end, nil
+}
+
+// snapshotToCSI converts api.Snapshot to CSI Spec Snapshot
+func snapshotToCSI(s *api.Snapshot, ready bool) *csi.Snapshot {
+	return &csi.Snapshot{
+		SnapshotId:     s.Id,
+		SourceVolumeId: s.VolumeId,
+		SizeBytes:      s.Size,
+		CreationTime:   &timestamp.Timestamp{Seconds: s.CreationTime},
+		ReadyToUse:     ready,
+	}
 }
 
-// ControllerExpandVolume is not implemented yet
-func (c *CSIControllerService) ControllerExpandVolume(context.Context, *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
+// ControllerGetVolume is not implemented yet
+func (c *CSIControllerService) ControllerGetVolume(context.Context, *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "not implemented yet")
 }
+
+// ControllerExpandVolume is the implementation of CSI Spec ControllerExpandVolume. This method increases size of
+// Volume CR and decreases size of corresponding AC CR. Only volumes on LVG could be expanded.
+// Real expansion of the logical volume and file system is performed by NodeExpandVolume
+// Receives golang context and CSI Spec ControllerExpandVolumeRequest
+// Returns CSI Spec ControllerExpandVolumeResponse or error if something went wrong
+func (c *CSIControllerService) ControllerExpandVolume(ctx context.Context,
+	req *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
+	ll := c.log.WithFields(logrus.Fields{
+		"method":   "ControllerExpandVolume",
+		"volumeID": req.GetVolumeId(),
+	})
+
+	ll.Infof("Processing request: %v", req)
+
+	if req.GetVolumeId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID must be provided")
+	}
+	if req.GetCapacityRange() == nil || req.GetCapacityRange().GetRequiredBytes() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "Required bytes must be provided")
+	}
+
+	c.reqMu.Lock()
+	vol, err := c.svc.ExpandVolume(ctx, req.GetVolumeId(), req.GetCapacityRange().GetRequiredBytes())
+	c.reqMu.Unlock()
+
+	if err != nil {
+		ll.Errorf("Unable to expand volume: %v", err)
+		return nil, err
+	}
+
+	ll.Infof("Volume was expanded up to %d bytes", vol.Size)
+
+	return &csi.ControllerExpandVolumeResponse{
+		CapacityBytes:         vol.Size,
+		NodeExpansionRequired: vol.LocationType == apiV1.LocationTypeLVM,
+	}, nil
+}
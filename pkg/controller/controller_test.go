@@ -31,13 +31,17 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
 	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
 	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
@@ -137,6 +141,44 @@ var _ = Describe("CSIControllerService CreateVolume", func() {
 			Expect(err).ToNot(BeNil())
 			Expect(err.Error()).To(ContainSubstring("Volume capabilities missing in request"))
 		})
+		It("Access mode isn't supported", func() {
+			req := getCreateVolumeRequest("req1", 1024*1024*1024, "")
+			req.VolumeCapabilities[0].AccessMode.Mode = csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER
+			resp, err := controller.CreateVolume(context.Background(), req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Mount options aren't allowed for file system", func() {
+			req := getCreateVolumeRequest("req1", 1024*1024*1024, "")
+			req.VolumeCapabilities[0].GetMount().MountFlags = []string{"compress=zstd"}
+			resp, err := controller.CreateVolume(context.Background(), req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Logical volume type isn't supported for storage class", func() {
+			req := getCreateVolumeRequest("req1", 1024*1024*1024, "")
+			req.Parameters = map[string]string{
+				base.StorageTypeKey: apiV1.StorageClassHDD, base.LVTypeKey: apiV1.LVTypeRAID1}
+			resp, err := controller.CreateVolume(context.Background(), req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Encryption isn't supported for storage class", func() {
+			req := getCreateVolumeRequest("req1", 1024*1024*1024, "")
+			req.Parameters = map[string]string{
+				base.StorageTypeKey: apiV1.StorageClassHDDRAID1, base.EncryptedKey: "true"}
+			resp, err := controller.CreateVolume(context.Background(), req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Cache is requested for volume which isn't in HDDLVG", func() {
+			req := getCreateVolumeRequest("req1", 1024*1024*1024, "")
+			req.Parameters = map[string]string{
+				base.StorageTypeKey: apiV1.StorageClassHDD, base.CacheStorageTypeKey: apiV1.StorageClassNVMeLVG}
+			resp, err := controller.CreateVolume(context.Background(), req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
 		It("There is no suitable Available Capacity (on all nodes)", func() {
 			req := getCreateVolumeRequest("req1", 1024*1024*1024*1024, "")
 
@@ -214,6 +256,50 @@ var _ = Describe("CSIControllerService CreateVolume", func() {
 			Expect(err).To(BeNil())
 			Expect(vol.Spec.CSIStatus).To(Equal(apiV1.Created))
 		})
+		It("Volume in RAW mode is created successfully", func() {
+			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
+			Expect(err).To(BeNil())
+			var (
+				capacity = int64(1024 * 53)
+				req      = getCreateVolumeRequest("req1", capacity, testNode1Name)
+				vol      = &vcrd.Volume{}
+			)
+			req.VolumeCapabilities[0].AccessType = &csi.VolumeCapability_Block{
+				Block: &csi.VolumeCapability_BlockVolume{},
+			}
+
+			go testutils.VolumeReconcileImitation(controller.k8sclient, "req1", apiV1.Created)
+
+			resp, err := controller.CreateVolume(context.Background(), req)
+			Expect(err).To(BeNil())
+			Expect(resp).ToNot(BeNil())
+
+			err = controller.k8sclient.ReadCR(context.Background(), "req1", vol)
+			Expect(err).To(BeNil())
+			Expect(vol.Spec.Mode).To(Equal(apiV1.ModeRAW))
+			Expect(vol.Spec.Type).To(BeEmpty())
+		})
+		It("Volume is created with default file system", func() {
+			err := testutils.AddAC(controller.k8sclient, &testAC1, &testAC2)
+			Expect(err).To(BeNil())
+			var (
+				capacity = int64(1024 * 53)
+				req      = getCreateVolumeRequest("req1", capacity, testNode1Name)
+				vol      = &vcrd.Volume{}
+			)
+			req.VolumeCapabilities[0].GetMount().FsType = ""
+
+			go testutils.VolumeReconcileImitation(controller.k8sclient, "req1", apiV1.Created)
+
+			resp, err := controller.CreateVolume(context.Background(), req)
+			Expect(err).To(BeNil())
+			Expect(resp).ToNot(BeNil())
+
+			err = controller.k8sclient.ReadCR(context.Background(), "req1", vol)
+			Expect(err).To(BeNil())
+			Expect(vol.Spec.Mode).To(Equal(apiV1.ModeFS))
+			Expect(vol.Spec.Type).To(Equal(base.DefaultFsType))
+		})
 		It("Volume CR has already exists", func() {
 			uuid := "uuid-1234"
 			capacity := int64(1024 * 42)
@@ -446,6 +532,536 @@ var _ = Describe("CSIControllerService DeleteVolume", func() {
 	})
 })
 
+var _ = Describe("CSIControllerService ControllerExpandVolume", func() {
+	var (
+		controller *CSIControllerService
+		lvgName    = "lvg-1"
+		volumeSize = int64(1024 * 1024 * 1024)
+		acSize     = volumeSize * 10
+	)
+
+	BeforeEach(func() {
+		controller = newSvc()
+		volumeCR := controller.k8sclient.ConstructVolumeCR(testID, api.Volume{
+			Id:           testID,
+			NodeId:       testNode1Name,
+			Location:     lvgName,
+			Size:         volumeSize,
+			StorageClass: apiV1.StorageClassHDDLVG,
+			LocationType: apiV1.LocationTypeLVM,
+			CSIStatus:    apiV1.Published,
+		})
+		Expect(controller.k8sclient.CreateCR(testCtx, testID, volumeCR)).To(BeNil())
+		acCR := controller.k8sclient.ConstructACCR(lvgName, api.AvailableCapacity{
+			Location:     lvgName,
+			NodeId:       testNode1Name,
+			StorageClass: apiV1.StorageClassHDDLVG,
+			Size:         acSize,
+		})
+		Expect(controller.k8sclient.CreateCR(testCtx, lvgName, acCR)).To(BeNil())
+	})
+
+	AfterEach(func() {
+		removeAllCrds(controller.k8sclient)
+	})
+
+	Context("Fail scenarios", func() {
+		It("Request doesn't contain volume ID", func() {
+			resp, err := controller.ControllerExpandVolume(testCtx, &csi.ControllerExpandVolumeRequest{})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Request doesn't contain capacity range", func() {
+			resp, err := controller.ControllerExpandVolume(testCtx, &csi.ControllerExpandVolumeRequest{VolumeId: testID})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("There is no enough capacity in LVG", func() {
+			resp, err := controller.ControllerExpandVolume(testCtx, &csi.ControllerExpandVolumeRequest{
+				VolumeId:      testID,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: acSize * 2},
+			})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+		})
+	})
+
+	Context("Success scenarios", func() {
+		It("Volume is expanded", func() {
+			requiredBytes := volumeSize * 2
+			resp, err := controller.ControllerExpandVolume(testCtx, &csi.ControllerExpandVolumeRequest{
+				VolumeId:      testID,
+				CapacityRange: &csi.CapacityRange{RequiredBytes: requiredBytes},
+			})
+			Expect(err).To(BeNil())
+			Expect(resp.CapacityBytes).To(Equal(requiredBytes))
+			Expect(resp.NodeExpansionRequired).To(BeTrue())
+
+			ac := &accrd.AvailableCapacity{}
+			Expect(controller.k8sclient.ReadCR(testCtx, lvgName, ac)).To(BeNil())
+			Expect(ac.Spec.Size).To(Equal(acSize - (requiredBytes - volumeSize)))
+		})
+	})
+})
+
+var _ = Describe("CSIControllerService GetCapacity", func() {
+	var controller *CSIControllerService
+
+	BeforeEach(func() {
+		controller = newSvc()
+		for _, ac := range []accrd.AvailableCapacity{testAC1, testAC2, testAC3} {
+			ac := ac
+			Expect(controller.k8sclient.CreateCR(testCtx, ac.Name, &ac)).To(BeNil())
+		}
+	})
+
+	AfterEach(func() {
+		removeAllCrds(controller.k8sclient)
+	})
+
+	getCapacity := func(storageType, nodeID string) int64 {
+		req := &csi.GetCapacityRequest{Parameters: map[string]string{base.StorageTypeKey: storageType}}
+		if nodeID != "" {
+			req.AccessibleTopology = &csi.Topology{
+				Segments: map[string]string{csibmnode.NodeIDAnnotationKey: nodeID},
+			}
+		}
+		resp, err := controller.GetCapacity(testCtx, req)
+		Expect(err).To(BeNil())
+		return resp.AvailableCapacity
+	}
+
+	It("Should report the largest AC for ANY storage type", func() {
+		Expect(getCapacity("", "")).To(Equal(testAC2.Spec.Size))
+	})
+	It("Should filter ACs by storage type", func() {
+		Expect(getCapacity(apiV1.StorageClassHDD, "")).To(Equal(testAC2.Spec.Size))
+		Expect(getCapacity(apiV1.StorageClassSSD, "")).To(Equal(int64(0)))
+	})
+	It("Should take into account drive ACs for LVG storage type", func() {
+		Expect(getCapacity(apiV1.StorageClassHDDLVG, "")).
+			To(Equal(testAC2.Spec.Size - capacityplanner.LvgDefaultMetadataSize))
+	})
+	It("Should filter ACs by node", func() {
+		Expect(getCapacity(apiV1.StorageClassHDD, testNode1Name)).To(Equal(testAC1.Spec.Size))
+		Expect(getCapacity(apiV1.StorageClassHDDLVG, testNode1Name)).
+			To(Equal(testAC1.Spec.Size - capacityplanner.LvgDefaultMetadataSize))
+		Expect(getCapacity(apiV1.StorageClassHDDLVG, testNode2Name)).
+			To(Equal(testAC2.Spec.Size - capacityplanner.LvgDefaultMetadataSize))
+	})
+	It("Should count size of MD RAID array on drives of the same node", func() {
+		driveSize := int64(10 * 1024 * 1024 * 1024)
+		for i, node := range []string{testNode1Name, testNode1Name, testNode1Name, testNode1Name, testNode1Name, testNode2Name} {
+			ac := controller.k8sclient.ConstructACCR(fmt.Sprintf("ssd-ac-%d", i), api.AvailableCapacity{
+				Location:     fmt.Sprintf("ssd-drive-%d", i),
+				NodeId:       node,
+				StorageClass: apiV1.StorageClassSSD,
+				Size:         driveSize,
+			})
+			Expect(controller.k8sclient.CreateCR(testCtx, ac.Name, ac)).To(BeNil())
+		}
+		memberSize := driveSize - capacityplanner.MDRaidMetadataSize
+
+		// node 1 has 5 drives for RAID1 array, node 2 has only 1 drive
+		Expect(getCapacity(apiV1.StorageClassSSDRAID1, "")).To(Equal(memberSize))
+		Expect(getCapacity(apiV1.StorageClassSSDRAID1, testNode2Name)).To(Equal(int64(0)))
+		// RAID10 array of 4 drives keeps 2 copies of the data
+		Expect(getCapacity(apiV1.StorageClassSSDRAID10, "")).To(Equal(2 * memberSize))
+		// each node has only 1 HDD
+		Expect(getCapacity(apiV1.StorageClassHDDRAID1, "")).To(Equal(int64(0)))
+	})
+	It("Should count overcommitted size for thin storage type", func() {
+		thinAC := controller.k8sclient.ConstructACCR("thin-ac", api.AvailableCapacity{
+			Location:        "thin-lvg",
+			NodeId:          testNode1Name,
+			StorageClass:    apiV1.StorageClassHDDLVGThin,
+			Size:            testAC1.Spec.Size,
+			OvercommitRatio: 3,
+		})
+		Expect(controller.k8sclient.CreateCR(testCtx, thinAC.Name, thinAC)).To(BeNil())
+
+		// thin pool LVG provides more than the new one on the drive
+		Expect(getCapacity(apiV1.StorageClassHDDLVGThin, testNode1Name)).To(Equal(3 * testAC1.Spec.Size))
+
+		// storage class sets overcommit ratio of the new thin pool LVG
+		req := &csi.GetCapacityRequest{
+			Parameters: map[string]string{
+				base.StorageTypeKey:     apiV1.StorageClassHDDLVGThin,
+				base.OvercommitRatioKey: "4",
+			},
+			AccessibleTopology: &csi.Topology{Segments: map[string]string{csibmnode.NodeIDAnnotationKey: testNode1Name}},
+		}
+		resp, err := controller.GetCapacity(testCtx, req)
+		Expect(err).To(BeNil())
+		Expect(resp.AvailableCapacity).To(Equal(4 * (testAC1.Spec.Size - capacityplanner.LvgDefaultMetadataSize)))
+
+		req.Parameters[base.OvercommitRatioKey] = "0"
+		_, err = controller.GetCapacity(testCtx, req)
+		Expect(err).NotTo(BeNil())
+	})
+	It("Should count capacity of cached storage type only on nodes with capacity for cache", func() {
+		cacheAC := controller.k8sclient.ConstructACCR("cache-ac", api.AvailableCapacity{
+			Location:     "nvme-lvg",
+			NodeId:       testNode2Name,
+			StorageClass: apiV1.StorageClassNVMeLVG,
+			Size:         testAC1.Spec.Size,
+		})
+		Expect(controller.k8sclient.CreateCR(testCtx, cacheAC.Name, cacheAC)).To(BeNil())
+
+		req := &csi.GetCapacityRequest{Parameters: map[string]string{
+			base.StorageTypeKey:      apiV1.StorageClassHDDLVG,
+			base.CacheStorageTypeKey: apiV1.StorageClassNVMeLVG,
+		}}
+		resp, err := controller.GetCapacity(testCtx, req)
+		Expect(err).To(BeNil())
+		Expect(resp.AvailableCapacity).To(Equal(testAC2.Spec.Size - capacityplanner.LvgDefaultMetadataSize))
+	})
+	It("Should not take into account reserved ACs if ACReservation is enabled", func() {
+		featureConf := featureconfig.NewFeatureConfig()
+		featureConf.Update(featureconfig.FeatureACReservation, true)
+		controller.featureChecker = featureConf
+
+		acr := controller.k8sclient.ConstructACRCR(api.AvailableCapacityReservation{
+			Name:         "acr-1",
+			StorageClass: apiV1.StorageClassHDD,
+			Size:         testAC1.Spec.Size,
+			Reservations: []string{testAC1Name},
+		})
+		Expect(controller.k8sclient.CreateCR(testCtx, acr.Name, acr)).To(BeNil())
+
+		Expect(getCapacity(apiV1.StorageClassHDD, "")).To(Equal(testAC2.Spec.Size))
+		Expect(controller.k8sclient.DeleteCR(testCtx, acr)).To(BeNil())
+	})
+})
+
+var _ = Describe("CSIControllerService Snapshots", func() {
+	var (
+		controller *CSIControllerService
+		lvgName    = "lvg-1"
+		snapshotID = "snapshot-1"
+		volumeSize = int64(1024 * 1024 * 1024)
+		acSize     = volumeSize * 10
+	)
+
+	BeforeEach(func() {
+		controller = newSvc()
+		volumeCR := controller.k8sclient.ConstructVolumeCR(testID, api.Volume{
+			Id:           testID,
+			NodeId:       testNode1Name,
+			Location:     lvgName,
+			Size:         volumeSize,
+			StorageClass: apiV1.StorageClassHDDLVG,
+			LocationType: apiV1.LocationTypeLVM,
+			CSIStatus:    apiV1.Published,
+			Mode:         apiV1.ModeFS,
+			Type:         string(fs.XFS),
+		})
+		Expect(controller.k8sclient.CreateCR(testCtx, testID, volumeCR)).To(BeNil())
+		acCR := controller.k8sclient.ConstructACCR(lvgName, api.AvailableCapacity{
+			Location:     lvgName,
+			NodeId:       testNode1Name,
+			StorageClass: apiV1.StorageClassHDDLVG,
+			Size:         acSize,
+		})
+		Expect(controller.k8sclient.CreateCR(testCtx, lvgName, acCR)).To(BeNil())
+	})
+
+	AfterEach(func() {
+		removeAllCrds(controller.k8sclient)
+	})
+
+	Context("CreateSnapshot", func() {
+		It("Request doesn't contain name or source volume ID", func() {
+			resp, err := controller.CreateSnapshot(testCtx, &csi.CreateSnapshotRequest{SourceVolumeId: testID})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			resp, err = controller.CreateSnapshot(testCtx, &csi.CreateSnapshotRequest{Name: snapshotID})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Source volume doesn't exist", func() {
+			resp, err := controller.CreateSnapshot(testCtx,
+				&csi.CreateSnapshotRequest{Name: snapshotID, SourceVolumeId: "unknown"})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+		It("Snapshot LV creation failed", func() {
+			go testutils.SnapshotReconcileImitation(controller.k8sclient, snapshotID, apiV1.Failed)
+			resp, err := controller.CreateSnapshot(testCtx,
+				&csi.CreateSnapshotRequest{Name: snapshotID, SourceVolumeId: testID})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+		})
+		It("Snapshot is created", func() {
+			go testutils.SnapshotReconcileImitation(controller.k8sclient, snapshotID, apiV1.Created)
+			resp, err := controller.CreateSnapshot(testCtx,
+				&csi.CreateSnapshotRequest{Name: snapshotID, SourceVolumeId: testID})
+			Expect(err).To(BeNil())
+			Expect(resp.Snapshot.SnapshotId).To(Equal(snapshotID))
+			Expect(resp.Snapshot.SourceVolumeId).To(Equal(testID))
+			Expect(resp.Snapshot.SizeBytes).To(Equal(volumeSize))
+			Expect(resp.Snapshot.ReadyToUse).To(BeTrue())
+
+			ac := &accrd.AvailableCapacity{}
+			Expect(controller.k8sclient.ReadCR(testCtx, lvgName, ac)).To(BeNil())
+			Expect(ac.Spec.Size).To(Equal(acSize - volumeSize))
+		})
+	})
+
+	Context("DeleteSnapshot", func() {
+		It("Request doesn't contain snapshot ID", func() {
+			resp, err := controller.DeleteSnapshot(testCtx, &csi.DeleteSnapshotRequest{})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Snapshot doesn't exist", func() {
+			resp, err := controller.DeleteSnapshot(testCtx, &csi.DeleteSnapshotRequest{SnapshotId: snapshotID})
+			Expect(err).To(BeNil())
+			Expect(resp).ToNot(BeNil())
+		})
+		It("Snapshot is deleted", func() {
+			snapshotCR := controller.k8sclient.ConstructSnapshotCR(snapshotID, api.Snapshot{
+				Id:        snapshotID,
+				VolumeId:  testID,
+				NodeId:    testNode1Name,
+				Location:  lvgName,
+				Size:      volumeSize,
+				CSIStatus: apiV1.Created,
+			})
+			Expect(controller.k8sclient.CreateCR(testCtx, snapshotID, snapshotCR)).To(BeNil())
+
+			go testutils.SnapshotReconcileImitation(controller.k8sclient, snapshotID, apiV1.Removed)
+			resp, err := controller.DeleteSnapshot(testCtx, &csi.DeleteSnapshotRequest{SnapshotId: snapshotID})
+			Expect(err).To(BeNil())
+			Expect(resp).ToNot(BeNil())
+
+			err = controller.k8sclient.ReadCR(testCtx, snapshotID, &snapshotcrd.Snapshot{})
+			Expect(k8sError.IsNotFound(err)).To(BeTrue())
+			ac := &accrd.AvailableCapacity{}
+			Expect(controller.k8sclient.ReadCR(testCtx, lvgName, ac)).To(BeNil())
+			Expect(ac.Spec.Size).To(Equal(acSize + volumeSize))
+		})
+	})
+
+	Context("CreateVolume from content source", func() {
+		It("Volume is created from snapshot", func() {
+			snapshotCR := controller.k8sclient.ConstructSnapshotCR(snapshotID, api.Snapshot{
+				Id:        snapshotID,
+				VolumeId:  testID,
+				NodeId:    testNode1Name,
+				Location:  lvgName,
+				Size:      volumeSize,
+				CSIStatus: apiV1.Created,
+			})
+			Expect(controller.k8sclient.CreateCR(testCtx, snapshotCR.Name, snapshotCR)).To(BeNil())
+
+			req := getCreateVolumeRequest("req-clone", 0, "")
+			req.VolumeContentSource = &csi.VolumeContentSource{
+				Type: &csi.VolumeContentSource_Snapshot{
+					Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: snapshotID},
+				},
+			}
+			go testutils.VolumeReconcileImitation(controller.k8sclient, "req-clone", apiV1.Created)
+			resp, err := controller.CreateVolume(testCtx, req)
+			Expect(err).To(BeNil())
+			Expect(resp.Volume.CapacityBytes).To(Equal(volumeSize))
+			Expect(resp.Volume.ContentSource.GetSnapshot().GetSnapshotId()).To(Equal(snapshotID))
+			Expect(resp.Volume.AccessibleTopology[0].Segments[csibmnode.NodeIDAnnotationKey]).To(Equal(testNode1Name))
+
+			vol := &vcrd.Volume{}
+			Expect(controller.k8sclient.ReadCR(testCtx, "req-clone", vol)).To(BeNil())
+			Expect(vol.Spec.Location).To(Equal(lvgName))
+			Expect(vol.Spec.ContentSourceType).To(Equal(apiV1.ContentSourceSnapshot))
+		})
+		It("Content source is empty", func() {
+			req := getCreateVolumeRequest("req-clone", 0, "")
+			req.VolumeContentSource = &csi.VolumeContentSource{
+				Type: &csi.VolumeContentSource_Volume{Volume: &csi.VolumeContentSource_VolumeSource{}},
+			}
+			resp, err := controller.CreateVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Context("ListSnapshots", func() {
+		BeforeEach(func() {
+			for _, s := range []api.Snapshot{
+				{Id: "snapshot-1", VolumeId: testID, CSIStatus: apiV1.Created},
+				{Id: "snapshot-2", VolumeId: testID, CSIStatus: apiV1.Creating},
+				{Id: "snapshot-3", VolumeId: "another-volume", CSIStatus: apiV1.Created},
+				{Id: "snapshot-4", VolumeId: testID, CSIStatus: apiV1.Removing},
+			} {
+				Expect(controller.k8sclient.CreateCR(testCtx, s.Id,
+					controller.k8sclient.ConstructSnapshotCR(s.Id, s))).To(BeNil())
+			}
+		})
+
+		It("Filter by snapshot ID and source volume ID", func() {
+			resp, err := controller.ListSnapshots(testCtx, &csi.ListSnapshotsRequest{SnapshotId: "snapshot-3"})
+			Expect(err).To(BeNil())
+			Expect(len(resp.Entries)).To(Equal(1))
+			Expect(resp.Entries[0].Snapshot.SourceVolumeId).To(Equal("another-volume"))
+
+			resp, err = controller.ListSnapshots(testCtx, &csi.ListSnapshotsRequest{SourceVolumeId: testID})
+			Expect(err).To(BeNil())
+			Expect(len(resp.Entries)).To(Equal(2))
+			Expect(resp.Entries[0].Snapshot.ReadyToUse).To(BeTrue())
+			Expect(resp.Entries[1].Snapshot.ReadyToUse).To(BeFalse())
+		})
+		It("Paginate snapshots", func() {
+			resp, err := controller.ListSnapshots(testCtx, &csi.ListSnapshotsRequest{MaxEntries: 2})
+			Expect(err).To(BeNil())
+			Expect(len(resp.Entries)).To(Equal(2))
+			Expect(resp.NextToken).To(Equal("2"))
+
+			resp, err = controller.ListSnapshots(testCtx,
+				&csi.ListSnapshotsRequest{MaxEntries: 2, StartingToken: resp.NextToken})
+			Expect(err).To(BeNil())
+			Expect(len(resp.Entries)).To(Equal(1))
+			Expect(resp.Entries[0].Snapshot.SnapshotId).To(Equal("snapshot-3"))
+			Expect(resp.NextToken).To(BeEmpty())
+		})
+		It("Invalid starting token", func() {
+			resp, err := controller.ListSnapshots(testCtx, &csi.ListSnapshotsRequest{StartingToken: "abc"})
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.Aborted))
+		})
+	})
+})
+
+var _ = Describe("CSIControllerService ListVolumes", func() {
+	var controller *CSIControllerService
+
+	BeforeEach(func() {
+		controller = newSvc()
+		for _, v := range []api.Volume{
+			{Id: "volume-1", NodeId: testNode1Name, Size: 100, CSIStatus: apiV1.Published,
+				Health: apiV1.HealthGood, OperationalStatus: apiV1.OperationalStatusOperative},
+			{Id: "volume-2", NodeId: testNode2Name, Size: 200, CSIStatus: apiV1.Created,
+				Health: apiV1.HealthBad, OperationalStatus: apiV1.OperationalStatusOperative},
+			{Id: "volume-3", NodeId: testNode1Name, Size: 300, CSIStatus: apiV1.VolumeReady,
+				Health: apiV1.HealthGood, OperationalStatus: apiV1.OperationalStatusMissing},
+			{Id: "volume-4", NodeId: testNode1Name, Size: 400, CSIStatus: apiV1.Removing},
+		} {
+			Expect(controller.k8sclient.CreateCR(testCtx, v.Id,
+				controller.k8sclient.ConstructVolumeCR(v.Id, v))).To(BeNil())
+		}
+	})
+
+	AfterEach(func() {
+		removeAllCrds(controller.k8sclient)
+	})
+
+	It("Should list volumes with status", func() {
+		resp, err := controller.ListVolumes(testCtx, &csi.ListVolumesRequest{})
+		Expect(err).To(BeNil())
+		Expect(len(resp.Entries)).To(Equal(3))
+		Expect(resp.NextToken).To(BeEmpty())
+
+		Expect(resp.Entries[0].Volume.VolumeId).To(Equal("volume-1"))
+		Expect(resp.Entries[0].Volume.CapacityBytes).To(Equal(int64(100)))
+		Expect(resp.Entries[0].Volume.AccessibleTopology[0].Segments[csibmnode.NodeIDAnnotationKey]).
+			To(Equal(testNode1Name))
+		Expect(resp.Entries[0].Status.PublishedNodeIds).To(Equal([]string{testNode1Name}))
+		Expect(resp.Entries[0].Status.VolumeCondition.Abnormal).To(BeFalse())
+
+		Expect(resp.Entries[1].Status.PublishedNodeIds).To(BeEmpty())
+		Expect(resp.Entries[1].Status.VolumeCondition.Abnormal).To(BeTrue())
+		Expect(resp.Entries[2].Status.VolumeCondition.Abnormal).To(BeTrue())
+	})
+
+	It("Should paginate volumes", func() {
+		resp, err := controller.ListVolumes(testCtx, &csi.ListVolumesRequest{MaxEntries: 2})
+		Expect(err).To(BeNil())
+		Expect(len(resp.Entries)).To(Equal(2))
+		Expect(resp.NextToken).To(Equal("2"))
+
+		resp, err = controller.ListVolumes(testCtx,
+			&csi.ListVolumesRequest{MaxEntries: 2, StartingToken: resp.NextToken})
+		Expect(err).To(BeNil())
+		Expect(len(resp.Entries)).To(Equal(1))
+		Expect(resp.Entries[0].Volume.VolumeId).To(Equal("volume-3"))
+		Expect(resp.NextToken).To(BeEmpty())
+	})
+
+	It("Should fail with invalid starting token", func() {
+		resp, err := controller.ListVolumes(testCtx, &csi.ListVolumesRequest{StartingToken: "10"})
+		Expect(resp).To(BeNil())
+		Expect(status.Code(err)).To(Equal(codes.Aborted))
+	})
+})
+
+var _ = Describe("CSIControllerService ValidateVolumeCapabilities", func() {
+	var (
+		controller *CSIControllerService
+		volumeID   = "volume-1"
+	)
+
+	BeforeEach(func() {
+		controller = newSvc()
+		v := api.Volume{Id: volumeID, NodeId: testNode1Name, Mode: apiV1.ModeFS, Type: string(fs.EXT4),
+			CSIStatus: apiV1.Created}
+		Expect(controller.k8sclient.CreateCR(testCtx, v.Id, controller.k8sclient.ConstructVolumeCR(v.Id, v))).To(BeNil())
+	})
+
+	AfterEach(func() {
+		removeAllCrds(controller.k8sclient)
+	})
+
+	It("Should confirm capabilities", func() {
+		caps := getCreateVolumeRequest("", 0, "").VolumeCapabilities
+		caps[0].GetMount().FsType = string(fs.EXT4)
+		caps[0].GetMount().MountFlags = []string{"noatime"}
+		caps = append(caps, &csi.VolumeCapability{
+			AccessType: caps[0].AccessType,
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY},
+		})
+
+		resp, err := controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId: volumeID, VolumeCapabilities: caps})
+		Expect(err).To(BeNil())
+		Expect(resp.Confirmed).NotTo(BeNil())
+		Expect(resp.Confirmed.VolumeCapabilities).To(Equal(caps))
+	})
+
+	It("Shouldn't confirm capabilities", func() {
+		caps := getCreateVolumeRequest("", 0, "").VolumeCapabilities
+		// volume has another FS
+		resp, err := controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId: volumeID, VolumeCapabilities: caps})
+		Expect(err).To(BeNil())
+		Expect(resp.Confirmed).To(BeNil())
+		Expect(resp.Message).NotTo(BeEmpty())
+
+		// multi node access mode
+		caps[0].GetMount().FsType = ""
+		caps[0].AccessMode.Mode = csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER
+		resp, err = controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId: volumeID, VolumeCapabilities: caps})
+		Expect(err).To(BeNil())
+		Expect(resp.Confirmed).To(BeNil())
+
+		// volume in FS mode is requested as a block device
+		caps[0].AccessMode.Mode = csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER
+		caps[0].AccessType = &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}
+		resp, err = controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId: volumeID, VolumeCapabilities: caps})
+		Expect(err).To(BeNil())
+		Expect(resp.Confirmed).To(BeNil())
+	})
+
+	It("Should fail", func() {
+		_, err := controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+		_, err = controller.ValidateVolumeCapabilities(testCtx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId: "not-existing", VolumeCapabilities: getCreateVolumeRequest("", 0, "").VolumeCapabilities})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+})
+
 var _ = Describe("CSIControllerService ControllerGetCapabilities", func() {
 	It("Should return right capabilities", func() {
 		var (
@@ -454,6 +1070,15 @@ var _ = Describe("CSIControllerService ControllerGetCapabilities", func() {
 			expectedCapabilitiesTypes = []csi.ControllerServiceCapability_RPC_Type{
 				csi.ControllerServiceCapability_RPC_CREATE_DELETE_VOLUME,
 				csi.ControllerServiceCapability_RPC_PUBLISH_UNPUBLISH_VOLUME,
+				csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
+				csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+				csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT,
+				csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+				csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+				csi.ControllerServiceCapability_RPC_LIST_VOLUMES,
+				csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+				csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
+				csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 			}
 		)
 
@@ -461,7 +1086,7 @@ var _ = Describe("CSIControllerService ControllerGetCapabilities", func() {
 
 		caps, err = svc.ControllerGetCapabilities(context.Background(), &csi.ControllerGetCapabilitiesRequest{})
 		Expect(err).To(BeNil())
-		Expect(len(caps.Capabilities)).To(Equal(2))
+		Expect(len(caps.Capabilities)).To(Equal(11))
 
 		currentCapabilitiesTypes := make([]csi.ControllerServiceCapability_RPC_Type, len(caps.Capabilities))
 		for i := 0; i < len(caps.Capabilities); i++ {
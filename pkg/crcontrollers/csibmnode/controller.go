@@ -42,6 +42,9 @@ import (
 const (
 	// NodeIDAnnotationKey hold key for annotation for node object
 	NodeIDAnnotationKey = "csibmnodes.csi-baremetal.dell.com/uuid"
+	// ReplacedByAnnotationKey hold key for annotation for CSIBMNode object that was replaced,
+	// value is a name of CSIBMNode CR of the new node
+	ReplacedByAnnotationKey = "csibmnodes.csi-baremetal.dell.com/replaced-by"
 	// namePrefix it is a prefix for CSIBMNode CR name
 	namePrefix = "csibmnode-"
 )
@@ -81,6 +84,13 @@ func (nc *nodesMapping) put(k8sNodeName, bmNodeName string) {
 	nc.bmToK8sNode[bmNodeName] = k8sNodeName
 }
 
+func (nc *nodesMapping) remove(bmNodeName string) {
+	if k8sNodeName, ok := nc.bmToK8sNode[bmNodeName]; ok {
+		delete(nc.k8sToBMNode, k8sNodeName)
+	}
+	delete(nc.bmToK8sNode, bmNodeName)
+}
+
 // NewController returns instance of Controller
 func NewController(nodeSelector string, k8sClient *k8s.KubeClient, logger *logrus.Logger) (*Controller, error) {
 	c := &Controller{
@@ -193,6 +203,9 @@ func (bmc *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	switch {
 	case err == nil:
 		ll.Infof("Reconcile CSIBMNode %s", bmNode.Name)
+		if _, ok := bmNode.GetAnnotations()[ReplacedByAnnotationKey]; ok {
+			return bmc.reconcileNodeReplacement(bmNode)
+		}
 		return bmc.reconcileForCSIBMNode(bmNode)
 	case !k8sError.IsNotFound(err):
 		ll.Errorf("Unable to read CSIBMNode object: %v", err)
@@ -0,0 +1,277 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csibmnode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	nodecrd "github.com/dell/csi-baremetal/api/v1/csibmnodecrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+)
+
+// crObject is a custom resource which could be garbage-collected during node replacement
+type crObject interface {
+	runtime.Object
+	metaV1.Object
+}
+
+// reconcileNodeReplacement handles CSIBMNode which has ReplacedByAnnotationKey annotation.
+// Old node keeps its UUID, so Drive, LVG, AC and Volume CRs of the old node as well as node affinity of PVs stay valid:
+// Drive CRs of the old node are matched with Drive CRs of the new node by VID/PID/SerialNumber and CRs based on drives
+// which weren't found on the new node are garbage-collected, then old CSIBMNode takes addresses of the new node and
+// k8s node is annotated with the old UUID. CRs created by the new node are removed together with new CSIBMNode.
+// Each step is derived from the state of CRs, so reconciliation could be safely repeated if some step failed.
+func (bmc *Controller) reconcileNodeReplacement(oldNode *nodecrd.CSIBMNode) (ctrl.Result, error) {
+	ll := bmc.log.WithFields(logrus.Fields{
+		"method": "reconcileNodeReplacement",
+		"name":   oldNode.Name,
+	})
+
+	ctx := context.Background()
+	newNode := new(nodecrd.CSIBMNode)
+	newNodeName := oldNode.GetAnnotations()[ReplacedByAnnotationKey]
+	if err := bmc.k8sClient.ReadCR(ctx, newNodeName, newNode); err != nil {
+		if k8sError.IsNotFound(err) {
+			ll.Errorf("CSIBMNode %s that replaces %s is not found, check %s annotation",
+				newNodeName, oldNode.Name, ReplacedByAnnotationKey)
+			return ctrl.Result{}, nil
+		}
+		ll.Errorf("Unable to read CSIBMNode %s: %v", newNodeName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	oldID, newID := oldNode.Spec.UUID, newNode.Spec.UUID
+	if oldID == newID {
+		err := errors.New("CSIBMNode can't be replaced by itself")
+		ll.Error(err)
+		return ctrl.Result{Requeue: false}, err
+	}
+
+	volumes := new(volumecrd.VolumeList)
+	if err := bmc.k8sClient.ReadList(ctx, volumes); err != nil {
+		ll.Errorf("Unable to read volumes: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	for _, vol := range volumes.Items {
+		if vol.Spec.NodeId == newID {
+			err := fmt.Errorf("volume %s was already created on the node %s, node can't be replaced", vol.Name, newID)
+			ll.Error(err)
+			return ctrl.Result{Requeue: false}, err
+		}
+	}
+	ll.Infof("Node %s is replaced by node %s", oldID, newID)
+
+	// addresses of the new node are taken by the old CSIBMNode after garbage collection only
+	if !reflect.DeepEqual(oldNode.Spec.Addresses, newNode.Spec.Addresses) {
+		if err := bmc.removeMissingResources(ctx, oldID, newID); err != nil {
+			ll.Errorf("Unable to remove resources which are based on missing drives: %v", err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		oldNode.Spec.Addresses = newNode.Spec.Addresses
+		if err := bmc.k8sClient.UpdateCR(ctx, oldNode); err != nil {
+			ll.Errorf("Unable to update CSIBMNode %s: %v", oldNode.Name, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+
+	// annotate k8s node with the old UUID, node service restarts and uses it as an id for all CRs
+	bmc.cache.remove(oldNode.Name)
+	bmc.cache.remove(newNode.Name)
+	if res, err := bmc.reconcileForCSIBMNode(oldNode); err != nil {
+		return res, err
+	}
+	if _, ok := bmc.cache.getK8sNodeName(oldNode.Name); !ok {
+		err := fmt.Errorf("k8s node with addresses %v is not found", oldNode.Spec.Addresses)
+		ll.Error(err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	if err := bmc.removeNodeResources(ctx, newID); err != nil {
+		ll.Errorf("Unable to remove resources of the node %s: %v", newID, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if err := bmc.removeCR(ctx, newNode); err != nil {
+		ll.Errorf("Unable to remove CSIBMNode %s: %v", newNode.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	delete(oldNode.Annotations, ReplacedByAnnotationKey)
+	if err := bmc.k8sClient.UpdateCR(ctx, oldNode); err != nil {
+		ll.Errorf("Unable to update CSIBMNode %s: %v", oldNode.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	ll.Infof("CSIBMNode %s was replaced by %s", oldNode.Name, newNode.Name)
+	return ctrl.Result{}, nil
+}
+
+// removeMissingResources removes Drive CRs of the old node which aren't present on the new node (matched by
+// VID/PID/SerialNumber) and LVG, AC and Volume CRs which are based on them. Volume on MD RAID array uses
+// all drives from its locations, cached volume uses cache LVG as well
+func (bmc *Controller) removeMissingResources(ctx context.Context, oldID, newID string) error {
+	var (
+		drives  = new(drivecrd.DriveList)
+		lvgs    = new(lvgcrd.LVGList)
+		acs     = new(accrd.AvailableCapacityList)
+		volumes = new(volumecrd.VolumeList)
+		kept    = make(map[string]bool)
+		removed = make([]crObject, 0)
+	)
+	for _, list := range []runtime.Object{drives, lvgs, acs, volumes} {
+		if err := bmc.k8sClient.ReadList(ctx, list); err != nil {
+			return err
+		}
+	}
+
+	present := make(map[string]bool)
+	for _, d := range drives.Items {
+		if d.Spec.NodeId == newID {
+			present[driveKey(d.Spec)] = true
+		}
+	}
+	for i := range drives.Items {
+		d := &drives.Items[i]
+		if d.Spec.NodeId != oldID {
+			continue
+		}
+		if present[driveKey(d.Spec)] {
+			kept[d.Spec.UUID] = true
+			continue
+		}
+		removed = append(removed, d)
+	}
+
+	for i := range lvgs.Items {
+		lvg := &lvgs.Items[i]
+		if lvg.Spec.Node != oldID {
+			continue
+		}
+		isKept := len(lvg.Spec.Locations) > 0
+		for _, location := range lvg.Spec.Locations {
+			isKept = isKept && kept[location]
+		}
+		if isKept {
+			kept[lvg.Name] = true
+			continue
+		}
+		removed = append(removed, lvg)
+	}
+
+	for i := range acs.Items {
+		ac := &acs.Items[i]
+		if ac.Spec.NodeId == oldID && !kept[ac.Spec.Location] {
+			removed = append(removed, ac)
+		}
+	}
+
+	for i := range volumes.Items {
+		vol := &volumes.Items[i]
+		if vol.Spec.NodeId != oldID {
+			continue
+		}
+		locations := append([]string{vol.Spec.Location}, vol.Spec.Locations...)
+		if vol.Spec.CacheLocation != "" {
+			locations = append(locations, vol.Spec.CacheLocation)
+		}
+		for _, location := range locations {
+			if !kept[location] {
+				removed = append(removed, vol)
+				break
+			}
+		}
+	}
+
+	// remove volumes at first and drives at last, since drives are used to find out what should be removed
+	for i := len(removed) - 1; i >= 0; i-- {
+		if err := bmc.removeCR(ctx, removed[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeNodeResources removes AC, LVG and Drive CRs which were created by the node with provided UUID
+func (bmc *Controller) removeNodeResources(ctx context.Context, nodeID string) error {
+	var (
+		drives  = new(drivecrd.DriveList)
+		lvgs    = new(lvgcrd.LVGList)
+		acs     = new(accrd.AvailableCapacityList)
+		removed = make([]crObject, 0)
+	)
+	for _, list := range []runtime.Object{drives, lvgs, acs} {
+		if err := bmc.k8sClient.ReadList(ctx, list); err != nil {
+			return err
+		}
+	}
+
+	for i := range acs.Items {
+		if acs.Items[i].Spec.NodeId == nodeID {
+			removed = append(removed, &acs.Items[i])
+		}
+	}
+	for i := range lvgs.Items {
+		if lvgs.Items[i].Spec.Node == nodeID {
+			removed = append(removed, &lvgs.Items[i])
+		}
+	}
+	for i := range drives.Items {
+		if drives.Items[i].Spec.NodeId == nodeID {
+			removed = append(removed, &drives.Items[i])
+		}
+	}
+
+	for _, obj := range removed {
+		if err := bmc.removeCR(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// driveKey returns key which identifies physical drive on any node
+func driveKey(drive api.Drive) string {
+	return strings.Join([]string{drive.VID, drive.PID, drive.SerialNumber}, "/")
+}
+
+// removeCR removes finalizers of the object, since node service which is responsible for them isn't available,
+// and deletes object
+func (bmc *Controller) removeCR(ctx context.Context, obj crObject) error {
+	if len(obj.GetFinalizers()) > 0 {
+		obj.SetFinalizers(nil)
+		if err := bmc.k8sClient.UpdateCR(ctx, obj); err != nil {
+			return err
+		}
+	}
+	if err := bmc.k8sClient.DeleteCR(ctx, obj); err != nil && !k8sError.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csibmnode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	nodecrd "github.com/dell/csi-baremetal/api/v1/csibmnodecrd"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+)
+
+func TestReconcileNodeReplacement(t *testing.T) {
+	t.Run("Old node UUID is kept", func(t *testing.T) {
+		var (
+			c       = setup(t)
+			oldNode = testCSIBMNode1.DeepCopy()
+			newNode = testCSIBMNode2.DeepCopy()
+			k8sNode = testNode2.DeepCopy()
+			oldID   = oldNode.Spec.UUID
+			newID   = newNode.Spec.UUID
+			k       = c.k8sClient
+		)
+		oldNode.Annotations = map[string]string{ReplacedByAnnotationKey: newNode.Name}
+		k8sNode.Annotations = map[string]string{NodeIDAnnotationKey: newID}
+		createObjects(t, k, oldNode, newNode, k8sNode,
+			k.ConstructDriveCR("old-drive-1", api.Drive{UUID: "old-drive-1", SerialNumber: "sn-1", NodeId: oldID}),
+			k.ConstructDriveCR("old-drive-2", api.Drive{UUID: "old-drive-2", SerialNumber: "sn-2", NodeId: oldID}),
+			k.ConstructDriveCR("old-drive-3", api.Drive{UUID: "old-drive-3", SerialNumber: "sn-3", NodeId: oldID}),
+			k.ConstructDriveCR("new-drive-1", api.Drive{UUID: "new-drive-1", SerialNumber: "sn-1", NodeId: newID, Path: "/dev/sdb"}),
+			k.ConstructDriveCR("new-drive-2", api.Drive{UUID: "new-drive-2", SerialNumber: "sn-2", NodeId: newID}),
+			k.ConstructDriveCR("new-drive-4", api.Drive{UUID: "new-drive-4", SerialNumber: "sn-4", NodeId: newID}),
+			k.ConstructLVGCR("lvg-1", api.LogicalVolumeGroup{Name: "lvg-1", Node: oldID, Locations: []string{"old-drive-2"}}),
+			k.ConstructLVGCR("lvg-2", api.LogicalVolumeGroup{Name: "lvg-2", Node: oldID, Locations: []string{"old-drive-2", "old-drive-3"}}),
+			k.ConstructLVGCR("lvg-3", api.LogicalVolumeGroup{Name: "lvg-3", Node: oldID, Locations: []string{"old-drive-1"}}),
+			k.ConstructLVGCR("lvg-new", api.LogicalVolumeGroup{Name: "lvg-new", Node: newID, Locations: []string{"new-drive-4"}}),
+			k.ConstructACCR("ac-old-1", api.AvailableCapacity{Location: "old-drive-1", NodeId: oldID}),
+			k.ConstructACCR("ac-old-3", api.AvailableCapacity{Location: "old-drive-3", NodeId: oldID}),
+			k.ConstructACCR("ac-lvg-1", api.AvailableCapacity{Location: "lvg-1", NodeId: oldID}),
+			k.ConstructACCR("ac-new-1", api.AvailableCapacity{Location: "new-drive-1", NodeId: newID}),
+			k.ConstructACCR("ac-new-4", api.AvailableCapacity{Location: "new-drive-4", NodeId: newID}),
+			k.ConstructVolumeCR("volume-1", api.Volume{Id: "volume-1", Location: "old-drive-1", NodeId: oldID}),
+			k.ConstructVolumeCR("volume-2", api.Volume{Id: "volume-2", Location: "lvg-1", NodeId: oldID}),
+			k.ConstructVolumeCR("volume-3", api.Volume{Id: "volume-3", Location: "old-drive-3", NodeId: oldID}),
+			// MD RAID volumes
+			k.ConstructVolumeCR("raid-volume-1", api.Volume{Id: "raid-volume-1", Location: "old-drive-1",
+				Locations: []string{"old-drive-1", "old-drive-2"}, NodeId: oldID}),
+			k.ConstructVolumeCR("raid-volume-2", api.Volume{Id: "raid-volume-2", Location: "old-drive-1",
+				Locations: []string{"old-drive-1", "old-drive-3"}, NodeId: oldID}),
+			// cached volumes
+			k.ConstructVolumeCR("cached-volume-1", api.Volume{Id: "cached-volume-1", Location: "lvg-1",
+				CacheLocation: "lvg-3", NodeId: oldID}),
+			k.ConstructVolumeCR("cached-volume-2", api.Volume{Id: "cached-volume-2", Location: "lvg-1",
+				CacheLocation: "lvg-2", NodeId: oldID}),
+		)
+		// node service of the old node isn't able to handle finalizer
+		vol := new(volumecrd.Volume)
+		assert.Nil(t, k.ReadCR(testCtx, "volume-3", vol))
+		vol.Finalizers = []string{"dell.emc.csi/volume-cleanup"}
+		assert.Nil(t, k.UpdateCR(testCtx, vol))
+
+		res, err := c.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: oldNode.Name, Namespace: testNS}})
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+
+		// drives
+		drive := new(drivecrd.Drive)
+		for _, name := range []string{"old-drive-1", "old-drive-2"} {
+			assert.Nil(t, k.ReadCR(testCtx, name, drive), name)
+			assert.Equal(t, oldID, drive.Spec.NodeId, name)
+		}
+		// new drives are discovered by node service with the old UUID
+		for _, name := range []string{"old-drive-3", "new-drive-1", "new-drive-2", "new-drive-4"} {
+			assert.True(t, k8sError.IsNotFound(k.ReadCR(testCtx, name, drive)), name)
+		}
+
+		// LVGs
+		lvg := new(lvgcrd.LVG)
+		for _, name := range []string{"lvg-1", "lvg-3"} {
+			assert.Nil(t, k.ReadCR(testCtx, name, lvg), name)
+			assert.Equal(t, oldID, lvg.Spec.Node, name)
+		}
+		for _, name := range []string{"lvg-2", "lvg-new"} {
+			assert.True(t, k8sError.IsNotFound(k.ReadCR(testCtx, name, lvg)), name)
+		}
+
+		// ACs
+		ac := new(accrd.AvailableCapacity)
+		for _, name := range []string{"ac-old-1", "ac-lvg-1"} {
+			assert.Nil(t, k.ReadCR(testCtx, name, ac), name)
+			assert.Equal(t, oldID, ac.Spec.NodeId, name)
+		}
+		for _, name := range []string{"ac-old-3", "ac-new-1", "ac-new-4"} {
+			assert.True(t, k8sError.IsNotFound(k.ReadCR(testCtx, name, ac)), name)
+		}
+
+		// volumes
+		for _, name := range []string{"volume-1", "volume-2", "raid-volume-1", "cached-volume-1"} {
+			assert.Nil(t, k.ReadCR(testCtx, name, vol), name)
+			assert.Equal(t, oldID, vol.Spec.NodeId, name)
+		}
+		// one of the array members or cache LVG isn't kept
+		for _, name := range []string{"volume-3", "raid-volume-2", "cached-volume-2"} {
+			assert.True(t, k8sError.IsNotFound(k.ReadCR(testCtx, name, vol)), name)
+		}
+
+		// old CSIBMNode takes addresses of the new node, new CSIBMNode is removed
+		bmNode := new(nodecrd.CSIBMNode)
+		assert.Nil(t, k.ReadCR(testCtx, oldNode.Name, bmNode))
+		assert.Equal(t, newNode.Spec.Addresses, bmNode.Spec.Addresses)
+		assert.Equal(t, oldID, bmNode.Spec.UUID)
+		assert.NotContains(t, bmNode.GetAnnotations(), ReplacedByAnnotationKey)
+		assert.True(t, k8sError.IsNotFound(k.ReadCR(testCtx, newNode.Name, bmNode)))
+
+		// k8s node is annotated with the old UUID
+		assert.Nil(t, k.ReadCR(testCtx, k8sNode.Name, k8sNode))
+		assert.Equal(t, oldID, k8sNode.GetAnnotations()[NodeIDAnnotationKey])
+	})
+
+	t.Run("Missing resources are removed once", func(t *testing.T) {
+		var (
+			c       = setup(t)
+			oldNode = testCSIBMNode1.DeepCopy()
+			newNode = testCSIBMNode2.DeepCopy()
+			oldID   = oldNode.Spec.UUID
+			newID   = newNode.Spec.UUID
+			k       = c.k8sClient
+		)
+		// previous reconciliation failed after garbage collection and removal of some duplicated drives
+		oldNode.Annotations = map[string]string{ReplacedByAnnotationKey: newNode.Name}
+		oldNode.Spec.Addresses = newNode.Spec.Addresses
+		createObjects(t, k, oldNode, newNode, testNode2.DeepCopy(),
+			k.ConstructDriveCR("old-drive-1", api.Drive{UUID: "old-drive-1", SerialNumber: "sn-1", NodeId: oldID}),
+			k.ConstructDriveCR("old-drive-2", api.Drive{UUID: "old-drive-2", SerialNumber: "sn-2", NodeId: oldID}),
+			k.ConstructDriveCR("new-drive-2", api.Drive{UUID: "new-drive-2", SerialNumber: "sn-2", NodeId: newID}),
+			k.ConstructACCR("ac-new-2", api.AvailableCapacity{Location: "new-drive-2", NodeId: newID}),
+		)
+
+		res, err := c.reconcileNodeReplacement(oldNode)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.Nil(t, k.ReadCR(testCtx, "old-drive-1", new(drivecrd.Drive)))
+		assert.True(t, k8sError.IsNotFound(k.ReadCR(testCtx, "new-drive-2", new(drivecrd.Drive))))
+		assert.True(t, k8sError.IsNotFound(k.ReadCR(testCtx, "ac-new-2", new(accrd.AvailableCapacity))))
+	})
+
+	t.Run("Volumes are created on the new node", func(t *testing.T) {
+		var (
+			c       = setup(t)
+			oldNode = testCSIBMNode1.DeepCopy()
+			newNode = testCSIBMNode2.DeepCopy()
+			k       = c.k8sClient
+		)
+		oldNode.Annotations = map[string]string{ReplacedByAnnotationKey: newNode.Name}
+		createObjects(t, k, oldNode, newNode,
+			k.ConstructVolumeCR("volume-1", api.Volume{Id: "volume-1", NodeId: newNode.Spec.UUID}))
+
+		res, err := c.reconcileNodeReplacement(oldNode)
+		assert.NotNil(t, err)
+		assert.Equal(t, ctrl.Result{Requeue: false}, res)
+		assert.Nil(t, k.ReadCR(testCtx, newNode.Name, new(nodecrd.CSIBMNode)))
+	})
+
+	t.Run("New CSIBMNode is not found", func(t *testing.T) {
+		var (
+			c       = setup(t)
+			oldNode = testCSIBMNode1.DeepCopy()
+		)
+		oldNode.Annotations = map[string]string{ReplacedByAnnotationKey: "csibmnode-unknown"}
+		createObjects(t, c.k8sClient, oldNode)
+
+		res, err := c.reconcileNodeReplacement(oldNode)
+		assert.Nil(t, err)
+		assert.Equal(t, ctrl.Result{}, res)
+		assert.Nil(t, c.k8sClient.ReadCR(testCtx, oldNode.Name, new(nodecrd.CSIBMNode)))
+	})
+
+	t.Run("CSIBMNode is replaced by itself", func(t *testing.T) {
+		var (
+			c       = setup(t)
+			oldNode = testCSIBMNode1.DeepCopy()
+		)
+		oldNode.Annotations = map[string]string{ReplacedByAnnotationKey: oldNode.Name}
+		createObjects(t, c.k8sClient, oldNode)
+
+		res, err := c.reconcileNodeReplacement(oldNode)
+		assert.NotNil(t, err)
+		assert.Equal(t, ctrl.Result{Requeue: false}, res)
+	})
+}
@@ -119,8 +119,8 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 					return ctrl.Result{}, nil
 				}
 			}
-			// update AC size that point on that LVG
-			c.increaseACSize(lvg.Spec.Locations[0], lvg.Spec.Size)
+			// update size of ACs that point on drives of that LVG
+			c.increaseACsSize(lvg)
 
 			if len(lvg.Spec.Locations) == 0 {
 				ll.Warn("Location fields is empty")
@@ -128,6 +128,12 @@ func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 				drivesUUIDs := append(c.k8sClient.GetSystemDriveUUIDs(), base.SystemDriveAsLocation)
 				if !util.ContainsString(drivesUUIDs, lvg.Spec.Locations[0]) {
 					// cleanup LVM artifacts
+					if lvg.Spec.ThinPool {
+						if err := c.removeThinPool(lvg.Name); err != nil {
+							ll.Errorf("Unable to remove thin pool: %v", err)
+							return ctrl.Result{}, err
+						}
+					}
 					if err := c.removeLVGArtifacts(lvg.Name); err != nil {
 						ll.Errorf("Unable to cleanup LVM artifacts: %v", err)
 						return ctrl.Result{}, err
@@ -234,9 +240,38 @@ func (c *Controller) createSystemLVG(lvg *lvgcrd.LVG) (locations []string, err e
 		ll.Errorf("Unable to create VG: %v", err)
 		return locations, err
 	}
+	// thin volumes of LVG are created in thin pool which takes the whole VG
+	if lvg.Spec.ThinPool {
+		if err = c.lvmOps.ThinPoolCreate(lvg.Name, lvm.ThinPoolName); err != nil {
+			ll.Errorf("Unable to create thin pool: %v", err)
+			return locations, err
+		}
+	}
 	return locations, nil
 }
 
+// removeThinPool removes thin pool of LVG if there are no thin volumes in it
+func (c *Controller) removeThinPool(lvgName string) error {
+	lvs, err := c.lvmOps.GetLVsInVG(lvgName)
+	if err != nil {
+		return fmt.Errorf("unable to read LVs of LVG %s: %v", lvgName, err)
+	}
+	var poolExists bool
+	for _, lv := range lvs {
+		switch lv {
+		case "":
+		case lvm.ThinPoolName:
+			poolExists = true
+		default:
+			return fmt.Errorf("there are LVs in thin pool of LVG %s", lvgName)
+		}
+	}
+	if !poolExists {
+		return nil
+	}
+	return c.lvmOps.LVRemove(fmt.Sprintf("/dev/%s/%s", lvgName, lvm.ThinPoolName))
+}
+
 // removeLVGArtifacts removes LVG and PVs that doesn't correspond to particular LVG
 // when LVG is removed all PVs that were in that LVG becomes orphans
 func (c *Controller) removeLVGArtifacts(lvgName string) error {
@@ -259,6 +294,23 @@ func (c *Controller) removeLVGArtifacts(lvgName string) error {
 	return nil
 }
 
+// increaseACsSize updates size of ACs related to drives of LVG, LVG on a single drive returns its size to AC,
+// each drive of LVG which spans several drives returns the whole drive size to its AC
+func (c *Controller) increaseACsSize(lvg *lvgcrd.LVG) {
+	if len(lvg.Spec.Locations) == 1 {
+		c.increaseACSize(lvg.Spec.Locations[0], lvg.Spec.Size)
+		return
+	}
+	for _, driveUUID := range lvg.Spec.Locations {
+		drive := &drivecrd.Drive{}
+		if err := c.k8sClient.ReadCR(context.Background(), driveUUID, drive); err != nil {
+			c.log.WithField("method", "increaseACsSize").Errorf("Unable to read drive %s: %v", driveUUID, err)
+			continue
+		}
+		c.increaseACSize(driveUUID, drive.Spec.Size)
+	}
+}
+
 // increaseACSize updates size of AC related to drive
 func (c *Controller) increaseACSize(driveID string, size int64) {
 	ll := c.log.WithFields(logrus.Fields{
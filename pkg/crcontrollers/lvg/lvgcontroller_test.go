@@ -234,6 +234,45 @@ func TestReconcile_SuccessCreatingLVG(t *testing.T) {
 	assert.Contains(t, currLVG.ObjectMeta.Finalizers, lvgFinalizer)
 }
 
+func TestReconcile_SuccessCreatingThinPoolLVG(t *testing.T) {
+	var (
+		c       = setup(t, node1ID)
+		lvmOps  = &mocklu.MockWrapLVM{}
+		listBlk = &mocklu.MockWrapLsblk{}
+		req     = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: lvgCR1.Name}}
+		lvg     = &lvgcrd.LVG{}
+	)
+	defer teardown(t, c)
+
+	c.lvmOps = lvmOps
+	c.listBlk = listBlk
+
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, req.Name, lvg))
+	lvg.Spec.ThinPool = true
+	assert.Nil(t, c.k8sClient.UpdateCR(tCtx, lvg))
+
+	listBlk.On("SearchDrivePath", mock.Anything).Return("", nil)
+	lvmOps.On("PVCreate", mock.Anything).Return(nil)
+	lvmOps.On("VGCreate", mock.Anything, mock.Anything).Return(nil)
+	lvmOps.On("ThinPoolCreate", lvgCR1.Name, lvm.ThinPoolName).Return(errors.New("error")).Once()
+
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, res, ctrl.Result{})
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, req.Name, lvg))
+	assert.Equal(t, apiV1.Failed, lvg.Spec.Status)
+
+	lvg.Spec.Status = apiV1.Creating
+	assert.Nil(t, c.k8sClient.UpdateCR(tCtx, lvg))
+	lvmOps.On("ThinPoolCreate", lvgCR1.Name, lvm.ThinPoolName).Return(nil).Once()
+
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, res, ctrl.Result{})
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, req.Name, lvg))
+	assert.Equal(t, apiV1.Created, lvg.Spec.Status)
+}
+
 func TestReconcile_SuccessDeletion(t *testing.T) {
 	var (
 		c   = setup(t, node1ID)
@@ -411,6 +450,34 @@ func Test_removeLVGArtifacts_Fail(t *testing.T) {
 	assert.Contains(t, err.Error(), "unable to remove LVG")
 }
 
+func Test_removeThinPool(t *testing.T) {
+	var (
+		c        = setup(t, node1ID)
+		e        = &mocks.GoMockExecutor{}
+		vg       = lvgCR1.Name
+		lvsCmd   = fmt.Sprintf(lvm.LVsInVGCmdTmpl, vg)
+		removeLV = fmt.Sprintf(lvm.LVRemoveCmdTmpl, fmt.Sprintf("/dev/%s/%s", vg, lvm.ThinPoolName))
+	)
+	defer teardown(t, c)
+
+	c.lvmOps = lvm.NewLVM(e, testLogger)
+
+	// thin pool contains thin volume
+	e.OnCommand(lvsCmd).Return("  "+lvm.ThinPoolName+"\n  some-lv1\n", "", nil).Times(1)
+	assert.NotNil(t, c.removeThinPool(vg))
+
+	// there is no thin pool
+	e.OnCommand(lvsCmd).Return("", "", nil).Times(1)
+	assert.Nil(t, c.removeThinPool(vg))
+
+	e.OnCommand(lvsCmd).Return("  "+lvm.ThinPoolName+"\n", "", nil).Times(1)
+	e.OnCommand(removeLV).Return("", "", nil).Times(1)
+	assert.Nil(t, c.removeThinPool(vg))
+
+	e.OnCommand(lvsCmd).Return("", "", errors.New("error")).Times(1)
+	assert.NotNil(t, c.removeThinPool(vg))
+}
+
 func Test_increaseACSize(t *testing.T) {
 	c := setup(t, node1ID)
 	defer teardown(t, c)
@@ -429,6 +496,36 @@ func Test_increaseACSize(t *testing.T) {
 	assert.Equal(t, size+1, acList.Items[0].Spec.Size)
 }
 
+func Test_increaseACsSize(t *testing.T) {
+	c := setup(t, node1ID)
+	defer teardown(t, c)
+
+	for _, drive := range []api.Drive{apiDrive1, apiDrive2} {
+		ac := c.k8sClient.ConstructACCR("ac-"+drive.UUID, api.AvailableCapacity{
+			Location:     drive.UUID,
+			NodeId:       drive.NodeId,
+			StorageClass: apiV1.StorageClassHDD,
+		})
+		assert.Nil(t, c.k8sClient.CreateCR(tCtx, ac.Name, ac))
+	}
+
+	// LVG spans two drives, each AC gets size of its drive
+	c.increaseACsSize(&lvgCR1)
+	ac := &accrd.AvailableCapacity{}
+	for _, drive := range []api.Drive{apiDrive1, apiDrive2} {
+		assert.Nil(t, c.k8sClient.ReadCR(tCtx, "ac-"+drive.UUID, ac))
+		assert.Equal(t, drive.Size, ac.Spec.Size)
+	}
+
+	// LVG on the single drive returns its size
+	lvg := lvgCR1.DeepCopy()
+	lvg.Spec.Locations = []string{apiDrive2.UUID}
+	lvg.Spec.Size = 1
+	c.increaseACsSize(lvg)
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, "ac-"+apiDrive2.UUID, ac))
+	assert.Equal(t, apiDrive2.Size+1, ac.Spec.Size)
+}
+
 // setup creates drive CRs and LVG CRs and returns Controller instance
 func setup(t *testing.T, node string) *Controller {
 	k8sClient, err := k8s.GetFakeKubeClient(ns, testLogger)
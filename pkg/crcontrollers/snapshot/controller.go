@@ -0,0 +1,172 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot contains controller for Snapshot custom resource which manages LVM snapshots on the node
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// Controller is the Snapshot custom resource Controller for serving LVM snapshot operations on Node side
+// in Reconcile loop
+type Controller struct {
+	k8sClient *k8s.KubeClient
+	crHelper  *k8s.CRHelper
+
+	lvmOps lvm.WrapLVM
+
+	node string
+	log  *logrus.Entry
+}
+
+// NewController is the constructor for Controller struct
+// Receives an instance of base.KubeClient, ID of a node where it works and logrus logger
+// Returns an instance of Controller
+func NewController(k8sClient *k8s.KubeClient, nodeID string, log *logrus.Logger) *Controller {
+	e := &command.Executor{}
+	e.SetLogger(log)
+	return &Controller{
+		k8sClient: k8sClient,
+		crHelper:  k8s.NewCRHelper(k8sClient, log),
+		lvmOps:    lvm.NewLVM(e, log),
+		node:      nodeID,
+		log:       log.WithField("component", "SnapshotController"),
+	}
+}
+
+// Reconcile is the main Reconcile loop of Controller. This loop creates snapshot LV of the source volume
+// if Snapshot.Spec.CSIStatus is Creating and removes snapshot LV if Snapshot.Spec.CSIStatus is Removing
+// Returns reconcile result as ctrl.Result or error if something went wrong
+func (c *Controller) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancelFn()
+
+	ll := c.log.WithFields(logrus.Fields{
+		"method":     "Reconcile",
+		"snapshotID": req.Name,
+	})
+
+	snapshot := &snapshotcrd.Snapshot{}
+	if err := c.k8sClient.ReadCR(ctx, req.Name, snapshot); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	ll.Infof("Reconciling snapshot: %v", snapshot)
+
+	var newStatus string
+	switch snapshot.Spec.CSIStatus {
+	case apiV1.Creating:
+		newStatus = apiV1.Created
+		if err := c.createSnapshotLV(snapshot); err != nil {
+			ll.Errorf("Unable to create snapshot LV: %v", err)
+			newStatus = apiV1.Failed
+		}
+	case apiV1.Removing:
+		newStatus = apiV1.Removed
+		if err := c.removeSnapshotLV(snapshot); err != nil {
+			ll.Errorf("Unable to remove snapshot LV: %v", err)
+			newStatus = apiV1.Failed
+		}
+	default:
+		return ctrl.Result{}, nil
+	}
+
+	snapshot.Spec.CSIStatus = newStatus
+	if err := c.k8sClient.UpdateCR(ctx, snapshot); err != nil {
+		ll.Errorf("Unable to update snapshot status to %s, error: %v.", newStatus, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager registers Controller to ControllerManager
+func (c *Controller) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&snapshotcrd.Snapshot{}).
+		WithEventFilter(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return c.filterCRs(e.Object)
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return c.filterCRs(e.Object)
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return c.filterCRs(e.ObjectOld)
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return c.filterCRs(e.Object)
+			},
+		}).
+		Complete(c)
+}
+
+func (c *Controller) filterCRs(obj runtime.Object) bool {
+	if snapshot, ok := obj.(*snapshotcrd.Snapshot); ok {
+		if snapshot.Spec.NodeId == c.node {
+			return true
+		}
+	}
+	return false
+}
+
+// createSnapshotLV creates snapshot LV with name snapshot.Spec.Id for LV of the source volume,
+// snapshot of the thin LV is created in the same thin pool without size
+func (c *Controller) createSnapshotLV(snapshot *snapshotcrd.Snapshot) error {
+	lvg := &lvgcrd.LVG{}
+	if err := c.k8sClient.ReadCR(context.Background(), snapshot.Spec.Location, lvg); err != nil {
+		return err
+	}
+	fullLVName := fmt.Sprintf("/dev/%s/%s", lvg.Spec.Name, snapshot.Spec.VolumeId)
+	if lvg.Spec.ThinPool {
+		return c.lvmOps.LVThinSnapshotCreate(snapshot.Spec.Id, fullLVName)
+	}
+
+	// prepare size in megabytes for the argument
+	size, _ := util.ToSizeUnit(snapshot.Spec.Size, util.BYTE, util.MBYTE)
+	sizeStr := strconv.FormatInt(size, 10) + "m"
+
+	return c.lvmOps.LVSnapshotCreate(snapshot.Spec.Id, sizeStr, fullLVName)
+}
+
+// removeSnapshotLV removes snapshot LV with name snapshot.Spec.Id
+func (c *Controller) removeSnapshotLV(snapshot *snapshotcrd.Snapshot) error {
+	vgName, err := c.crHelper.GetVGNameByLVGCRName(snapshot.Spec.Location)
+	if err != nil {
+		return err
+	}
+
+	return c.lvmOps.LVSnapshotRemove(fmt.Sprintf("/dev/%s/%s", vgName, snapshot.Spec.Id))
+}
@@ -0,0 +1,181 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/lvgcrd"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+var (
+	tCtx       = context.Background()
+	testLogger = logrus.New()
+	ns         = "default"
+	node1ID    = "node1"
+	node2ID    = "node2"
+	lvgName    = "lvg-cr-1"
+	vgName     = "vg-1"
+	volumeID   = "volume-1"
+
+	apiSnapshot = api.Snapshot{
+		Id:        "snapshot-1",
+		VolumeId:  volumeID,
+		NodeId:    node1ID,
+		Location:  lvgName,
+		Size:      int64(util.GBYTE),
+		CSIStatus: apiV1.Creating,
+	}
+	apiLVG = api.LogicalVolumeGroup{
+		Name:      vgName,
+		Node:      node1ID,
+		Locations: []string{"drive-uuid"},
+		Size:      int64(100 * util.GBYTE),
+		Status:    apiV1.Created,
+	}
+)
+
+func TestNewController(t *testing.T) {
+	c := NewController(nil, node1ID, testLogger)
+	assert.NotNil(t, c)
+}
+
+func TestReconcile_NotFound(t *testing.T) {
+	c, _ := setup(t)
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: "not-found-that-name"}}
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+}
+
+func TestReconcile_CreateAndRemove(t *testing.T) {
+	var (
+		c, lvmOps = setup(t)
+		snapshot  = createSnapshotCR(t, c, apiSnapshot)
+		req       = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: snapshot.Name}}
+	)
+
+	lvmOps.On("LVSnapshotCreate", apiSnapshot.Id, "1024m", "/dev/vg-1/volume-1").Return(nil).Times(1)
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, req.Name, snapshot))
+	assert.Equal(t, apiV1.Created, snapshot.Spec.CSIStatus)
+
+	// nothing to do for Created snapshot
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+
+	snapshot.Spec.CSIStatus = apiV1.Removing
+	assert.Nil(t, c.k8sClient.UpdateCR(tCtx, snapshot))
+	lvmOps.On("LVSnapshotRemove", "/dev/vg-1/snapshot-1").Return(nil).Times(1)
+	res, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, req.Name, snapshot))
+	assert.Equal(t, apiV1.Removed, snapshot.Spec.CSIStatus)
+	lvmOps.AssertExpectations(t)
+}
+
+func TestReconcile_CreateThinSnapshot(t *testing.T) {
+	var (
+		c, lvmOps = setup(t)
+		lvg       = &lvgcrd.LVG{}
+		snapshot  = createSnapshotCR(t, c, apiSnapshot)
+		req       = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: snapshot.Name}}
+	)
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, lvgName, lvg))
+	lvg.Spec.ThinPool = true
+	assert.Nil(t, c.k8sClient.UpdateCR(tCtx, lvg))
+
+	lvmOps.On("LVThinSnapshotCreate", apiSnapshot.Id, "/dev/vg-1/volume-1").Return(nil).Times(1)
+	res, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, req.Name, snapshot))
+	assert.Equal(t, apiV1.Created, snapshot.Spec.CSIStatus)
+	lvmOps.AssertExpectations(t)
+}
+
+func TestReconcile_Failed(t *testing.T) {
+	var (
+		c, lvmOps = setup(t)
+		snapshot  = createSnapshotCR(t, c, apiSnapshot)
+		req       = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: snapshot.Name}}
+	)
+
+	lvmOps.On("LVSnapshotCreate", apiSnapshot.Id, "1024m", "/dev/vg-1/volume-1").
+		Return(errors.New("lvcreate failed")).Times(1)
+	_, err := c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, req.Name, snapshot))
+	assert.Equal(t, apiV1.Failed, snapshot.Spec.CSIStatus)
+
+	// LVG CR doesn't exist
+	s := apiSnapshot
+	s.Id = "snapshot-2"
+	s.Location = "another-lvg"
+	snapshot = createSnapshotCR(t, c, s)
+	req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: snapshot.Name}}
+	_, err = c.Reconcile(req)
+	assert.Nil(t, err)
+	assert.Nil(t, c.k8sClient.ReadCR(tCtx, req.Name, snapshot))
+	assert.Equal(t, apiV1.Failed, snapshot.Spec.CSIStatus)
+}
+
+func TestFilterCRs(t *testing.T) {
+	c, _ := setup(t)
+
+	s := apiSnapshot
+	assert.True(t, c.filterCRs(c.k8sClient.ConstructSnapshotCR(s.Id, s)))
+	s.NodeId = node2ID
+	assert.False(t, c.filterCRs(c.k8sClient.ConstructSnapshotCR(s.Id, s)))
+	assert.False(t, c.filterCRs(c.k8sClient.ConstructLVGCR(lvgName, apiLVG)))
+}
+
+func setup(t *testing.T) (*Controller, *mocklu.MockWrapLVM) {
+	k8sClient, err := k8s.GetFakeKubeClient(ns, testLogger)
+	assert.Nil(t, err)
+
+	c := NewController(k8sClient, node1ID, testLogger)
+	lvmOps := &mocklu.MockWrapLVM{}
+	c.lvmOps = lvmOps
+
+	assert.Nil(t, k8sClient.CreateCR(tCtx, lvgName, k8sClient.ConstructLVGCR(lvgName, apiLVG)))
+	return c, lvmOps
+}
+
+func createSnapshotCR(t *testing.T, c *Controller, s api.Snapshot) *snapshotcrd.Snapshot {
+	snapshot := c.k8sClient.ConstructSnapshotCR(s.Id, s)
+	assert.Nil(t, c.k8sClient.CreateCR(tCtx, s.Id, snapshot))
+	return snapshot
+}
@@ -17,16 +17,36 @@ limitations under the License.
 package basemgr
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ledctl"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsscsi"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/nvmecli"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ses"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/smartctl"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/uevent"
+	"github.com/dell/csi-baremetal/pkg/drivemgr"
+	"github.com/dell/csi-baremetal/pkg/drivemgr/healthpolicy"
+)
+
+const (
+	// ueventReadTimeout is a period of checking whether watching of drives is cancelled
+	ueventReadTimeout = time.Second
+	// megaRAIDDevicesPerChannel is a number of physical drives on one SCSI channel of MegaRAID controller,
+	// it is used to calculate device ID of the drive for smartctl
+	megaRAIDDevicesPerChannel = 128
 )
 
 //BaseManager is a drive manager based on Linux system utils
@@ -34,8 +54,15 @@ type BaseManager struct {
 	exec     command.CmdExecutor
 	log      *logrus.Entry
 	lsscsi   lsscsi.WrapLsscsi
+	lsblk    lsblk.WrapLsblk
 	smartctl smartctl.WrapSmartctl
 	nvme     nvmecli.WrapNvmecli
+	ledctl   ledctl.WrapLedctl
+	ses      ses.WrapSES
+	// derives drive health from SMART attributes
+	healthPolicy *healthpolicy.Policy
+	// opens source of kernel uevents for WatchDrives
+	newUeventReader func() (uevent.Reader, error)
 }
 
 //GetDrivesList gets api.Drive slice using Linux system utils
@@ -53,20 +80,132 @@ func (mgr BaseManager) GetDrivesList() ([]*api.Drive, error) {
 		ll.Errorf("Failed to initialize devices, Error: %v", err)
 	}
 	devices = append(devices, nvmDevices...)
+	mgr.fillLocations(devices)
 	return devices, nil
 }
 
+//fillLocations sets enclosure, slot and bay of the drives using SCSI enclosure services
+func (mgr BaseManager) fillLocations(devices []*api.Drive) {
+	if len(devices) == 0 {
+		return
+	}
+	locations, err := mgr.ses.GetLocations()
+	if err != nil {
+		mgr.log.WithField("method", "fillLocations").Errorf("Failed to get locations of drives, Error: %v", err)
+		return
+	}
+	for _, device := range devices {
+		if location, ok := locations[filepath.Base(device.Path)]; ok {
+			device.Enclosure = location.Enclosure
+			device.Slot = location.Slot
+			device.Bay = location.Bay
+		}
+	}
+}
+
 //New is a constructor BaseManager
 func New(exec command.CmdExecutor, logger *logrus.Logger) *BaseManager {
 	return &BaseManager{
-		exec:     exec,
-		log:      logger.WithField("component", "BaseManager"),
-		lsscsi:   lsscsi.NewLSSCSI(exec, logger),
-		smartctl: smartctl.NewSMARTCTL(exec),
-		nvme:     nvmecli.NewNVMECLI(exec, logger),
+		exec:         exec,
+		log:          logger.WithField("component", "BaseManager"),
+		lsscsi:       lsscsi.NewLSSCSI(exec, logger),
+		lsblk:        lsblk.NewLSBLK(logger),
+		smartctl:     smartctl.NewSMARTCTL(exec),
+		nvme:         nvmecli.NewNVMECLI(exec, logger),
+		ledctl:       ledctl.NewLEDCTL(exec, logger),
+		ses:          ses.NewSES(exec, logger),
+		healthPolicy: healthpolicy.DefaultPolicy(),
+		newUeventReader: func() (uevent.Reader, error) {
+			return uevent.NewListener(ueventReadTimeout)
+		},
+	}
+}
+
+//SetHealthPolicy sets policy which is used to derive drive health from SMART attributes
+func (mgr *BaseManager) SetHealthPolicy(policy *healthpolicy.Policy) {
+	mgr.healthPolicy = policy
+}
+
+//applyHealthPolicy updates health of the drive according to health policy
+func (mgr *BaseManager) applyHealthPolicy(drive *api.Drive) {
+	if mgr.healthPolicy == nil {
+		return
+	}
+	health := mgr.healthPolicy.Evaluate(drive)
+	if health != drive.Health {
+		mgr.log.WithField("method", "applyHealthPolicy").
+			Infof("Health of drive %s is set to %s by health policy, SMART: %v", drive.SerialNumber, health, drive.SMART)
+		drive.Health = health
+	}
+}
+
+//WatchDrives listens for kernel uevents and calls notify when whole block device is added, removed or changed
+//Device mapper, loop and ram devices are skipped because they aren't managed by BaseManager
+func (mgr *BaseManager) WatchDrives(ctx context.Context, notify func()) error {
+	ll := mgr.log.WithField("method", "WatchDrives")
+
+	reader, err := mgr.newUeventReader()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			ll.Errorf("Unable to close uevent reader: %v", err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		event, err := reader.ReadEvent()
+		if err != nil {
+			return err
+		}
+		if event == nil || !event.IsDisk() {
+			continue
+		}
+		devName := event.Env["DEVNAME"]
+		if strings.HasPrefix(devName, "dm-") || strings.HasPrefix(devName, "loop") ||
+			strings.HasPrefix(devName, "ram") {
+			continue
+		}
+		switch event.Action {
+		case uevent.ActionAdd, uevent.ActionRemove, uevent.ActionChange:
+			ll.Infof("Got %s event for device %s", event.Action, devName)
+			notify()
+		}
 	}
 }
 
+//SetDriveLED sets LED state of the drive with provided serial number using ledctl system util,
+//sg_ses system util is used when ledctl fails
+func (mgr *BaseManager) SetDriveLED(serialNumber, state string) error {
+	ll := mgr.log.WithField("method", "SetDriveLED")
+	devices, err := mgr.lsblk.GetBlockDevices("")
+	if err != nil {
+		return err
+	}
+	path := ""
+	for _, d := range devices {
+		if strings.EqualFold(strings.TrimSpace(d.Serial), serialNumber) {
+			path = d.Name
+			break
+		}
+	}
+	if path == "" {
+		return drivemgr.ErrDriveNotFound
+	}
+	if err = mgr.ledctl.SetLEDState(path, state); err != nil {
+		ll.Warnf("Unable to set LED of %s with ledctl: %v, trying sg_ses", path, err)
+		return mgr.ses.SetLEDState(filepath.Base(path), state)
+	}
+	return nil
+}
+
 //GetSCSIDevices get []*api.Drive using lsscsi system util
 func (mgr *BaseManager) GetSCSIDevices() ([]*api.Drive, error) {
 	ll := mgr.log.WithField("method", "GetSCSIDevices")
@@ -83,11 +222,26 @@ func (mgr *BaseManager) GetSCSIDevices() ([]*api.Drive, error) {
 			VID:      device.Vendor,
 			PID:      device.Model,
 			Size:     device.Size,
+			// filled from SMART information below if drive reports it
+			Endurance: apiV1.DriveEnduranceUnknown,
 		})
 	}
 	devices := make([]*api.Drive, 0)
 	for i, device := range allDevices {
 		smartInfo, err := mgr.smartctl.GetDriveInfoByPath(device.Path)
+		var raidErr *smartctl.RAIDControllerError
+		if errors.As(err, &raidErr) {
+			smartInfo, err = mgr.getRAIDDriveInfo(scsiDevices[i], raidErr.ControllerType)
+			if err == nil {
+				// lsscsi could report controller instead of the physical drive
+				if smartInfo.Vendor != "" {
+					allDevices[i].VID = smartInfo.Vendor
+				}
+				if smartInfo.ModelName != "" {
+					allDevices[i].PID = smartInfo.ModelName
+				}
+			}
+		}
 		if err != nil {
 			//We don't fail whole drivemgr because of error with just one device, we don't add it in allDevices slice
 			ll.Errorf("Failed to get SMART information for Device %v, Error: %v", allDevices[i], err)
@@ -104,6 +258,11 @@ func (mgr *BaseManager) GetSCSIDevices() ([]*api.Drive, error) {
 				} else {
 					allDevices[i].Health = apiV1.HealthBad
 				}
+				allDevices[i].SMART = smartInfo.GetSMARTInfo()
+				if endurance, ok := smartInfo.GetEndurance(); ok {
+					allDevices[i].Endurance = endurance
+				}
+				mgr.applyHealthPolicy(allDevices[i])
 				devices = append(devices, allDevices[i])
 			} else {
 				ll.Errorf("Device has empty VID, PID or SN field: %v", allDevices[i])
@@ -113,6 +272,29 @@ func (mgr *BaseManager) GetSCSIDevices() ([]*api.Drive, error) {
 	return devices, nil
 }
 
+//getRAIDDriveInfo gets SMART information of physical drive behind RAID controller in pass-through (JBOD) mode
+//Device ID of the drive is calculated from SCSI address the same way as controller drivers do it:
+//megaraid_sas exposes drive with ID N as channel N / 128 and target N % 128, hpsa exposes drives as LUNs
+func (mgr *BaseManager) getRAIDDriveInfo(device *lsscsi.SCSIDevice,
+	controllerType string) (*smartctl.DeviceSMARTInfo, error) {
+	_, channel, target, lun, err := device.Address()
+	if err != nil {
+		return nil, err
+	}
+	var deviceID int
+	switch controllerType {
+	case smartctl.RAIDControllerMegaRAID:
+		deviceID = channel*megaRAIDDevicesPerChannel + target
+	case smartctl.RAIDControllerCCISS:
+		deviceID = lun
+	default:
+		return nil, fmt.Errorf("unsupported RAID controller %s", controllerType)
+	}
+	mgr.log.WithField("method", "getRAIDDriveInfo").
+		Infof("Device %s is behind %s controller, reading drive with device ID %d", device.Path, controllerType, deviceID)
+	return mgr.smartctl.GetRAIDDriveInfo(device.Path, controllerType, deviceID)
+}
+
 //GetNVMDevices get []*api.Drive using nvme_cli system util
 func (mgr *BaseManager) GetNVMDevices() ([]*api.Drive, error) {
 	ll := mgr.log.WithField("method", "GetNVMDevices")
@@ -124,7 +306,7 @@ func (mgr *BaseManager) GetNVMDevices() ([]*api.Drive, error) {
 	}
 	for _, device := range nvmeDevices {
 		if device.Vendor != 0 && device.ModelNumber != "" && device.SerialNumber != "" {
-			devices = append(devices, &api.Drive{
+			drive := &api.Drive{
 				Health:       device.Health,
 				PID:          device.ModelNumber,
 				VID:          strconv.Itoa(device.Vendor),
@@ -133,7 +315,17 @@ func (mgr *BaseManager) GetNVMDevices() ([]*api.Drive, error) {
 				Size:         device.PhysicalSize,
 				Firmware:     device.Firmware,
 				Path:         device.DevicePath,
-			})
+				SMART:        device.SMART,
+				Endurance:    apiV1.DriveEnduranceUnknown,
+			}
+			if device.SMART != nil {
+				drive.Endurance = 100 - device.SMART.PercentageUsed
+				if drive.Endurance < 0 {
+					drive.Endurance = 0
+				}
+			}
+			mgr.applyHealthPolicy(drive)
+			devices = append(devices, drive)
 		} else {
 			ll.Errorf("Device has empty VID, PID or SN field: %v", device)
 		}
@@ -17,6 +17,7 @@ limitations under the License.
 package basemgr
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -24,10 +25,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsscsi"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/nvmecli"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/ses"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/smartctl"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/uevent"
+	"github.com/dell/csi-baremetal/pkg/drivemgr"
+	"github.com/dell/csi-baremetal/pkg/drivemgr/healthpolicy"
 	"github.com/dell/csi-baremetal/pkg/mocks"
 	"github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
 )
@@ -163,6 +170,154 @@ func TestLoopBackManager_GetSCSIDevices(t *testing.T) {
 	assert.Equal(t, apiV1.DriveTypeHDD, devices[0].Type)
 }
 
+func TestBaseManager_HealthPolicy(t *testing.T) {
+	var (
+		mockexec     = &mocks.GoMockExecutor{}
+		manager      = New(mockexec, logger)
+		mockLsscsi   = &linuxutils.MockWrapLsscsi{}
+		mockSmartctl = &linuxutils.MockWrapSmartctl{}
+		mockNvme     = &linuxutils.MockWrapNvmecli{}
+	)
+
+	smart := &smartctl.DeviceSMARTInfo{
+		SerialNumber: "testSN",
+		SmartStatus:  map[string]bool{"passed": true},
+	}
+	smart.ATAAttributes.Table = []smartctl.ATAAttribute{{ID: 197}}
+	smart.ATAAttributes.Table[0].Raw.Value = 12
+	mockLsscsi.On("GetSCSIDevices", mock.Anything).Return([]*lsscsi.SCSIDevice{
+		{Path: "testPath", Vendor: "testVendor", Model: "testModel"},
+	}, nil)
+	mockSmartctl.On("GetDriveInfoByPath", "testPath").Return(smart, nil)
+	mockNvme.On("GetNVMDevices", mock.Anything).Return([]nvmecli.NVMDevice{{
+		DevicePath:   "nvmePath",
+		ModelNumber:  "testModel",
+		SerialNumber: "nvmeSN",
+		Vendor:       2311,
+		Health:       apiV1.HealthGood,
+		SMART:        &api.SMARTInfo{PercentageUsed: 100},
+	}}, nil)
+	manager.lsscsi = mockLsscsi
+	manager.smartctl = mockSmartctl
+	manager.nvme = mockNvme
+
+	// pending sectors exceed default threshold
+	devices, err := manager.GetSCSIDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(devices))
+	assert.Equal(t, int64(12), devices[0].SMART.PendingSectors)
+	assert.Equal(t, apiV1.HealthSuspect, devices[0].Health)
+	assert.Equal(t, int64(apiV1.DriveEnduranceUnknown), devices[0].Endurance)
+
+	devices, err = manager.GetNVMDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(devices))
+	assert.Equal(t, int64(100), devices[0].SMART.PercentageUsed)
+	assert.Equal(t, apiV1.HealthBad, devices[0].Health)
+	assert.Equal(t, int64(0), devices[0].Endurance)
+
+	manager.SetHealthPolicy(&healthpolicy.Policy{})
+	devices, err = manager.GetSCSIDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.HealthGood, devices[0].Health)
+}
+
+func TestBaseManager_Endurance(t *testing.T) {
+	var (
+		mockexec     = &mocks.GoMockExecutor{}
+		manager      = New(mockexec, logger)
+		mockLsscsi   = &linuxutils.MockWrapLsscsi{}
+		mockSmartctl = &linuxutils.MockWrapSmartctl{}
+		mockNvme     = &linuxutils.MockWrapNvmecli{}
+	)
+
+	smart := &smartctl.DeviceSMARTInfo{
+		SerialNumber: "testSN",
+		SmartStatus:  map[string]bool{"passed": true},
+	}
+	smart.ATAAttributes.Table = []smartctl.ATAAttribute{{ID: 233, Value: 85}}
+	mockLsscsi.On("GetSCSIDevices", mock.Anything).Return([]*lsscsi.SCSIDevice{
+		{Path: "testPath", Vendor: "testVendor", Model: "testModel"},
+	}, nil)
+	mockSmartctl.On("GetDriveInfoByPath", "testPath").Return(smart, nil)
+	mockNvme.On("GetNVMDevices", mock.Anything).Return([]nvmecli.NVMDevice{{
+		DevicePath:   "nvmePath",
+		ModelNumber:  "testModel",
+		SerialNumber: "nvmeSN",
+		Vendor:       2311,
+		Health:       apiV1.HealthGood,
+		SMART:        &api.SMARTInfo{PercentageUsed: 7},
+	}}, nil)
+	manager.lsscsi = mockLsscsi
+	manager.smartctl = mockSmartctl
+	manager.nvme = mockNvme
+
+	devices, err := manager.GetSCSIDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(devices))
+	assert.Equal(t, int64(85), devices[0].Endurance)
+
+	devices, err = manager.GetNVMDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(devices))
+	assert.Equal(t, int64(93), devices[0].Endurance)
+}
+
+func TestBaseManager_GetSCSIDevicesRAIDController(t *testing.T) {
+	var (
+		mockexec     = &mocks.GoMockExecutor{}
+		manager      = New(mockexec, logger)
+		mockLsscsi   = &linuxutils.MockWrapLsscsi{}
+		mockSmartctl = &linuxutils.MockWrapSmartctl{}
+	)
+
+	mockLsscsi.On("GetSCSIDevices", mock.Anything).Return([]*lsscsi.SCSIDevice{
+		{ID: "[0:1:2:0]", Path: "/dev/sda", Vendor: "DELL", Model: "PERC H730P Mini"},
+		{ID: "[1:0:0:3]", Path: "/dev/sdb", Vendor: "HP", Model: "LOGICAL VOLUME"},
+		{ID: "[2:0:0:0]", Path: "/dev/sdc", Vendor: "HP", Model: "LOGICAL VOLUME"},
+	}, nil)
+	mockSmartctl.On("GetDriveInfoByPath", "/dev/sda").Return((*smartctl.DeviceSMARTInfo)(nil),
+		&smartctl.RAIDControllerError{ControllerType: smartctl.RAIDControllerMegaRAID})
+	mockSmartctl.On("GetDriveInfoByPath", "/dev/sdb").Return((*smartctl.DeviceSMARTInfo)(nil),
+		&smartctl.RAIDControllerError{ControllerType: smartctl.RAIDControllerCCISS})
+	mockSmartctl.On("GetDriveInfoByPath", "/dev/sdc").Return((*smartctl.DeviceSMARTInfo)(nil),
+		&smartctl.RAIDControllerError{ControllerType: smartctl.RAIDControllerCCISS})
+	mockSmartctl.On("GetRAIDDriveInfo", "/dev/sda", smartctl.RAIDControllerMegaRAID, 130).
+		Return(&smartctl.DeviceSMARTInfo{
+			SerialNumber: "megaraidSN",
+			ModelName:    "ST4000NM0023",
+			Rotation:     7200,
+			SmartStatus:  map[string]bool{"passed": true},
+		}, nil)
+	mockSmartctl.On("GetRAIDDriveInfo", "/dev/sdb", smartctl.RAIDControllerCCISS, 3).
+		Return(&smartctl.DeviceSMARTInfo{
+			SerialNumber: "ccissSN",
+			Vendor:       "SEAGATE",
+			ModelName:    "SEAGATE XS800LE10003",
+			SmartStatus:  map[string]bool{"passed": false},
+		}, nil)
+	mockSmartctl.On("GetRAIDDriveInfo", "/dev/sdc", smartctl.RAIDControllerCCISS, 0).
+		Return((*smartctl.DeviceSMARTInfo)(nil), fmt.Errorf("error"))
+	manager.lsscsi = mockLsscsi
+	manager.smartctl = mockSmartctl
+
+	devices, err := manager.GetSCSIDevices()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(devices))
+
+	assert.Equal(t, "megaraidSN", devices[0].SerialNumber)
+	assert.Equal(t, "DELL", devices[0].VID)
+	assert.Equal(t, "ST4000NM0023", devices[0].PID)
+	assert.Equal(t, apiV1.DriveTypeHDD, devices[0].Type)
+	assert.Equal(t, apiV1.HealthGood, devices[0].Health)
+
+	assert.Equal(t, "ccissSN", devices[1].SerialNumber)
+	assert.Equal(t, "SEAGATE", devices[1].VID)
+	assert.Equal(t, "SEAGATE XS800LE10003", devices[1].PID)
+	assert.Equal(t, apiV1.DriveTypeSSD, devices[1].Type)
+	assert.Equal(t, apiV1.HealthBad, devices[1].Health)
+}
+
 func TestLoopBackManager_GetSCSIDevicesEmptyVidPidSn(t *testing.T) {
 	var (
 		mockexec     = &mocks.GoMockExecutor{}
@@ -306,3 +461,127 @@ func TestLoopBackManager_GetDrivesListSuccess(t *testing.T) {
 
 	assert.Nil(t, err)
 }
+
+func TestBaseManager_GetDrivesListLocations(t *testing.T) {
+	var (
+		mockexec   = &mocks.GoMockExecutor{}
+		manager    = New(mockexec, logger)
+		mockLsscsi = &linuxutils.MockWrapLsscsi{}
+		mockNvme   = &linuxutils.MockWrapNvmecli{}
+		mockSES    = &linuxutils.MockWrapSES{}
+	)
+	mockNvme.On("GetNVMDevices", mock.Anything).Return([]nvmecli.NVMDevice{
+		{DevicePath: "/dev/nvme0n1", SerialNumber: "nvmeSN1", ModelNumber: "testModel", Vendor: 2311},
+		{DevicePath: "/dev/nvme1n1", SerialNumber: "nvmeSN2", ModelNumber: "testModel", Vendor: 2311},
+	}, nil)
+	mockLsscsi.On("GetSCSIDevices", mock.Anything).Return([]*lsscsi.SCSIDevice{}, nil)
+	mockSES.On("GetLocations").Return(map[string]*ses.Location{
+		"nvme0n1": {Enclosure: "0x5000cca04a000000", Slot: "7", Bay: "Slot07"},
+	}, nil).Once()
+	manager.lsscsi = mockLsscsi
+	manager.nvme = mockNvme
+	manager.ses = mockSES
+
+	devices, err := manager.GetDrivesList()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(devices))
+	assert.Equal(t, "0x5000cca04a000000", devices[0].Enclosure)
+	assert.Equal(t, "7", devices[0].Slot)
+	assert.Equal(t, "Slot07", devices[0].Bay)
+	assert.Equal(t, "", devices[1].Slot)
+
+	// drives are reported without locations if enclosures can't be read
+	mockSES.On("GetLocations").Return(map[string]*ses.Location{}, fmt.Errorf("error")).Once()
+	devices, err = manager.GetDrivesList()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(devices))
+	assert.Equal(t, "", devices[0].Slot)
+}
+
+func TestBaseManager_SetDriveLED(t *testing.T) {
+	var (
+		mockexec   = &mocks.GoMockExecutor{}
+		manager    = New(mockexec, logger)
+		mockLsblk  = &linuxutils.MockWrapLsblk{}
+		mockLedctl = &linuxutils.MockWrapLedctl{}
+		mockSES    = &linuxutils.MockWrapSES{}
+	)
+	mockLsblk.On("GetBlockDevices", "").Return([]lsblk.BlockDevice{
+		{Name: "/dev/sda", Serial: "scsiSN"},
+		{Name: "/dev/nvme0n1", Serial: "nvmeSN  "},
+	}, nil)
+	mockLedctl.On("SetLEDState", "/dev/nvme0n1", apiV1.LEDStateLocate).Return(nil).Once()
+	mockLedctl.On("SetLEDState", "/dev/sda", apiV1.LEDStateLocate).Return(fmt.Errorf("error")).Once()
+	mockSES.On("SetLEDState", "sda", apiV1.LEDStateLocate).Return(nil).Once()
+	manager.lsblk = mockLsblk
+	manager.ledctl = mockLedctl
+	manager.ses = mockSES
+
+	assert.Nil(t, manager.SetDriveLED("nvmeSN", apiV1.LEDStateLocate))
+	// sg_ses is used when ledctl fails
+	assert.Nil(t, manager.SetDriveLED("scsiSN", apiV1.LEDStateLocate))
+	assert.Equal(t, drivemgr.ErrDriveNotFound, manager.SetDriveLED("unknownSN", apiV1.LEDStateLocate))
+	mockLedctl.AssertExpectations(t)
+	mockSES.AssertExpectations(t)
+
+	mockLsblk = &linuxutils.MockWrapLsblk{}
+	mockLsblk.On("GetBlockDevices", "").Return(nil, fmt.Errorf("error"))
+	manager.lsblk = mockLsblk
+	assert.NotNil(t, manager.SetDriveLED("nvmeSN", apiV1.LEDStateLocate))
+}
+
+// fakeUeventReader returns prepared events and cancels context when they are over
+type fakeUeventReader struct {
+	events   []*uevent.Event
+	cancelFn context.CancelFunc
+	closed   bool
+}
+
+func (r *fakeUeventReader) ReadEvent() (*uevent.Event, error) {
+	if len(r.events) == 0 {
+		r.cancelFn()
+		return nil, nil
+	}
+	event := r.events[0]
+	r.events = r.events[1:]
+	return event, nil
+}
+
+func (r *fakeUeventReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestBaseManager_WatchDrives(t *testing.T) {
+	var (
+		mockexec    = &mocks.GoMockExecutor{}
+		manager     = New(mockexec, logger)
+		ctx, cancel = context.WithCancel(context.Background())
+		diskEnv     = func(name string) map[string]string {
+			return map[string]string{"SUBSYSTEM": uevent.SubsystemBlock, "DEVTYPE": uevent.DevTypeDisk, "DEVNAME": name}
+		}
+		reader = &fakeUeventReader{
+			cancelFn: cancel,
+			events: []*uevent.Event{
+				{Action: uevent.ActionAdd, Env: diskEnv("sdb")},
+				{Action: uevent.ActionRemove, Env: diskEnv("nvme0n1")},
+				// skipped events
+				{Action: uevent.ActionChange, Env: diskEnv("dm-1")},
+				{Action: uevent.ActionAdd, Env: diskEnv("loop0")},
+				{Action: uevent.ActionAdd, Env: map[string]string{"SUBSYSTEM": uevent.SubsystemBlock,
+					"DEVTYPE": "partition", "DEVNAME": "sdb1"}},
+				{Action: "bind", Env: diskEnv("sdc")},
+				nil,
+			},
+		}
+		notifications int
+	)
+	manager.newUeventReader = func() (uevent.Reader, error) { return reader, nil }
+
+	assert.Nil(t, manager.WatchDrives(ctx, func() { notifications++ }))
+	assert.Equal(t, 2, notifications)
+	assert.True(t, reader.closed)
+
+	manager.newUeventReader = func() (uevent.Reader, error) { return nil, fmt.Errorf("error") }
+	assert.NotNil(t, manager.WatchDrives(context.Background(), func() {}))
+}
@@ -17,10 +17,57 @@ limitations under the License.
 // Package drivemgr contains a code for managers of storage hardware such as drives
 package drivemgr
 
-import api "github.com/dell/csi-baremetal/api/generated/v1"
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// ErrDriveNotFound is returned by DriveManager if drive with provided serial number isn't found
+var ErrDriveNotFound = errors.New("drive not found")
 
 // DriveManager is the interface for managers that provide information about drives on a node
 type DriveManager interface {
 	// get list of drives
 	GetDrivesList() ([]*api.Drive, error)
+	// set state (one of LEDState* constants) of the LED of the drive with provided serial number
+	SetDriveLED(serialNumber, state string) error
+}
+
+// DriveWatcher is the interface for managers which are able to detect changes of drives without polling
+type DriveWatcher interface {
+	// call notify on each (possible) change of drives until ctx is done
+	WatchDrives(ctx context.Context, notify func()) error
+}
+
+// DiffDrives compares two lists of drives got from DriveManager, drives are matched by serial number
+// Returns events which describe transition from prev to cur
+func DiffDrives(prev, cur []*api.Drive) []*api.DriveEvent {
+	prevBySN := make(map[string]*api.Drive, len(prev))
+	for _, d := range prev {
+		prevBySN[d.SerialNumber] = d
+	}
+
+	events := make([]*api.DriveEvent, 0)
+	for _, d := range cur {
+		p, ok := prevBySN[d.SerialNumber]
+		switch {
+		case !ok:
+			events = append(events, &api.DriveEvent{Type: apiV1.DriveEventAdded, Drive: d})
+		case !proto.Equal(p, d):
+			events = append(events, &api.DriveEvent{Type: apiV1.DriveEventModified, Drive: d})
+		}
+		delete(prevBySN, d.SerialNumber)
+	}
+	// keep order of removed drives stable
+	for _, d := range prev {
+		if _, ok := prevBySN[d.SerialNumber]; ok {
+			events = append(events, &api.DriveEvent{Type: apiV1.DriveEventRemoved, Drive: d})
+		}
+	}
+	return events
 }
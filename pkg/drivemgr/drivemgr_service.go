@@ -22,6 +22,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
@@ -55,14 +56,116 @@ func (svc *DriveServiceServerImpl) GetDrivesList(ctx context.Context, req *api.D
 		svc.log.Errorf("DriveManager failed with error: %s", err.Error())
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	// All drives are ONLINE by default
+	fillDefaults(drives, req.NodeId)
+	return &api.DrivesResponse{
+		Disks: drives,
+	}, nil
+}
+
+// SetDriveLED invokes DriveManager's SetDriveLED() to change state of the drive LED
+// Receives go context and DriveLEDRequest which contains drive serial number and LED state
+// Returns DriveLEDResponse with applied LED state
+func (svc *DriveServiceServerImpl) SetDriveLED(ctx context.Context, req *api.DriveLEDRequest) (*api.DriveLEDResponse, error) {
+	ll := svc.log.WithFields(logrus.Fields{
+		"method":  "SetDriveLED",
+		"driveID": req.DriveUUID,
+	})
+
+	switch {
+	case req.SerialNumber == "":
+		return nil, status.Error(codes.InvalidArgument, "drive serial number must be provided")
+	case req.State != apiV1.LEDStateOff && req.State != apiV1.LEDStateLocate && req.State != apiV1.LEDStateFailure:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported LED state %s", req.State)
+	}
+
+	ll.Infof("Setting LED state %s for drive %s", req.State, req.SerialNumber)
+	if err := svc.mgr.SetDriveLED(req.SerialNumber, req.State); err != nil {
+		ll.Errorf("DriveManager failed with error: %v", err)
+		if err == ErrDriveNotFound {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.DriveLEDResponse{State: req.State}, nil
+}
+
+// WatchDrives streams changes of drives if DriveManager implements DriveWatcher interface
+// On each notification from DriveManager drives list is re-read and compared with the previous one,
+// so only added, modified and removed drives are sent
+// Returns Unimplemented status error if DriveManager isn't able to watch drives
+func (svc *DriveServiceServerImpl) WatchDrives(req *api.DrivesRequest, stream api.DriveService_WatchDrivesServer) error {
+	ll := svc.log.WithFields(logrus.Fields{
+		"method": "WatchDrives",
+		"nodeID": req.NodeId,
+	})
+
+	watcher, ok := svc.mgr.(DriveWatcher)
+	if !ok {
+		return status.Error(codes.Unimplemented, "drive manager doesn't support watching of drives")
+	}
+
+	prev, err := svc.mgr.GetDrivesList()
+	if err != nil {
+		ll.Errorf("DriveManager failed with error: %v", err)
+		return status.Error(codes.Internal, err.Error())
+	}
+	fillDefaults(prev, req.NodeId)
+
+	ctx, cancelFn := context.WithCancel(stream.Context())
+	defer cancelFn()
+
+	// notifications are coalesced, drives list is read once for a burst of changes
+	changes := make(chan struct{}, 1)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- watcher.WatchDrives(ctx, func() {
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		})
+	}()
+
+	if err = stream.SendHeader(metadata.Pairs(apiV1.DriveWatchHeaderKey, "true")); err != nil {
+		return err
+	}
+	ll.Info("Watching of drives is started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			ll.Info("Watching of drives is stopped")
+			return nil
+		case err = <-watchErr:
+			if err == nil {
+				return nil
+			}
+			ll.Errorf("Watching of drives failed: %v", err)
+			return status.Errorf(codes.Internal, "watching of drives failed: %v", err)
+		case <-changes:
+			cur, err := svc.mgr.GetDrivesList()
+			if err != nil {
+				ll.Errorf("DriveManager failed with error: %v", err)
+				continue
+			}
+			fillDefaults(cur, req.NodeId)
+			for _, event := range DiffDrives(prev, cur) {
+				ll.Infof("Drive %s is %s", event.Drive.SerialNumber, event.Type)
+				if err = stream.Send(event); err != nil {
+					return err
+				}
+			}
+			prev = cur
+		}
+	}
+}
+
+// fillDefaults sets node ID of the drives, all drives are ONLINE by default
+func fillDefaults(drives []*api.Drive, nodeID string) {
 	for _, drive := range drives {
-		drive.NodeId = req.NodeId
+		drive.NodeId = nodeID
 		if drive.Status == "" {
 			drive.Status = apiV1.DriveStatusOnline
 		}
 	}
-	return &api.DrivesResponse{
-		Disks: drives,
-	}, nil
 }
@@ -0,0 +1,161 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivemgr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+var testLogger = logrus.New()
+
+// watchingManager is a DriveManager which calls notify when drives are set
+type watchingManager struct {
+	drives chan []*api.Drive
+	notify chan func()
+	last   []*api.Drive
+}
+
+func (m *watchingManager) GetDrivesList() ([]*api.Drive, error) {
+	select {
+	case drives := <-m.drives:
+		m.last = drives
+	default:
+	}
+	return m.last, nil
+}
+
+func (m *watchingManager) SetDriveLED(serialNumber, state string) error {
+	return nil
+}
+
+func (m *watchingManager) WatchDrives(ctx context.Context, notify func()) error {
+	m.notify <- notify
+	<-ctx.Done()
+	return nil
+}
+
+// plainManager is a DriveManager which isn't able to watch drives
+type plainManager struct{}
+
+func (m *plainManager) GetDrivesList() ([]*api.Drive, error) {
+	return nil, nil
+}
+
+func (m *plainManager) SetDriveLED(serialNumber, state string) error {
+	return nil
+}
+
+// fakeWatchStream collects events sent by WatchDrives
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+	events chan *api.DriveEvent
+}
+
+func (s *fakeWatchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeWatchStream) SendHeader(md metadata.MD) error {
+	s.header = md
+	return nil
+}
+
+func (s *fakeWatchStream) Send(event *api.DriveEvent) error {
+	s.events <- event
+	return nil
+}
+
+func TestDiffDrives(t *testing.T) {
+	var (
+		d1        = &api.Drive{SerialNumber: "sn-1", Health: apiV1.HealthGood}
+		d2        = &api.Drive{SerialNumber: "sn-2", Health: apiV1.HealthGood}
+		d2Bad     = &api.Drive{SerialNumber: "sn-2", Health: apiV1.HealthBad}
+		d3        = &api.Drive{SerialNumber: "sn-3", Health: apiV1.HealthGood}
+		d1Same    = &api.Drive{SerialNumber: "sn-1", Health: apiV1.HealthGood}
+		prevDrive = []*api.Drive{d1, d2}
+	)
+
+	assert.Empty(t, DiffDrives(prevDrive, []*api.Drive{d1Same, d2}))
+
+	events := DiffDrives(prevDrive, []*api.Drive{d2Bad, d3})
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, apiV1.DriveEventModified, events[0].Type)
+	assert.Equal(t, d2Bad, events[0].Drive)
+	assert.Equal(t, apiV1.DriveEventAdded, events[1].Type)
+	assert.Equal(t, d3, events[1].Drive)
+	assert.Equal(t, apiV1.DriveEventRemoved, events[2].Type)
+	assert.Equal(t, d1, events[2].Drive)
+}
+
+func TestDriveServiceServerImpl_WatchDrives(t *testing.T) {
+	var (
+		mgr = &watchingManager{
+			drives: make(chan []*api.Drive, 1),
+			notify: make(chan func()),
+			last:   []*api.Drive{{SerialNumber: "sn-1"}},
+		}
+		svc         = NewDriveServer(testLogger, mgr)
+		ctx, cancel = context.WithCancel(context.Background())
+		stream      = &fakeWatchStream{ctx: ctx, events: make(chan *api.DriveEvent, 10)}
+		done        = make(chan error)
+	)
+	go func() {
+		done <- svc.WatchDrives(&api.DrivesRequest{NodeId: "node-1"}, stream)
+	}()
+	notify := <-mgr.notify
+
+	mgr.drives <- []*api.Drive{{SerialNumber: "sn-2"}}
+	notify()
+	select {
+	case event := <-stream.events:
+		assert.Equal(t, apiV1.DriveEventAdded, event.Type)
+		assert.Equal(t, "sn-2", event.Drive.SerialNumber)
+		assert.Equal(t, "node-1", event.Drive.NodeId)
+		assert.Equal(t, apiV1.DriveStatusOnline, event.Drive.Status)
+	case <-time.After(time.Second):
+		t.Fatal("event isn't sent")
+	}
+	event := <-stream.events
+	assert.Equal(t, apiV1.DriveEventRemoved, event.Type)
+	assert.Equal(t, "sn-1", event.Drive.SerialNumber)
+	assert.Equal(t, []string{"true"}, stream.header.Get(apiV1.DriveWatchHeaderKey))
+
+	cancel()
+	assert.Nil(t, <-done)
+}
+
+func TestDriveServiceServerImpl_WatchDrivesUnimplemented(t *testing.T) {
+	svc := NewDriveServer(testLogger, &plainManager{})
+	stream := &fakeWatchStream{ctx: context.Background()}
+
+	err := svc.WatchDrives(&api.DrivesRequest{}, stream)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
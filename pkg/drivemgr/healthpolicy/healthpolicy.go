@@ -0,0 +1,178 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package healthpolicy contains rules which derive health of the drive from its SMART attributes
+package healthpolicy
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// Names of SMART attributes which could be used in rules
+const (
+	ReallocatedSectors = "reallocatedSectors"
+	PendingSectors     = "pendingSectors"
+	MediaErrors        = "mediaErrors"
+	Temperature        = "temperature"
+	PowerOnHours       = "powerOnHours"
+	PercentageUsed     = "percentageUsed"
+)
+
+// Rule sets thresholds for one SMART attribute. Drive becomes SUSPECT or BAD when value of the attribute
+// is greater than or equal to the corresponding threshold. Zero threshold is disabled.
+type Rule struct {
+	Attribute string `yaml:"attribute"`
+	Suspect   int64  `yaml:"suspect"`
+	Bad       int64  `yaml:"bad"`
+	// DriveTypes limits rule to drives of provided types (HDD, SSD, NVME), rule is applied to all drives if empty
+	DriveTypes []string `yaml:"driveTypes"`
+}
+
+// Policy is a set of rules which is used by drive manager to derive drive health
+type Policy struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// DefaultPolicy returns policy which is used when drive manager isn't configured with policy file
+func DefaultPolicy() *Policy {
+	return &Policy{Rules: []*Rule{
+		{Attribute: ReallocatedSectors, Suspect: 10, Bad: 100},
+		{Attribute: PendingSectors, Suspect: 10, Bad: 100},
+		{Attribute: MediaErrors, Suspect: 10, Bad: 100},
+		{Attribute: PercentageUsed, Suspect: 90, Bad: 100},
+	}}
+}
+
+// Load reads policy from YAML file and validates it
+func Load(path string) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	policy := &Policy{}
+	if err = yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal health policy: %v", err)
+	}
+	if err = policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Validate checks that rules refer to known attributes and have consistent thresholds
+func (p *Policy) Validate() error {
+	for _, rule := range p.Rules {
+		if _, err := attributeValue(&api.SMARTInfo{}, rule.Attribute); err != nil {
+			return err
+		}
+		if rule.Suspect < 0 || rule.Bad < 0 {
+			return fmt.Errorf("thresholds of attribute %s must not be negative", rule.Attribute)
+		}
+		if rule.Suspect > 0 && rule.Bad > 0 && rule.Suspect > rule.Bad {
+			return fmt.Errorf("suspect threshold of attribute %s is greater than bad threshold", rule.Attribute)
+		}
+		for _, driveType := range rule.DriveTypes {
+			switch driveType {
+			case apiV1.DriveTypeHDD, apiV1.DriveTypeSSD, apiV1.DriveTypeNVMe:
+			default:
+				return fmt.Errorf("unknown drive type %s in rule for attribute %s", driveType, rule.Attribute)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate returns health of the drive derived from its SMART attributes. Health reported by the drive itself
+// is returned when it is worse than derived one or when drive doesn't have SMART attributes.
+// Drive with unknown health stays UNKNOWN only if none of the rules is broken.
+func (p *Policy) Evaluate(drive *api.Drive) string {
+	health := drive.Health
+	if drive.SMART == nil {
+		return health
+	}
+	for _, rule := range p.Rules {
+		if !rule.appliesTo(drive.Type) {
+			continue
+		}
+		// attributes are validated during loading
+		value, _ := attributeValue(drive.SMART, rule.Attribute)
+		switch {
+		case rule.Bad > 0 && value >= rule.Bad:
+			health = Worst(health, apiV1.HealthBad)
+		case rule.Suspect > 0 && value >= rule.Suspect:
+			health = Worst(health, apiV1.HealthSuspect)
+		}
+	}
+	return health
+}
+
+// Worst returns more severe of two health values
+func Worst(first, second string) string {
+	if severity(second) > severity(first) {
+		return second
+	}
+	return first
+}
+
+// severity returns severity of the health, the bigger value means the worse health
+func severity(health string) int {
+	switch health {
+	case apiV1.HealthGood:
+		return 0
+	case apiV1.HealthSuspect:
+		return 2
+	case apiV1.HealthBad:
+		return 3
+	default:
+		return 1
+	}
+}
+
+func (r *Rule) appliesTo(driveType string) bool {
+	if len(r.DriveTypes) == 0 {
+		return true
+	}
+	for _, t := range r.DriveTypes {
+		if t == driveType {
+			return true
+		}
+	}
+	return false
+}
+
+func attributeValue(info *api.SMARTInfo, attribute string) (int64, error) {
+	switch attribute {
+	case ReallocatedSectors:
+		return info.ReallocatedSectors, nil
+	case PendingSectors:
+		return info.PendingSectors, nil
+	case MediaErrors:
+		return info.MediaErrors, nil
+	case Temperature:
+		return info.Temperature, nil
+	case PowerOnHours:
+		return info.PowerOnHours, nil
+	case PercentageUsed:
+		return info.PercentageUsed, nil
+	}
+	return 0, fmt.Errorf("unknown SMART attribute %s", attribute)
+}
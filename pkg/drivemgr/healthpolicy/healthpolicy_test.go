@@ -0,0 +1,116 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthpolicy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+func writePolicy(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "healthpolicy")
+	assert.Nil(t, err)
+	path := filepath.Join(dir, "policy.yaml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writePolicy(t, `
+rules:
+  - attribute: pendingSectors
+    suspect: 1
+    bad: 5
+  - attribute: temperature
+    suspect: 60
+    driveTypes: [NVME]
+`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	policy, err := Load(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []*Rule{
+		{Attribute: PendingSectors, Suspect: 1, Bad: 5},
+		{Attribute: Temperature, Suspect: 60, DriveTypes: []string{apiV1.DriveTypeNVMe}},
+	}, policy.Rules)
+}
+
+func TestLoad_Fail(t *testing.T) {
+	_, err := Load("/not/existing/policy.yaml")
+	assert.NotNil(t, err)
+
+	for _, content := range []string{
+		"rules: [{attribute: unknown, suspect: 1}]",
+		"rules: [{attribute: mediaErrors, suspect: 10, bad: 1}]",
+		"rules: [{attribute: mediaErrors, suspect: -1}]",
+		"rules: [{attribute: mediaErrors, suspect: 1, driveTypes: [TAPE]}]",
+		"rules: [{attribute: mediaErrors, unknownField: 1}]",
+	} {
+		path := writePolicy(t, content)
+		_, err = Load(path)
+		assert.NotNil(t, err, content)
+		_ = os.RemoveAll(filepath.Dir(path))
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	policy := &Policy{Rules: []*Rule{
+		{Attribute: ReallocatedSectors, Suspect: 10, Bad: 100},
+		{Attribute: Temperature, Suspect: 60, DriveTypes: []string{apiV1.DriveTypeNVMe}},
+	}}
+
+	testCases := []struct {
+		drive    *api.Drive
+		expected string
+	}{
+		{&api.Drive{Health: apiV1.HealthGood}, apiV1.HealthGood},
+		{&api.Drive{Health: apiV1.HealthUnknown, SMART: &api.SMARTInfo{ReallocatedSectors: 200}},
+			apiV1.HealthBad},
+		{&api.Drive{Health: apiV1.HealthUnknown, SMART: &api.SMARTInfo{ReallocatedSectors: 10}},
+			apiV1.HealthSuspect},
+		{&api.Drive{Health: apiV1.HealthUnknown, SMART: &api.SMARTInfo{ReallocatedSectors: 9}},
+			apiV1.HealthUnknown},
+		{&api.Drive{Health: apiV1.HealthGood, SMART: &api.SMARTInfo{ReallocatedSectors: 9}}, apiV1.HealthGood},
+		{&api.Drive{Health: apiV1.HealthGood, SMART: &api.SMARTInfo{ReallocatedSectors: 10}}, apiV1.HealthSuspect},
+		{&api.Drive{Health: apiV1.HealthGood, SMART: &api.SMARTInfo{ReallocatedSectors: 100}}, apiV1.HealthBad},
+		{&api.Drive{Health: apiV1.HealthBad, SMART: &api.SMARTInfo{}}, apiV1.HealthBad},
+		{&api.Drive{Health: apiV1.HealthGood, Type: apiV1.DriveTypeHDD, SMART: &api.SMARTInfo{Temperature: 70}},
+			apiV1.HealthGood},
+		{&api.Drive{Health: apiV1.HealthGood, Type: apiV1.DriveTypeNVMe, SMART: &api.SMARTInfo{Temperature: 70}},
+			apiV1.HealthSuspect},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, policy.Evaluate(tc.drive), tc.drive.String())
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	assert.Nil(t, DefaultPolicy().Validate())
+}
+
+func TestWorst(t *testing.T) {
+	assert.Equal(t, apiV1.HealthBad, Worst(apiV1.HealthSuspect, apiV1.HealthBad))
+	assert.Equal(t, apiV1.HealthSuspect, Worst(apiV1.HealthSuspect, apiV1.HealthGood))
+	assert.Equal(t, apiV1.HealthUnknown, Worst(apiV1.HealthGood, apiV1.HealthUnknown))
+}
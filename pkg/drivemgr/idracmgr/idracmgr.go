@@ -19,10 +19,12 @@ package idracmgr
 
 import "C"
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -30,6 +32,7 @@ import (
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/drivemgr"
 )
 
 const (
@@ -122,6 +125,49 @@ func (mgr *IDRACManager) GetDrivesList() ([]*api.Drive, error) {
 	return drives, nil
 }
 
+// SetDriveLED sets IndicatorLED of iDRAC drive with provided serial number
+// Receives drive serial number and LED state (one of apiV1.LEDState*)
+// Returns drivemgr.ErrDriveNotFound if drive isn't found or error if something went wrong
+func (mgr *IDRACManager) SetDriveLED(serialNumber, state string) error {
+	var indicator string
+	switch state {
+	case apiV1.LEDStateOff:
+		indicator = "Off"
+	case apiV1.LEDStateLocate:
+		indicator = "Blinking"
+	case apiV1.LEDStateFailure:
+		indicator = "Lit"
+	default:
+		return fmt.Errorf("unsupported LED state %s", state)
+	}
+	for _, c := range mgr.getControllerURLs() {
+		for _, driveURL := range mgr.getDrivesURLs(c) {
+			drive := mgr.getDrive(driveURL)
+			if drive == nil || drive.SerialNumber != serialNumber {
+				continue
+			}
+			body, err := json.Marshal(map[string]string{"IndicatorLED": indicator})
+			if err != nil {
+				return err
+			}
+			response, err := mgr.doRequestWithBody(http.MethodPatch, driveURL, bytes.NewReader(body))
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if err := response.Body.Close(); err != nil {
+					mgr.log.Errorf("Fail to close connection, url: %s, err: %v", driveURL, err)
+				}
+			}()
+			if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNoContent {
+				return fmt.Errorf("unable to set LED of drive %s, response status %s", serialNumber, response.Status)
+			}
+			return nil
+		}
+	}
+	return drivemgr.ErrDriveNotFound
+}
+
 // getControllerURLs returns slice of all controllers url in Storage
 func (mgr *IDRACManager) getControllerURLs() []string {
 	endpoint := fmt.Sprintf("https://%s%s", mgr.ip, storageURL)
@@ -211,6 +257,7 @@ func (mgr *IDRACManager) getDrive(driveURL string) *api.Drive {
 		Type:         diskType,
 		Size:         drive.CapacityBytes,
 		Status:       apiV1.DriveStatusOnline,
+		Endurance:    apiV1.DriveEnduranceUnknown,
 	}
 	return apiDrive
 }
@@ -219,13 +266,23 @@ func (mgr *IDRACManager) getDrive(driveURL string) *api.Drive {
 // Receives url to request
 // Returns *http.Response or error if something went wrong
 func (mgr *IDRACManager) doRequest(url string) (*http.Response, error) {
-	mgr.log.Info("Connecting to IDRAC with url ", url)
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	return mgr.doRequestWithBody(http.MethodGet, url, nil)
+}
+
+// doRequestWithBody performs HTTP request with provided method and JSON body on provided url
+// Receives HTTP method, url to request and body (might be nil)
+// Returns *http.Response or error if something went wrong
+func (mgr *IDRACManager) doRequestWithBody(method, url string, body io.Reader) (*http.Response, error) {
+	mgr.log.Infof("Connecting to IDRAC with url %s, method %s", url, method)
+	request, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
 	request.SetBasicAuth(mgr.user, mgr.password)
 	request.Header.Add("Accept", "application/json")
+	if body != nil {
+		request.Header.Add("Content-Type", "application/json")
+	}
 	response, err := mgr.client.Do(request)
 	if err != nil {
 		return nil, err
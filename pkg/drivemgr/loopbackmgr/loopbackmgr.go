@@ -18,6 +18,7 @@ limitations under the License.
 package loopbackmgr
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -35,6 +36,7 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/drivemgr"
 )
 
 const (
@@ -75,6 +77,9 @@ type LoopBackManager struct {
 	nodeID   string
 	devices  []*LoopBackDevice
 	config   *Config
+	// notify functions of WatchDrives subscribers
+	watchers    map[int]func()
+	nextWatcher int
 	sync.Mutex
 }
 
@@ -91,6 +96,8 @@ type LoopBackDevice struct {
 	fileName string
 	// for example, /dev/loop0
 	devicePath string
+	// LED is simulated in memory
+	ledState string
 }
 
 // Node struct represents particular configuration of LoopBackManager for specified node
@@ -526,12 +533,28 @@ func (mgr *LoopBackManager) GetDrivesList() ([]*api.Drive, error) {
 			Size:         sizeBytes,
 			Status:       driveStatus,
 			Path:         mgr.devices[i].devicePath,
+			LEDState:     mgr.devices[i].ledState,
+			Endurance:    apiV1.DriveEnduranceUnknown,
 		}
 		drives = append(drives, drive)
 	}
 	return drives, nil
 }
 
+// SetDriveLED sets in-memory LED state of the loopback device with provided serial number
+// Returns error if device isn't found
+func (mgr *LoopBackManager) SetDriveLED(serialNumber, state string) error {
+	mgr.Lock()
+	defer mgr.Unlock()
+	for _, device := range mgr.devices {
+		if device.SerialNumber == serialNumber {
+			device.ledState = state
+			return nil
+		}
+	}
+	return drivemgr.ErrDriveNotFound
+}
+
 // GetBackFileToLoopMap return mapping between backing file and loopback devices
 // Multiple loopback devices can be created from on backing file.
 func (mgr *LoopBackManager) GetBackFileToLoopMap() (map[string][]string, error) {
@@ -605,5 +628,35 @@ func (mgr *LoopBackManager) UpdateOnConfigChange(watcher *fsnotify.Watcher) {
 		ll.Debugf("triggering devices update on %s event", event.Op)
 		mgr.updateDevicesFromConfig()
 		mgr.Init()
+		mgr.notifyWatchers()
+	}
+}
+
+// WatchDrives subscribes notify on changes of devices which are made by config watcher (see UpdateOnConfigChange)
+// Blocks until ctx is done
+func (mgr *LoopBackManager) WatchDrives(ctx context.Context, notify func()) error {
+	mgr.Lock()
+	if mgr.watchers == nil {
+		mgr.watchers = make(map[int]func())
+	}
+	id := mgr.nextWatcher
+	mgr.nextWatcher++
+	mgr.watchers[id] = notify
+	mgr.Unlock()
+
+	<-ctx.Done()
+
+	mgr.Lock()
+	delete(mgr.watchers, id)
+	mgr.Unlock()
+	return nil
+}
+
+// notifyWatchers calls notify functions of all WatchDrives subscribers
+func (mgr *LoopBackManager) notifyWatchers() {
+	mgr.Lock()
+	defer mgr.Unlock()
+	for _, notify := range mgr.watchers {
+		notify()
 	}
 }
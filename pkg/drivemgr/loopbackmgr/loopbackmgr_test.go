@@ -17,11 +17,13 @@ limitations under the License.
 package loopbackmgr
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -379,3 +381,47 @@ func TestLoopBackManager_attemptToRecoverDevicesFromDefaults(t *testing.T) {
 	manager.attemptToRecoverDevices(testImagesPath)
 	assert.Equal(t, len(manager.devices), 1)
 }
+
+func TestLoopBackManager_SetDriveLED(t *testing.T) {
+	var mockexec = &mocks.GoMockExecutor{}
+	var manager = NewLoopBackManager(mockexec, logger)
+
+	manager.updateDevicesFromConfig()
+	sn := manager.devices[0].SerialNumber
+	assert.Nil(t, manager.SetDriveLED(sn, apiV1.LEDStateFailure))
+	assert.NotNil(t, manager.SetDriveLED("unknown", apiV1.LEDStateFailure))
+
+	drives, err := manager.GetDrivesList()
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.LEDStateFailure, drives[0].LEDState)
+}
+
+func TestLoopBackManager_WatchDrives(t *testing.T) {
+	var (
+		mockexec      = &mocks.GoMockExecutor{}
+		manager       = NewLoopBackManager(mockexec, logger)
+		ctx, cancel   = context.WithCancel(context.Background())
+		notifications = make(chan struct{}, 1)
+		done          = make(chan error)
+	)
+	go func() {
+		done <- manager.WatchDrives(ctx, func() { notifications <- struct{}{} })
+	}()
+	// wait for subscription
+	for {
+		manager.Lock()
+		subscribed := len(manager.watchers) == 1
+		manager.Unlock()
+		if subscribed {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	manager.notifyWatchers()
+	<-notifications
+
+	cancel()
+	assert.Nil(t, <-done)
+	assert.Equal(t, 0, len(manager.watchers))
+}
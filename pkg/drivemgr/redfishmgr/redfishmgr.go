@@ -0,0 +1,525 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package redfishmgr contains vendor-neutral DriveManager which uses Redfish API of BMC to discover drives
+package redfishmgr
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/drivemgr"
+)
+
+const (
+	// SystemsURL is a path to the Redfish collection of computer systems
+	SystemsURL = "/redfish/v1/Systems"
+	// SessionsURL is a path to the Redfish collection of sessions
+	SessionsURL = "/redfish/v1/SessionService/Sessions"
+	// AuthTokenHeader is a header which holds Redfish session token
+	AuthTokenHeader = "X-Auth-Token"
+
+	// DefaultWorkers is a default amount of drives which are fetched concurrently
+	DefaultWorkers = 8
+	// DefaultRetries is a default amount of attempts for each request
+	DefaultRetries = 3
+	// DefaultRetryInterval is a default interval between attempts
+	DefaultRetryInterval = 500 * time.Millisecond
+
+	keyURL = "@odata.id"
+)
+
+// RedfishManager is a DriveManager which walks standard Redfish Systems/*/Storage/*/Drives resources
+type RedfishManager struct {
+	log      *logrus.Entry
+	client   *http.Client
+	endpoint string
+	user     string
+	password string
+
+	workers       int
+	retries       int
+	retryInterval time.Duration
+
+	sessionMu  sync.Mutex
+	token      string
+	sessionURL string
+}
+
+// collection is a Redfish resource collection
+type collection struct {
+	Members []map[string]string `json:"Members"`
+}
+
+// storage is a Redfish Storage resource
+type storage struct {
+	Drives []map[string]string `json:"Drives"`
+}
+
+// location is a deprecated Redfish Location resource which is still returned by most of BMCs
+type location struct {
+	Info       string `json:"Info"`
+	InfoFormat string `json:"InfoFormat"`
+}
+
+// physicalLocation is a Redfish Resource.Location resource
+type physicalLocation struct {
+	PartLocation struct {
+		LocationOrdinalValue *int   `json:"LocationOrdinalValue"`
+		LocationType         string `json:"LocationType"`
+		ServiceLabel         string `json:"ServiceLabel"`
+	} `json:"PartLocation"`
+}
+
+// Drive is a Redfish Drive resource
+type Drive struct {
+	ID                            string            `json:"Id"`
+	Status                        map[string]string `json:"Status"`
+	SerialNumber                  string            `json:"SerialNumber"`
+	CapacityBytes                 int64             `json:"CapacityBytes"`
+	MediaType                     string            `json:"MediaType"`
+	Manufacturer                  string            `json:"Manufacturer"`
+	Model                         string            `json:"Model"`
+	Protocol                      string            `json:"Protocol"`
+	Revision                      string            `json:"Revision"`
+	PredictedMediaLifeLeftPercent *float64          `json:"PredictedMediaLifeLeftPercent"`
+	Location                      []location        `json:"Location"`
+	PhysicalLocation              *physicalLocation `json:"PhysicalLocation"`
+	IndicatorLED                  string            `json:"IndicatorLED"`
+}
+
+// NewRedfishManager is the constructor for RedfishManager
+// Receives logrus logger, BMC endpoint (e.g. https://10.10.10.10), credentials, pool of CA certificates which
+// is used to verify BMC certificate (system pool is used if nil) and timeout for each request
+// Returns an instance of RedfishManager
+func NewRedfishManager(logger *logrus.Logger, endpoint, user, password string,
+	rootCAs *x509.CertPool, timeout time.Duration) *RedfishManager {
+	tr := &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12},
+	}
+	return &RedfishManager{
+		log:           logger.WithField("component", "RedfishManager"),
+		client:        &http.Client{Timeout: timeout, Transport: tr},
+		endpoint:      strings.TrimSuffix(endpoint, "/"),
+		user:          user,
+		password:      password,
+		workers:       DefaultWorkers,
+		retries:       DefaultRetries,
+		retryInterval: DefaultRetryInterval,
+	}
+}
+
+// LoadCertPool reads PEM encoded CA certificates from the file
+// Returns pool of certificates or error if something went wrong
+func LoadCertPool(caFile string) (*x509.CertPool, error) {
+	data, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("there are no PEM certificates in %s", caFile)
+	}
+	return pool, nil
+}
+
+// GetDrivesList walks Redfish Systems/*/Storage/*/Drives resources and converts each drive into api.Drive
+// Drives are fetched concurrently
+// Returns slice of api.Drive or error if something went wrong, partial list isn't returned if any drive wasn't fetched
+// because missing drive is considered as removed one
+func (mgr *RedfishManager) GetDrivesList() ([]*api.Drive, error) {
+	ll := mgr.log.WithField("method", "GetDrivesList")
+
+	drivesURLs, err := mgr.getDrivesURLs()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		urlsCh  = make(chan string)
+		results = make([]*api.Drive, len(drivesURLs))
+		index   = make(map[string]int, len(drivesURLs))
+		mu      sync.Mutex
+		errURL  string
+		lastErr error
+	)
+	for i, u := range drivesURLs {
+		index[u] = i
+	}
+
+	for i := 0; i < mgr.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range urlsCh {
+				drive, err := mgr.getDrive(u)
+				mu.Lock()
+				if err != nil {
+					ll.Errorf("Unable to get drive %s: %v", u, err)
+					errURL, lastErr = u, err
+				} else {
+					results[index[u]] = drive
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, u := range drivesURLs {
+		urlsCh <- u
+	}
+	close(urlsCh)
+	wg.Wait()
+	if lastErr != nil {
+		return nil, fmt.Errorf("unable to get drive %s: %v", errURL, lastErr)
+	}
+
+	drives := make([]*api.Drive, 0, len(results))
+	for _, d := range results {
+		if d != nil {
+			drives = append(drives, d)
+		}
+	}
+	return drives, nil
+}
+
+// getDrivesURLs returns paths to all Drive resources of all Storage resources of all Systems
+func (mgr *RedfishManager) getDrivesURLs() ([]string, error) {
+	systems := collection{}
+	if err := mgr.get(SystemsURL, &systems); err != nil {
+		return nil, fmt.Errorf("unable to get systems: %v", err)
+	}
+
+	drivesURLs := make([]string, 0)
+	for _, system := range systems.Members {
+		storages := collection{}
+		if err := mgr.get(system[keyURL]+"/Storage", &storages); err != nil {
+			return nil, fmt.Errorf("unable to get storage of system %s: %v", system[keyURL], err)
+		}
+		for _, member := range storages.Members {
+			s := storage{}
+			if err := mgr.get(member[keyURL], &s); err != nil {
+				return nil, fmt.Errorf("unable to get storage %s: %v", member[keyURL], err)
+			}
+			for _, d := range s.Drives {
+				drivesURLs = append(drivesURLs, d[keyURL])
+			}
+		}
+	}
+	return drivesURLs, nil
+}
+
+// getDrive reads Redfish Drive resource and converts it into api.Drive
+// Returns nil without error if drive is absent
+func (mgr *RedfishManager) getDrive(driveURL string) (*api.Drive, error) {
+	drive := Drive{}
+	if err := mgr.get(driveURL, &drive); err != nil {
+		return nil, err
+	}
+	if drive.Status["State"] == "Absent" {
+		return nil, nil
+	}
+	return convertDrive(&drive), nil
+}
+
+// SetDriveLED sets IndicatorLED property of the Redfish Drive resource with provided serial number
+// Returns drivemgr.ErrDriveNotFound if there is no such drive
+func (mgr *RedfishManager) SetDriveLED(serialNumber, state string) error {
+	indicator, err := convertLEDState(state)
+	if err != nil {
+		return err
+	}
+
+	drivesURLs, err := mgr.getDrivesURLs()
+	if err != nil {
+		return err
+	}
+	for _, u := range drivesURLs {
+		drive := Drive{}
+		if err := mgr.get(u, &drive); err != nil {
+			mgr.log.WithField("method", "SetDriveLED").Errorf("Unable to get drive %s: %v", u, err)
+			continue
+		}
+		if drive.SerialNumber == serialNumber {
+			return mgr.patch(u, map[string]string{"IndicatorLED": indicator})
+		}
+	}
+	return drivemgr.ErrDriveNotFound
+}
+
+// get performs GET request of the Redfish resource with retries and decodes response into v
+// Session is created (or recreated if it is expired) before request
+func (mgr *RedfishManager) get(path string, v interface{}) error {
+	return mgr.request(http.MethodGet, path, nil, v)
+}
+
+// patch performs PATCH request of the Redfish resource with retries, body is encoded as JSON
+func (mgr *RedfishManager) patch(path string, body interface{}) error {
+	return mgr.request(http.MethodPatch, path, body, nil)
+}
+
+// request performs request of the Redfish resource with retries
+func (mgr *RedfishManager) request(method, path string, body, v interface{}) error {
+	var err error
+	for attempt := 1; attempt <= mgr.retries; attempt++ {
+		if err = mgr.doRequest(method, path, body, v); err == nil {
+			return nil
+		}
+		mgr.log.WithField("method", "request").
+			Warnf("Attempt %d of %d to %s %s failed: %v", attempt, mgr.retries, method, path, err)
+		if attempt < mgr.retries {
+			time.Sleep(mgr.retryInterval)
+		}
+	}
+	return err
+}
+
+// doRequest performs single request with the token of the current session
+// Response is decoded into v if v isn't nil
+func (mgr *RedfishManager) doRequest(method, path string, body, v interface{}) error {
+	token, err := mgr.getToken()
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	request, err := http.NewRequest(method, mgr.endpoint+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set(AuthTokenHeader, token)
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := mgr.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	switch {
+	case response.StatusCode == http.StatusUnauthorized:
+		mgr.resetToken(token)
+		return errors.New("session is expired")
+	case response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNoContent:
+		return fmt.Errorf("unexpected response status %s", response.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(response.Body).Decode(v)
+}
+
+// getToken returns token of the current Redfish session, creates new session if there is no session
+func (mgr *RedfishManager) getToken() (string, error) {
+	mgr.sessionMu.Lock()
+	defer mgr.sessionMu.Unlock()
+
+	if mgr.token != "" {
+		return mgr.token, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"UserName": mgr.user, "Password": mgr.password})
+	if err != nil {
+		return "", err
+	}
+	response, err := mgr.client.Post(mgr.endpoint+SessionsURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("unable to create session: %v", err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusCreated && response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to create session, response status %s", response.Status)
+	}
+	token := response.Header.Get(AuthTokenHeader)
+	if token == "" {
+		return "", errors.New("unable to create session, token is missing")
+	}
+	mgr.token = token
+	mgr.sessionURL = response.Header.Get("Location")
+	return mgr.token, nil
+}
+
+// resetToken drops token of the expired session, new session will be created on the next request
+func (mgr *RedfishManager) resetToken(token string) {
+	mgr.sessionMu.Lock()
+	defer mgr.sessionMu.Unlock()
+
+	if mgr.token == token {
+		mgr.token = ""
+		mgr.sessionURL = ""
+	}
+}
+
+// Close removes current Redfish session
+func (mgr *RedfishManager) Close() {
+	mgr.sessionMu.Lock()
+	defer mgr.sessionMu.Unlock()
+
+	if mgr.token == "" || mgr.sessionURL == "" {
+		return
+	}
+	sessionURL := mgr.sessionURL
+	if strings.HasPrefix(sessionURL, "/") {
+		sessionURL = mgr.endpoint + sessionURL
+	}
+	request, err := http.NewRequest(http.MethodDelete, sessionURL, nil)
+	if err == nil {
+		request.Header.Set(AuthTokenHeader, mgr.token)
+		var response *http.Response
+		if response, err = mgr.client.Do(request); err == nil {
+			_ = response.Body.Close()
+		}
+	}
+	if err != nil {
+		mgr.log.WithField("method", "Close").Errorf("Unable to remove session: %v", err)
+	}
+	mgr.token = ""
+	mgr.sessionURL = ""
+}
+
+// convertDrive converts Redfish Drive resource into api.Drive
+func convertDrive(drive *Drive) *api.Drive {
+	apiDrive := &api.Drive{
+		VID:          drive.Manufacturer,
+		PID:          drive.Model,
+		SerialNumber: drive.SerialNumber,
+		Health:       convertDriveHealth(drive.Status["Health"]),
+		Type:         convertDriveType(drive.Protocol, drive.MediaType),
+		Size:         drive.CapacityBytes,
+		Status:       apiV1.DriveStatusOnline,
+		Firmware:     drive.Revision,
+		LEDState:     convertIndicatorLED(drive.IndicatorLED),
+		Endurance:    apiV1.DriveEnduranceUnknown,
+	}
+	if drive.Status["State"] != "" && drive.Status["State"] != "Enabled" {
+		apiDrive.Status = apiV1.DriveStatusOffline
+	}
+	if drive.PredictedMediaLifeLeftPercent != nil {
+		apiDrive.Endurance = int64(math.Round(*drive.PredictedMediaLifeLeftPercent))
+	}
+	fillLocation(drive, apiDrive)
+	return apiDrive
+}
+
+// fillLocation fills enclosure, slot and bay of api.Drive based on Location and PhysicalLocation of Redfish Drive
+func fillLocation(drive *Drive, apiDrive *api.Drive) {
+	for _, l := range drive.Location {
+		format := strings.ToLower(l.InfoFormat)
+		switch {
+		case strings.Contains(format, "enclosure"):
+			apiDrive.Enclosure = l.Info
+		case strings.Contains(format, "slot"):
+			apiDrive.Slot = l.Info
+		case strings.Contains(format, "bay"):
+			apiDrive.Bay = l.Info
+		}
+	}
+
+	if drive.PhysicalLocation == nil || drive.PhysicalLocation.PartLocation.LocationOrdinalValue == nil {
+		return
+	}
+	value := strconv.Itoa(*drive.PhysicalLocation.PartLocation.LocationOrdinalValue)
+	switch drive.PhysicalLocation.PartLocation.LocationType {
+	case "Slot":
+		apiDrive.Slot = value
+	case "Bay":
+		apiDrive.Bay = value
+	}
+}
+
+func convertDriveHealth(health string) string {
+	switch health {
+	case "OK":
+		return apiV1.HealthGood
+	case "Warning":
+		return apiV1.HealthSuspect
+	case "Critical":
+		return apiV1.HealthBad
+	default:
+		return apiV1.HealthUnknown
+	}
+}
+
+func convertDriveType(protocol, mediaType string) string {
+	if protocol == "NVMe" {
+		return apiV1.DriveTypeNVMe
+	}
+	switch mediaType {
+	case "SSD":
+		return apiV1.DriveTypeSSD
+	default:
+		return apiV1.DriveTypeHDD
+	}
+}
+
+// convertLEDState converts LED state into value of Redfish IndicatorLED property
+func convertLEDState(state string) (string, error) {
+	switch state {
+	case apiV1.LEDStateOff:
+		return "Off", nil
+	case apiV1.LEDStateLocate:
+		return "Blinking", nil
+	case apiV1.LEDStateFailure:
+		return "Lit", nil
+	default:
+		return "", fmt.Errorf("unsupported LED state %s", state)
+	}
+}
+
+// convertIndicatorLED converts value of Redfish IndicatorLED property into LED state
+func convertIndicatorLED(indicator string) string {
+	switch indicator {
+	case "Off":
+		return apiV1.LEDStateOff
+	case "Blinking":
+		return apiV1.LEDStateLocate
+	case "Lit":
+		return apiV1.LEDStateFailure
+	default:
+		return ""
+	}
+}
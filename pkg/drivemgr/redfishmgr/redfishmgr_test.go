@@ -0,0 +1,225 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfishmgr
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/drivemgr"
+	"github.com/dell/csi-baremetal/pkg/mocks/redfish"
+)
+
+var (
+	logger   = logrus.New()
+	user     = "user"
+	password = "password"
+
+	testDrive1 = map[string]interface{}{
+		"Id":                            "Disk.Bay.1",
+		"Status":                        map[string]string{"Health": "OK", "State": "Enabled"},
+		"SerialNumber":                  "sn-1",
+		"CapacityBytes":                 int64(480103981056),
+		"MediaType":                     "SSD",
+		"Manufacturer":                  "INTEL",
+		"Model":                         "SSDSC2KB480G8R",
+		"Protocol":                      "SATA",
+		"Revision":                      "XCV1DL67",
+		"PredictedMediaLifeLeftPercent": 97.6,
+		"Location": []map[string]string{
+			{"Info": "0", "InfoFormat": "Enclosure Number"},
+			{"Info": "1", "InfoFormat": "Bay Number"},
+		},
+	}
+	testDrive2 = map[string]interface{}{
+		"Id":            "Disk.Bay.2",
+		"Status":        map[string]string{"Health": "Warning", "State": "Enabled"},
+		"SerialNumber":  "sn-2",
+		"CapacityBytes": int64(1600321314816),
+		"MediaType":     "SSD",
+		"Protocol":      "NVMe",
+		"PhysicalLocation": map[string]interface{}{
+			"PartLocation": map[string]interface{}{"LocationOrdinalValue": 2, "LocationType": "Slot"},
+		},
+	}
+	testDrive3 = map[string]interface{}{
+		"Id":           "Disk.Bay.3",
+		"Status":       map[string]string{"Health": "Critical", "State": "Enabled"},
+		"SerialNumber": "sn-3",
+		"MediaType":    "HDD",
+		"Protocol":     "SAS",
+	}
+	absentDrive = map[string]interface{}{
+		"Id":     "Disk.Bay.4",
+		"Status": map[string]string{"State": "Absent"},
+	}
+)
+
+func prepareManager(s *redfish.Simulator) *RedfishManager {
+	mgr := NewRedfishManager(logger, s.URL, user, password, s.CertPool(), time.Second)
+	mgr.retryInterval = time.Millisecond
+	return mgr
+}
+
+func TestRedfishManager_GetDrivesList(t *testing.T) {
+	s := redfish.NewSimulator(user, password)
+	defer s.Close()
+	s.AddDrive("System.1", "RAID.1", testDrive1)
+	s.AddDrive("System.1", "RAID.1", absentDrive)
+	s.AddDrive("System.1", "NVMe.1", testDrive2)
+	s.AddDrive("System.2", "HBA.1", testDrive3)
+
+	mgr := prepareManager(s)
+	drives, err := mgr.GetDrivesList()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(drives))
+
+	assert.Equal(t, "sn-1", drives[0].SerialNumber)
+	assert.Equal(t, "INTEL", drives[0].VID)
+	assert.Equal(t, "SSDSC2KB480G8R", drives[0].PID)
+	assert.Equal(t, "XCV1DL67", drives[0].Firmware)
+	assert.Equal(t, apiV1.HealthGood, drives[0].Health)
+	assert.Equal(t, apiV1.DriveTypeSSD, drives[0].Type)
+	assert.Equal(t, apiV1.DriveStatusOnline, drives[0].Status)
+	assert.Equal(t, int64(480103981056), drives[0].Size)
+	assert.Equal(t, int64(98), drives[0].Endurance)
+	assert.Equal(t, "0", drives[0].Enclosure)
+	assert.Equal(t, "1", drives[0].Bay)
+
+	assert.Equal(t, apiV1.HealthSuspect, drives[1].Health)
+	assert.Equal(t, apiV1.DriveTypeNVMe, drives[1].Type)
+	assert.Equal(t, "2", drives[1].Slot)
+
+	assert.Equal(t, apiV1.HealthBad, drives[2].Health)
+	assert.Equal(t, apiV1.DriveTypeHDD, drives[2].Type)
+
+	// single session is used for all requests
+	assert.Equal(t, 1, s.SessionsCount())
+	mgr.Close()
+	assert.Equal(t, 0, s.SessionsCount())
+}
+
+func TestRedfishManager_GetDrivesListRetries(t *testing.T) {
+	s := redfish.NewSimulator(user, password)
+	defer s.Close()
+	driveURL := s.AddDrive("System.1", "RAID.1", testDrive1)
+
+	mgr := prepareManager(s)
+
+	// request is retried
+	s.FailRequests(driveURL, DefaultRetries-1)
+	drives, err := mgr.GetDrivesList()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(drives))
+	assert.Equal(t, DefaultRetries, s.RequestsCount(driveURL))
+
+	// all attempts failed, drive isn't reported as removed
+	s.FailRequests(driveURL, DefaultRetries)
+	drives, err = mgr.GetDrivesList()
+	assert.NotNil(t, err)
+	assert.Nil(t, drives)
+
+	// unable to get systems
+	s.FailRequests(SystemsURL, DefaultRetries)
+	_, err = mgr.GetDrivesList()
+	assert.NotNil(t, err)
+
+	// session is expired, new session is created
+	s.ExpireSessions()
+	drives, err = mgr.GetDrivesList()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(drives))
+	assert.Equal(t, 1, s.SessionsCount())
+}
+
+func TestRedfishManager_Auth(t *testing.T) {
+	s := redfish.NewSimulator(user, password)
+	defer s.Close()
+
+	// wrong credentials
+	mgr := NewRedfishManager(logger, s.URL, user, "wrong", s.CertPool(), time.Second)
+	mgr.retryInterval = time.Millisecond
+	_, err := mgr.GetDrivesList()
+	assert.NotNil(t, err)
+
+	// certificate of the simulator isn't trusted
+	mgr = NewRedfishManager(logger, s.URL, user, password, nil, time.Second)
+	mgr.retryInterval = time.Millisecond
+	_, err = mgr.GetDrivesList()
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, s.SessionsCount())
+}
+
+func TestRedfishManager_SetDriveLED(t *testing.T) {
+	s := redfish.NewSimulator(user, password)
+	defer s.Close()
+	// simulator modifies drive on PATCH request, so copy is used
+	drive := map[string]interface{}{"IndicatorLED": "Off"}
+	for k, v := range testDrive1 {
+		drive[k] = v
+	}
+	s.AddDrive("System.1", "RAID.1", drive)
+
+	mgr := prepareManager(s)
+	drives, err := mgr.GetDrivesList()
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.LEDStateOff, drives[0].LEDState)
+
+	assert.Nil(t, mgr.SetDriveLED("sn-1", apiV1.LEDStateLocate))
+	assert.Equal(t, "Blinking", drive["IndicatorLED"])
+	drives, err = mgr.GetDrivesList()
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.LEDStateLocate, drives[0].LEDState)
+
+	assert.Equal(t, drivemgr.ErrDriveNotFound, mgr.SetDriveLED("sn-2", apiV1.LEDStateLocate))
+	assert.NotNil(t, mgr.SetDriveLED("sn-1", "BLINK"))
+}
+
+func TestLoadCertPool(t *testing.T) {
+	_, err := LoadCertPool("/not/existing/file")
+	assert.NotNil(t, err)
+
+	f, err := ioutil.TempFile("", "ca")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("not a certificate")
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	_, err = LoadCertPool(f.Name())
+	assert.NotNil(t, err)
+}
+
+func Test_convertDriveHealth(t *testing.T) {
+	assert.Equal(t, apiV1.HealthGood, convertDriveHealth("OK"))
+	assert.Equal(t, apiV1.HealthSuspect, convertDriveHealth("Warning"))
+	assert.Equal(t, apiV1.HealthBad, convertDriveHealth("Critical"))
+	assert.Equal(t, apiV1.HealthUnknown, convertDriveHealth(""))
+}
+
+func Test_convertDriveType(t *testing.T) {
+	assert.Equal(t, apiV1.DriveTypeNVMe, convertDriveType("NVMe", "SSD"))
+	assert.Equal(t, apiV1.DriveTypeSSD, convertDriveType("SATA", "SSD"))
+	assert.Equal(t, apiV1.DriveTypeHDD, convertDriveType("SAS", "HDD"))
+	assert.Equal(t, apiV1.DriveTypeHDD, convertDriveType("", ""))
+}
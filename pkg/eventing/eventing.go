@@ -39,4 +39,20 @@ const (
 	DriveHealthUnknown = "DriveHealthUnknown"
 	DriveStatusOnline  = "DriveStatusOnline"
 	DriveStatusOffline = "DriveStatusOffline"
+
+	DriveReleasing           = "DriveReleasing"
+	DriveReadyForRemoval     = "DriveReadyForRemoval"
+	DriveReleaseFailed       = "DriveReleaseFailed"
+	DriveRemoving            = "DriveRemoving"
+	DriveReadyForReplacement = "DriveReadyForReplacement"
+	DriveRemovalFailed       = "DriveRemovalFailed"
+
+	DriveLEDStateChanged = "DriveLEDStateChanged"
+	DriveLEDStateFailed  = "DriveLEDStateFailed"
+
+	DriveEnduranceLow = "DriveEnduranceLow"
+
+	ThinPoolUsageNormal = "ThinPoolUsageNormal"
+	ThinPoolUsageHigh   = "ThinPoolUsageHigh"
+	ThinPoolFull        = "ThinPoolFull"
 )
@@ -53,8 +53,9 @@ func (a *ACOperationsMock) DeleteIfEmpty(ctx context.Context, acLocation string)
 // RecreateACToLVGSC is the mock implementation of RecreateACToLVGSC method from AvailableCapacityOperations made for simulating
 // recreation of list of ACs to LVG AC
 // Returns error if user simulates error in tests or nil
-func (a *ACOperationsMock) RecreateACToLVGSC(ctx context.Context, sc string, acs ...accrd.AvailableCapacity) *accrd.AvailableCapacity {
-	args := a.Mock.Called(ctx, sc, acs)
+func (a *ACOperationsMock) RecreateACToLVGSC(ctx context.Context, sc string, overcommitRatio float64,
+	acs ...accrd.AvailableCapacity) *accrd.AvailableCapacity {
+	args := a.Mock.Called(ctx, sc, overcommitRatio, acs)
 	if args.Get(0) == nil {
 		return nil
 	}
@@ -22,6 +22,8 @@ import (
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // MockDriveMgrClient is the implementation of DriveManager interface to imitate success state
@@ -63,3 +65,31 @@ func (m MockDriveMgrClient) GetDrivesList(ctx context.Context, in *api.DrivesReq
 		Disks: m.drives,
 	}, nil
 }
+
+// SetDriveLED sets LEDState of the drive with provided serial number to imitate working of DriveManager
+func (m MockDriveMgrClient) SetDriveLED(ctx context.Context, in *api.DriveLEDRequest, opts ...grpc.CallOption) (*api.DriveLEDResponse, error) {
+	for _, d := range m.drives {
+		if d.SerialNumber == in.SerialNumber {
+			d.LEDState = in.State
+		}
+	}
+	return &api.DriveLEDResponse{State: in.State}, nil
+}
+
+// SetDriveLED is the simulation of failure during DriveManager's SetDriveLED
+// Returns nil DriveLEDResponse and non nil error
+func (m MockDriveMgrClientFail) SetDriveLED(ctx context.Context, in *api.DriveLEDRequest, opts ...grpc.CallOption) (*api.DriveLEDResponse, error) {
+	return nil, errors.New("drivemgr error")
+}
+
+// WatchDrives imitates DriveManager which isn't able to watch drives
+// Returns nil stream and Unimplemented status error
+func (m MockDriveMgrClient) WatchDrives(ctx context.Context, in *api.DrivesRequest, opts ...grpc.CallOption) (api.DriveService_WatchDrivesClient, error) {
+	return nil, status.Error(codes.Unimplemented, "drive watching isn't supported")
+}
+
+// WatchDrives is the simulation of failure during DriveManager's WatchDrives
+// Returns nil stream and non nil error
+func (m MockDriveMgrClientFail) WatchDrives(ctx context.Context, in *api.DrivesRequest, opts ...grpc.CallOption) (api.DriveService_WatchDrivesClient, error) {
+	return nil, errors.New("drivemgr error")
+}
@@ -19,6 +19,8 @@ package mocks
 import (
 	"errors"
 	"fmt"
+	"os/exec"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/mock"
@@ -157,7 +159,11 @@ type GoMockExecutor struct {
 }
 
 // RunCmd simulates execution of a command with OnCommand where user can set what the method should return
+// command which is passed as exec.Cmd is matched by its arguments
 func (g *GoMockExecutor) RunCmd(cmd interface{}) (string, string, error) {
+	if cmdObj, ok := cmd.(*exec.Cmd); ok {
+		cmd = strings.Join(cmdObj.Args, " ")
+	}
 	args := g.Mock.Called(cmd.(string))
 	return args.String(0), args.String(1), args.Error(2)
 }
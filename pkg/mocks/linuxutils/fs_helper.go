@@ -34,6 +34,23 @@ func (m *MockWrapFS) GetFSSpace(src string) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+// GetFSStats is a mock implementations
+func (m *MockWrapFS) GetFSStats(path string) (*fs.FSStats, error) {
+	args := m.Mock.Called(path)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*fs.FSStats), args.Error(1)
+}
+
+// GetDeviceSize is a mock implementations
+func (m *MockWrapFS) GetDeviceSize(device string) (int64, error) {
+	args := m.Mock.Called(device)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MkDir is a mock implementations
 func (m *MockWrapFS) MkDir(src string) error {
 	args := m.Mock.Called(src)
@@ -41,6 +58,13 @@ func (m *MockWrapFS) MkDir(src string) error {
 	return args.Error(0)
 }
 
+// MkFile is a mock implementations
+func (m *MockWrapFS) MkFile(src string) error {
+	args := m.Mock.Called(src)
+
+	return args.Error(0)
+}
+
 // RmDir is a mock implementations
 func (m *MockWrapFS) RmDir(src string) error {
 	args := m.Mock.Called(src)
@@ -49,7 +73,28 @@ func (m *MockWrapFS) RmDir(src string) error {
 }
 
 // CreateFS is a mock implementations
-func (m *MockWrapFS) CreateFS(fsType fs.FileSystem, device string) error {
+func (m *MockWrapFS) CreateFS(fsType fs.FileSystem, device string, opts ...string) error {
+	args := m.Mock.Called(fsType, device, opts)
+
+	return args.Error(0)
+}
+
+// GrowFS is a mock implementations
+func (m *MockWrapFS) GrowFS(fsType fs.FileSystem, device, mountPoint string) error {
+	args := m.Mock.Called(fsType, device, mountPoint)
+
+	return args.Error(0)
+}
+
+// CopyDevice is a mock implementations
+func (m *MockWrapFS) CopyDevice(src, dst string) error {
+	args := m.Mock.Called(src, dst)
+
+	return args.Error(0)
+}
+
+// RegenerateFSUUID is a mock implementations
+func (m *MockWrapFS) RegenerateFSUUID(fsType fs.FileSystem, device string) error {
 	args := m.Mock.Called(fsType, device)
 
 	return args.Error(0)
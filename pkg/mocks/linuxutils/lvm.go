@@ -46,6 +46,20 @@ func (m *MockWrapLVM) VGCreate(name string, pvs ...string) error {
 	return args.Error(0)
 }
 
+// VGExtend is a mock implementations
+func (m *MockWrapLVM) VGExtend(name, pv string) error {
+	args := m.Mock.Called(name, pv)
+
+	return args.Error(0)
+}
+
+// VGReduce is a mock implementations
+func (m *MockWrapLVM) VGReduce(name, pv string) error {
+	args := m.Mock.Called(name, pv)
+
+	return args.Error(0)
+}
+
 // VGRemove is a mock implementations
 func (m *MockWrapLVM) VGRemove(name string) error {
 	args := m.Mock.Called(name)
@@ -60,6 +74,69 @@ func (m *MockWrapLVM) LVCreate(name, size, vgName string) error {
 	return args.Error(0)
 }
 
+// LVCreateStriped is a mock implementations
+func (m *MockWrapLVM) LVCreateStriped(name, size, vgName string, stripes int32) error {
+	args := m.Mock.Called(name, size, vgName, stripes)
+
+	return args.Error(0)
+}
+
+// LVCreateRAID1 is a mock implementations
+func (m *MockWrapLVM) LVCreateRAID1(name, size, vgName string, mirrors int32) error {
+	args := m.Mock.Called(name, size, vgName, mirrors)
+
+	return args.Error(0)
+}
+
+// ThinPoolCreate is a mock implementations
+func (m *MockWrapLVM) ThinPoolCreate(vgName, poolName string) error {
+	args := m.Mock.Called(vgName, poolName)
+
+	return args.Error(0)
+}
+
+// ThinLVCreate is a mock implementations
+func (m *MockWrapLVM) ThinLVCreate(name, size, vgName, poolName string) error {
+	args := m.Mock.Called(name, size, vgName, poolName)
+
+	return args.Error(0)
+}
+
+// GetThinPoolUsage is a mock implementations
+func (m *MockWrapLVM) GetThinPoolUsage(vgName, poolName string) (float64, float64, error) {
+	args := m.Mock.Called(vgName, poolName)
+
+	return args.Get(0).(float64), args.Get(1).(float64), args.Error(2)
+}
+
+// PVDisableAllocation is a mock implementations
+func (m *MockWrapLVM) PVDisableAllocation(name string) error {
+	args := m.Mock.Called(name)
+
+	return args.Error(0)
+}
+
+// VGActivate is a mock implementations
+func (m *MockWrapLVM) VGActivate(name string) error {
+	args := m.Mock.Called(name)
+
+	return args.Error(0)
+}
+
+// CachePoolCreate is a mock implementations
+func (m *MockWrapLVM) CachePoolCreate(name, vgName, pv string) error {
+	args := m.Mock.Called(name, vgName, pv)
+
+	return args.Error(0)
+}
+
+// LVCacheAttach is a mock implementations
+func (m *MockWrapLVM) LVCacheAttach(fullLVName, fullPoolName, mode string) error {
+	args := m.Mock.Called(fullLVName, fullPoolName, mode)
+
+	return args.Error(0)
+}
+
 // LVRemove is a mock implementations
 func (m *MockWrapLVM) LVRemove(fullLVName string) error {
 	args := m.Mock.Called(fullLVName)
@@ -67,6 +144,34 @@ func (m *MockWrapLVM) LVRemove(fullLVName string) error {
 	return args.Error(0)
 }
 
+// LVResize is a mock implementations
+func (m *MockWrapLVM) LVResize(fullLVName, size string) error {
+	args := m.Mock.Called(fullLVName, size)
+
+	return args.Error(0)
+}
+
+// LVSnapshotCreate is a mock implementations
+func (m *MockWrapLVM) LVSnapshotCreate(name, size, fullLVName string) error {
+	args := m.Mock.Called(name, size, fullLVName)
+
+	return args.Error(0)
+}
+
+// LVThinSnapshotCreate is a mock implementations
+func (m *MockWrapLVM) LVThinSnapshotCreate(name, fullLVName string) error {
+	args := m.Mock.Called(name, fullLVName)
+
+	return args.Error(0)
+}
+
+// LVSnapshotRemove is a mock implementations
+func (m *MockWrapLVM) LVSnapshotRemove(fullSnapshotName string) error {
+	args := m.Mock.Called(fullSnapshotName)
+
+	return args.Error(0)
+}
+
 // IsVGContainsLVs is a mock implementations
 func (m *MockWrapLVM) IsVGContainsLVs(vgName string) bool {
 	args := m.Mock.Called(vgName)
@@ -95,6 +200,17 @@ func (m *MockWrapLVM) GetVgFreeSpace(vgName string) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+// GetPVsFreeSpace is a mock implementations
+func (m *MockWrapLVM) GetPVsFreeSpace(vgName string) ([]int64, error) {
+	args := m.Mock.Called(vgName)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]int64), args.Error(1)
+}
+
 // IsLVGExists is a mock implementations
 func (m *MockWrapLVM) IsLVGExists(lvName string) (bool, error) {
 	args := m.Mock.Called(lvName)
@@ -0,0 +1,66 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linuxutils
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/mdadm"
+)
+
+// MockWrapMDADM is a mock implementation of WrapMDADM interface from mdadm package
+type MockWrapMDADM struct {
+	mock.Mock
+}
+
+// CreateArray is a mock implementations
+func (m *MockWrapMDADM) CreateArray(name, level string, devices []string) error {
+	args := m.Mock.Called(name, level, devices)
+
+	return args.Error(0)
+}
+
+// AssembleArray is a mock implementations
+func (m *MockWrapMDADM) AssembleArray(name string, devices []string) error {
+	args := m.Mock.Called(name, devices)
+
+	return args.Error(0)
+}
+
+// StopArray is a mock implementations
+func (m *MockWrapMDADM) StopArray(name string) error {
+	args := m.Mock.Called(name)
+
+	return args.Error(0)
+}
+
+// ZeroSuperblock is a mock implementations
+func (m *MockWrapMDADM) ZeroSuperblock(device string) error {
+	args := m.Mock.Called(device)
+
+	return args.Error(0)
+}
+
+// GetArray is a mock implementations
+func (m *MockWrapMDADM) GetArray(name string) (*mdadm.Array, error) {
+	args := m.Mock.Called(name)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mdadm.Array), args.Error(1)
+}
@@ -33,3 +33,11 @@ func (m *MockWrapSmartctl) GetDriveInfoByPath(path string) (*smartctl.DeviceSMAR
 
 	return args.Get(0).(*smartctl.DeviceSMARTInfo), args.Error(1)
 }
+
+// GetRAIDDriveInfo is a mock implementations
+func (m *MockWrapSmartctl) GetRAIDDriveInfo(path, controllerType string,
+	deviceID int) (*smartctl.DeviceSMARTInfo, error) {
+	args := m.Mock.Called(path, controllerType, deviceID)
+
+	return args.Get(0).(*smartctl.DeviceSMARTInfo), args.Error(1)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package redfish contains in-process Redfish simulator for tests of Redfish based drive managers
+package redfish
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const (
+	systemsURL  = "/redfish/v1/Systems"
+	sessionsURL = "/redfish/v1/SessionService/Sessions"
+	tokenHeader = "X-Auth-Token"
+)
+
+// Simulator is an in-process Redfish service which serves Systems/*/Storage/*/Drives resources over TLS
+// and supports session-token authentication, PATCH requests update properties of the resources
+type Simulator struct {
+	*httptest.Server
+
+	user     string
+	password string
+
+	mu        sync.Mutex
+	resources map[string]interface{} // path to Redfish resource
+	sessions  map[string]string      // token to session path
+	failures  map[string]int         // path to amount of requests which should fail
+	requests  map[string]int         // path to amount of served GET requests
+}
+
+// NewSimulator creates and starts Redfish simulator which accepts provided credentials
+// Simulator must be closed by caller
+func NewSimulator(user, password string) *Simulator {
+	s := &Simulator{
+		user:      user,
+		password:  password,
+		resources: make(map[string]interface{}),
+		sessions:  make(map[string]string),
+		failures:  make(map[string]int),
+		requests:  make(map[string]int),
+	}
+	s.resources[systemsURL] = members()
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// CertPool returns pool with certificate of the simulator
+func (s *Simulator) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+	return pool
+}
+
+// AddDrive adds Redfish Drive resource to the storage of the system, system and storage are created if needed
+// Returns path to the drive resource
+func (s *Simulator) AddDrive(system, storage string, drive map[string]interface{}) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	systemURL := fmt.Sprintf("%s/%s", systemsURL, system)
+	storagesURL := systemURL + "/Storage"
+	storageURL := fmt.Sprintf("%s/%s", storagesURL, storage)
+	driveURL := fmt.Sprintf("%s/Drives/%s", storageURL, drive["Id"])
+
+	if _, ok := s.resources[systemURL]; !ok {
+		s.resources[systemURL] = map[string]interface{}{"Id": system}
+		s.resources[storagesURL] = members()
+		s.appendLink(systemsURL, "Members", systemURL)
+	}
+	if _, ok := s.resources[storageURL]; !ok {
+		s.resources[storageURL] = map[string]interface{}{"Id": storage, "Drives": []map[string]string{}}
+		s.appendLink(storagesURL, "Members", storageURL)
+	}
+	s.resources[driveURL] = drive
+	s.appendLink(storageURL, "Drives", driveURL)
+	return driveURL
+}
+
+// FailRequests makes next count GET requests of the path fail with internal server error
+func (s *Simulator) FailRequests(path string, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[path] = count
+}
+
+// ExpireSessions removes all sessions, so clients receive unauthorized error on the next request
+func (s *Simulator) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]string)
+}
+
+// SessionsCount returns amount of active sessions
+func (s *Simulator) SessionsCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// RequestsCount returns amount of GET requests of the path
+func (s *Simulator) RequestsCount(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+func (s *Simulator) handle(rw http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimSuffix(req.URL.Path, "/")
+	switch {
+	case path == sessionsURL && req.Method == http.MethodPost:
+		s.createSession(rw, req)
+		return
+	case s.sessions[req.Header.Get(tokenHeader)] == "":
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	case strings.HasPrefix(path, sessionsURL+"/") && req.Method == http.MethodDelete:
+		delete(s.sessions, req.Header.Get(tokenHeader))
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	case req.Method == http.MethodPatch:
+		s.patchResource(rw, req, path)
+		return
+	case req.Method != http.MethodGet:
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.requests[path]++
+	if s.failures[path] > 0 {
+		s.failures[path]--
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	resource, ok := s.resources[path]
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(resource)
+}
+
+func (s *Simulator) createSession(rw http.ResponseWriter, req *http.Request) {
+	credentials := map[string]string{}
+	if err := json.NewDecoder(req.Body).Decode(&credentials); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if credentials["UserName"] != s.user || credentials["Password"] != s.password {
+		rw.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	token := uuid.New().String()
+	sessionURL := fmt.Sprintf("%s/%s", sessionsURL, token)
+	s.sessions[token] = sessionURL
+	rw.Header().Set(tokenHeader, token)
+	rw.Header().Set("Location", sessionURL)
+	rw.WriteHeader(http.StatusCreated)
+}
+
+// patchResource merges properties from the request body into the resource
+func (s *Simulator) patchResource(rw http.ResponseWriter, req *http.Request, path string) {
+	resource, ok := s.resources[path].(map[string]interface{})
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+	properties := map[string]interface{}{}
+	if err := json.NewDecoder(req.Body).Decode(&properties); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	for k, v := range properties {
+		resource[k] = v
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// appendLink appends link to the resource into the array field of another resource
+func (s *Simulator) appendLink(path, field, link string) {
+	resource := s.resources[path].(map[string]interface{})
+	links := resource[field].([]map[string]string)
+	resource[field] = append(links, map[string]string{"@odata.id": link})
+}
+
+func members() map[string]interface{} {
+	return map[string]interface{}{"Members": []map[string]string{}}
+}
@@ -60,3 +60,15 @@ func (vo *VolumeOperationsMock) WaitStatus(ctx context.Context, volumeID string,
 
 	return args.Error(0)
 }
+
+// ExpandVolume is the mock implementation of ExpandVolume method from VolumeOperations made for simulating
+// expansion of Volume CR on a cluster.
+// Returns a fake api.Volume instance
+func (vo *VolumeOperationsMock) ExpandVolume(ctx context.Context, volumeID string, requiredBytes int64) (*api.Volume, error) {
+	args := vo.Mock.Called(ctx, volumeID, requiredBytes)
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.Volume), args.Error(1)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// reconcileDrivesLED sets LED of the drives of the current node to the state requested by DriveLEDAnnotationKey
+// annotation through DriveManager and saves the applied state in drive CR LEDState field
+func (m *VolumeManager) reconcileDrivesLED(ctx context.Context) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method": "reconcileDrivesLED",
+	})
+
+	drives, err := m.crHelper.GetDriveCRs(m.nodeID)
+	if err != nil {
+		ll.Errorf("Unable to read drive CRs: %v", err)
+		return
+	}
+
+	for i := range drives {
+		drive := &drives[i]
+		state := strings.ToUpper(drive.GetAnnotations()[apiV1.DriveLEDAnnotationKey])
+		if state == "" || state == drive.Spec.LEDState {
+			continue
+		}
+
+		ll.Infof("Setting LED of drive %s from %s to %s", drive.Spec.UUID, drive.Spec.LEDState, state)
+		resp, err := m.driveMgrClient.SetDriveLED(ctx, &api.DriveLEDRequest{
+			DriveUUID:    drive.Spec.UUID,
+			SerialNumber: drive.Spec.SerialNumber,
+			State:        state,
+		})
+		if err != nil {
+			ll.Errorf("Unable to set LED of drive %s: %v", drive.Spec.UUID, err)
+			m.sendEventForDrive(drive, eventing.ErrorType, eventing.DriveLEDStateFailed,
+				"Unable to set LED state %s: %v.", state, err)
+			continue
+		}
+
+		prevState := drive.Spec.LEDState
+		drive.Spec.LEDState = resp.State
+		if err = m.k8sClient.UpdateCR(ctx, drive); err != nil {
+			ll.Errorf("Unable to save LED state of drive %s: %v", drive.Spec.UUID, err)
+			continue
+		}
+		m.sendEventForDrive(drive, eventing.InfoType, eventing.DriveLEDStateChanged,
+			"LED state changed from %s to %s.", prevState, resp.State)
+	}
+}
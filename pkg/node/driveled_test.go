@@ -0,0 +1,99 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+)
+
+func TestVolumeManager_reconcileDrivesLED(t *testing.T) {
+	var (
+		vm    = prepareSuccessVolumeManager(t)
+		drive = disk1
+	)
+	vm.driveMgrClient = mocks.NewMockDriveMgrClient([]*api.Drive{&drive})
+	addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(drive.UUID, drive))
+
+	// there is no annotation, LED isn't changed
+	vm.reconcileDrivesLED(testCtx)
+	assert.Equal(t, "", getDriveLEDState(t, vm, drive.UUID))
+
+	driveCR := vm.crHelper.GetDriveCRByUUID(drive.UUID)
+	driveCR.Annotations = map[string]string{apiV1.DriveLEDAnnotationKey: "locate"}
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, driveCR))
+	vm.reconcileDrivesLED(testCtx)
+	assert.Equal(t, apiV1.LEDStateLocate, getDriveLEDState(t, vm, drive.UUID))
+	assert.Equal(t, apiV1.LEDStateLocate, drive.LEDState)
+
+	// drivemgr fails, last applied state is kept
+	vm.driveMgrClient = mocks.MockDriveMgrClientFail{}
+	driveCR = vm.crHelper.GetDriveCRByUUID(drive.UUID)
+	driveCR.Annotations[apiV1.DriveLEDAnnotationKey] = "off"
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, driveCR))
+	vm.reconcileDrivesLED(testCtx)
+	assert.Equal(t, apiV1.LEDStateLocate, getDriveLEDState(t, vm, drive.UUID))
+}
+
+func TestVolumeManager_Maintain(t *testing.T) {
+	var (
+		vm    = prepareSuccessVolumeManager(t)
+		drive = disk1
+	)
+	vm.driveMgrClient = mocks.NewMockDriveMgrClient([]*api.Drive{&drive})
+	addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(drive.UUID, drive))
+	driveCR := vm.crHelper.GetDriveCRByUUID(drive.UUID)
+	driveCR.Annotations = map[string]string{apiV1.DriveLEDAnnotationKey: "locate"}
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, driveCR))
+
+	// the first discovery isn't performed yet
+	vm.Maintain()
+	assert.Equal(t, "", getDriveLEDState(t, vm, drive.UUID))
+
+	// LEDs are reconciled without drives list from DriveManager
+	vm.initialized = true
+	vm.Maintain()
+	assert.Equal(t, apiV1.LEDStateLocate, getDriveLEDState(t, vm, drive.UUID))
+}
+
+func TestVolumeManager_updateDrivesCRsKeepsLEDState(t *testing.T) {
+	var (
+		vm    = prepareSuccessVolumeManager(t)
+		drive = disk1
+	)
+	drive.LEDState = apiV1.LEDStateFailure
+	addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(drive.UUID, drive))
+
+	// drivemgr doesn't report LED state
+	fromMgr := disk1
+	fromMgr.Health = apiV1.HealthBad
+	_, err := vm.updateDrivesCRs(testCtx, []*api.Drive{&fromMgr})
+	assert.Nil(t, err)
+	assert.Equal(t, apiV1.LEDStateFailure, getDriveLEDState(t, vm, drive.UUID))
+}
+
+func getDriveLEDState(t *testing.T, vm *VolumeManager, driveUUID string) string {
+	drive := &drivecrd.Drive{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, driveUUID, drive))
+	return drive.Spec.LEDState
+}
@@ -0,0 +1,270 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	coreV1 "k8s.io/api/core/v1"
+	k8sError "k8s.io/apimachinery/pkg/api/errors"
+	k8sCl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+	"github.com/dell/csi-baremetal/pkg/eventing"
+)
+
+// handleDrivesReplacement moves drives of the current node through operational statuses of the drive replacement
+// workflow: OPERATIVE -> RELEASING -> RELEASED -> REMOVING -> REMOVED. FAILED is set if volumes weren't released
+// or drive wasn't prepared for removal. Transitions are driven by drive health and annotations on Volume, PVC and Drive
+func (m *VolumeManager) handleDrivesReplacement(ctx context.Context) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method": "handleDrivesReplacement",
+	})
+
+	drives, err := m.crHelper.GetDriveCRs(m.nodeID)
+	if err != nil {
+		ll.Errorf("Unable to read drive CRs: %v", err)
+		return
+	}
+
+	for i := range drives {
+		drive := &drives[i]
+		switch drive.Spec.OperationalStatus {
+		case "", apiV1.DriveOpStatusOperative:
+			if drive.Spec.Health == apiV1.HealthSuspect || drive.Spec.Health == apiV1.HealthBad {
+				m.startDriveRelease(ctx, drive)
+			}
+		case apiV1.DriveOpStatusReleasing:
+			m.checkDriveRelease(ctx, drive)
+		case apiV1.DriveOpStatusReleased:
+			if drive.GetAnnotations()[apiV1.DriveRemovalAnnotationKey] == apiV1.DriveRemovalReady {
+				m.setDriveOpStatus(ctx, drive, apiV1.DriveOpStatusRemoving, eventing.InfoType, eventing.DriveRemoving,
+					"Drive removal is requested, waiting for deletion of persistent volumes.")
+			}
+		case apiV1.DriveOpStatusRemoving:
+			m.checkDriveRemoval(ctx, drive)
+		}
+	}
+}
+
+// startDriveRelease puts health and release process annotations on the volumes of unhealthy drive
+// and sets drive operational status to RELEASING
+func (m *VolumeManager) startDriveRelease(ctx context.Context, drive *drivecrd.Drive) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":  "startDriveRelease",
+		"driveID": drive.Spec.UUID,
+	})
+
+	volumes, err := m.getVolumesOnDrive(drive.Spec.UUID)
+	if err != nil {
+		ll.Errorf("Unable to find volumes on drive: %v", err)
+		return
+	}
+
+	for i := range volumes {
+		vol := &volumes[i]
+		if vol.Annotations == nil {
+			vol.Annotations = make(map[string]string, 2)
+		}
+		vol.Annotations[apiV1.VolumeHealthAnnotationKey] = strings.ToLower(drive.Spec.Health)
+		vol.Annotations[apiV1.VolumeReleaseProcessAnnotationKey] = apiV1.VolumeReleaseProcessStart
+		if err = m.k8sClient.UpdateCR(ctx, vol); err != nil {
+			ll.Errorf("Unable to start release of volume %s: %v", vol.Name, err)
+			return
+		}
+	}
+
+	m.setDriveOpStatus(ctx, drive, apiV1.DriveOpStatusReleasing, eventing.WarningType, eventing.DriveReleasing,
+		"Drive health is %s, release of %d volume(s) is started.", drive.Spec.Health, len(volumes))
+}
+
+// checkDriveRelease inspects release annotations of the drive volumes. Volumes whose PVC doesn't have
+// volume release support annotation are considered as released.
+// Sets drive operational status to RELEASED if all volumes are released and to FAILED if any of them failed
+func (m *VolumeManager) checkDriveRelease(ctx context.Context, drive *drivecrd.Drive) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":  "checkDriveRelease",
+		"driveID": drive.Spec.UUID,
+	})
+
+	volumes, err := m.getVolumesOnDrive(drive.Spec.UUID)
+	if err != nil {
+		ll.Errorf("Unable to find volumes on drive: %v", err)
+		return
+	}
+
+	for _, vol := range volumes {
+		supported, err := m.isVolumeReleaseSupported(ctx, vol.Spec.Id)
+		if err != nil {
+			ll.Errorf("Unable to check whether release of volume %s is supported: %v", vol.Name, err)
+			return
+		}
+		if !supported {
+			continue
+		}
+		switch vol.Annotations[apiV1.VolumeReleaseAnnotationKey] {
+		case apiV1.VolumeReleaseCompleted:
+			continue
+		case apiV1.VolumeReleaseFailed:
+			m.setDriveOpStatus(ctx, drive, apiV1.DriveOpStatusFailed, eventing.ErrorType, eventing.DriveReleaseFailed,
+				"Release of volume %s failed: %s.", vol.Name, vol.Annotations[apiV1.VolumeReleaseStatusAnnotationKey])
+			return
+		default:
+			ll.Infof("Volume %s is not released yet, recovery progress: %s", vol.Name,
+				vol.Annotations[apiV1.VolumeReleaseRecoveryAnnotationKey])
+			return
+		}
+	}
+
+	m.setDriveOpStatus(ctx, drive, apiV1.DriveOpStatusReleased, eventing.InfoType, eventing.DriveReadyForRemoval,
+		"Drive is released, set annotation %s=%s to start its removal.",
+		apiV1.DriveRemovalAnnotationKey, apiV1.DriveRemovalReady)
+}
+
+// checkDriveRemoval waits until all volumes and LVGs on the drive are deleted, removes AC based on the drive,
+// starts LED locate and detaches the drive from the kernel for safe removal.
+// Sets drive operational status to REMOVED or to FAILED if any of these operations failed
+func (m *VolumeManager) checkDriveRemoval(ctx context.Context, drive *drivecrd.Drive) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":  "checkDriveRemoval",
+		"driveID": drive.Spec.UUID,
+	})
+
+	volumes, err := m.getVolumesOnDrive(drive.Spec.UUID)
+	if err != nil {
+		ll.Errorf("Unable to find volumes on drive: %v", err)
+		return
+	}
+	if len(volumes) > 0 {
+		ll.Infof("Waiting for deletion of %d volume(s)", len(volumes))
+		return
+	}
+	// VG should be removed before its PV is detached
+	for _, lvg := range m.crHelper.GetLVGCRs(m.nodeID) {
+		if util.ContainsString(lvg.Spec.Locations, drive.Spec.UUID) {
+			ll.Infof("Waiting for deletion of LVG %s", lvg.Name)
+			return
+		}
+	}
+
+	if ac := m.crHelper.GetACByLocation(drive.Spec.UUID); ac != nil {
+		if err = m.k8sClient.DeleteCR(ctx, ac); err != nil {
+			m.setDriveOpStatus(ctx, drive, apiV1.DriveOpStatusFailed, eventing.ErrorType, eventing.DriveRemovalFailed,
+				"Unable to remove available capacity %s: %v.", ac.Name, err)
+			return
+		}
+	}
+
+	// drive manager finds drive by its device, so LED is set before the drive is detached
+	if drive.Spec.LEDState != apiV1.LEDStateLocate {
+		resp, err := m.driveMgrClient.SetDriveLED(ctx, &api.DriveLEDRequest{
+			DriveUUID:    drive.Spec.UUID,
+			SerialNumber: drive.Spec.SerialNumber,
+			State:        apiV1.LEDStateLocate,
+		})
+		if err != nil {
+			m.setDriveOpStatus(ctx, drive, apiV1.DriveOpStatusFailed, eventing.ErrorType, eventing.DriveRemovalFailed,
+				"Unable to start LED locate: %v.", err)
+			return
+		}
+		drive.Spec.LEDState = resp.State
+	}
+
+	if err = m.blockDevOps.SafeRemove(drive.Spec.Path); err != nil {
+		m.setDriveOpStatus(ctx, drive, apiV1.DriveOpStatusFailed, eventing.ErrorType, eventing.DriveRemovalFailed,
+			"Unable to prepare drive for safe removal: %v.", err)
+		return
+	}
+
+	m.setDriveOpStatus(ctx, drive, apiV1.DriveOpStatusRemoved, eventing.InfoType, eventing.DriveReadyForReplacement,
+		"Drive is detached and its LED is set to %s, it is ready for physical replacement.", drive.Spec.LEDState)
+}
+
+// setDriveOpStatus updates operational status of the drive CR and sends event about it
+func (m *VolumeManager) setDriveOpStatus(ctx context.Context, drive *drivecrd.Drive, opStatus,
+	eventType, reason, messageFmt string, args ...interface{}) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":  "setDriveOpStatus",
+		"driveID": drive.Spec.UUID,
+	})
+
+	prevStatus := drive.Spec.OperationalStatus
+	drive.Spec.OperationalStatus = opStatus
+	if err := m.k8sClient.UpdateCR(ctx, drive); err != nil {
+		ll.Errorf("Unable to change operational status from %s to %s: %v", prevStatus, opStatus, err)
+		drive.Spec.OperationalStatus = prevStatus
+		return
+	}
+	ll.Infof("Operational status changed from %s to %s", prevStatus, opStatus)
+	m.sendEventForDrive(drive, eventType, reason, messageFmt, args...)
+}
+
+// getVolumesOnDrive returns volume CRs which are located on the drive directly, on LVG based on the drive
+// or on MD RAID array which the drive is a member of
+func (m *VolumeManager) getVolumesOnDrive(driveUUID string) ([]volumecrd.Volume, error) {
+	volumes, err := m.crHelper.GetVolumeCRs(m.nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := []string{driveUUID}
+	for _, lvg := range m.crHelper.GetLVGCRs(m.nodeID) {
+		if util.ContainsString(lvg.Spec.Locations, driveUUID) {
+			locations = append(locations, lvg.Name)
+		}
+	}
+
+	result := make([]volumecrd.Volume, 0)
+	for _, vol := range volumes {
+		if util.ContainsString(locations, vol.Spec.Location) || util.ContainsString(vol.Spec.Locations, driveUUID) {
+			result = append(result, vol)
+		}
+	}
+	return result, nil
+}
+
+// isVolumeReleaseSupported checks whether PVC of the volume has volume release support annotation,
+// volume without PV or PVC doesn't support release
+// Returns error if PV or PVC wasn't read, the check should be repeated in that case
+func (m *VolumeManager) isVolumeReleaseSupported(ctx context.Context, volumeID string) (bool, error) {
+	pv := &coreV1.PersistentVolume{}
+	if err := m.k8sClient.Get(ctx, k8sCl.ObjectKey{Name: volumeID}, pv); err != nil {
+		if k8sError.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if pv.Spec.ClaimRef == nil {
+		return false, nil
+	}
+
+	pvc := &coreV1.PersistentVolumeClaim{}
+	key := k8sCl.ObjectKey{Name: pv.Spec.ClaimRef.Name, Namespace: pv.Spec.ClaimRef.Namespace}
+	if err := m.k8sClient.Get(ctx, key, pvc); err != nil {
+		if k8sError.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return pvc.GetAnnotations()[apiV1.VolumeReleaseSupportAnnotationKey] == apiV1.VolumeReleaseSupported, nil
+}
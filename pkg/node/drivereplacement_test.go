@@ -0,0 +1,195 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coreV1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+func TestVolumeManager_handleDrivesReplacement(t *testing.T) {
+	var (
+		vm    = prepareSuccessVolumeManager(t)
+		drive = disk1
+		vol   = testVolumeCR1
+	)
+	drive.Health = apiV1.HealthBad
+	drive.OperationalStatus = apiV1.DriveOpStatusOperative
+	addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(drive.UUID, drive))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, vol.Name, &vol))
+	createPVWithPVC(t, vm.k8sClient, vol.Name, true)
+
+	// unhealthy drive, release is started
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusReleasing, getDriveOpStatus(t, vm, drive.UUID))
+	rVolume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, "bad", rVolume.Annotations[apiV1.VolumeHealthAnnotationKey])
+	assert.Equal(t, apiV1.VolumeReleaseProcessStart, rVolume.Annotations[apiV1.VolumeReleaseProcessAnnotationKey])
+
+	// operator doesn't complete release yet
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusReleasing, getDriveOpStatus(t, vm, drive.UUID))
+
+	// operator completes release
+	rVolume.Annotations[apiV1.VolumeReleaseAnnotationKey] = apiV1.VolumeReleaseCompleted
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, rVolume))
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusReleased, getDriveOpStatus(t, vm, drive.UUID))
+
+	// drive stays released until user requests removal
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusReleased, getDriveOpStatus(t, vm, drive.UUID))
+
+	driveCR := vm.crHelper.GetDriveCRByUUID(drive.UUID)
+	driveCR.Annotations = map[string]string{apiV1.DriveRemovalAnnotationKey: apiV1.DriveRemovalReady}
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, driveCR))
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusRemoving, getDriveOpStatus(t, vm, drive.UUID))
+
+	// volume isn't deleted yet
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusRemoving, getDriveOpStatus(t, vm, drive.UUID))
+
+	blockDevOps := &mocklu.MockWrapBlockDev{}
+	blockDevOps.On("SafeRemove", drive.Path).Return(nil).Times(1)
+	vm.blockDevOps = blockDevOps
+	ac := testAC1
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, ac.Name, &ac))
+	assert.Nil(t, vm.k8sClient.DeleteCR(testCtx, rVolume))
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusRemoved, getDriveOpStatus(t, vm, drive.UUID))
+	assert.Nil(t, vm.crHelper.GetACByLocation(drive.UUID))
+	assert.Equal(t, apiV1.LEDStateLocate, vm.crHelper.GetDriveCRByUUID(drive.UUID).Spec.LEDState)
+	blockDevOps.AssertExpectations(t)
+}
+
+func TestVolumeManager_handleDrivesReplacementRemovalFailed(t *testing.T) {
+	var (
+		vm          = prepareSuccessVolumeManager(t)
+		drive       = disk1
+		blockDevOps = &mocklu.MockWrapBlockDev{}
+	)
+	drive.OperationalStatus = apiV1.DriveOpStatusRemoving
+	addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(drive.UUID, drive))
+	vm.blockDevOps = blockDevOps
+
+	// LVG on the drive isn't deleted yet
+	lvg := vm.k8sClient.ConstructLVGCR(testLVGName, api.LogicalVolumeGroup{
+		Name: testLVGName, Node: nodeID, Locations: []string{drive.UUID},
+	})
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, lvg.Name, lvg))
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusRemoving, getDriveOpStatus(t, vm, drive.UUID))
+	blockDevOps.AssertNotCalled(t, "SafeRemove", drive.Path)
+
+	// drive wasn't detached
+	assert.Nil(t, vm.k8sClient.DeleteCR(testCtx, lvg))
+	blockDevOps.On("SafeRemove", drive.Path).Return(errors.New("error")).Times(1)
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusFailed, getDriveOpStatus(t, vm, drive.UUID))
+
+	// LED locate wasn't started
+	vm = prepareSuccessVolumeManager(t)
+	vm.driveMgrClient = mocks.MockDriveMgrClientFail{}
+	addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(drive.UUID, drive))
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusFailed, getDriveOpStatus(t, vm, drive.UUID))
+}
+
+func TestVolumeManager_handleDrivesReplacementReleaseFailed(t *testing.T) {
+	var (
+		vm    = prepareSuccessVolumeManager(t)
+		drive = disk1
+		vol   = testVolumeCR1
+	)
+	drive.Health = apiV1.HealthSuspect
+	drive.OperationalStatus = apiV1.DriveOpStatusReleasing
+	addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(drive.UUID, drive))
+	vol.Annotations = map[string]string{
+		apiV1.VolumeReleaseAnnotationKey:       apiV1.VolumeReleaseFailed,
+		apiV1.VolumeReleaseStatusAnnotationKey: "recovery failed by timeout",
+	}
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, vol.Name, &vol))
+	createPVWithPVC(t, vm.k8sClient, vol.Name, true)
+
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusFailed, getDriveOpStatus(t, vm, drive.UUID))
+}
+
+func TestVolumeManager_handleDrivesReplacementNotSupported(t *testing.T) {
+	var (
+		vm     = prepareSuccessVolumeManager(t)
+		drive  = disk1
+		lvgVol = testVolumeCR2
+	)
+	drive.Health = apiV1.HealthBad
+	addDriveCRs(vm.k8sClient, vm.k8sClient.ConstructDriveCR(drive.UUID, drive))
+
+	lvg := vm.k8sClient.ConstructLVGCR(testLVGName, api.LogicalVolumeGroup{
+		Name: testLVGName, Node: nodeID, Locations: []string{drive.UUID},
+	})
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, lvg.Name, lvg))
+	lvgVol.Spec.Location = testLVGName
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, lvgVol.Name, &lvgVol))
+	createPVWithPVC(t, vm.k8sClient, lvgVol.Name, false)
+
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusReleasing, getDriveOpStatus(t, vm, drive.UUID))
+	rVolume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvgVol.Name, rVolume))
+	assert.Equal(t, apiV1.VolumeReleaseProcessStart, rVolume.Annotations[apiV1.VolumeReleaseProcessAnnotationKey])
+
+	// PVC doesn't support volume release, CSI doesn't wait for operator
+	vm.handleDrivesReplacement(testCtx)
+	assert.Equal(t, apiV1.DriveOpStatusReleased, getDriveOpStatus(t, vm, drive.UUID))
+}
+
+func getDriveOpStatus(t *testing.T, vm *VolumeManager, driveUUID string) string {
+	drive := &drivecrd.Drive{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, driveUUID, drive))
+	return drive.Spec.OperationalStatus
+}
+
+func createPVWithPVC(t *testing.T, k *k8s.KubeClient, pvName string, releaseSupported bool) {
+	pvc := &coreV1.PersistentVolumeClaim{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: pvName + "-claim", Namespace: testNs},
+	}
+	if releaseSupported {
+		pvc.Annotations = map[string]string{apiV1.VolumeReleaseSupportAnnotationKey: apiV1.VolumeReleaseSupported}
+	}
+	pv := &coreV1.PersistentVolume{
+		ObjectMeta: k8smetav1.ObjectMeta{Name: pvName},
+		Spec: coreV1.PersistentVolumeSpec{
+			ClaimRef: &coreV1.ObjectReference{Name: pvc.Name, Namespace: pvc.Namespace},
+		},
+	}
+	assert.Nil(t, k.Create(testCtx, pvc))
+	assert.Nil(t, k.Create(testCtx, pv))
+}
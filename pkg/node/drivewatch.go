@@ -0,0 +1,115 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+)
+
+// WatchDrives subscribes on changes of drives which are reported by DriveManager and applies them immediately
+// Blocks until stream is interrupted or ctx is done
+// Returns error from DriveManager, it has Unimplemented code if DriveManager isn't able to watch drives
+func (m *VolumeManager) WatchDrives(ctx context.Context) error {
+	ll := m.log.WithFields(logrus.Fields{
+		"method": "WatchDrives",
+	})
+
+	stream, err := m.driveMgrClient.WatchDrives(ctx, &api.DrivesRequest{NodeId: m.nodeID})
+	if err != nil {
+		return err
+	}
+	header, err := stream.Header()
+	if err != nil {
+		return err
+	}
+	if len(header.Get(apiV1.DriveWatchHeaderKey)) == 0 {
+		// stream is finished by DriveManager without confirmation, Recv returns the reason
+		if _, err = stream.Recv(); err == nil || err == io.EOF {
+			err = errors.New("watching of drives isn't confirmed by DriveManager")
+		}
+		return err
+	}
+
+	atomic.StoreInt32(&m.drivesWatched, 1)
+	defer atomic.StoreInt32(&m.drivesWatched, 0)
+	ll.Info("Watching of drives is started")
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		ll.Infof("Drive %s is %s", event.Drive.SerialNumber, event.Type)
+		if err = m.handleDriveEvent(event); err != nil {
+			ll.Errorf("Unable to handle drive event: %v", err)
+		}
+	}
+}
+
+// IsWatchingDrives returns true if changes of drives are received from DriveManager, so polling might be rare
+func (m *VolumeManager) IsWatchingDrives() bool {
+	return atomic.LoadInt32(&m.drivesWatched) == 1
+}
+
+// handleDriveEvent applies change of the single drive. Drives list is built from Drive CRs and the change,
+// so the same discovery steps are performed as for the full list from DriveManager
+func (m *VolumeManager) handleDriveEvent(event *api.DriveEvent) error {
+	ctx, cancelFn := context.WithTimeout(context.Background(), DiscoverDrivesTimeout)
+	defer cancelFn()
+
+	m.discoverMu.Lock()
+	defer m.discoverMu.Unlock()
+
+	// the first discovery is performed with full list by Discover
+	if !m.initialized {
+		return nil
+	}
+
+	driveCRs, err := m.crHelper.GetDriveCRs(m.nodeID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		drives = make([]*api.Drive, 0, len(driveCRs)+1)
+		found  bool
+	)
+	for i := range driveCRs {
+		drive := driveCRs[i].Spec
+		if m.drivesAreTheSame(&drive, event.Drive) {
+			found = true
+			if event.Type != apiV1.DriveEventRemoved {
+				drives = append(drives, event.Drive)
+			}
+			continue
+		}
+		drives = append(drives, &drive)
+	}
+	if !found && event.Type != apiV1.DriveEventRemoved {
+		drives = append(drives, event.Drive)
+	}
+
+	return m.discover(ctx, drives)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+// watchingDriveMgrClient is a DriveServiceClient which streams prepared drive events
+type watchingDriveMgrClient struct {
+	*mocks.MockDriveMgrClient
+	stream *driveEventsStream
+}
+
+func (c *watchingDriveMgrClient) WatchDrives(ctx context.Context, in *api.DrivesRequest,
+	opts ...grpc.CallOption) (api.DriveService_WatchDrivesClient, error) {
+	return c.stream, nil
+}
+
+// driveEventsStream returns prepared events and then io.EOF, vm is inspected before each event
+type driveEventsStream struct {
+	grpc.ClientStream
+	header  metadata.MD
+	events  []*api.DriveEvent
+	onEvent func()
+}
+
+func (s *driveEventsStream) Header() (metadata.MD, error) {
+	return s.header, nil
+}
+
+func (s *driveEventsStream) Recv() (*api.DriveEvent, error) {
+	if s.onEvent != nil {
+		s.onEvent()
+	}
+	if len(s.events) == 0 {
+		return nil, io.EOF
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
+func TestVolumeManager_WatchDrives(t *testing.T) {
+	var (
+		vm       = prepareSuccessVolumeManager(t)
+		listBlk  = &mocklu.MockWrapLsblk{}
+		d1       = drive1
+		d1Bad    = drive1
+		d2       = drive2
+		watching []bool
+	)
+	d1Bad.Health = apiV1.HealthBad
+	stream := &driveEventsStream{
+		header: metadata.Pairs(apiV1.DriveWatchHeaderKey, "true"),
+		events: []*api.DriveEvent{
+			{Type: apiV1.DriveEventAdded, Drive: &d2},
+			{Type: apiV1.DriveEventModified, Drive: &d1Bad},
+			{Type: apiV1.DriveEventRemoved, Drive: &d2},
+		},
+		onEvent: func() { watching = append(watching, vm.IsWatchingDrives()) },
+	}
+	vm.driveMgrClient = &watchingDriveMgrClient{
+		MockDriveMgrClient: mocks.NewMockDriveMgrClient([]*api.Drive{&d1}),
+		stream:             stream,
+	}
+	vm.listBlk = listBlk
+	listBlk.On("GetBlockDevices", "").Return([]lsblk.BlockDevice{bdev1, bdev2}, nil)
+	listBlk.On("GetBlockDevices", drive1.Path).Return([]lsblk.BlockDevice{bdev1}, nil)
+	listBlk.On("GetBlockDevices", drive2.Path).Return([]lsblk.BlockDevice{bdev2}, nil)
+
+	assert.Nil(t, vm.Discover())
+	assert.Equal(t, 1, len(getDriveCRsListItems(t, vm.k8sClient)))
+
+	assert.Equal(t, io.EOF, vm.WatchDrives(testCtx))
+	assert.Equal(t, []bool{true, true, true, true}, watching)
+	assert.False(t, vm.IsWatchingDrives())
+
+	drives := getDriveCRsListItems(t, vm.k8sClient)
+	assert.Equal(t, 2, len(drives))
+	for _, d := range drives {
+		switch d.Spec.SerialNumber {
+		case drive1.SerialNumber:
+			assert.Equal(t, apiV1.HealthBad, d.Spec.Health)
+		case drive2.SerialNumber:
+			assert.Equal(t, apiV1.DriveStatusOffline, d.Spec.Status)
+		}
+	}
+}
+
+func TestVolumeManager_WatchDrivesNotSupported(t *testing.T) {
+	vm := prepareSuccessVolumeManager(t)
+	err := vm.WatchDrives(testCtx)
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	// DriveManager finished stream without confirmation
+	vm.driveMgrClient = &watchingDriveMgrClient{stream: &driveEventsStream{}}
+	assert.NotNil(t, vm.WatchDrives(testCtx))
+	assert.False(t, vm.IsWatchingDrives())
+}
+
+func TestVolumeManager_handleDriveEventNotInitialized(t *testing.T) {
+	vm := prepareSuccessVolumeManager(t)
+	d := drive1
+	assert.Nil(t, vm.handleDriveEvent(&api.DriveEvent{Type: apiV1.DriveEventAdded, Drive: &d}))
+	assert.Equal(t, 0, len(getDriveCRsListItems(t, vm.k8sClient)))
+}
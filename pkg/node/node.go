@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
 	"strconv"
 	"strings"
 	"sync"
@@ -40,10 +41,13 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/cryptsetup"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/common"
 	"github.com/dell/csi-baremetal/pkg/controller"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/csibmnode"
+	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
 )
 
 // CSINodeService is the implementation of NodeServer interface from GO CSI specification.
@@ -65,8 +69,6 @@ type CSINodeService struct {
 const (
 	// PodNameKey to read pod name from PodInfoOnMount feature
 	PodNameKey = "csi.storage.k8s.io/pod.name"
-	// UnknownPodName is used when pod name isn't provided in request
-	UnknownPodName = "UNKNOWN"
 	// EphemeralKey in volume context means that in node publish request we need to create ephemeral volume
 	EphemeralKey = "csi.storage.k8s.io/ephemeral"
 )
@@ -168,24 +170,54 @@ func (s *CSINodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStage
 			currStatus)
 	}
 
-	targetPath := req.StagingTargetPath
+	targetPath := getStagingPath(&volumeCR.Spec, req.GetStagingTargetPath())
 
 	partition, err := s.getProvisionerForVolume(&volumeCR.Spec).GetVolumePath(volumeCR.Spec)
 	if err != nil {
 		ll.Errorf("failed to get partition, for volume %v: %v", volumeCR.Spec, err)
 		return nil, status.Error(codes.Internal, "failed to stage volume: partition error")
 	}
+	if volumeCR.Spec.Encrypted {
+		mapping, err := s.openLUKS(&volumeCR.Spec, partition, req.GetSecrets())
+		if err != nil {
+			ll.Errorf("Unable to open encrypted device %s: %v", partition, err)
+			return nil, err
+		}
+		partition = mapping
+	}
 	ll.Infof("Work with partition %s", partition)
 
+	// block device is bind mounted to a file, FS is mounted to a directory with mount options from StorageClass
+	isRaw := volumeCR.Spec.Mode == apiV1.ModeRAW
+	var mountOptions []string
+	if !isRaw {
+		mountOptions = req.GetVolumeCapability().GetMount().GetMountFlags()
+		if err := fs.ValidateMountOptions(fs.FileSystem(volumeCR.Spec.Type), mountOptions); err != nil {
+			ll.Errorf("Mount options %v are invalid: %v", mountOptions, err)
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		// xfs copy keeps UUID of its source, so it couldn't be mounted along with the source otherwise
+		if volumeCR.Spec.ContentSourceId != "" && fs.FileSystem(volumeCR.Spec.Type) == fs.XFS {
+			mountOptions = append(mountOptions, "nouuid")
+		}
+	}
+
 	var (
 		resp        = &csi.NodeStageVolumeResponse{}
 		errToReturn error
 		newStatus   = apiV1.VolumeReady
 	)
-	if err := s.fsOps.PrepareAndPerformMount(partition, targetPath, false); err != nil {
+	if err := s.fsOps.PrepareAndPerformMount(partition, targetPath, isRaw, !isRaw, mountOptions...); err != nil {
 		ll.Errorf("Unable to prepare and mount: %v. Going to set volumes status to failed", err)
 		newStatus = apiV1.Failed
 		resp, errToReturn = nil, status.Error(codes.Internal, "failed to stage volume: mount error")
+	} else if !isRaw && currStatus == apiV1.Created && volumeCR.Spec.ContentSourceId != "" {
+		// FS copied from the source has size of the source, volume could be bigger
+		if err := s.fsOps.GrowFS(fs.FileSystem(volumeCR.Spec.Type), partition, targetPath); err != nil {
+			ll.Errorf("Unable to grow file system on %s: %v. Going to set volumes status to failed", partition, err)
+			newStatus = apiV1.Failed
+			resp, errToReturn = nil, status.Error(codes.Internal, "failed to stage volume: unable to grow file system")
+		}
 	}
 
 	if currStatus != apiV1.VolumeReady || newStatus == apiV1.Failed {
@@ -244,19 +276,34 @@ func (s *CSINodeService) NodeUnstageVolume(ctx context.Context, req *csi.NodeUns
 		return nil, status.Error(codes.FailedPrecondition, msg)
 	}
 
-	// This is a temporary solution to clear all owners during NodeUnstage
-	// because NodeUnpublishRequest doesn't contain info about pod
-	// TODO: remove owner from Owners slice during Unpublish properly - https://github.com/dell/csi-baremetal/issues/86
-	//volumeCR.Spec.Owners = nil
+	// owners are removed during NodeUnpublish, volume isn't used by any pod after that
+	volumeCR.Spec.Owners = nil
 	volumeCR.Spec.CSIStatus = apiV1.Created
 
 	var (
 		resp        = &csi.NodeUnstageVolumeResponse{}
 		errToReturn error
 	)
-	if errToReturn = s.fsOps.UnmountWithCheck(req.GetStagingTargetPath()); errToReturn != nil {
+	stagingPath := getStagingPath(&volumeCR.Spec, req.GetStagingTargetPath())
+	if errToReturn = s.fsOps.UnmountWithCheck(stagingPath); errToReturn != nil {
 		volumeCR.Spec.CSIStatus = apiV1.Failed
 		resp = nil
+	} else if volumeCR.Spec.Mode == apiV1.ModeRAW {
+		if errToReturn = s.fsOps.RmDir(stagingPath); errToReturn != nil {
+			ll.Errorf("Unable to remove staging file %s: %v", stagingPath, errToReturn)
+			volumeCR.Spec.CSIStatus = apiV1.Failed
+			resp = nil
+		}
+	}
+	if errToReturn == nil && volumeCR.Spec.Encrypted {
+		name := p.GetLUKSMappingName(volumeCR.Spec.Id)
+		if s.cryptOps.IsOpened(name) {
+			if errToReturn = s.cryptOps.LuksClose(name); errToReturn != nil {
+				ll.Errorf("Unable to close encrypted device %s: %v", name, errToReturn)
+				volumeCR.Spec.CSIStatus = apiV1.Failed
+				resp = nil
+			}
+		}
 	}
 
 	ctxWithID := context.WithValue(context.Background(), k8s.RequestUUID, req.GetVolumeId())
@@ -301,6 +348,10 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 	if len(req.GetTargetPath()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Target Path missing in request")
 	}
+	if !util.IsAccessModeSupported(req.GetVolumeCapability().GetAccessMode()) {
+		return nil, status.Errorf(codes.InvalidArgument, "Access mode %s isn't supported",
+			req.GetVolumeCapability().GetAccessMode().GetMode())
+	}
 	var (
 		inline bool
 		err    error
@@ -322,6 +373,8 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 		srcPath  = req.GetStagingTargetPath()
 		dstPath  = req.GetTargetPath()
 		bind     = true // for mount option
+		// mount options are applied on stage, inline volume isn't staged so they are applied on publish
+		mountOptions []string
 	)
 	// Inline volume has the same cycle as usual volume,
 	// but k8s calls only Publish/Unpulish methods so we need to call CreateVolume before publish it
@@ -336,8 +389,15 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 			ll.Errorf("failed to get partition for volume %v: %v", vol, err)
 			return nil, status.Error(codes.Internal, "failed to publish inline volume: partition error")
 		}
-		// For inline volume mount is performed without options
+		// For inline volume mount is performed without bind option
 		bind = false
+		if vol.Mode != apiV1.ModeRAW {
+			mountOptions = req.GetVolumeCapability().GetMount().GetMountFlags()
+			if err = fs.ValidateMountOptions(fs.FileSystem(vol.Type), mountOptions); err != nil {
+				ll.Errorf("Mount options %v are invalid: %v", mountOptions, err)
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
 	} else if len(srcPath) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Staging Path missing in request")
 	}
@@ -362,27 +422,33 @@ func (s *CSINodeService) NodePublishVolume(ctx context.Context, req *csi.NodePub
 		errToReturn error
 	)
 
-	if err := s.fsOps.PrepareAndPerformMount(srcPath, dstPath, bind); err != nil {
+	isRaw := volumeCR.Spec.Mode == apiV1.ModeRAW
+	if !inline {
+		srcPath = getStagingPath(&volumeCR.Spec, srcPath)
+	}
+	if req.GetReadonly() || util.IsReadOnlyAccessMode(req.GetVolumeCapability().GetAccessMode()) {
+		ll.Info("Volume will be published as read-only")
+		mountOptions = append(mountOptions, fs.ReadOnlyOption)
+	}
+	if err := s.fsOps.PrepareAndPerformMount(srcPath, dstPath, bind, !isRaw, mountOptions...); err != nil {
 		ll.Errorf("Unable to mount volume: %v", err)
 		newStatus = apiV1.Failed
 		resp, errToReturn = nil, fmt.Errorf("failed to publish volume: mount error")
+	} else {
+		// several pods on the same node could use volume, each of them is tracked as a volume owner
+		owner := getVolumeOwner(dstPath)
+		if !util.ContainsString(volumeCR.Spec.Owners, owner) {
+			volumeCR.Spec.Owners = append(volumeCR.Spec.Owners, owner)
+		}
+		ll.Infof("Volume is published for pod %s (%s), owners: %v",
+			req.GetVolumeContext()[PodNameKey], owner, volumeCR.Spec.Owners)
+		// volume stays published and tracked by owner, so kubelet retry or unpublish is able to proceed
+		if err := s.applyIOLimits(&volumeCR.Spec, owner); err != nil {
+			ll.Errorf("Unable to apply I/O limits for pod %s: %v", owner, err)
+			resp, errToReturn = nil, status.Errorf(codes.Internal, "failed to publish volume: unable to apply I/O limits: %v", err)
+		}
 	}
 
-	//TODO: need to provide better logic for volumes Owners https://github.com/dell/csi-baremetal/issues/86
-	//add volume owner info
-	//var podName string
-	//podName, ok := req.VolumeContext[PodNameKey]
-	//if !ok {
-	//	podName = UnknownPodName
-	//	ll.Warnf("flag podInfoOnMount isn't provided will add %s for volume owners", podName)
-	//}
-	//
-	//owners := volumeCR.Spec.Owners
-	//if !util.ContainsString(owners, podName) { // check whether podName already in owners or no
-	//	owners = append(owners, podName)
-	//	volumeCR.Spec.Owners = owners
-	//}
-
 	ctxWithID := context.WithValue(context.Background(), k8s.RequestUUID, volumeID)
 	volumeCR.Spec.CSIStatus = newStatus
 	if err = s.k8sClient.UpdateCR(ctxWithID, volumeCR); err != nil {
@@ -427,8 +493,7 @@ func (s *CSINodeService) createInlineVolume(ctx context.Context, volumeID string
 		scl = apiV1.StorageClassHDD // do not use sc ANY for inline volumes
 	}
 
-	s.reqMu.Lock()
-	vol, err := s.svc.CreateVolume(ctx, api.Volume{
+	volume := api.Volume{
 		Id:           volumeID,
 		StorageClass: scl,
 		NodeId:       s.nodeID,
@@ -436,7 +501,13 @@ func (s *CSINodeService) createInlineVolume(ctx context.Context, volumeID string
 		Ephemeral:    true,
 		Mode:         mode,
 		Type:         fsType,
-	})
+	}
+	if err = util.FillIOLimits(&volume, volumeContext); err != nil {
+		return nil, err
+	}
+
+	s.reqMu.Lock()
+	vol, err := s.svc.CreateVolume(ctx, volume)
 	s.reqMu.Unlock()
 	if err != nil {
 		return nil, err
@@ -504,10 +575,27 @@ func (s *CSINodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeU
 		}
 		return nil, status.Error(codes.Internal, "unmount error")
 	}
-	// If volume has more than 1 owner pods then keep its status as Published
-	//if len(volumeCR.Spec.Owners) > 1 {
-	//	return &csi.NodeUnpublishVolumeResponse{}, nil
-	//}
+	// target file for block device is created by CSI driver and should be removed by it
+	if volumeCR.Spec.Mode == apiV1.ModeRAW {
+		if err := s.fsOps.RmDir(req.GetTargetPath()); err != nil {
+			ll.Errorf("Unable to remove target file %s: %v", req.GetTargetPath(), err)
+			return nil, status.Error(codes.Internal, "unable to remove target file")
+		}
+	}
+	owner := getVolumeOwner(req.GetTargetPath())
+	if err := s.clearIOLimits(&volumeCR.Spec, owner); err != nil {
+		ll.Warnf("Unable to clear I/O limits for pod %s: %v", owner, err)
+	}
+	// If volume is still used by other pods then keep its status as Published
+	volumeCR.Spec.Owners = util.RemoveString(volumeCR.Spec.Owners, owner)
+	if len(volumeCR.Spec.Owners) > 0 && !volumeCR.Spec.Ephemeral {
+		ll.Infof("Volume is still used by %v", volumeCR.Spec.Owners)
+		if updateErr := s.k8sClient.UpdateCR(ctxWithID, volumeCR); updateErr != nil {
+			ll.Errorf("Unable to update volume CR owners: %v", updateErr)
+			return nil, status.Error(codes.Internal, "unable to update volume CR")
+		}
+		return &csi.NodeUnpublishVolumeResponse{}, nil
+	}
 
 	// k8s doesn't call DeleteVolume for inline volumes, so we perform DeleteVolume operation in Unpublish request
 	if volumeCR.Spec.Ephemeral {
@@ -542,30 +630,269 @@ func (s *CSINodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeU
 	return &csi.NodeUnpublishVolumeResponse{}, nil
 }
 
-// NodeGetVolumeStats returns empty response
+// getVolumeOwner returns UID of the pod which uses volume published to the targetPath,
+// kubelet provides target path in format <KubeletRootPath>/<podUID>/volumes/kubernetes.io~csi/<PV name>/mount
+// for volume in FS mode and with pod UID as the last element after KubeletBlockPublishDir for block volume,
+// if targetPath has another format then it is used as an owner itself
+func getVolumeOwner(targetPath string) string {
+	podsPath := base.KubeletRootPath + "/"
+	switch {
+	case strings.HasPrefix(targetPath, podsPath):
+		return strings.SplitN(strings.TrimPrefix(targetPath, podsPath), "/", 2)[0]
+	case strings.Contains(targetPath, base.KubeletBlockPublishDir):
+		return path.Base(targetPath)
+	}
+	return targetPath
+}
+
+// getStagingPath returns path where volume is staged: stagingTargetPath for volumes in FS mode
+// and file with volume ID as a name inside stagingTargetPath for volumes in RAW mode,
+// because block device could be bind mounted only to a file
+func getStagingPath(vol *api.Volume, stagingTargetPath string) string {
+	if vol.Mode == apiV1.ModeRAW {
+		return path.Join(stagingTargetPath, vol.Id)
+	}
+	return stagingTargetPath
+}
+
+// openLUKS opens dm-crypt mapping of the encrypted volume on device with passphrase from node stage secrets,
+// mapping which is already opened is reused. Device is formatted with LUKS on the first staging and FS is created
+// on the mapping if it doesn't have it yet (volume mode isn't RAW). Returns path of the mapping which should be mounted
+// instead of device
+func (s *CSINodeService) openLUKS(vol *api.Volume, device string, secrets map[string]string) (string, error) {
+	ll := s.log.WithFields(logrus.Fields{
+		"method":   "openLUKS",
+		"volumeID": vol.Id,
+	})
+
+	name := p.GetLUKSMappingName(vol.Id)
+	mapping := cryptsetup.GetMappingPath(name)
+	if !s.cryptOps.IsOpened(name) {
+		passphrase, ok := secrets[base.PassphraseKey]
+		if !ok || passphrase == "" {
+			return "", status.Errorf(codes.InvalidArgument,
+				"node stage secrets of encrypted volume don't contain %s", base.PassphraseKey)
+		}
+
+		signature, err := s.fsOps.GetFSType(device)
+		if err != nil {
+			ll.Errorf("Unable to read signature of device %s: %v", device, err)
+			return "", status.Error(codes.Internal, "failed to stage volume: unable to inspect encrypted device")
+		}
+		switch signature {
+		case cryptsetup.LuksSignature:
+		case "":
+			ll.Infof("Formatting device %s with LUKS", device)
+			if err = s.cryptOps.LuksFormat(device, passphrase); err != nil {
+				return "", status.Error(codes.Internal, "failed to stage volume: unable to format encrypted device")
+			}
+		default:
+			// device is wiped on volume creation, any other signature means that it is used by someone else
+			ll.Errorf("Device %s has unexpected signature %s", device, signature)
+			return "", status.Errorf(codes.FailedPrecondition,
+				"failed to stage volume: encrypted device has %s signature", signature)
+		}
+
+		if err = s.cryptOps.LuksOpen(device, name, passphrase); err != nil {
+			return "", status.Error(codes.Internal, "failed to stage volume: unable to open encrypted device")
+		}
+	}
+	if vol.Mode == apiV1.ModeRAW {
+		return mapping, nil
+	}
+
+	// FS creation could fail after formatting, so FS is checked every time
+	fsType, err := s.fsOps.GetFSType(mapping)
+	if err != nil {
+		ll.Errorf("Unable to read FS type of %s: %v", mapping, err)
+		return "", status.Error(codes.Internal, "failed to stage volume: unable to inspect encrypted device")
+	}
+	if fsType == "" {
+		ll.Infof("Creating FS on %s", mapping)
+		if err = s.fsOps.CreateFS(fs.FileSystem(vol.Type), mapping, strings.Fields(vol.MkfsOptions)...); err != nil {
+			ll.Errorf("Unable to create FS on %s: %v", mapping, err)
+			return "", status.Error(codes.Internal, "failed to stage volume: unable to create FS on encrypted device")
+		}
+	}
+	return mapping, nil
+}
+
+// NodeGetVolumeStats is the implementation of CSI Spec NodeGetVolumeStats. Provides capacity and inodes usage
+// of the file system mounted to VolumePath or size of the device for volumes in RAW mode.
+// Also reports condition of the volume based on Health and OperationalStatus of Volume CR.
+// Receives golang context and CSI Spec NodeGetVolumeStatsRequest
+// Returns CSI Spec NodeGetVolumeStatsResponse or error if something went wrong
 func (s *CSINodeService) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
-	return &csi.NodeGetVolumeStatsResponse{}, nil
+	ll := s.log.WithFields(logrus.Fields{
+		"method":   "NodeGetVolumeStats",
+		"volumeID": req.GetVolumeId(),
+	})
+
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+	if len(req.GetVolumePath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume path missing in request")
+	}
+
+	volumeCR := s.crHelper.GetVolumeByID(req.GetVolumeId())
+	if volumeCR == nil {
+		message := fmt.Sprintf("Unable to find volume with ID %s", req.GetVolumeId())
+		ll.Error(message)
+		return nil, status.Error(codes.NotFound, message)
+	}
+
+	resp := &csi.NodeGetVolumeStatsResponse{VolumeCondition: util.GetVolumeCondition(&volumeCR.Spec)}
+
+	if volumeCR.Spec.Mode == apiV1.ModeRAW {
+		device, err := s.getProvisionerForVolume(&volumeCR.Spec).GetVolumePath(volumeCR.Spec)
+		if err != nil {
+			ll.Errorf("failed to get device for volume %v: %v", volumeCR.Spec, err)
+			return statsErrorResponse(resp, status.Error(codes.Internal, "failed to get volume stats: device error"))
+		}
+		size, err := s.fsOps.GetDeviceSize(device)
+		if err != nil {
+			ll.Errorf("Unable to get size of device %s: %v", device, err)
+			return statsErrorResponse(resp, status.Error(codes.Internal, "failed to get volume stats: device size error"))
+		}
+		resp.Usage = []*csi.VolumeUsage{{Total: size, Unit: csi.VolumeUsage_BYTES}}
+		return resp, nil
+	}
+
+	stats, err := s.fsOps.GetFSStats(req.GetVolumePath())
+	if err != nil {
+		ll.Errorf("Unable to get file system stats for %s: %v", req.GetVolumePath(), err)
+		return statsErrorResponse(resp, status.Error(codes.Internal, "failed to get volume stats: statfs error"))
+	}
+	resp.Usage = []*csi.VolumeUsage{
+		{
+			Total:     stats.TotalBytes,
+			Available: stats.AvailableBytes,
+			Used:      stats.UsedBytes,
+			Unit:      csi.VolumeUsage_BYTES,
+		},
+		{
+			Total:     stats.TotalInodes,
+			Available: stats.FreeInodes,
+			Used:      stats.UsedInodes,
+			Unit:      csi.VolumeUsage_INODES,
+		},
+	}
+
+	return resp, nil
 }
 
-// NodeExpandVolume returns empty response
+// statsErrorResponse returns response with volume condition only if volume is abnormal, because usage for such
+// volumes couldn't be collected, and provided error otherwise
+func statsErrorResponse(resp *csi.NodeGetVolumeStatsResponse, err error) (*csi.NodeGetVolumeStatsResponse, error) {
+	if resp.GetVolumeCondition().GetAbnormal() {
+		return resp, nil
+	}
+	return nil, err
+}
+
+// NodeExpandVolume is the implementation of CSI Spec NodeExpandVolume. Size of the volume CR is already increased
+// by ControllerExpandVolume, this method extends logical volume up to that size and grows file system on it.
+// Receives golang context and CSI Spec NodeExpandVolumeRequest
+// Returns CSI Spec NodeExpandVolumeResponse or error if something went wrong
 func (s *CSINodeService) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
-	return &csi.NodeExpandVolumeResponse{}, nil
+	ll := s.log.WithFields(logrus.Fields{
+		"method":   "NodeExpandVolume",
+		"volumeID": req.GetVolumeId(),
+	})
+
+	ll.Infof("locking volume on request: %v", req)
+	s.volMu.LockKey(req.GetVolumeId())
+	defer func() {
+		err := s.volMu.UnlockKey(req.GetVolumeId())
+		if err != nil {
+			ll.Warnf("Unlocking  volume with error %s", err)
+		}
+	}()
+
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume ID missing in request")
+	}
+	if len(req.GetVolumePath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Volume path missing in request")
+	}
+
+	volumeCR := s.crHelper.GetVolumeByID(req.GetVolumeId())
+	if volumeCR == nil {
+		message := fmt.Sprintf("Unable to find volume with ID %s", req.GetVolumeId())
+		ll.Error(message)
+		return nil, status.Error(codes.NotFound, message)
+	}
+
+	vol := volumeCR.Spec
+	if vol.LocationType != apiV1.LocationTypeLVM {
+		ll.Infof("Volume with location type %s can't be expanded, nothing to do", vol.LocationType)
+		return &csi.NodeExpandVolumeResponse{CapacityBytes: vol.Size}, nil
+	}
+
+	device, err := s.getProvisionerForVolume(&vol).GetVolumePath(vol)
+	if err != nil {
+		ll.Errorf("failed to get device for volume %v: %v", vol, err)
+		return nil, status.Error(codes.Internal, "failed to expand volume: device error")
+	}
+
+	size, _ := util.ToSizeUnit(vol.Size, util.BYTE, util.MBYTE)
+	sizeStr := strconv.FormatInt(size, 10) + "m"
+	ll.Infof("Resizing LV %s up to %s", device, sizeStr)
+	if err = s.lvmOps.LVResize(device, sizeStr); err != nil {
+		ll.Errorf("Unable to resize LV %s: %v", device, err)
+		return nil, status.Error(codes.Internal, "failed to expand volume: unable to resize logical volume")
+	}
+
+	if vol.Encrypted {
+		name := p.GetLUKSMappingName(vol.Id)
+		if err = s.cryptOps.LuksResize(name); err != nil {
+			ll.Errorf("Unable to resize encrypted device %s: %v", name, err)
+			return nil, status.Error(codes.Internal, "failed to expand volume: unable to resize encrypted device")
+		}
+		device = cryptsetup.GetMappingPath(name)
+	}
+
+	if vol.Mode != apiV1.ModeRAW {
+		if err = s.fsOps.GrowFS(fs.FileSystem(vol.Type), device, req.GetVolumePath()); err != nil {
+			ll.Errorf("Unable to grow file system on %s: %v", device, err)
+			return nil, status.Error(codes.Internal, "failed to expand volume: unable to grow file system")
+		}
+	}
+
+	ll.Infof("Volume was expanded up to %d bytes", vol.Size)
+
+	return &csi.NodeExpandVolumeResponse{CapacityBytes: vol.Size}, nil
 }
 
 // NodeGetCapabilities is the implementation of CSI Spec NodeGetCapabilities.
-// Provides Node capabilities of CSI driver to k8s. STAGE/UNSTAGE Volume for now.
+// Provides Node capabilities of CSI driver to k8s: STAGE/UNSTAGE, EXPAND, GET_VOLUME_STATS, VOLUME_CONDITION
+// and SINGLE_NODE_MULTI_WRITER.
 // Receives golang context and CSI Spec NodeGetCapabilitiesRequest
 // Returns CSI Spec NodeGetCapabilitiesResponse and nil error
 func (s *CSINodeService) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
-	return &csi.NodeGetCapabilitiesResponse{Capabilities: []*csi.NodeServiceCapability{
-		{
+	newCap := func(cap csi.NodeServiceCapability_RPC_Type) *csi.NodeServiceCapability {
+		return &csi.NodeServiceCapability{
 			Type: &csi.NodeServiceCapability_Rpc{
 				Rpc: &csi.NodeServiceCapability_RPC{
-					Type: csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+					Type: cap,
 				},
 			},
-		}},
-	}, nil
+		}
+	}
+
+	caps := make([]*csi.NodeServiceCapability, 0)
+	for _, c := range []csi.NodeServiceCapability_RPC_Type{
+		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+		csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
+	} {
+		caps = append(caps, newCap(c))
+	}
+
+	return &csi.NodeGetCapabilitiesResponse{Capabilities: caps}, nil
 }
 
 // NodeGetInfo is the implementation of CSI Spec NodeGetInfo. It plays a role in CSI Topology feature when Controller
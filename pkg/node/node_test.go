@@ -19,6 +19,7 @@ package node
 import (
 	"errors"
 	"fmt"
+	"path"
 	"testing"
 	"time"
 
@@ -37,8 +38,12 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/featureconfig"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/cgroup"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/cryptsetup"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/crcontrollers/csibmnode"
 	"github.com/dell/csi-baremetal/pkg/mocks"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
 	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
 	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
 	"github.com/dell/csi-baremetal/pkg/testutils"
@@ -80,7 +85,7 @@ var _ = Describe("CSINodeService NodePublish()", func() {
 			req.VolumeContext[PodNameKey] = testPodName
 
 			fsOps.On("PrepareAndPerformMount",
-				req.GetStagingTargetPath(), req.GetTargetPath(), true).
+				req.GetStagingTargetPath(), req.GetTargetPath(), true, true, mock.Anything).
 				Return(nil)
 
 			resp, err := node.NodePublishVolume(testCtx, req)
@@ -91,7 +96,7 @@ var _ = Describe("CSINodeService NodePublish()", func() {
 			volumeCR := &vcrd.Volume{}
 			err = node.k8sClient.ReadCR(testCtx, testV1ID, volumeCR)
 			Expect(err).To(BeNil())
-			//Expect(volumeCR.Spec.Owners[0]).To(Equal(testPodName))
+			Expect(volumeCR.Spec.Owners).To(Equal([]string{targetPath}))
 
 			// publish again such volume
 			resp, err = node.NodePublishVolume(testCtx, req)
@@ -102,11 +107,183 @@ var _ = Describe("CSINodeService NodePublish()", func() {
 			volumeCR = &vcrd.Volume{}
 			err = node.k8sClient.ReadCR(testCtx, testV1ID, volumeCR)
 			Expect(err).To(BeNil())
-			//Expect(len(volumeCR.Spec.Owners)).To(Equal(1))
+			Expect(len(volumeCR.Spec.Owners)).To(Equal(1))
+		})
+		It("Should publish volume for several pods", func() {
+			podTargetPath := func(podUID string) string {
+				return path.Join(base.KubeletRootPath, podUID, "volumes/kubernetes.io~csi", testV1ID, "mount")
+			}
+			for _, podUID := range []string{"pod-uid-1", "pod-uid-2"} {
+				req := getNodePublishRequest(testV1ID, podTargetPath(podUID), *testVolumeCap)
+				fsOps.On("PrepareAndPerformMount",
+					req.GetStagingTargetPath(), req.GetTargetPath(), true, true, mock.Anything).
+					Return(nil)
+
+				resp, err := node.NodePublishVolume(testCtx, req)
+				Expect(resp).NotTo(BeNil())
+				Expect(err).To(BeNil())
+			}
+
+			volumeCR := &vcrd.Volume{}
+			err := node.k8sClient.ReadCR(testCtx, testV1ID, volumeCR)
+			Expect(err).To(BeNil())
+			Expect(volumeCR.Spec.Owners).To(Equal([]string{"pod-uid-1", "pod-uid-2"}))
+			Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.Published))
+		})
+		It("Should publish and unpublish volume with I/O limits", func() {
+			var (
+				cgroupOps     = &mocklu.MockWrapCgroup{}
+				limits        = cgroup.IOLimits{ReadBps: 1048576, WriteBps: 2097152}
+				podUID        = "pod-uid-1"
+				podTargetPath = path.Join(base.KubeletRootPath, podUID, "volumes/kubernetes.io~csi", testV1ID, "mount")
+				devicePath    = "/dev/sda1"
+			)
+			node.cgroupOps = cgroupOps
+			vol1 := testVolumeCR1
+			vol1.Spec.ReadBps, vol1.Spec.WriteBps = limits.ReadBps, limits.WriteBps
+			err := node.k8sClient.UpdateCR(testCtx, &vol1)
+			Expect(err).To(BeNil())
+
+			req := getNodePublishRequest(testV1ID, podTargetPath, *testVolumeCap)
+			fsOps.On("PrepareAndPerformMount",
+				req.GetStagingTargetPath(), req.GetTargetPath(), true, true, mock.Anything).
+				Return(nil)
+			prov.On("GetVolumePath", mock.Anything).Return(devicePath, nil)
+			cgroupOps.On("SetIOLimits", podUID, devicePath, limits).Return(nil).Once()
+
+			resp, err := node.NodePublishVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+
+			fsOps.On("UnmountWithCheck", podTargetPath).Return(nil)
+			cgroupOps.On("ClearIOLimits", podUID, devicePath).Return(nil).Once()
+			unpublishResp, err := node.NodeUnpublishVolume(testCtx, getNodeUnpublishRequest(testV1ID, podTargetPath))
+			Expect(unpublishResp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+			cgroupOps.AssertExpectations(GinkgoT())
+		})
+		It("Should fail to publish volume when I/O limits weren't applied", func() {
+			var (
+				cgroupOps     = &mocklu.MockWrapCgroup{}
+				limits        = cgroup.IOLimits{WriteIops: 100}
+				podUID        = "pod-uid-1"
+				podTargetPath = path.Join(base.KubeletRootPath, podUID, "volumes/kubernetes.io~csi", testV1ID, "mount")
+				devicePath    = "/dev/sda1"
+			)
+			node.cgroupOps = cgroupOps
+			vol1 := testVolumeCR1
+			vol1.Spec.WriteIops = limits.WriteIops
+			err := node.k8sClient.UpdateCR(testCtx, &vol1)
+			Expect(err).To(BeNil())
+
+			req := getNodePublishRequest(testV1ID, podTargetPath, *testVolumeCap)
+			fsOps.On("PrepareAndPerformMount",
+				req.GetStagingTargetPath(), req.GetTargetPath(), true, true, mock.Anything).
+				Return(nil)
+			prov.On("GetVolumePath", mock.Anything).Return(devicePath, nil)
+			cgroupOps.On("SetIOLimits", podUID, devicePath, limits).Return(errors.New("error")).Once()
+
+			resp, err := node.NodePublishVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(err).NotTo(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+
+			volumeCR := &vcrd.Volume{}
+			err = node.k8sClient.ReadCR(testCtx, testV1ID, volumeCR)
+			Expect(err).To(BeNil())
+			Expect(volumeCR.Spec.Owners).To(Equal([]string{podUID}))
+		})
+		It("Should publish volume as read-only", func() {
+			req := getNodePublishRequest(testV1ID, targetPath, *testVolumeCap)
+			req.Readonly = true
+			fsOps.On("PrepareAndPerformMount",
+				req.GetStagingTargetPath(), req.GetTargetPath(), true, true, []string{fs.ReadOnlyOption}).
+				Return(nil)
+
+			resp, err := node.NodePublishVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+
+			// read-only access mode
+			volumeCap := *testVolumeCap
+			volumeCap.AccessMode = &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY,
+			}
+			req = getNodePublishRequest(testV1ID, targetPath, volumeCap)
+			resp, err = node.NodePublishVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+			fsOps.AssertNumberOfCalls(GinkgoT(), "PrepareAndPerformMount", 2)
+		})
+	})
+
+	Context("NodePublish() success for volume in RAW mode", func() {
+		It("Should publish volume to the file", func() {
+			req := getNodePublishRequest(testV1ID, targetPath, *testBlockVolumeCap)
+			vol1 := testVolumeCR1
+			vol1.Spec.Mode = apiV1.ModeRAW
+			err := node.k8sClient.UpdateCR(testCtx, &vol1)
+			Expect(err).To(BeNil())
+
+			fsOps.On("PrepareAndPerformMount",
+				path.Join(req.GetStagingTargetPath(), testV1ID), req.GetTargetPath(), true, false, mock.Anything).
+				Return(nil)
+
+			resp, err := node.NodePublishVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+		})
+		It("Should apply I/O limits for pod from target path of the block volume", func() {
+			var (
+				cgroupOps  = &mocklu.MockWrapCgroup{}
+				limits     = cgroup.IOLimits{ReadIops: 100}
+				podUID     = "pod-uid-1"
+				devicePath = "/dev/sda1"
+				blockPath  = path.Join("/var/lib/kubelet/plugins/kubernetes.io/csi",
+					base.KubeletBlockPublishDir, testV1ID, podUID)
+			)
+			node.cgroupOps = cgroupOps
+			vol1 := testVolumeCR1
+			vol1.Spec.Mode = apiV1.ModeRAW
+			vol1.Spec.ReadIops = limits.ReadIops
+			err := node.k8sClient.UpdateCR(testCtx, &vol1)
+			Expect(err).To(BeNil())
+
+			req := getNodePublishRequest(testV1ID, blockPath, *testBlockVolumeCap)
+			fsOps.On("PrepareAndPerformMount",
+				path.Join(req.GetStagingTargetPath(), testV1ID), blockPath, true, false, mock.Anything).
+				Return(nil)
+			prov.On("GetVolumePath", mock.Anything).Return(devicePath, nil)
+			cgroupOps.On("SetIOLimits", podUID, devicePath, limits).Return(nil).Once()
+
+			resp, err := node.NodePublishVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+			cgroupOps.AssertExpectations(GinkgoT())
+
+			// pod couldn't be determined from target path
+			req = getNodePublishRequest(testV1ID, targetPath, *testBlockVolumeCap)
+			fsOps.On("PrepareAndPerformMount",
+				path.Join(req.GetStagingTargetPath(), testV1ID), targetPath, true, false, mock.Anything).
+				Return(nil)
+			resp, err = node.NodePublishVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
 		})
 	})
 
 	Context("NodePublish() failure", func() {
+		It("Should fail with unsupported access mode", func() {
+			volumeCap := *testVolumeCap
+			volumeCap.AccessMode = &csi.VolumeCapability_AccessMode{
+				Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			}
+			req := getNodePublishRequest(testV1ID, targetPath, volumeCap)
+
+			resp, err := node.NodePublishVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
 		It("Should fail with missing volume capabilities", func() {
 			req := &csi.NodePublishVolumeRequest{}
 
@@ -175,7 +352,7 @@ var _ = Describe("CSINodeService NodePublish()", func() {
 			req := getNodePublishRequest(testV1ID, targetPath, *testVolumeCap)
 
 			fsOps.On("PrepareAndPerformMount",
-				req.GetStagingTargetPath(), req.GetTargetPath(), true).
+				req.GetStagingTargetPath(), req.GetTargetPath(), true, true, mock.Anything).
 				Return(errors.New("error mount"))
 
 			resp, err := node.NodePublishVolume(testCtx, req)
@@ -198,7 +375,7 @@ var _ = Describe("CSINodeService NodeStage()", func() {
 			partitionPath := "/partition/path/for/volume1"
 			prov.On("GetVolumePath", testVolume2).Return(partitionPath, nil)
 			fsOps.On("PrepareAndPerformMount",
-				partitionPath, req.GetStagingTargetPath(), false).
+				partitionPath, req.GetStagingTargetPath(), false, true, mock.Anything).
 				Return(nil)
 
 			resp, err := node.NodeStageVolume(testCtx, req)
@@ -210,6 +387,23 @@ var _ = Describe("CSINodeService NodeStage()", func() {
 			Expect(err).To(BeNil())
 			Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.VolumeReady))
 		})
+		It("Should stage volume in RAW mode to the file", func() {
+			req := getNodeStageRequest(testVolume2.Id, *testBlockVolumeCap)
+			vol2 := testVolumeCR2
+			vol2.Spec.Mode = apiV1.ModeRAW
+			err := node.k8sClient.UpdateCR(testCtx, &vol2)
+			Expect(err).To(BeNil())
+
+			partitionPath := "/partition/path/for/volume2"
+			prov.On("GetVolumePath", vol2.Spec).Return(partitionPath, nil)
+			fsOps.On("PrepareAndPerformMount",
+				partitionPath, path.Join(req.GetStagingTargetPath(), testVolume2.Id), true, false, mock.Anything).
+				Return(nil)
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+		})
 		It("Should stage, volume CR with VolumeReady status", func() {
 			req := getNodeStageRequest(testVolume1.Id, *testVolumeCap)
 			vol1 := testVolumeCR1
@@ -219,16 +413,168 @@ var _ = Describe("CSINodeService NodeStage()", func() {
 			partitionPath := "/partition/path/for/volume1"
 			prov.On("GetVolumePath", vol1.Spec).Return(partitionPath, nil)
 			fsOps.On("PrepareAndPerformMount",
-				partitionPath, req.GetStagingTargetPath(), false).
+				partitionPath, req.GetStagingTargetPath(), false, true, mock.Anything).
+				Return(nil)
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+		})
+		It("Should stage volume with mount options", func() {
+			vol2 := testVolumeCR2
+			vol2.Spec.Type = string(fs.XFS)
+			err := node.k8sClient.UpdateCR(testCtx, &vol2)
+			Expect(err).To(BeNil())
+
+			volumeCap := csi.VolumeCapability{AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{FsType: string(fs.XFS), MountFlags: []string{"noatime", "prjquota"}},
+			}}
+			req := getNodeStageRequest(testVolume2.Id, volumeCap)
+			partitionPath := "/partition/path/for/volume2"
+			prov.On("GetVolumePath", vol2.Spec).Return(partitionPath, nil)
+			fsOps.On("PrepareAndPerformMount",
+				partitionPath, req.GetStagingTargetPath(), false, true, []string{"noatime", "prjquota"}).
+				Return(nil)
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+		})
+		It("Should stage copy of xfs volume with nouuid and grow its FS", func() {
+			vol2 := testVolumeCR2
+			vol2.Spec.Type = string(fs.XFS)
+			vol2.Spec.ContentSourceType, vol2.Spec.ContentSourceId = apiV1.ContentSourceVolume, testVolume1.Id
+			err := node.k8sClient.UpdateCR(testCtx, &vol2)
+			Expect(err).To(BeNil())
+
+			req := getNodeStageRequest(testVolume2.Id, *testVolumeCap)
+			partitionPath := "/partition/path/for/volume2"
+			prov.On("GetVolumePath", vol2.Spec).Return(partitionPath, nil)
+			fsOps.On("PrepareAndPerformMount",
+				partitionPath, req.GetStagingTargetPath(), false, true, []string{"nouuid"}).
+				Return(nil)
+			fsOps.On("GrowFS", fs.XFS, partitionPath, req.GetStagingTargetPath()).Return(nil).Times(1)
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+			fsOps.AssertCalled(GinkgoT(), "GrowFS", fs.XFS, partitionPath, req.GetStagingTargetPath())
+		})
+		It("Should open encrypted volume and stage its mapping", func() {
+			vol2 := testVolumeCR2
+			vol2.Spec.Encrypted = true
+			err := node.k8sClient.UpdateCR(testCtx, &vol2)
+			Expect(err).To(BeNil())
+			cryptOps := &mocklu.MockWrapCryptsetup{}
+			node.cryptOps = cryptOps
+
+			req := getNodeStageRequest(testVolume2.Id, *testVolumeCap)
+			req.Secrets = map[string]string{base.PassphraseKey: "secret"}
+			partitionPath := "/partition/path/for/volume2"
+			mapping := p.GetLUKSMappingName(testVolume2.Id)
+			prov.On("GetVolumePath", vol2.Spec).Return(partitionPath, nil)
+			cryptOps.On("IsOpened", mapping).Return(false)
+			fsOps.On("GetFSType", partitionPath).Return(fs.FileSystem(cryptsetup.LuksSignature), nil)
+			cryptOps.On("LuksOpen", partitionPath, mapping, "secret").Return(nil).Times(1)
+			fsOps.On("GetFSType", cryptsetup.GetMappingPath(mapping)).Return(fs.XFS, nil)
+			fsOps.On("PrepareAndPerformMount",
+				cryptsetup.GetMappingPath(mapping), req.GetStagingTargetPath(), false, true, mock.Anything).
+				Return(nil)
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+			cryptOps.AssertExpectations(GinkgoT())
+			cryptOps.AssertNotCalled(GinkgoT(), "LuksFormat", mock.Anything, mock.Anything)
+			fsOps.AssertNotCalled(GinkgoT(), "CreateFS", mock.Anything, mock.Anything, mock.Anything)
+		})
+		It("Should format encrypted volume with LUKS and FS on the first staging", func() {
+			vol2 := testVolumeCR2
+			vol2.Spec.Encrypted = true
+			err := node.k8sClient.UpdateCR(testCtx, &vol2)
+			Expect(err).To(BeNil())
+			cryptOps := &mocklu.MockWrapCryptsetup{}
+			node.cryptOps = cryptOps
+
+			req := getNodeStageRequest(testVolume2.Id, *testVolumeCap)
+			req.Secrets = map[string]string{base.PassphraseKey: "secret"}
+			partitionPath := "/partition/path/for/volume2"
+			mapping := p.GetLUKSMappingName(testVolume2.Id)
+			prov.On("GetVolumePath", vol2.Spec).Return(partitionPath, nil)
+			cryptOps.On("IsOpened", mapping).Return(false)
+			fsOps.On("GetFSType", partitionPath).Return(fs.FileSystem(""), nil)
+			cryptOps.On("LuksFormat", partitionPath, "secret").Return(nil).Times(1)
+			cryptOps.On("LuksOpen", partitionPath, mapping, "secret").Return(nil).Times(1)
+			fsOps.On("GetFSType", cryptsetup.GetMappingPath(mapping)).Return(fs.FileSystem(""), nil)
+			fsOps.On("CreateFS", fs.FileSystem(vol2.Spec.Type), cryptsetup.GetMappingPath(mapping), mock.Anything).
+				Return(nil).Times(1)
+			fsOps.On("PrepareAndPerformMount",
+				cryptsetup.GetMappingPath(mapping), req.GetStagingTargetPath(), false, true, mock.Anything).
 				Return(nil)
 
 			resp, err := node.NodeStageVolume(testCtx, req)
 			Expect(resp).NotTo(BeNil())
 			Expect(err).To(BeNil())
+			cryptOps.AssertExpectations(GinkgoT())
+			fsOps.AssertExpectations(GinkgoT())
 		})
 	})
 
 	Context("NodeStage() failure", func() {
+		It("Should fail for encrypted volume without passphrase in secrets", func() {
+			vol2 := testVolumeCR2
+			vol2.Spec.Encrypted = true
+			err := node.k8sClient.UpdateCR(testCtx, &vol2)
+			Expect(err).To(BeNil())
+			cryptOps := &mocklu.MockWrapCryptsetup{}
+			node.cryptOps = cryptOps
+
+			req := getNodeStageRequest(testVolume2.Id, *testVolumeCap)
+			prov.On("GetVolumePath", vol2.Spec).Return("/partition/path/for/volume2", nil)
+			cryptOps.On("IsOpened", p.GetLUKSMappingName(testVolume2.Id)).Return(false)
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			fsOps.AssertNotCalled(GinkgoT(), "PrepareAndPerformMount",
+				mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+		It("Should fail for encrypted volume whose device has foreign signature", func() {
+			vol2 := testVolumeCR2
+			vol2.Spec.Encrypted = true
+			err := node.k8sClient.UpdateCR(testCtx, &vol2)
+			Expect(err).To(BeNil())
+			cryptOps := &mocklu.MockWrapCryptsetup{}
+			node.cryptOps = cryptOps
+
+			req := getNodeStageRequest(testVolume2.Id, *testVolumeCap)
+			req.Secrets = map[string]string{base.PassphraseKey: "secret"}
+			partitionPath := "/partition/path/for/volume2"
+			prov.On("GetVolumePath", vol2.Spec).Return(partitionPath, nil)
+			cryptOps.On("IsOpened", p.GetLUKSMappingName(testVolume2.Id)).Return(false)
+			fsOps.On("GetFSType", partitionPath).Return(fs.EXT4, nil)
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+			cryptOps.AssertNotCalled(GinkgoT(), "LuksFormat", mock.Anything, mock.Anything)
+		})
+		It("Should fail with mount options which aren't allowed for file system", func() {
+			vol2 := testVolumeCR2
+			vol2.Spec.Type = string(fs.EXT4)
+			err := node.k8sClient.UpdateCR(testCtx, &vol2)
+			Expect(err).To(BeNil())
+
+			volumeCap := csi.VolumeCapability{AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{MountFlags: []string{"compress=zstd"}},
+			}}
+			req := getNodeStageRequest(testVolume2.Id, volumeCap)
+			prov.On("GetVolumePath", vol2.Spec).Return("/partition/path/for/volume2", nil)
+
+			resp, err := node.NodeStageVolume(testCtx, req)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
 		It("Should fail with missing volume capabilities", func() {
 			req := &csi.NodeStageVolumeRequest{}
 
@@ -285,7 +631,7 @@ var _ = Describe("CSINodeService NodeStage()", func() {
 			partitionPath := "/partition/path/for/volume1"
 			prov.On("GetVolumePath", testVolume2).Return(partitionPath, nil)
 			fsOps.On("PrepareAndPerformMount",
-				partitionPath, req.GetStagingTargetPath(), false).
+				partitionPath, req.GetStagingTargetPath(), false, true, mock.Anything).
 				Return(errors.New("PrepareAndPerformMount error"))
 
 			resp, err := node.NodeStageVolume(testCtx, req)
@@ -302,7 +648,7 @@ var _ = Describe("CSINodeService NodeStage()", func() {
 			partitionPath := "/partition/path/for/volume1"
 			prov.On("GetVolumePath", vol1.Spec).Return(partitionPath, nil)
 			fsOps.On("PrepareAndPerformMount",
-				partitionPath, req.GetStagingTargetPath(), false).
+				partitionPath, req.GetStagingTargetPath(), false, true, mock.Anything).
 				Return(errors.New("mount error"))
 
 			resp, err := node.NodeStageVolume(testCtx, req)
@@ -343,24 +689,39 @@ var _ = Describe("CSINodeService NodeUnPublish()", func() {
 			Expect(err).To(BeNil())
 			Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.VolumeReady))
 		})
-		//It("Should unpublish volume and don't change volume CR status", func() {
-		//	req := getNodeUnpublishRequest(testV1ID, targetPath)
-		//	vol1 := testVolumeCR1
-		//	vol1.Spec.Owners = []string{"pod-1", "pod-2"}
-		//	vol1.Spec.CSIStatus = apiV1.Published
-		//	err := node.k8sClient.UpdateCR(testCtx, &vol1)
-		//	Expect(err).To(BeNil())
-		//	fsOps.On("UnmountWithCheck", req.GetTargetPath()).Return(nil)
-		//
-		//	resp, err := node.NodeUnpublishVolume(testCtx, req)
-		//	Expect(resp).NotTo(BeNil())
-		//	Expect(err).To(BeNil())
-		//	// check volume CR status
-		//	volumeCR := &vcrd.Volume{}
-		//	err = node.k8sClient.ReadCR(testCtx, testV1ID, volumeCR)
-		//	Expect(err).To(BeNil())
-		//	Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.Published))
-		//})
+		It("Should unpublish volume in RAW mode and remove target file", func() {
+			req := getNodeUnpublishRequest(testV1ID, targetPath)
+			vol1 := testVolumeCR1
+			vol1.Spec.Mode = apiV1.ModeRAW
+			err := node.k8sClient.UpdateCR(testCtx, &vol1)
+			Expect(err).To(BeNil())
+			fsOps.On("UnmountWithCheck", req.GetTargetPath()).Return(nil)
+			fsOps.On("RmDir", req.GetTargetPath()).Return(nil)
+
+			resp, err := node.NodeUnpublishVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+			fsOps.AssertCalled(GinkgoT(), "RmDir", req.GetTargetPath())
+		})
+		It("Should unpublish volume and don't change volume CR status", func() {
+			req := getNodeUnpublishRequest(testV1ID, targetPath)
+			vol1 := testVolumeCR1
+			vol1.Spec.Owners = []string{targetPath, "pod-2"}
+			vol1.Spec.CSIStatus = apiV1.Published
+			err := node.k8sClient.UpdateCR(testCtx, &vol1)
+			Expect(err).To(BeNil())
+			fsOps.On("UnmountWithCheck", req.GetTargetPath()).Return(nil)
+
+			resp, err := node.NodeUnpublishVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+			// check volume CR status
+			volumeCR := &vcrd.Volume{}
+			err = node.k8sClient.ReadCR(testCtx, testV1ID, volumeCR)
+			Expect(err).To(BeNil())
+			Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.Published))
+			Expect(volumeCR.Spec.Owners).To(Equal([]string{"pod-2"}))
+		})
 
 	})
 
@@ -421,9 +782,42 @@ var _ = Describe("CSINodeService NodeUnStage()", func() {
 			volumeCR := &vcrd.Volume{}
 			err = node.k8sClient.ReadCR(testCtx, testV1ID, volumeCR)
 			Expect(err).To(BeNil())
-			//Expect(volumeCR.Spec.Owners).To(BeNil())
+			Expect(volumeCR.Spec.Owners).To(BeNil())
 			Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.Created))
 		})
+		It("Should unstage volume in RAW mode and remove staging file", func() {
+			req := getNodeUnstageRequest(testV1ID, stagePath)
+			vol1 := testVolumeCR1
+			vol1.Spec.Mode = apiV1.ModeRAW
+			err := node.k8sClient.UpdateCR(testCtx, &vol1)
+			Expect(err).To(BeNil())
+			stagingFile := path.Join(req.GetStagingTargetPath(), testV1ID)
+			fsOps.On("UnmountWithCheck", stagingFile).Return(nil)
+			fsOps.On("RmDir", stagingFile).Return(nil)
+
+			resp, err := node.NodeUnstageVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+			fsOps.AssertCalled(GinkgoT(), "RmDir", stagingFile)
+		})
+		It("Should unstage encrypted volume and close its mapping", func() {
+			req := getNodeUnstageRequest(testV1ID, stagePath)
+			vol1 := testVolumeCR1
+			vol1.Spec.Encrypted = true
+			err := node.k8sClient.UpdateCR(testCtx, &vol1)
+			Expect(err).To(BeNil())
+			cryptOps := &mocklu.MockWrapCryptsetup{}
+			node.cryptOps = cryptOps
+			mapping := p.GetLUKSMappingName(testV1ID)
+			fsOps.On("UnmountWithCheck", req.GetStagingTargetPath()).Return(nil)
+			cryptOps.On("IsOpened", mapping).Return(true)
+			cryptOps.On("LuksClose", mapping).Return(nil).Times(1)
+
+			resp, err := node.NodeUnstageVolume(testCtx, req)
+			Expect(resp).NotTo(BeNil())
+			Expect(err).To(BeNil())
+			cryptOps.AssertExpectations(GinkgoT())
+		})
 	})
 
 	Context("NodeUnPublish() failure", func() {
@@ -467,7 +861,7 @@ var _ = Describe("CSINodeService NodeUnStage()", func() {
 			volumeCR := &vcrd.Volume{}
 			err = node.k8sClient.ReadCR(testCtx, testV1ID, volumeCR)
 			Expect(err).To(BeNil())
-			//Expect(volumeCR.Spec.Owners).To(BeNil())
+			Expect(volumeCR.Spec.Owners).To(BeNil())
 			Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.Failed))
 		})
 
@@ -514,7 +908,7 @@ var _ = Describe("CSINodeService NodeUnStage()", func() {
 			volumeCR := &vcrd.Volume{}
 			err = node.k8sClient.ReadCR(testCtx, testV1ID, volumeCR)
 			Expect(err).To(BeNil())
-			//Expect(volumeCR.Spec.Owners).To(BeNil())
+			Expect(volumeCR.Spec.Owners).To(BeNil())
 			Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.Created))
 		})
 	})
@@ -533,20 +927,201 @@ var _ = Describe("CSINodeService NodeGetInfo()", func() {
 })
 
 var _ = Describe("CSINodeService NodeGetCapabilities()", func() {
-	It("Should return STAGE_UNSTAGE_VOLUME capabilities", func() {
+	It("Should return node capabilities", func() {
 		node := newNodeService()
 
 		resp, err := node.NodeGetCapabilities(testCtx, &csi.NodeGetCapabilitiesRequest{})
 		Expect(err).To(BeNil())
 		Expect(resp).ToNot(BeNil())
 		capabilities := resp.GetCapabilities()
-		expectedCapability := &csi.NodeServiceCapability_Rpc{
-			Rpc: &csi.NodeServiceCapability_RPC{
-				Type: csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
-			},
+		expectedCapabilitiesTypes := []csi.NodeServiceCapability_RPC_Type{
+			csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
+			csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+			csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+			csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
+			csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 		}
-		Expect(len(capabilities)).To(Equal(1))
-		Expect(capabilities[0].Type).To(Equal(expectedCapability))
+		Expect(len(capabilities)).To(Equal(5))
+		currentCapabilitiesTypes := make([]csi.NodeServiceCapability_RPC_Type, len(capabilities))
+		for i := 0; i < len(capabilities); i++ {
+			currentCapabilitiesTypes[i] = capabilities[i].GetRpc().GetType()
+		}
+		Expect(expectedCapabilitiesTypes).To(ConsistOf(currentCapabilitiesTypes))
+	})
+})
+
+var _ = Describe("CSINodeService NodeGetVolumeStats()", func() {
+	var vol1 vcrd.Volume
+
+	BeforeEach(func() {
+		setVariables()
+		vol1 = testVolumeCR1
+		vol1.Spec.Mode = apiV1.ModeFS
+		vol1.Spec.Health = apiV1.HealthGood
+		Expect(node.k8sClient.UpdateCR(testCtx, &vol1)).To(BeNil())
+	})
+
+	Context("NodeGetVolumeStats() success", func() {
+		It("Should return bytes and inodes usage for FS volume", func() {
+			stats := &fs.FSStats{TotalBytes: 100, AvailableBytes: 60, UsedBytes: 40,
+				TotalInodes: 10, FreeInodes: 7, UsedInodes: 3}
+			fsOps.On("GetFSStats", targetPath).Return(stats, nil).Times(1)
+
+			resp, err := node.NodeGetVolumeStats(testCtx, getNodeGetVolumeStatsRequest(testV1ID))
+			Expect(err).To(BeNil())
+			Expect(resp.VolumeCondition.Abnormal).To(BeFalse())
+			Expect(resp.Usage).To(ConsistOf(
+				&csi.VolumeUsage{Total: 100, Available: 60, Used: 40, Unit: csi.VolumeUsage_BYTES},
+				&csi.VolumeUsage{Total: 10, Available: 7, Used: 3, Unit: csi.VolumeUsage_INODES},
+			))
+		})
+		It("Should return device size for RAW volume", func() {
+			devicePath := "/dev/sda1"
+			vol1.Spec.Mode = apiV1.ModeRAW
+			Expect(node.k8sClient.UpdateCR(testCtx, &vol1)).To(BeNil())
+			prov.On("GetVolumePath", vol1.Spec).Return(devicePath, nil)
+			fsOps.On("GetDeviceSize", devicePath).Return(int64(1024), nil).Times(1)
+
+			resp, err := node.NodeGetVolumeStats(testCtx, getNodeGetVolumeStatsRequest(testV1ID))
+			Expect(err).To(BeNil())
+			Expect(resp.Usage).To(ConsistOf(&csi.VolumeUsage{Total: 1024, Unit: csi.VolumeUsage_BYTES}))
+		})
+		It("Should report abnormal condition for volume with BAD health", func() {
+			vol1.Spec.Health = apiV1.HealthBad
+			Expect(node.k8sClient.UpdateCR(testCtx, &vol1)).To(BeNil())
+			fsOps.On("GetFSStats", targetPath).Return(&fs.FSStats{}, nil).Times(1)
+
+			resp, err := node.NodeGetVolumeStats(testCtx, getNodeGetVolumeStatsRequest(testV1ID))
+			Expect(err).To(BeNil())
+			Expect(resp.VolumeCondition.Abnormal).To(BeTrue())
+		})
+		It("Should report abnormal condition for missing volume even if stats are unavailable", func() {
+			vol1.Spec.OperationalStatus = apiV1.OperationalStatusMissing
+			Expect(node.k8sClient.UpdateCR(testCtx, &vol1)).To(BeNil())
+			fsOps.On("GetFSStats", targetPath).Return(nil, errors.New("error")).Times(1)
+
+			resp, err := node.NodeGetVolumeStats(testCtx, getNodeGetVolumeStatsRequest(testV1ID))
+			Expect(err).To(BeNil())
+			Expect(resp.VolumeCondition.Abnormal).To(BeTrue())
+			Expect(resp.Usage).To(BeNil())
+		})
+	})
+
+	Context("NodeGetVolumeStats() failure", func() {
+		It("Should fail with missing volume ID", func() {
+			_, err := node.NodeGetVolumeStats(testCtx, &csi.NodeGetVolumeStatsRequest{VolumePath: targetPath})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Should fail with missing volume path", func() {
+			_, err := node.NodeGetVolumeStats(testCtx, &csi.NodeGetVolumeStatsRequest{VolumeId: testV1ID})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Should fail when volume CR isn't found", func() {
+			_, err := node.NodeGetVolumeStats(testCtx, getNodeGetVolumeStatsRequest("unknown-volume"))
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+		It("Should fail when statfs failed", func() {
+			fsOps.On("GetFSStats", targetPath).Return(nil, errors.New("error")).Times(1)
+
+			_, err := node.NodeGetVolumeStats(testCtx, getNodeGetVolumeStatsRequest(testV1ID))
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+		})
+	})
+})
+
+var _ = Describe("CSINodeService NodeExpandVolume()", func() {
+	var (
+		lvmOps     *mocklu.MockWrapLVM
+		devicePath = "/dev/lvg/volume-1-id"
+		vol1       vcrd.Volume
+	)
+
+	BeforeEach(func() {
+		setVariables()
+		lvmOps = &mocklu.MockWrapLVM{}
+		node.lvmOps = lvmOps
+
+		vol1 = testVolumeCR1
+		vol1.Spec.LocationType = apiV1.LocationTypeLVM
+		vol1.Spec.Size = 1024 * 1024 * 1024
+		vol1.Spec.Type = "xfs"
+		vol1.Spec.Mode = apiV1.ModeFS
+		Expect(node.k8sClient.UpdateCR(testCtx, &vol1)).To(BeNil())
+	})
+
+	Context("NodeExpandVolume() success", func() {
+		It("Should expand LV and grow file system", func() {
+			prov.On("GetVolumePath", vol1.Spec).Return(devicePath, nil)
+			lvmOps.On("LVResize", devicePath, "1024m").Return(nil).Times(1)
+			fsOps.On("GrowFS", fs.XFS, devicePath, targetPath).Return(nil).Times(1)
+
+			resp, err := node.NodeExpandVolume(testCtx, getNodeExpandRequest(testV1ID))
+			Expect(err).To(BeNil())
+			Expect(resp.CapacityBytes).To(Equal(vol1.Spec.Size))
+		})
+		It("Should expand LV in RAW mode without growing file system", func() {
+			vol1.Spec.Mode = apiV1.ModeRAW
+			Expect(node.k8sClient.UpdateCR(testCtx, &vol1)).To(BeNil())
+			prov.On("GetVolumePath", vol1.Spec).Return(devicePath, nil)
+			lvmOps.On("LVResize", devicePath, "1024m").Return(nil).Times(1)
+
+			resp, err := node.NodeExpandVolume(testCtx, getNodeExpandRequest(testV1ID))
+			Expect(err).To(BeNil())
+			Expect(resp.CapacityBytes).To(Equal(vol1.Spec.Size))
+			fsOps.AssertNotCalled(GinkgoT(), "GrowFS", mock.Anything, mock.Anything, mock.Anything)
+		})
+		It("Should expand LV, resize encrypted device and grow file system on its mapping", func() {
+			vol1.Spec.Encrypted = true
+			Expect(node.k8sClient.UpdateCR(testCtx, &vol1)).To(BeNil())
+			cryptOps := &mocklu.MockWrapCryptsetup{}
+			node.cryptOps = cryptOps
+			mapping := p.GetLUKSMappingName(testV1ID)
+			prov.On("GetVolumePath", vol1.Spec).Return(devicePath, nil)
+			lvmOps.On("LVResize", devicePath, "1024m").Return(nil).Times(1)
+			cryptOps.On("LuksResize", mapping).Return(nil).Times(1)
+			fsOps.On("GrowFS", fs.XFS, cryptsetup.GetMappingPath(mapping), targetPath).Return(nil).Times(1)
+
+			resp, err := node.NodeExpandVolume(testCtx, getNodeExpandRequest(testV1ID))
+			Expect(err).To(BeNil())
+			Expect(resp.CapacityBytes).To(Equal(vol1.Spec.Size))
+			cryptOps.AssertExpectations(GinkgoT())
+		})
+		It("Should return current size for drive based volume", func() {
+			resp, err := node.NodeExpandVolume(testCtx, getNodeExpandRequest(testV2ID))
+			Expect(err).To(BeNil())
+			Expect(resp.CapacityBytes).To(Equal(testVolume2.Size))
+			lvmOps.AssertNotCalled(GinkgoT(), "LVResize", mock.Anything, mock.Anything)
+		})
+	})
+
+	Context("NodeExpandVolume() failure", func() {
+		It("Should fail with missing volume ID", func() {
+			_, err := node.NodeExpandVolume(testCtx, &csi.NodeExpandVolumeRequest{VolumePath: targetPath})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Should fail with missing volume path", func() {
+			_, err := node.NodeExpandVolume(testCtx, &csi.NodeExpandVolumeRequest{VolumeId: testV1ID})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+		It("Should fail when volume CR isn't found", func() {
+			_, err := node.NodeExpandVolume(testCtx, getNodeExpandRequest("unknown-volume"))
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+		It("Should fail when LVResize failed", func() {
+			prov.On("GetVolumePath", vol1.Spec).Return(devicePath, nil)
+			lvmOps.On("LVResize", devicePath, "1024m").Return(errors.New("error")).Times(1)
+
+			_, err := node.NodeExpandVolume(testCtx, getNodeExpandRequest(testV1ID))
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+		})
+		It("Should fail when GrowFS failed", func() {
+			prov.On("GetVolumePath", vol1.Spec).Return(devicePath, nil)
+			lvmOps.On("LVResize", devicePath, "1024m").Return(nil).Times(1)
+			fsOps.On("GrowFS", fs.XFS, devicePath, targetPath).Return(errors.New("error")).Times(1)
+
+			_, err := node.NodeExpandVolume(testCtx, getNodeExpandRequest(testV1ID))
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+		})
 	})
 })
 
@@ -604,7 +1179,7 @@ var _ = Describe("CSINodeService InlineVolumes", func() {
 
 			volOps.On("CreateVolume", mock.Anything, mock.Anything).Return(&createdVolCR.Spec, nil)
 			prov.On("GetVolumePath", createdVolCR.Spec).Return(srcPath, nil)
-			fsOps.On("PrepareAndPerformMount", srcPath, req.GetTargetPath(), false).Return(nil)
+			fsOps.On("PrepareAndPerformMount", srcPath, req.GetTargetPath(), false, true, mock.Anything).Return(nil)
 
 			resp, err := node.NodePublishVolume(testCtx, req)
 			Expect(resp).NotTo(BeNil())
@@ -615,7 +1190,7 @@ var _ = Describe("CSINodeService InlineVolumes", func() {
 			Expect(err).To(BeNil())
 
 			Expect(volumeCR.Spec.CSIStatus).To(Equal(apiV1.Published))
-			//Expect(volumeCR.Spec.Owners[0]).To(Equal(testPodName))
+			Expect(volumeCR.Spec.Owners).To(Equal([]string{targetPath}))
 		})
 		It("Should fail to create inline volume in CreateVolume step", func() {
 			req := getNodePublishRequest(testV1ID, targetPath, *testVolumeCap)
@@ -717,6 +1292,20 @@ func getNodeUnstageRequest(volumeID, stagePath string) *csi.NodeUnstageVolumeReq
 	}
 }
 
+func getNodeGetVolumeStatsRequest(volumeID string) *csi.NodeGetVolumeStatsRequest {
+	return &csi.NodeGetVolumeStatsRequest{
+		VolumeId:   volumeID,
+		VolumePath: targetPath,
+	}
+}
+
+func getNodeExpandRequest(volumeID string) *csi.NodeExpandVolumeRequest {
+	return &csi.NodeExpandVolumeRequest{
+		VolumeId:   volumeID,
+		VolumePath: targetPath,
+	}
+}
+
 func newNodeService() *CSINodeService {
 	client := mocks.NewMockDriveMgrClient(mocks.DriveMgrRespDrives)
 	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
@@ -20,13 +20,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/cryptsetup"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/partitionhelper"
@@ -49,6 +52,8 @@ type DriveProvisioner struct {
 	fsOps fs.WrapFS
 	// partOps uses for operations with partitions
 	partOps uw.PartitionOperations
+	// luksOps uses for LUKS encryption of the partitions
+	luksOps luksOperations
 
 	k8sClient *k8s.KubeClient
 	crHelper  *k8s.CRHelper
@@ -65,6 +70,7 @@ func NewDriveProvisioner(
 		listBlk:   lsblk.NewLSBLK(log),
 		fsOps:     fs.NewFSImpl(e),
 		partOps:   uw.NewPartitionOperationsImpl(e, log),
+		luksOps:   luksOperations{cryptOps: cryptsetup.NewCryptsetup(e, log)},
 		k8sClient: k,
 		crHelper:  k8s.NewCRHelper(k, log),
 		log:       log.WithField("component", "DriveProvisioner"),
@@ -72,6 +78,7 @@ func NewDriveProvisioner(
 }
 
 // PrepareVolume create partition and FS based on vol attributes.
+// FS isn't created for volumes in RAW mode and for encrypted volumes, they are formatted with LUKS on staging.
 // After that partition is ready for mount operations
 func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
 	ll := d.log.WithFields(logrus.Fields{
@@ -115,8 +122,18 @@ func (d *DriveProvisioner) PrepareVolume(vol api.Volume) error {
 	}
 	ll.Infof("Partition was created successfully %v", partPtr)
 
+	if vol.Encrypted {
+		ll.Infof("Volume is encrypted, partition is formatted with LUKS on staging")
+		return nil
+	}
+
+	if vol.Mode == apiV1.ModeRAW {
+		ll.Infof("Volume mode is %s, skip FS creation", vol.Mode)
+		return nil
+	}
+
 	// create FS
-	return d.fsOps.CreateFS(fs.FileSystem(vol.Type), partPtr.GetFullPath())
+	return d.fsOps.CreateFS(fs.FileSystem(vol.Type), partPtr.GetFullPath(), strings.Fields(vol.MkfsOptions)...)
 }
 
 // ReleaseVolume remove FS and partition based on vol attributes.
@@ -166,6 +183,13 @@ func (d *DriveProvisioner) ReleaseVolume(vol api.Volume) error {
 			fmt.Errorf("unable to find partition name for volume %s", vol.Id), ll)
 	}
 
+	// destroy LUKS key slots of the encrypted volume
+	if vol.Encrypted {
+		if err = d.luksOps.eraseLUKS(&vol, part.GetFullPath()); err != nil {
+			return err
+		}
+	}
+
 	// wipe FS on partition
 	if err = d.fsOps.WipeFS(part.GetFullPath()); err != nil {
 		return err
@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
 	"github.com/dell/csi-baremetal/api/v1/drivecrd"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
@@ -89,11 +90,26 @@ func TestDriveProvisioner_PrepareVolume_Success(t *testing.T) {
 		mock.MatchedBy(func(d *drivecrd.Drive) bool { return d.Name == testDriveCR.Name })).
 		Return(device, nil)
 	mockPH.On("PreparePartition", part).Return(&expectedPart, nil)
-	mockFS.On("CreateFS", fs.FileSystem(testVolume2.Type), expectedPart.GetFullPath()).
+	mockFS.On("CreateFS", fs.FileSystem(testVolume2.Type), expectedPart.GetFullPath(), mock.Anything).
 		Return(nil)
 
 	err = dp.PrepareVolume(testVolume2)
 	assert.Nil(t, err)
+
+	// volume in RAW mode, FS shouldn't be created
+	rawVolume := testVolume2
+	rawVolume.Mode = apiV1.ModeRAW
+	rawVolume.Type = ""
+	err = dp.PrepareVolume(rawVolume)
+	assert.Nil(t, err)
+	mockFS.AssertNumberOfCalls(t, "CreateFS", 1)
+
+	// partition of encrypted volume is formatted with LUKS and FS on staging
+	encrypted := testVolume2
+	encrypted.Encrypted = true
+	err = dp.PrepareVolume(encrypted)
+	assert.Nil(t, err)
+	mockFS.AssertNumberOfCalls(t, "CreateFS", 1)
 }
 
 func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
@@ -134,7 +150,7 @@ func TestDriveProvisioner_PrepareVolume_Fail(t *testing.T) {
 	// CreateFS failed
 	mockPH.On("PreparePartition", mock.Anything).
 		Return(&uw.Partition{}, nil).Once()
-	mockFS.On("CreateFS", fs.FileSystem(testVolume2.Type), mock.Anything).Return(errTest)
+	mockFS.On("CreateFS", fs.FileSystem(testVolume2.Type), mock.Anything, mock.Anything).Return(errTest)
 
 	err = dp.PrepareVolume(testVolume2)
 	assert.Error(t, err)
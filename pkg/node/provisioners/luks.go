@@ -0,0 +1,54 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"fmt"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/cryptsetup"
+)
+
+// luksOperations destroys key slots of the encrypted volume on release, device of the encrypted volume
+// is formatted with LUKS on the first NodeStageVolume because passphrase is provided only in node stage secrets
+type luksOperations struct {
+	cryptOps cryptsetup.WrapCryptsetup
+}
+
+// GetLUKSMappingName returns name of dm-crypt mapping of the encrypted volume
+func GetLUKSMappingName(volumeID string) string {
+	return "luks-" + volumeID
+}
+
+// eraseLUKS closes mapping of the encrypted volume if it remains opened and destroys LUKS key slots on its device,
+// so the data can't be decrypted anymore even with the passphrase. Device of the volume which has never been staged
+// isn't formatted with LUKS, there is nothing to erase
+func (o *luksOperations) eraseLUKS(vol *api.Volume, device string) error {
+	name := GetLUKSMappingName(vol.Id)
+	if o.cryptOps.IsOpened(name) {
+		if err := o.cryptOps.LuksClose(name); err != nil {
+			return fmt.Errorf("unable to close LUKS device %s: %v", device, err)
+		}
+	}
+	if !o.cryptOps.IsLuks(device) {
+		return nil
+	}
+	if err := o.cryptOps.LuksErase(device); err != nil {
+		return fmt.Errorf("unable to erase LUKS key slots on device %s: %v", device, err)
+	}
+	return nil
+}
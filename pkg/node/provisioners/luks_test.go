@@ -0,0 +1,64 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+)
+
+func setupTestLUKSOperations() (luksOperations, *mocklu.MockWrapCryptsetup) {
+	cryptOps := &mocklu.MockWrapCryptsetup{}
+	return luksOperations{cryptOps: cryptOps}, cryptOps
+}
+
+func getTestEncryptedVolume() *api.Volume {
+	vol := testVolume1
+	vol.Encrypted = true
+	return &vol
+}
+
+func Test_eraseLUKS(t *testing.T) {
+	var (
+		luksOps, cryptOps = setupTestLUKSOperations()
+		vol               = getTestEncryptedVolume()
+		device            = "/dev/sdb1"
+		name              = GetLUKSMappingName(vol.Id)
+	)
+
+	cryptOps.On("IsOpened", name).Return(true).Once()
+	cryptOps.On("LuksClose", name).Return(nil).Once()
+	cryptOps.On("IsLuks", device).Return(true).Once()
+	cryptOps.On("LuksErase", device).Return(nil).Once()
+	assert.Nil(t, luksOps.eraseLUKS(vol, device))
+
+	cryptOps.On("IsOpened", name).Return(false).Once()
+	cryptOps.On("IsLuks", device).Return(true).Once()
+	cryptOps.On("LuksErase", device).Return(errTest).Once()
+	assert.NotNil(t, luksOps.eraseLUKS(vol, device))
+	cryptOps.AssertNumberOfCalls(t, "LuksClose", 1)
+
+	// volume wasn't staged, device isn't formatted with LUKS
+	cryptOps.On("IsOpened", name).Return(false).Once()
+	cryptOps.On("IsLuks", device).Return(false).Once()
+	assert.Nil(t, luksOps.eraseLUKS(vol, device))
+	cryptOps.AssertNumberOfCalls(t, "LuksErase", 2)
+}
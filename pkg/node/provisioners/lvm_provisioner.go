@@ -19,13 +19,16 @@ package provisioners
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	api "github.com/dell/csi-baremetal/api/generated/v1"
 	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/cryptsetup"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
 	"github.com/dell/csi-baremetal/pkg/base/util"
@@ -36,6 +39,7 @@ import (
 type LVMProvisioner struct {
 	lvmOps   lvm.WrapLVM
 	fsOps    fs.WrapFS
+	luksOps  luksOperations
 	crHelper *k8s.CRHelper
 	log      *logrus.Entry
 }
@@ -45,13 +49,16 @@ func NewLVMProvisioner(e command.CmdExecutor, k *k8s.KubeClient, log *logrus.Log
 	return &LVMProvisioner{
 		lvmOps:   lvm.NewLVM(e, log),
 		fsOps:    fs.NewFSImpl(e),
+		luksOps:  luksOperations{cryptOps: cryptsetup.NewCryptsetup(e, log)},
 		crHelper: k8s.NewCRHelper(k, log),
 		log:      log.WithField("component", "LVMProvisioner"),
 	}
 }
 
 // PrepareVolume search volume group based on vol attributes, creates Logical Volume
-// and create file system on it. After that Logical Volume is ready for mount operations
+// and create file system on it (if vol mode isn't RAW), LV of encrypted vol is formatted with LUKS on staging instead. If vol has content source, Logical Volume is populated
+// from the source snapshot or volume instead of FS creation. Cache is attached to Logical Volume of cached vol right after its creation.
+// After that Logical Volume is ready for mount operations
 func (l *LVMProvisioner) PrepareVolume(vol api.Volume) error {
 	ll := l.log.WithFields(logrus.Fields{
 		"method":   "PrepareVolume",
@@ -75,18 +82,172 @@ func (l *LVMProvisioner) PrepareVolume(vol api.Volume) error {
 	}
 
 	// create lv with name /dev/VG_NAME/vol.Id
-	ll.Infof("Creating LV %s sizeof %s in VG %s", vol.Id, sizeStr, vgName)
-	if err = l.lvmOps.LVCreate(vol.Id, sizeStr, vgName); err != nil {
+	ll.Infof("Creating %s LV %s sizeof %s in VG %s", vol.LVType, vol.Id, sizeStr, vgName)
+	switch {
+	case util.IsStorageClassLVGThin(vol.StorageClass):
+		err = l.lvmOps.ThinLVCreate(vol.Id, sizeStr, vgName, lvm.ThinPoolName)
+	case vol.LVType == apiV1.LVTypeStriped:
+		err = l.lvmOps.LVCreateStriped(vol.Id, sizeStr, vgName, vol.Stripes)
+	case vol.LVType == apiV1.LVTypeRAID1:
+		err = l.lvmOps.LVCreateRAID1(vol.Id, sizeStr, vgName, vol.Mirrors)
+	default:
+		err = l.lvmOps.LVCreate(vol.Id, sizeStr, vgName)
+	}
+	if err != nil {
 		return fmt.Errorf("unable to create LV: %v", err)
 	}
 
 	deviceFile := fmt.Sprintf("/dev/%s/%s", vgName, vol.Id)
+	if vol.CacheLocation != "" {
+		if err = l.attachCache(&vol, vgName, deviceFile); err != nil {
+			return fmt.Errorf("unable to attach cache to LV: %v", err)
+		}
+	}
+
+	if vol.ContentSourceId != "" {
+		return l.populateVolume(vol, vgName, deviceFile)
+	}
+
+	if vol.Encrypted {
+		ll.Infof("Volume is encrypted, LV is formatted with LUKS on staging")
+		return nil
+	}
+
+	if vol.Mode == apiV1.ModeRAW {
+		ll.Infof("Volume mode is %s, skip FS creation", vol.Mode)
+		return nil
+	}
+
 	ll.Debugf("Creating FS on %s", deviceFile)
-	return l.fsOps.CreateFS(fs.FileSystem(vol.Type), deviceFile)
+	return l.fsOps.CreateFS(fs.FileSystem(vol.Type), deviceFile, strings.Fields(vol.MkfsOptions)...)
+}
+
+// attachCache creates cache LV of vol in the cache VG (vol.CacheLocation), initializes it as a PV of VG vgName,
+// creates cache pool on that PV and attaches the pool to the LV deviceFile of vol.
+// LVM is able to attach cache pool only from the same VG, that is why cache LV is stacked into VG of vol.
+// LVM 2.02 of the node image scans LVs for PVs, VGs are activated in order during Discover
+func (l *LVMProvisioner) attachCache(vol *api.Volume, vgName, deviceFile string) error {
+	ll := l.log.WithFields(logrus.Fields{
+		"method":   "attachCache",
+		"volumeID": vol.Id,
+	})
+
+	size, _ := util.ToSizeUnit(capacityplanner.GetCacheLVSize(vol), util.BYTE, util.MBYTE)
+	sizeStr := strconv.FormatInt(size, 10) + "m"
+	cacheLV := getCacheLVName(vol)
+	cachePV := fmt.Sprintf("/dev/%s/%s", vol.CacheLocation, cacheLV)
+
+	ll.Infof("Creating cache LV %s sizeof %s in VG %s", cacheLV, sizeStr, vol.CacheLocation)
+	if err := l.lvmOps.LVCreate(cacheLV, sizeStr, vol.CacheLocation); err != nil {
+		return err
+	}
+	if err := l.lvmOps.PVCreate(cachePV); err != nil {
+		return err
+	}
+	if err := l.lvmOps.VGExtend(vgName, cachePV); err != nil {
+		return err
+	}
+	cachePool := getCachePoolName(vol)
+	if err := l.lvmOps.CachePoolCreate(cachePool, vgName, cachePV); err != nil {
+		return err
+	}
+	// other LVs of VG vgName mustn't be allocated on the cache PV, otherwise they depend on the cache drive
+	if err := l.lvmOps.PVDisableAllocation(cachePV); err != nil {
+		return err
+	}
+
+	ll.Infof("Attaching cache pool %s to %s in %s mode", cachePool, deviceFile, vol.CacheMode)
+	return l.lvmOps.LVCacheAttach(deviceFile, fmt.Sprintf("%s/%s", vgName, cachePool), vol.CacheMode)
+}
+
+// detachCache removes cache LV of vol from VG vgName and from the cache VG (vol.CacheLocation).
+// Cache pool is removed along with LV of vol, so it should be called after LV removal
+func (l *LVMProvisioner) detachCache(vol *api.Volume, vgName string) error {
+	cachePV := fmt.Sprintf("/dev/%s/%s", vol.CacheLocation, getCacheLVName(vol))
+	if err := l.lvmOps.VGReduce(vgName, cachePV); err != nil {
+		return err
+	}
+	if err := l.lvmOps.PVRemove(cachePV); err != nil {
+		return err
+	}
+	return l.lvmOps.LVRemove(cachePV)
+}
+
+// populateVolume copies content of the source snapshot or volume to the LV of vol.
+// Source LV is always placed in the same VG as vol, snapshot LV has name of the snapshot ID.
+// Source volume could be used by pods, so it's copied from its temporary snapshot.
+// FS of the copy is grown up to the size of vol on staging
+func (l *LVMProvisioner) populateVolume(vol api.Volume, vgName, deviceFile string) error {
+	ll := l.log.WithFields(logrus.Fields{
+		"method":   "populateVolume",
+		"volumeID": vol.Id,
+	})
+
+	srcDeviceFile := fmt.Sprintf("/dev/%s/%s", vgName, vol.ContentSourceId)
+	if vol.ContentSourceType == apiV1.ContentSourceVolume {
+		snapshotDeviceFile, err := l.createSourceSnapshot(&vol, vgName)
+		if err != nil {
+			return fmt.Errorf("unable to create snapshot of source volume %s: %v", vol.ContentSourceId, err)
+		}
+		defer func() {
+			if err := l.lvmOps.LVRemove(snapshotDeviceFile); err != nil {
+				ll.Errorf("Unable to remove temporary snapshot %s: %v", snapshotDeviceFile, err)
+			}
+		}()
+		srcDeviceFile = snapshotDeviceFile
+	}
+
+	ll.Infof("Copying %s %s to %s", vol.ContentSourceType, srcDeviceFile, deviceFile)
+	if err := l.fsOps.CopyDevice(srcDeviceFile, deviceFile); err != nil {
+		return fmt.Errorf("unable to populate LV from %s %s: %v", vol.ContentSourceType, vol.ContentSourceId, err)
+	}
+
+	// source FS could be mounted on the same node, so copy has to get its own FS UUID,
+	// xfs copy of the mounted source has dirty log which prevents UUID change, so it's mounted with nouuid instead
+	if vol.Mode == apiV1.ModeRAW || fs.FileSystem(vol.Type) == fs.XFS {
+		return nil
+	}
+	if err := l.fsOps.RegenerateFSUUID(fs.FileSystem(vol.Type), deviceFile); err != nil {
+		return fmt.Errorf("unable to populate LV from %s %s: %v", vol.ContentSourceType, vol.ContentSourceId, err)
+	}
+	return nil
+}
+
+// createSourceSnapshot creates temporary snapshot of the source volume of vol in VG vgName,
+// snapshot of the thin LV is created in the same thin pool, snapshot of the usual LV takes free space of VG
+// up to the size of the source volume, snapshot becomes invalid and copy fails if source is changed more
+// Returns device file of the snapshot
+func (l *LVMProvisioner) createSourceSnapshot(vol *api.Volume, vgName string) (string, error) {
+	var (
+		name       = vol.Id + "-source"
+		fullLVName = fmt.Sprintf("%s/%s", vgName, vol.ContentSourceId)
+		deviceFile = fmt.Sprintf("/dev/%s/%s", vgName, name)
+	)
+	if util.IsStorageClassLVGThin(vol.StorageClass) {
+		return deviceFile, l.lvmOps.LVThinSnapshotCreate(name, fullLVName)
+	}
+
+	source := l.crHelper.GetVolumeByID(vol.ContentSourceId)
+	if source == nil {
+		return "", fmt.Errorf("source volume %s isn't found", vol.ContentSourceId)
+	}
+	size, err := l.lvmOps.GetVgFreeSpace(vgName)
+	if err != nil {
+		return "", err
+	}
+	if size > source.Spec.Size {
+		size = source.Spec.Size
+	}
+	if size < capacityplanner.DefaultPESize {
+		return "", fmt.Errorf("there is no free space in VG %s", vgName)
+	}
+	size, _ = util.ToSizeUnit(size, util.BYTE, util.MBYTE)
+	return deviceFile, l.lvmOps.LVSnapshotCreate(name, strconv.FormatInt(size, 10)+"m", fullLVName)
 }
 
 // ReleaseVolume search volume group based on vol attributes, remove Logical Volume
-// and wipe file system on it. After that Logical Volume that had consumed by vol is completely removed
+// and wipe file system on it. Cache LV of cached vol is removed as well.
+// After that Logical Volume that had consumed by vol is completely removed
 func (l *LVMProvisioner) ReleaseVolume(vol api.Volume) error {
 	ll := logrus.WithFields(logrus.Fields{
 		"method":   "ReleaseVolume",
@@ -99,7 +260,7 @@ func (l *LVMProvisioner) ReleaseVolume(vol api.Volume) error {
 		return fmt.Errorf("unable to determine full path of the volume: %v", err)
 	}
 
-	if err := l.fsOps.WipeFS(deviceFile); err != nil {
+	if err := l.wipeVolume(&vol, deviceFile); err != nil {
 		// check whether such LV (deviceFile) exist or not
 		vgName, sErr := l.getVGName(&vol)
 		if sErr != nil {
@@ -113,12 +274,40 @@ func (l *LVMProvisioner) ReleaseVolume(vol api.Volume) error {
 		}
 		if !util.ContainsString(lvs, vol.Id) {
 			ll.Infof("LV %s has been already removed", deviceFile)
-			return nil
+			return l.releaseCache(&vol, vgName)
 		}
 		return fmt.Errorf("failed to wipe FS on device %s: %v", deviceFile, err)
 	}
 
-	return l.lvmOps.LVRemove(deviceFile)
+	if err := l.lvmOps.LVRemove(deviceFile); err != nil {
+		return err
+	}
+	vgName, err := l.getVGName(&vol)
+	if err != nil {
+		return err
+	}
+	return l.releaseCache(&vol, vgName)
+}
+
+// releaseCache calls detachCache for cached vol
+func (l *LVMProvisioner) releaseCache(vol *api.Volume, vgName string) error {
+	if vol.CacheLocation == "" {
+		return nil
+	}
+	if err := l.detachCache(vol, vgName); err != nil {
+		return fmt.Errorf("unable to remove cache LV of volume %s: %v", vol.Id, err)
+	}
+	return nil
+}
+
+// wipeVolume destroys LUKS key slots of the encrypted volume and wipes signatures on its device
+func (l *LVMProvisioner) wipeVolume(vol *api.Volume, deviceFile string) error {
+	if vol.Encrypted {
+		if err := l.luksOps.eraseLUKS(vol, deviceFile); err != nil {
+			return err
+		}
+	}
+	return l.fsOps.WipeFS(deviceFile)
 }
 
 // GetVolumePath search Volume Group name by vol attributes and construct
@@ -138,6 +327,16 @@ func (l *LVMProvisioner) GetVolumePath(vol api.Volume) (string, error) {
 	return fmt.Sprintf("/dev/%s/%s", vgName, vol.Id), nil // /dev/VG_NAME/LV_NAME
 }
 
+// getCacheLVName returns name of the cache LV of vol in the cache VG
+func getCacheLVName(vol *api.Volume) string {
+	return vol.Id + "-cache"
+}
+
+// getCachePoolName returns name of the cache pool which is attached to LV of vol
+func getCachePoolName(vol *api.Volume) string {
+	return vol.Id + "-cpool"
+}
+
 func (l *LVMProvisioner) getVGName(vol *api.Volume) (string, error) {
 	var vgName = vol.Location
 
@@ -27,6 +27,8 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
+	"github.com/dell/csi-baremetal/pkg/base/util"
 	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
 	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
 )
@@ -58,11 +60,220 @@ func TestLVMProvisioner_PrepareVolume_Success(t *testing.T) {
 		Return(nil).Times(1)
 
 	devFile := fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
-	fsOps.On("CreateFS", fs.FileSystem(testVolume1.Type), devFile).
+	fsOps.On("CreateFS", fs.FileSystem(testVolume1.Type), devFile, mock.Anything).
 		Return(nil).Times(1)
 
 	err := lp.PrepareVolume(testVolume1)
 	assert.Nil(t, err)
+
+	// volume in RAW mode, FS shouldn't be created
+	rawVolume := testVolume1
+	rawVolume.Mode = apiV1.ModeRAW
+	rawVolume.Type = ""
+	lvmOps.On("LVCreate", rawVolume.Id, mock.Anything, rawVolume.Location).
+		Return(nil).Times(1)
+
+	err = lp.PrepareVolume(rawVolume)
+	assert.Nil(t, err)
+	fsOps.AssertNumberOfCalls(t, "CreateFS", 1)
+}
+
+func TestLVMProvisioner_PrepareVolume_LVType(t *testing.T) {
+	setupTestLVMProvisioner()
+
+	devFile := fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
+	fsOps.On("CreateFS", fs.FileSystem(testVolume1.Type), devFile, mock.Anything).Return(nil)
+
+	striped := testVolume1
+	striped.LVType = apiV1.LVTypeStriped
+	striped.Stripes = 3
+	lvmOps.On("LVCreateStriped", striped.Id, mock.Anything, striped.Location, int32(3)).
+		Return(nil).Times(1)
+
+	err := lp.PrepareVolume(striped)
+	assert.Nil(t, err)
+
+	mirrored := testVolume1
+	mirrored.LVType = apiV1.LVTypeRAID1
+	mirrored.Mirrors = 1
+	lvmOps.On("LVCreateRAID1", mirrored.Id, mock.Anything, mirrored.Location, int32(1)).
+		Return(errTest).Times(1)
+
+	err = lp.PrepareVolume(mirrored)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unable to create LV")
+
+	thin := testVolume1
+	thin.StorageClass = apiV1.StorageClassHDDLVGThin
+	lvmOps.On("ThinLVCreate", thin.Id, mock.Anything, thin.Location, lvm.ThinPoolName).
+		Return(nil).Times(1)
+
+	err = lp.PrepareVolume(thin)
+	assert.Nil(t, err)
+	lvmOps.AssertNotCalled(t, "LVCreate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLVMProvisioner_Encrypted(t *testing.T) {
+	setupTestLVMProvisioner()
+
+	var (
+		luksOps, cryptOps = setupTestLUKSOperations()
+		vol               = getTestEncryptedVolume()
+		devFile           = fmt.Sprintf("/dev/%s/%s", vol.Location, vol.Id)
+		name              = GetLUKSMappingName(vol.Id)
+	)
+	lp.luksOps = luksOps
+
+	// LV is formatted with LUKS and FS on staging
+	lvmOps.On("LVCreate", vol.Id, mock.Anything, vol.Location).Return(nil).Times(1)
+
+	err := lp.PrepareVolume(*vol)
+	assert.Nil(t, err)
+	fsOps.AssertNotCalled(t, "CreateFS", mock.Anything, mock.Anything, mock.Anything)
+
+	// key slots are destroyed before LV removal
+	cryptOps.On("IsOpened", name).Return(false).Times(1)
+	cryptOps.On("IsLuks", devFile).Return(true).Times(1)
+	cryptOps.On("LuksErase", devFile).Return(nil).Times(1)
+	fsOps.On("WipeFS", devFile).Return(nil).Times(1)
+	lvmOps.On("LVRemove", devFile).Return(nil).Times(1)
+
+	err = lp.ReleaseVolume(*vol)
+	assert.Nil(t, err)
+	cryptOps.AssertExpectations(t)
+}
+
+func TestLVMProvisioner_Cached(t *testing.T) {
+	setupTestLVMProvisioner()
+
+	var (
+		vol     = testVolume1
+		devFile = fmt.Sprintf("/dev/%s/%s", vol.Location, vol.Id)
+		cachePV string
+	)
+	vol.CacheLocation = "cache-lvg"
+	vol.CacheSize = 64 * int64(util.MBYTE)
+	vol.CacheMode = apiV1.CacheModeWritethrough
+	cachePV = fmt.Sprintf("/dev/%s/%s", vol.CacheLocation, getCacheLVName(&vol))
+
+	lvmOps.On("LVCreate", vol.Id, mock.Anything, vol.Location).Return(nil).Times(1)
+	lvmOps.On("LVCreate", getCacheLVName(&vol), "68m", vol.CacheLocation).Return(nil).Times(1)
+	lvmOps.On("PVCreate", cachePV).Return(nil).Times(1)
+	lvmOps.On("VGExtend", vol.Location, cachePV).Return(nil).Times(1)
+	lvmOps.On("CachePoolCreate", getCachePoolName(&vol), vol.Location, cachePV).Return(nil).Times(1)
+	lvmOps.On("PVDisableAllocation", cachePV).Return(nil).Times(1)
+	lvmOps.On("LVCacheAttach", devFile, vol.Location+"/"+getCachePoolName(&vol), vol.CacheMode).
+		Return(nil).Times(1)
+	fsOps.On("CreateFS", fs.FileSystem(vol.Type), devFile, mock.Anything).Return(nil).Times(1)
+
+	err := lp.PrepareVolume(vol)
+	assert.Nil(t, err)
+
+	// cache LV is removed from both VGs after LV removal
+	fsOps.On("WipeFS", devFile).Return(nil).Times(1)
+	lvmOps.On("LVRemove", devFile).Return(nil).Times(1)
+	lvmOps.On("VGReduce", vol.Location, cachePV).Return(nil).Times(1)
+	lvmOps.On("PVRemove", cachePV).Return(nil).Times(1)
+	lvmOps.On("LVRemove", cachePV).Return(nil).Times(1)
+
+	err = lp.ReleaseVolume(vol)
+	assert.Nil(t, err)
+	lvmOps.AssertExpectations(t)
+
+	// cache pool isn't attached
+	setupTestLVMProvisioner()
+	lvmOps.On("LVCreate", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	lvmOps.On("PVCreate", cachePV).Return(nil).Times(1)
+	lvmOps.On("VGExtend", vol.Location, cachePV).Return(errTest).Times(1)
+
+	err = lp.PrepareVolume(vol)
+	assert.NotNil(t, err)
+	fsOps.AssertNotCalled(t, "CreateFS", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLVMProvisioner_PrepareVolume_FromContentSource(t *testing.T) {
+	setupTestLVMProvisioner()
+
+	var (
+		vol        = testVolume1
+		devFile    = fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
+		srcDevFile = fmt.Sprintf("/dev/%s/%s", testVolume1.Location, "snapshot-1")
+	)
+	vol.ContentSourceType = apiV1.ContentSourceSnapshot
+	vol.ContentSourceId = "snapshot-1"
+
+	lvmOps.On("LVCreate", vol.Id, mock.Anything, vol.Location).Return(nil)
+	fsOps.On("CopyDevice", srcDevFile, devFile).Return(nil).Times(1)
+
+	// xfs copy is mounted with nouuid
+	err := lp.PrepareVolume(vol)
+	assert.Nil(t, err)
+	fsOps.AssertNotCalled(t, "CreateFS", mock.Anything, mock.Anything, mock.Anything)
+	fsOps.AssertNotCalled(t, "RegenerateFSUUID", mock.Anything, mock.Anything)
+
+	vol.Type = string(fs.BTRFS)
+	fsOps.On("CopyDevice", srcDevFile, devFile).Return(nil).Times(1)
+	fsOps.On("RegenerateFSUUID", fs.BTRFS, devFile).Return(nil).Times(1)
+	err = lp.PrepareVolume(vol)
+	assert.Nil(t, err)
+
+	// RegenerateFSUUID failed, copy couldn't be mounted along with its source
+	fsOps.On("CopyDevice", srcDevFile, devFile).Return(nil).Times(1)
+	fsOps.On("RegenerateFSUUID", fs.BTRFS, devFile).Return(errTest).Times(1)
+	err = lp.PrepareVolume(vol)
+	assert.NotNil(t, err)
+
+	// CopyDevice failed
+	fsOps.On("CopyDevice", srcDevFile, devFile).Return(errTest).Times(1)
+	err = lp.PrepareVolume(vol)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unable to populate LV")
+}
+
+func TestLVMProvisioner_PrepareVolume_FromSourceVolume(t *testing.T) {
+	setupTestLVMProvisioner()
+	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+	lp.crHelper = k8s.NewCRHelper(kubeClient, testLogger)
+
+	var (
+		vol             = testVolume1
+		source          = testVolume1
+		devFile         = fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
+		snapshotName    = testVolume1.Id + "-source"
+		snapshotDevFile = fmt.Sprintf("/dev/%s/%s", testVolume1.Location, snapshotName)
+		sourceLVName    = fmt.Sprintf("%s/%s", testVolume1.Location, "source-volume")
+	)
+	vol.ContentSourceType = apiV1.ContentSourceVolume
+	vol.ContentSourceId = "source-volume"
+	source.Id = vol.ContentSourceId
+	source.Size = int64(util.GBYTE)
+	source.CSIStatus = apiV1.Published
+	sourceCR := kubeClient.ConstructVolumeCR(source.Id, source)
+	assert.Nil(t, kubeClient.CreateCR(testCtx, source.Id, sourceCR))
+	lvmOps.On("LVCreate", vol.Id, mock.Anything, vol.Location).Return(nil)
+
+	// there is no free space in VG for the snapshot
+	lvmOps.On("GetVgFreeSpace", vol.Location).Return(int64(0), nil).Times(1)
+	err = lp.PrepareVolume(vol)
+	assert.NotNil(t, err)
+	fsOps.AssertNotCalled(t, "CopyDevice", mock.Anything, mock.Anything)
+
+	// published source volume is copied from the snapshot which is limited by its size
+	lvmOps.On("GetVgFreeSpace", vol.Location).Return(2*source.Size, nil).Times(1)
+	lvmOps.On("LVSnapshotCreate", snapshotName, "1024m", sourceLVName).Return(nil).Times(1)
+	fsOps.On("CopyDevice", snapshotDevFile, devFile).Return(nil).Times(1)
+	lvmOps.On("LVRemove", snapshotDevFile).Return(nil).Times(1)
+	assert.Nil(t, lp.PrepareVolume(vol))
+
+	// snapshot of the thin volume is created in the thin pool and removed even if copy fails
+	vol.StorageClass = apiV1.StorageClassHDDLVGThin
+	lvmOps.On("ThinLVCreate", vol.Id, mock.Anything, vol.Location, lvm.ThinPoolName).Return(nil).Times(1)
+	lvmOps.On("LVThinSnapshotCreate", snapshotName, sourceLVName).Return(nil).Times(1)
+	fsOps.On("CopyDevice", snapshotDevFile, devFile).Return(errTest).Times(1)
+	lvmOps.On("LVRemove", snapshotDevFile).Return(nil).Times(1)
+	assert.NotNil(t, lp.PrepareVolume(vol))
+	lvmOps.AssertNumberOfCalls(t, "LVRemove", 2)
 }
 
 func TestLVMProvisioner_PrepareVolume_Fail(t *testing.T) {
@@ -91,7 +302,7 @@ func TestLVMProvisioner_PrepareVolume_Fail(t *testing.T) {
 		Return(nil).Times(1)
 
 	devFile := fmt.Sprintf("/dev/%s/%s", testVolume1.Location, testVolume1.Id)
-	fsOps.On("CreateFS", fs.FileSystem(testVolume1.Type), devFile).
+	fsOps.On("CreateFS", fs.FileSystem(testVolume1.Type), devFile, mock.Anything).
 		Return(errTest).Times(1)
 
 	err = lp.PrepareVolume(testVolume1)
@@ -0,0 +1,211 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/mdadm"
+	"github.com/dell/csi-baremetal/pkg/base/util"
+)
+
+// mdArrayNameLen is a length of the MD array name, superblock 1.2 holds up to 32 bytes including homehost
+const mdArrayNameLen = 24
+
+// MDRaidProvisioner is a implementation of Provisioner interface
+// Work with volumes based on MD RAID arrays, each member of the array is a whole drive
+type MDRaidProvisioner struct {
+	listBlk  lsblk.WrapLsblk
+	fsOps    fs.WrapFS
+	mdOps    mdadm.WrapMDADM
+	crHelper *k8s.CRHelper
+	log      *logrus.Entry
+}
+
+// NewMDRaidProvisioner is a constructor for MDRaidProvisioner
+func NewMDRaidProvisioner(e command.CmdExecutor, k *k8s.KubeClient, log *logrus.Logger) *MDRaidProvisioner {
+	return &MDRaidProvisioner{
+		listBlk:  lsblk.NewLSBLK(log),
+		fsOps:    fs.NewFSImpl(e),
+		mdOps:    mdadm.NewMDADM(e, log),
+		crHelper: k8s.NewCRHelper(k, log),
+		log:      log.WithField("component", "MDRaidProvisioner"),
+	}
+}
+
+// PrepareVolume creates MD array of the level that corresponds to vol storage class on drives
+// from vol.Locations and creates file system on it (if vol mode isn't RAW).
+// Array which is running or could be assembled from its members (e.g. created during previous attempt) is reused,
+// file system is created on such array only if there is no file system yet.
+// After that array is ready for mount operations
+func (m *MDRaidProvisioner) PrepareVolume(vol api.Volume) error {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "PrepareVolume",
+		"volumeID": vol.Id,
+	})
+	ll.Infof("Processing for volume %v", vol)
+
+	level := util.GetRAIDLevel(vol.StorageClass)
+	if level == "" {
+		return fmt.Errorf("storage class %s doesn't relate to MD RAID", vol.StorageClass)
+	}
+
+	devices := make([]string, 0, len(vol.Locations))
+	for _, location := range vol.Locations {
+		device, err := m.getDevice(location)
+		if err != nil {
+			return err
+		}
+		devices = append(devices, device)
+	}
+
+	name := GetMDArrayName(vol.Id)
+	reused, err := m.reuseArray(name, devices)
+	if err != nil {
+		return err
+	}
+	if !reused {
+		for _, device := range devices {
+			// remove signatures of the previous usage, otherwise they could be found on the array
+			if err = m.fsOps.WipeFS(device); err != nil {
+				return fmt.Errorf("unable to wipe device %s: %v", device, err)
+			}
+		}
+		ll.Infof("Creating RAID%s array %s on devices %v", level, name, devices)
+		if err = m.mdOps.CreateArray(name, level, devices); err != nil {
+			return fmt.Errorf("unable to create MD array: %v", err)
+		}
+	}
+
+	if vol.Mode == apiV1.ModeRAW {
+		ll.Infof("Volume mode is %s, skip FS creation", vol.Mode)
+		return nil
+	}
+
+	deviceFile := mdadm.GetArrayPath(name)
+	if reused {
+		fsType, err := m.fsOps.GetFSType(deviceFile)
+		if err != nil {
+			return err
+		}
+		switch fsType {
+		case "":
+		case fs.FileSystem(vol.Type):
+			ll.Infof("FS %s already exists on %s", fsType, deviceFile)
+			return nil
+		default:
+			return fmt.Errorf("MD array %s holds FS %s instead of %s", name, fsType, vol.Type)
+		}
+	}
+	ll.Debugf("Creating FS on %s", deviceFile)
+	return m.fsOps.CreateFS(fs.FileSystem(vol.Type), deviceFile, strings.Fields(vol.MkfsOptions)...)
+}
+
+// reuseArray returns true if MD array with provided name is running or it was assembled from devices,
+// array isn't reused if devices don't hold its superblocks
+func (m *MDRaidProvisioner) reuseArray(name string, devices []string) (bool, error) {
+	ll := m.log.WithField("method", "reuseArray")
+
+	if _, err := m.mdOps.GetArray(name); err == nil {
+		ll.Infof("MD array %s is already running", name)
+		return true, nil
+	}
+	if err := m.mdOps.AssembleArray(name, devices); err != nil {
+		ll.Debugf("MD array %s couldn't be assembled from devices %v: %v", name, devices, err)
+		return false, nil
+	}
+	if _, err := m.mdOps.GetArray(name); err != nil {
+		return false, fmt.Errorf("MD array %s was assembled, but isn't running: %v", name, err)
+	}
+	ll.Infof("MD array %s was assembled from devices %v", name, devices)
+	return true, nil
+}
+
+// ReleaseVolume wipes file system on the MD array of vol, stops it and removes MD superblocks from its members.
+// Members which drives are absent are skipped. After that drives are ready for the new allocations
+func (m *MDRaidProvisioner) ReleaseVolume(vol api.Volume) error {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":   "ReleaseVolume",
+		"volumeID": vol.Id,
+	})
+	ll.Infof("Processing for volume %v", vol)
+
+	name := GetMDArrayName(vol.Id)
+	if _, err := m.mdOps.GetArray(name); err != nil {
+		// array could be already stopped during previous attempt
+		ll.Infof("MD array %s isn't running: %v", name, err)
+	} else {
+		if err = m.fsOps.WipeFS(mdadm.GetArrayPath(name)); err != nil {
+			return fmt.Errorf("failed to wipe FS on MD array %s: %v", name, err)
+		}
+		if err = m.mdOps.StopArray(name); err != nil {
+			return fmt.Errorf("unable to stop MD array %s: %v", name, err)
+		}
+	}
+
+	for _, location := range vol.Locations {
+		device, err := m.getDevice(location)
+		if err != nil {
+			ll.Warnf("Member %s of MD array %s is skipped: %v", location, name, err)
+			continue
+		}
+		if err = m.mdOps.ZeroSuperblock(device); err != nil {
+			return fmt.Errorf("unable to remove MD superblock from %s: %v", device, err)
+		}
+		if err = m.fsOps.WipeFS(device); err != nil {
+			return fmt.Errorf("unable to wipe device %s: %v", device, err)
+		}
+	}
+	return nil
+}
+
+// GetVolumePath returns full path to the MD array of the volume: /dev/md/ARRAY_NAME
+func (m *MDRaidProvisioner) GetVolumePath(vol api.Volume) (string, error) {
+	return mdadm.GetArrayPath(GetMDArrayName(vol.Id)), nil
+}
+
+// getDevice returns device file of the drive with UUID location
+func (m *MDRaidProvisioner) getDevice(location string) (string, error) {
+	drive := m.crHelper.GetDriveCRByUUID(location)
+	if drive == nil {
+		return "", errors.New("unable to find drive by location " + location)
+	}
+	return m.listBlk.SearchDrivePath(drive)
+}
+
+// GetMDArrayName returns name of the MD array for the volume, that is UUID of the volume without dashes
+// truncated to mdArrayNameLen
+func GetMDArrayName(volumeID string) string {
+	name := volumeID
+	if uuid, err := util.GetVolumeUUID(volumeID); err == nil {
+		name = strings.ReplaceAll(uuid, "-", "")
+	}
+	if len(name) > mdArrayNameLen {
+		name = name[:mdArrayNameLen]
+	}
+	return name
+}
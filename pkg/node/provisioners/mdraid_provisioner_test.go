@@ -0,0 +1,185 @@
+/*
+Copyright © 2020 Dell Inc. or its subsidiaries. All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioners
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/dell/csi-baremetal/api/generated/v1"
+	apiV1 "github.com/dell/csi-baremetal/api/v1"
+	"github.com/dell/csi-baremetal/api/v1/drivecrd"
+	"github.com/dell/csi-baremetal/pkg/base/command"
+	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/mdadm"
+	mocklu "github.com/dell/csi-baremetal/pkg/mocks/linuxutils"
+	mockProv "github.com/dell/csi-baremetal/pkg/mocks/provisioners"
+)
+
+var (
+	mp      *MDRaidProvisioner
+	mdOps   *mocklu.MockWrapMDADM
+	mdFSOps *mockProv.MockFsOpts
+	mdLsblk *mocklu.MockWrapLsblk
+
+	testRAIDVolume = api.Volume{
+		Id:           "pvc-4e8b1b31-0a3f-4c2e-9d59-5f9b5e0c6a7d",
+		NodeId:       testNodeID,
+		Location:     "raid-drive-1",
+		Locations:    []string{"raid-drive-1", "raid-drive-2"},
+		StorageClass: apiV1.StorageClassHDDRAID1,
+		LocationType: apiV1.LocationTypeMDRaid,
+		Type:         "xfs",
+	}
+	testRAIDArrayName = "4e8b1b310a3f4c2e9d595f9b"
+)
+
+func setupTestMDRaidProvisioner(t *testing.T) {
+	kubeClient, err := k8s.GetFakeKubeClient(testNs, testLogger)
+	assert.Nil(t, err)
+
+	mp = NewMDRaidProvisioner(&command.Executor{}, kubeClient, testLogger)
+	mdOps = &mocklu.MockWrapMDADM{}
+	mdFSOps = &mockProv.MockFsOpts{}
+	mdLsblk = &mocklu.MockWrapLsblk{}
+	mp.mdOps = mdOps
+	mp.fsOps = mdFSOps
+	mp.listBlk = mdLsblk
+
+	for i, location := range testRAIDVolume.Locations {
+		location := location
+		drive := &drivecrd.Drive{
+			TypeMeta:   k8smetav1.TypeMeta{Kind: "Drive", APIVersion: apiV1.APIV1Version},
+			ObjectMeta: k8smetav1.ObjectMeta{Name: location, Namespace: testNs},
+			Spec:       api.Drive{UUID: location, SerialNumber: location, NodeId: testNodeID},
+		}
+		assert.Nil(t, kubeClient.CreateCR(testCtx, location, drive))
+		mdLsblk.On("SearchDrivePath", mock.MatchedBy(func(d *drivecrd.Drive) bool {
+			return d.Spec.UUID == location
+		})).Return([]string{"/dev/sdb", "/dev/sdc"}[i], nil)
+	}
+}
+
+func TestMDRaidProvisioner_PrepareVolume(t *testing.T) {
+	setupTestMDRaidProvisioner(t)
+
+	mdOps.On("GetArray", testRAIDArrayName).Return(nil, errTest)
+	mdOps.On("AssembleArray", testRAIDArrayName, []string{"/dev/sdb", "/dev/sdc"}).Return(errTest)
+	mdFSOps.On("WipeFS", mock.Anything).Return(nil)
+	mdOps.On("CreateArray", testRAIDArrayName, "1", []string{"/dev/sdb", "/dev/sdc"}).Return(nil)
+	mdFSOps.On("CreateFS", fs.FileSystem(testRAIDVolume.Type), "/dev/md/"+testRAIDArrayName, mock.Anything).
+		Return(nil).Once()
+
+	assert.Nil(t, mp.PrepareVolume(testRAIDVolume))
+
+	// volume in RAW mode, FS shouldn't be created
+	rawVolume := testRAIDVolume
+	rawVolume.Mode = apiV1.ModeRAW
+	assert.Nil(t, mp.PrepareVolume(rawVolume))
+	mdFSOps.AssertNumberOfCalls(t, "CreateFS", 1)
+}
+
+func TestMDRaidProvisioner_PrepareVolume_ExistingArray(t *testing.T) {
+	setupTestMDRaidProvisioner(t)
+	deviceFile := "/dev/md/" + testRAIDArrayName
+
+	// array and FS were created during previous attempt
+	mdOps.On("GetArray", testRAIDArrayName).Return(&mdadm.Array{Name: "md127"}, nil).Once()
+	mdFSOps.On("GetFSType", deviceFile).Return(fs.FileSystem(testRAIDVolume.Type), nil).Once()
+	assert.Nil(t, mp.PrepareVolume(testRAIDVolume))
+
+	// array isn't running after node reboot, it is assembled from its members, FS wasn't created yet
+	mdOps.On("GetArray", testRAIDArrayName).Return(nil, errTest).Once()
+	mdOps.On("AssembleArray", testRAIDArrayName, []string{"/dev/sdb", "/dev/sdc"}).Return(nil).Once()
+	mdOps.On("GetArray", testRAIDArrayName).Return(&mdadm.Array{Name: "md127"}, nil).Once()
+	mdFSOps.On("GetFSType", deviceFile).Return(fs.FileSystem(""), nil).Once()
+	mdFSOps.On("CreateFS", fs.FileSystem(testRAIDVolume.Type), deviceFile, mock.Anything).Return(nil).Once()
+	assert.Nil(t, mp.PrepareVolume(testRAIDVolume))
+
+	// array holds another FS
+	mdOps.On("GetArray", testRAIDArrayName).Return(&mdadm.Array{Name: "md127"}, nil).Once()
+	mdFSOps.On("GetFSType", deviceFile).Return(fs.FileSystem("ext4"), nil).Once()
+	assert.NotNil(t, mp.PrepareVolume(testRAIDVolume))
+
+	mdOps.AssertNotCalled(t, "CreateArray", mock.Anything, mock.Anything, mock.Anything)
+	mdFSOps.AssertNotCalled(t, "WipeFS", mock.Anything)
+	mdFSOps.AssertNumberOfCalls(t, "CreateFS", 1)
+}
+
+func TestMDRaidProvisioner_PrepareVolume_Fail(t *testing.T) {
+	setupTestMDRaidProvisioner(t)
+
+	// storage class doesn't relate to MD RAID
+	vol := testRAIDVolume
+	vol.StorageClass = apiV1.StorageClassHDD
+	assert.NotNil(t, mp.PrepareVolume(vol))
+
+	// drive isn't found
+	vol = testRAIDVolume
+	vol.Locations = []string{"unknown"}
+	assert.NotNil(t, mp.PrepareVolume(vol))
+
+	// array creation failed
+	mdOps.On("GetArray", testRAIDArrayName).Return(nil, errTest)
+	mdOps.On("AssembleArray", testRAIDArrayName, []string{"/dev/sdb", "/dev/sdc"}).Return(errTest)
+	mdFSOps.On("WipeFS", mock.Anything).Return(nil)
+	mdOps.On("CreateArray", testRAIDArrayName, "1", []string{"/dev/sdb", "/dev/sdc"}).Return(errTest)
+	assert.NotNil(t, mp.PrepareVolume(testRAIDVolume))
+}
+
+func TestMDRaidProvisioner_ReleaseVolume(t *testing.T) {
+	setupTestMDRaidProvisioner(t)
+
+	mdOps.On("GetArray", testRAIDArrayName).Return(&mdadm.Array{Name: "md127"}, nil).Once()
+	mdFSOps.On("WipeFS", mock.Anything).Return(nil)
+	mdOps.On("StopArray", testRAIDArrayName).Return(nil).Once()
+	mdOps.On("ZeroSuperblock", "/dev/sdb").Return(nil)
+	mdOps.On("ZeroSuperblock", "/dev/sdc").Return(nil)
+
+	assert.Nil(t, mp.ReleaseVolume(testRAIDVolume))
+
+	// array has been already stopped, drive of the failed member was removed
+	vol := testRAIDVolume
+	vol.Locations = append(vol.Locations, "unknown")
+	mdOps.On("GetArray", testRAIDArrayName).Return(nil, errTest).Once()
+	assert.Nil(t, mp.ReleaseVolume(vol))
+	mdOps.AssertNumberOfCalls(t, "StopArray", 1)
+	mdOps.AssertNumberOfCalls(t, "ZeroSuperblock", 4)
+}
+
+func TestMDRaidProvisioner_ReleaseVolume_Fail(t *testing.T) {
+	setupTestMDRaidProvisioner(t)
+
+	mdOps.On("GetArray", testRAIDArrayName).Return(&mdadm.Array{Name: "md127"}, nil)
+	mdFSOps.On("WipeFS", mock.Anything).Return(nil)
+	mdOps.On("StopArray", testRAIDArrayName).Return(errTest)
+	assert.NotNil(t, mp.ReleaseVolume(testRAIDVolume))
+}
+
+func TestMDRaidProvisioner_GetVolumePath(t *testing.T) {
+	setupTestMDRaidProvisioner(t)
+
+	path, err := mp.GetVolumePath(testRAIDVolume)
+	assert.Nil(t, err)
+	assert.Equal(t, "/dev/md/"+testRAIDArrayName, path)
+
+	assert.Equal(t, "volume1id", GetMDArrayName("volume-1-id"))
+}
@@ -19,6 +19,7 @@ package utilwrappers
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
@@ -29,8 +30,8 @@ import (
 // FSOperations is holds idempotent methods that consists of WrapFS methods
 type FSOperations interface {
 	// PrepareAndPerformMount composite methods which is prepare source and destination directories
-	// and performs mount operation from src to dst
-	PrepareAndPerformMount(src, dst string, bindMount bool) error
+	// and performs mount operation from src to dst with provided mount options
+	PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool, mountOptions ...string) error
 	// UnmountWithCheck unmount operation
 	UnmountWithCheck(path string) error
 	fs.WrapFS
@@ -53,8 +54,13 @@ func NewFSOperationsImpl(e command.CmdExecutor, log *logrus.Logger) *FSOperation
 // PrepareAndPerformMount (idempotent) implementation of FSOperations method
 // create (if isn't exist) dst folder on node and perform mount from src to dst
 // if bindMount set to true - mount operation will contain "--bind" option
+// if dstIsDir set to false - dst will be created as a file (e.g. for bind mount of block device)
+// if mountOptions are provided - mount operation will contain them as a value of "-o" option,
+// bind mount ignores options so it is remounted with them (e.g. to make it read-only),
+// existing bind mount is remounted with them as well since it could be mounted with other options before
 // if error occurs and dst has created during current method call then dst will be removed
-func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount bool) error {
+func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount, dstIsDir bool,
+	mountOptions ...string) error {
 	ll := fsOp.log.WithFields(logrus.Fields{
 		"method": "PrepareAndPerformMount",
 	})
@@ -67,7 +73,12 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount
 		if !os.IsNotExist(err) {
 			return err
 		}
-		if err = fsOp.MkDir(dst); err != nil {
+		if dstIsDir {
+			err = fsOp.MkDir(dst)
+		} else {
+			err = fsOp.MkFile(dst)
+		}
+		if err != nil {
 			return err
 		}
 		wasCreated = true // if something went wrong we will remove path that had created based on that flag
@@ -82,20 +93,44 @@ func (fsOp *FSOperationsImpl) PrepareAndPerformMount(src, dst string, bindMount
 		}
 		if alreadyMounted {
 			ll.Infof("%s has already mounted to %s", src, dst)
+			if bindMount && len(mountOptions) > 0 {
+				return fsOp.remountBind(dst, mountOptions)
+			}
 			return nil
 		}
 	}
 
-	var opts string
+	opts := make([]string, 0, 2)
 	if bindMount {
-		opts = fs.BindOption
+		opts = append(opts, fs.BindOption)
+	} else if len(mountOptions) > 0 {
+		opts = append(opts, "-o "+strings.Join(mountOptions, fs.MountOptionsSeparator))
 	}
-	if err := fsOp.Mount(src, dst, opts); err != nil {
+	if err := fsOp.Mount(src, dst, opts...); err != nil {
 		if wasCreated {
 			_ = fsOp.RmDir(dst)
 		}
 		return fmt.Errorf("unable to mount %s to %s: %v", src, dst, err)
 	}
+
+	if bindMount && len(mountOptions) > 0 {
+		if err := fsOp.remountBind(dst, mountOptions); err != nil {
+			_ = fsOp.Unmount(dst)
+			if wasCreated {
+				_ = fsOp.RmDir(dst)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// remountBind applies mountOptions to the bind mount dst
+func (fsOp *FSOperationsImpl) remountBind(dst string, mountOptions []string) error {
+	remountOpts := append([]string{fs.RemountOption, "bind"}, mountOptions...)
+	if err := fsOp.Mount("", dst, "-o "+strings.Join(remountOpts, fs.MountOptionsSeparator)); err != nil {
+		return fmt.Errorf("unable to remount %s with options %v: %v", dst, mountOptions, err)
+	}
 	return nil
 }
 
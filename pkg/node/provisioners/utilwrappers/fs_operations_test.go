@@ -34,7 +34,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Success(t *testing.T) {
 		wrapFS     = &mocklu.MockWrapFS{}
 		dst        = "~/some/unusual/name"
 		src        = "/tmp"
-		bindOption = []string{""} // for bind == false
+		bindOption = []string{} // for bind == false
 		err        error
 	)
 	fsOps.WrapFS = wrapFS
@@ -42,21 +42,51 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Success(t *testing.T) {
 	// dst folder isn't exist
 	wrapFS.On("MkDir", dst).Return(nil).Once()
 	wrapFS.On("Mount", src, dst, bindOption).Return(nil).Once()
-	err = fsOps.PrepareAndPerformMount(src, dst, false)
+	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
 	assert.Nil(t, err)
 	wrapFS.AssertCalled(t, "MkDir", dst) // ensure that folder was created
 
 	// dst folder is exist and has already mounted
 	dst = "/tmp"
 	wrapFS.On("IsMounted", dst).Return(true, nil).Once()
-	err = fsOps.PrepareAndPerformMount(src, dst, false)
+	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
 
 	// dst folder is exist and isn't a mount point, also use bind = true
 	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
 	wrapFS.On("Mount", src, dst, []string{fs.BindOption}).Return(nil).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, true)
+	err = fsOps.PrepareAndPerformMount(src, dst, true, true)
 	wrapFS.AssertCalled(t, "IsMounted", dst)
+
+	// dst file isn't exist, bind mount of block device
+	dst = "~/some/unusual/file"
+	wrapFS.On("MkFile", dst).Return(nil).Once()
+	wrapFS.On("Mount", src, dst, []string{fs.BindOption}).Return(nil).Once()
+	err = fsOps.PrepareAndPerformMount(src, dst, true, false)
+	assert.Nil(t, err)
+	wrapFS.AssertCalled(t, "MkFile", dst)
+	wrapFS.AssertNotCalled(t, "MkDir", dst)
+
+	// dst folder is exist and isn't a mount point, mount with options
+	dst = "/tmp"
+	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
+	wrapFS.On("Mount", src, dst, []string{"-o noatime,discard"}).Return(nil).Once()
+	err = fsOps.PrepareAndPerformMount(src, dst, false, true, "noatime", "discard")
+	assert.Nil(t, err)
+
+	// read-only bind mount, bind mount is remounted with options
+	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
+	wrapFS.On("Mount", src, dst, []string{fs.BindOption}).Return(nil).Once()
+	wrapFS.On("Mount", "", dst, []string{"-o remount,bind,ro"}).Return(nil).Once()
+	err = fsOps.PrepareAndPerformMount(src, dst, true, true, fs.ReadOnlyOption)
+	assert.Nil(t, err)
+
+	// bind mount already exists, it is remounted with options
+	wrapFS.On("IsMounted", dst).Return(true, nil).Once()
+	wrapFS.On("Mount", "", dst, []string{"-o remount,bind,ro"}).Return(nil).Once()
+	err = fsOps.PrepareAndPerformMount(src, dst, true, true, fs.ReadOnlyOption)
+	assert.Nil(t, err)
+	wrapFS.AssertNumberOfCalls(t, "Mount", 7)
 }
 
 func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
@@ -65,7 +95,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 		wrapFS      = &mocklu.MockWrapFS{}
 		dst         = "~/some/unusual/name"
 		src         = "/tmp"
-		bindOption  = []string{""} // for bind == false
+		bindOption  = []string{} // for bind == false
 		expectedErr = errors.New("error")
 		err         error
 	)
@@ -74,7 +104,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 	// dst ins't exist and MkDir failed
 	wrapFS.On("MkDir", dst).Return(expectedErr).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, false)
+	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
 	assert.Error(t, err)
 	assert.Equal(t, expectedErr, err)
 
@@ -83,7 +113,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 	wrapFS.On("IsMounted", dst).Return(false, expectedErr).Once()
 	wrapFS.On("RmDir", dst).Return(nil).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, false)
+	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
 
 	assert.Error(t, err)
 	wrapFS.AssertCalled(t, "RmDir", dst)
@@ -94,7 +124,7 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 	wrapFS.On("Mount", src, dst, bindOption).Return(expectedErr).Once()
 	wrapFS.On("RmDir", dst).Return(nil).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, false)
+	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
 	assert.Error(t, err)
 	wrapFS.AssertCalled(t, "MkDir", dst)
 	wrapFS.AssertCalled(t, "RmDir", dst)
@@ -104,10 +134,29 @@ func TestFSOperationsImpl_PrepareAndPerformMount_Fail(t *testing.T) {
 	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
 	wrapFS.On("Mount", src, dst, bindOption).Return(expectedErr).Once()
 
-	err = fsOps.PrepareAndPerformMount(src, dst, false)
+	err = fsOps.PrepareAndPerformMount(src, dst, false, true)
 	assert.Error(t, err)
 	wrapFS.AssertCalled(t, "IsMounted", dst)
 	wrapFS.AssertNotCalled(t, "RmDir", dst)
+
+	// remount of bind mount failed, dst should be unmounted
+	wrapFS.On("IsMounted", dst).Return(false, nil).Once()
+	wrapFS.On("Mount", src, dst, []string{fs.BindOption}).Return(nil).Once()
+	wrapFS.On("Mount", "", dst, []string{"-o remount,bind,ro"}).Return(expectedErr).Once()
+	wrapFS.On("Unmount", dst).Return(nil).Once()
+
+	err = fsOps.PrepareAndPerformMount(src, dst, true, true, fs.ReadOnlyOption)
+	assert.Error(t, err)
+	wrapFS.AssertCalled(t, "Unmount", dst)
+
+	// remount of existing bind mount failed, it isn't unmounted since it was mounted by previous call
+	dst = "/tmp"
+	wrapFS.On("IsMounted", dst).Return(true, nil).Once()
+	wrapFS.On("Mount", "", dst, []string{"-o remount,bind,ro"}).Return(expectedErr).Once()
+
+	err = fsOps.PrepareAndPerformMount(src, dst, true, true, fs.ReadOnlyOption)
+	assert.Error(t, err)
+	wrapFS.AssertNotCalled(t, "Unmount", dst)
 }
 
 func TestFSOperationsImpl_MountWithCheck_Success(t *testing.T) {
@@ -31,6 +31,8 @@ const (
 	DriveBasedVolumeType VolumeType = "DriveBased"
 	// LVMBasedVolumeType represents volume that based on Volume Group
 	LVMBasedVolumeType VolumeType = "LVMBased"
+	// MDRaidBasedVolumeType represents volume that based on MD RAID array of several whole drives
+	MDRaidBasedVolumeType VolumeType = "MDRaidBased"
 )
 
 // Provisioner is a high-level interface that encapsulates all low-level work with volumes on node
@@ -20,8 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -45,11 +47,16 @@ import (
 	"github.com/dell/csi-baremetal/pkg/base/capacityplanner"
 	"github.com/dell/csi-baremetal/pkg/base/command"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/blockdev"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/cgroup"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/cryptsetup"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/mdadm"
 	ph "github.com/dell/csi-baremetal/pkg/base/linuxutils/partitionhelper"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/common"
+	"github.com/dell/csi-baremetal/pkg/drivemgr/healthpolicy"
 	"github.com/dell/csi-baremetal/pkg/eventing"
 	p "github.com/dell/csi-baremetal/pkg/node/provisioners"
 	"github.com/dell/csi-baremetal/pkg/node/provisioners/utilwrappers"
@@ -80,8 +87,16 @@ type VolumeManager struct {
 	fsOps utilwrappers.FSOperations
 	// uses for LVM operations
 	lvmOps lvm.WrapLVM
+	// uses for inspecting state of MD RAID arrays
+	mdOps mdadm.WrapMDADM
 	// uses for running lsblk util
 	listBlk lsblk.WrapLsblk
+	// uses for setting I/O limits of the volumes in cgroups of the pods
+	cgroupOps cgroup.WrapCgroup
+	// uses for opening and closing dm-crypt mappings of encrypted volumes
+	cryptOps cryptsetup.WrapCryptsetup
+	// uses for preparing replaced drives for physical removal
+	blockDevOps blockdev.WrapBlockDev
 
 	// uses for searching suitable Available Capacity
 	acProvider common.AvailableCapacityOperations
@@ -102,6 +117,14 @@ type VolumeManager struct {
 	// systemDrivesUUIDs represent system drive uuids, used to avoid unnecessary calls to Kubernetes API.
 	// We use slice in case of RAID and multiple system disks
 	systemDrivesUUIDs []string
+	// serializes discovery which is triggered by polling and by drive events
+	discoverMu sync.Mutex
+	// whether drive events are received from DriveManager, accessed atomically
+	drivesWatched int32
+	// percentages of drive life left, event is sent when endurance of the drive falls to one of them
+	enduranceThresholds []int64
+	// LVG name to health of its thin pool, accessed under discoverMu
+	thinPoolsHealth map[string]string
 }
 
 // driveStates internal struct, holds info about drive updates
@@ -138,8 +161,15 @@ const (
 	VolumeOperationsTimeout = 900 * time.Second
 	// amount of reconcile requests that could be processed simultaneously
 	maxConcurrentReconciles = 15
+	// thin pool becomes SUSPECT when its data or metadata usage reaches that percentage
+	thinPoolUsageSuspectThreshold = 80
+	// thin pool becomes BAD when its data or metadata usage reaches that percentage
+	thinPoolUsageBadThreshold = 95
 )
 
+// DefaultEnduranceThresholds are percentages of drive life left on which DriveEnduranceLow event is sent by default
+var DefaultEnduranceThresholds = []int64{20, 10, 5}
+
 // NewVolumeManager is the constructor for VolumeManager struct
 // Receives an instance of DriveServiceClient to interact with DriveManager, CmdExecutor to execute linux commands,
 // logrus logger, base.KubeClient and ID of a node where VolumeManager works
@@ -156,23 +186,35 @@ func NewVolumeManager(
 		driveMgrClient: client,
 		acProvider:     common.NewACOperationsImpl(k8sclient, logger),
 		provisioners: map[p.VolumeType]p.Provisioner{
-			p.DriveBasedVolumeType: p.NewDriveProvisioner(executor, k8sclient, logger),
-			p.LVMBasedVolumeType:   p.NewLVMProvisioner(executor, k8sclient, logger),
+			p.DriveBasedVolumeType:  p.NewDriveProvisioner(executor, k8sclient, logger),
+			p.LVMBasedVolumeType:    p.NewLVMProvisioner(executor, k8sclient, logger),
+			p.MDRaidBasedVolumeType: p.NewMDRaidProvisioner(executor, k8sclient, logger),
 		},
-		fsOps:             utilwrappers.NewFSOperationsImpl(executor, logger),
-		lvmOps:            lvm.NewLVM(executor, logger),
-		listBlk:           lsblk.NewLSBLK(logger),
-		partOps:           ph.NewWrapPartitionImpl(executor, logger),
-		nodeID:            nodeID,
-		log:               logger.WithField("component", "VolumeManager"),
-		recorder:          recorder,
-		discoverLvgSSD:    true,
-		volMu:             keymutex.NewHashed(0),
-		systemDrivesUUIDs: make([]string, 0),
+		fsOps:               utilwrappers.NewFSOperationsImpl(executor, logger),
+		lvmOps:              lvm.NewLVM(executor, logger),
+		mdOps:               mdadm.NewMDADM(executor, logger),
+		listBlk:             lsblk.NewLSBLK(logger),
+		cgroupOps:           cgroup.NewCgroup(logger),
+		cryptOps:            cryptsetup.NewCryptsetup(executor, logger),
+		blockDevOps:         blockdev.NewBlockDev(logger),
+		partOps:             ph.NewWrapPartitionImpl(executor, logger),
+		nodeID:              nodeID,
+		log:                 logger.WithField("component", "VolumeManager"),
+		recorder:            recorder,
+		discoverLvgSSD:      true,
+		volMu:               keymutex.NewHashed(0),
+		systemDrivesUUIDs:   make([]string, 0),
+		enduranceThresholds: DefaultEnduranceThresholds,
+		thinPoolsHealth:     map[string]string{},
 	}
 	return vm
 }
 
+// SetEnduranceThresholds sets percentages of drive life left on which DriveEnduranceLow event is sent
+func (m *VolumeManager) SetEnduranceThresholds(thresholds []int64) {
+	m.enduranceThresholds = thresholds
+}
+
 // SetProvisioners sets provisioners for current VolumeManager instance
 // uses for UTs and Sanity tests purposes
 func (m *VolumeManager) SetProvisioners(provs map[p.VolumeType]p.Provisioner) {
@@ -251,7 +293,8 @@ func (m *VolumeManager) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 }
 
 // handleCreatingVolumeInLVG handles volume CR that has storage class related to LVG and CSIStatus creating
-// check whether underlying LVG ready or not, add volume to LVG volumeRefs (if needed) and create real storage based on volume
+// check whether underlying LVG (and cache LVG of cached volume) ready or not, add volume to LVG volumeRefs (if needed)
+// and create real storage based on volume
 // uses as a step for Reconcile for Volume CR
 func (m *VolumeManager) handleCreatingVolumeInLVG(ctx context.Context, volume *volumecrd.Volume) (ctrl.Result, error) {
 	ll := m.log.WithFields(logrus.Fields{
@@ -259,52 +302,61 @@ func (m *VolumeManager) handleCreatingVolumeInLVG(ctx context.Context, volume *v
 		"volumeID": volume.Spec.Id,
 	})
 
-	var (
-		lvg = &lvgcrd.LVG{}
-		err error
-	)
-
-	if err = m.k8sClient.ReadCR(ctx, volume.Spec.Location, lvg); err != nil {
-		ll.Errorf("Unable to read underlying LVG %s: %v", volume.Spec.Location, err)
-		if k8sError.IsNotFound(err) {
-			volume.Spec.CSIStatus = apiV1.Failed
-			err = m.k8sClient.UpdateCR(ctx, volume)
-			if err == nil {
-				return ctrl.Result{}, nil // no need to retry
-			}
-			ll.Errorf("Unable to update volume CR and set status to failed: %v", err)
-		}
-		// retry because of LVG wasn't read or Volume status wasn't updated
-		return ctrl.Result{Requeue: true, RequeueAfter: base.DefaultRequeueForVolume}, err
+	// cached volume is created in two LVGs, its cache LV is placed in LVG on faster drives
+	locations := []string{volume.Spec.Location}
+	if volume.Spec.CacheLocation != "" {
+		locations = append(locations, volume.Spec.CacheLocation)
 	}
 
-	switch lvg.Spec.Status {
-	case apiV1.Creating:
-		ll.Debugf("Underlying LVG %s is still being created", lvg.Name)
-		return ctrl.Result{Requeue: true, RequeueAfter: base.DefaultRequeueForVolume}, nil
-	case apiV1.Failed:
-		ll.Errorf("Underlying LVG %s has reached failed status. Unable to create volume on failed lvg.", lvg.Name)
-		volume.Spec.CSIStatus = apiV1.Failed
-		if err = m.k8sClient.UpdateCR(ctx, volume); err != nil {
-			ll.Errorf("Unable to update volume CR and set status to failed: %v", err)
-			// retry because of volume status wasn't updated
+	for _, location := range locations {
+		var (
+			lvg = &lvgcrd.LVG{}
+			err error
+		)
+
+		if err = m.k8sClient.ReadCR(ctx, location, lvg); err != nil {
+			ll.Errorf("Unable to read underlying LVG %s: %v", location, err)
+			if k8sError.IsNotFound(err) {
+				volume.Spec.CSIStatus = apiV1.Failed
+				err = m.k8sClient.UpdateCR(ctx, volume)
+				if err == nil {
+					return ctrl.Result{}, nil // no need to retry
+				}
+				ll.Errorf("Unable to update volume CR and set status to failed: %v", err)
+			}
+			// retry because of LVG wasn't read or Volume status wasn't updated
 			return ctrl.Result{Requeue: true, RequeueAfter: base.DefaultRequeueForVolume}, err
 		}
-		return ctrl.Result{}, nil // no need to retry
-	case apiV1.Created:
-		// add volume ID to LVG.Spec.VolumeRefs
-		if !util.ContainsString(lvg.Spec.VolumeRefs, volume.Spec.Id) {
-			lvg.Spec.VolumeRefs = append(lvg.Spec.VolumeRefs, volume.Spec.Id)
-			if err = m.k8sClient.UpdateCR(ctx, lvg); err != nil {
-				ll.Errorf("Unable to add Volume ID to LVG %s volume refs: %v", lvg.Name, err)
-				return ctrl.Result{Requeue: true}, err
+
+		switch lvg.Spec.Status {
+		case apiV1.Creating:
+			ll.Debugf("Underlying LVG %s is still being created", lvg.Name)
+			return ctrl.Result{Requeue: true, RequeueAfter: base.DefaultRequeueForVolume}, nil
+		case apiV1.Failed:
+			ll.Errorf("Underlying LVG %s has reached failed status. Unable to create volume on failed lvg.", lvg.Name)
+			volume.Spec.CSIStatus = apiV1.Failed
+			if err = m.k8sClient.UpdateCR(ctx, volume); err != nil {
+				ll.Errorf("Unable to update volume CR and set status to failed: %v", err)
+				// retry because of volume status wasn't updated
+				return ctrl.Result{Requeue: true, RequeueAfter: base.DefaultRequeueForVolume}, err
+			}
+			return ctrl.Result{}, nil // no need to retry
+		case apiV1.Created:
+			// add volume ID to LVG.Spec.VolumeRefs
+			if !util.ContainsString(lvg.Spec.VolumeRefs, volume.Spec.Id) {
+				lvg.Spec.VolumeRefs = append(lvg.Spec.VolumeRefs, volume.Spec.Id)
+				if err = m.k8sClient.UpdateCR(ctx, lvg); err != nil {
+					ll.Errorf("Unable to add Volume ID to LVG %s volume refs: %v", lvg.Name, err)
+					return ctrl.Result{Requeue: true}, err
+				}
 			}
+		default:
+			ll.Warnf("Unable to recognize LVG status. LVG - %v", lvg)
+			return ctrl.Result{Requeue: true, RequeueAfter: base.DefaultRequeueForVolume}, nil
 		}
-		return m.prepareVolume(ctx, volume)
-	default:
-		ll.Warnf("Unable to recognize LVG status. LVG - %v", lvg)
-		return ctrl.Result{Requeue: true, RequeueAfter: base.DefaultRequeueForVolume}, nil
 	}
+
+	return m.prepareVolume(ctx, volume)
 }
 
 // prepareVolume prepares real storage based on provided volume and update corresponding volume CR's CSIStatus
@@ -398,7 +450,8 @@ func (m *VolumeManager) isCorrespondedToNodePredicate(obj runtime.Object) bool {
 
 // Discover inspects actual drives structs from DriveManager and create volume object if partition exist on some of them
 // (in case of VolumeManager restart). Updates Drives CRs based on gathered from DriveManager information.
-// Also this method creates AC CRs. Performs at some intervals in a goroutine
+// Also this method creates AC CRs, restores I/O limits of published volumes and moves drives through replacement workflow.
+// Performs at some intervals in a goroutine as a full resync, incremental changes are applied by WatchDrives
 // Returns error if something went wrong during discovering
 func (m *VolumeManager) Discover() error {
 	ctx, cancelFn := context.WithTimeout(context.Background(), DiscoverDrivesTimeout)
@@ -408,7 +461,15 @@ func (m *VolumeManager) Discover() error {
 		return err
 	}
 
-	updates, err := m.updateDrivesCRs(ctx, drivesResponse.Disks)
+	m.discoverMu.Lock()
+	defer m.discoverMu.Unlock()
+	return m.discover(ctx, drivesResponse.Disks)
+}
+
+// discover updates Drives CRs based on provided drives and performs the rest of discovery steps
+// discoverMu must be held by caller
+func (m *VolumeManager) discover(ctx context.Context, drives []*api.Drive) error {
+	updates, err := m.updateDrivesCRs(ctx, drives)
 	if err != nil {
 		return fmt.Errorf("updateDrivesCRs return error: %v", err)
 	}
@@ -434,10 +495,39 @@ func (m *VolumeManager) Discover() error {
 		return fmt.Errorf("discoverAvailableCapacity return error: %v", err)
 	}
 
+	m.discoverCachedVolumes()
+	m.discoverIOLimits()
+	m.maintain(ctx)
+
 	m.initialized = true
 	return nil
 }
 
+// Maintain performs the part of discovery which doesn't need drives list from DriveManager,
+// it's used between full resyncs of drives when changes of drives are received through WatchDrives
+func (m *VolumeManager) Maintain() {
+	ctx, cancelFn := context.WithTimeout(context.Background(), DiscoverDrivesTimeout)
+	defer cancelFn()
+
+	m.discoverMu.Lock()
+	defer m.discoverMu.Unlock()
+
+	// the first discovery is performed with full list by Discover
+	if !m.initialized {
+		return
+	}
+	m.maintain(ctx)
+}
+
+// maintain checks health of MD RAID arrays and usage of thin pools, handles drive replacement and drive LEDs
+// discoverMu must be held by caller
+func (m *VolumeManager) maintain(ctx context.Context) {
+	m.discoverMDRaidHealth(ctx)
+	m.discoverThinPoolsUsage(ctx)
+	m.handleDrivesReplacement(ctx)
+	m.reconcileDrivesLED(ctx)
+}
+
 // updateDrivesCRs updates Drives CRs based on provided list of Drives.
 // Receives golang context and slice of discovered api.Drive structs usually got from DriveManager
 // returns struct with information about drives updates
@@ -473,6 +563,12 @@ func (m *VolumeManager) updateDrivesCRs(ctx context.Context, drivesFromMgr []*ap
 				} else {
 					previousState := driveCR.DeepCopy()
 					drivePtr.UUID = driveCR.Spec.UUID
+					// operational status is managed by drive replacement workflow, drivemgr doesn't know about it
+					drivePtr.OperationalStatus = driveCR.Spec.OperationalStatus
+					// not every drivemgr is able to report LED state, keep the last one set through SetDriveLED
+					if drivePtr.LEDState == "" {
+						drivePtr.LEDState = driveCR.Spec.LEDState
+					}
 					toUpdate := driveCR
 					toUpdate.Spec = *drivePtr
 					if err := m.k8sClient.UpdateCR(ctx, &toUpdate); err != nil {
@@ -491,6 +587,7 @@ func (m *VolumeManager) updateDrivesCRs(ctx context.Context, drivesFromMgr []*ap
 			toCreateSpec := *drivePtr
 			toCreateSpec.NodeId = m.nodeID
 			toCreateSpec.UUID = uuid.New().String()
+			toCreateSpec.OperationalStatus = apiV1.DriveOpStatusOperative
 			isSystem, err := m.isDriveSystem(drivePtr.Path)
 			if err != nil {
 				ll.Errorf("Failed to determine if drive %v is system, error: %v", drivePtr, err)
@@ -586,6 +683,9 @@ func (m *VolumeManager) drivesAreNotUsed() ([]*drivecrd.Drive, error) {
 	var locations = make(map[string]struct{}, len(volumeCRs))
 	for _, v := range volumeCRs {
 		locations[v.Spec.Location] = struct{}{}
+		for _, location := range v.Spec.Locations {
+			locations[location] = struct{}{}
+		}
 	}
 	for _, lvg := range lvgCRs {
 		if len(lvg.Spec.Locations) > 0 && util.ContainsString(m.systemDrivesUUIDs, lvg.Spec.Locations[0]) {
@@ -708,6 +808,10 @@ func (m *VolumeManager) discoverAvailableCapacity(ctx context.Context, freeDrive
 	}
 	for _, v := range volumeList.Items {
 		volumeLocations[v.Spec.Location] = struct{}{}
+		// members of MD RAID array
+		for _, location := range v.Spec.Locations {
+			volumeLocations[location] = struct{}{}
+		}
 	}
 
 	for _, drive := range freeDrives {
@@ -841,6 +945,7 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 			Size:       vgFreeSpace,
 			Status:     apiV1.Created,
 			VolumeRefs: lvs,
+			Health:     apiV1.HealthGood,
 		}
 		vgCR = m.k8sClient.ConstructLVGCR(vgCRName, vg)
 		ctx  = context.WithValue(context.Background(), k8s.RequestUUID, vg.Name)
@@ -851,11 +956,268 @@ func (m *VolumeManager) discoverLVGOnSystemDrive() error {
 	return m.createACIfFreeSpace(vgCRName, apiV1.StorageClassSystemLVG, vgFreeSpace)
 }
 
+// discoverIOLimits applies I/O limits of the published volumes to cgroups of the pods which use them,
+// so limits are restored if they weren't applied during NodePublishVolume or were lost
+func (m *VolumeManager) discoverIOLimits() {
+	ll := m.log.WithField("method", "discoverIOLimits")
+
+	volumes, err := m.crHelper.GetVolumeCRs(m.nodeID)
+	if err != nil {
+		ll.Errorf("Unable to read volume CRs: %v", err)
+		return
+	}
+
+	for i := range volumes {
+		vol := &volumes[i].Spec
+		if vol.CSIStatus != apiV1.Published {
+			continue
+		}
+		for _, owner := range vol.Owners {
+			if err = m.applyIOLimits(vol, owner); err != nil {
+				ll.Errorf("Unable to apply I/O limits of volume %s for pod %s: %v", vol.Id, owner, err)
+			}
+		}
+	}
+}
+
+// discoverCachedVolumes activates VGs of the cached volumes. Cache LV is a PV of VG of the cached volume,
+// so after node reboot VG of the cache LV has to be activated first, otherwise VG of the volume has missing PV
+func (m *VolumeManager) discoverCachedVolumes() {
+	ll := m.log.WithField("method", "discoverCachedVolumes")
+
+	volumes, err := m.crHelper.GetVolumeCRs(m.nodeID)
+	if err != nil {
+		ll.Errorf("Unable to read volume CRs: %v", err)
+		return
+	}
+
+	activated := make(map[string]bool)
+	for _, vol := range volumes {
+		if vol.Spec.CacheLocation == "" {
+			continue
+		}
+		switch vol.Spec.CSIStatus {
+		case apiV1.Created, apiV1.VolumeReady, apiV1.Published:
+		default:
+			continue
+		}
+
+		for _, vgName := range []string{vol.Spec.CacheLocation, vol.Spec.Location} {
+			if activated[vgName] {
+				continue
+			}
+			if err = m.lvmOps.VGActivate(vgName); err != nil {
+				ll.Errorf("Unable to activate VG %s of volume %s: %v", vgName, vol.Name, err)
+				break
+			}
+			activated[vgName] = true
+		}
+	}
+}
+
+// assembleMDArray assembles MD array of the volume from its members, members which drives are absent are skipped
+func (m *VolumeManager) assembleMDArray(vol *volumecrd.Volume, name string) error {
+	ll := m.log.WithField("method", "assembleMDArray")
+
+	devices := make([]string, 0, len(vol.Spec.Locations))
+	for _, location := range vol.Spec.Locations {
+		drive := m.crHelper.GetDriveCRByUUID(location)
+		if drive == nil {
+			ll.Warnf("Member %s of MD array %s is skipped: drive CR isn't found", location, name)
+			continue
+		}
+		device, err := m.listBlk.SearchDrivePath(drive)
+		if err != nil {
+			ll.Warnf("Member %s of MD array %s is skipped: %v", location, name, err)
+			continue
+		}
+		devices = append(devices, device)
+	}
+	if len(devices) == 0 {
+		return fmt.Errorf("none of the members is found")
+	}
+
+	ll.Infof("Assembling MD array %s of volume %s on devices %v", name, vol.Name, devices)
+	return m.mdOps.AssembleArray(name, devices)
+}
+
+// discoverMDRaidHealth assembles MD RAID arrays of the volumes which aren't running (e.g. after node reboot)
+// and sets health of the volumes according to the arrays state in /proc/mdstat:
+// degraded array is SUSPECT since it still serves data, inactive array is BAD
+// and array which state can't be determined is UNKNOWN
+func (m *VolumeManager) discoverMDRaidHealth(ctx context.Context) {
+	ll := m.log.WithField("method", "discoverMDRaidHealth")
+
+	volumes, err := m.crHelper.GetVolumeCRs(m.nodeID)
+	if err != nil {
+		ll.Errorf("Unable to read volume CRs: %v", err)
+		return
+	}
+
+	for i := range volumes {
+		vol := &volumes[i]
+		if vol.Spec.LocationType != apiV1.LocationTypeMDRaid {
+			continue
+		}
+		switch vol.Spec.CSIStatus {
+		case apiV1.Created, apiV1.VolumeReady, apiV1.Published:
+		default:
+			continue
+		}
+
+		var (
+			name           = p.GetMDArrayName(vol.Spec.Id)
+			health         string
+			reason, detail string
+		)
+		array, err := m.mdOps.GetArray(name)
+		if err != nil {
+			// array isn't assembled automatically after node reboot
+			if aErr := m.assembleMDArray(vol, name); aErr != nil {
+				ll.Errorf("Unable to assemble MD array %s of volume %s: %v", name, vol.Name, aErr)
+			} else {
+				array, err = m.mdOps.GetArray(name)
+			}
+		}
+		switch {
+		case err != nil:
+			health, detail = apiV1.HealthUnknown, err.Error()
+		case array.State != mdadm.ArrayStateActive:
+			health, detail = apiV1.HealthBad, fmt.Sprintf("MD array %s is %s", array.Name, array.State)
+		case array.IsDegraded():
+			health = apiV1.HealthSuspect
+			detail = fmt.Sprintf("MD array %s is degraded, %d of %d drives are active, failed drives: %v",
+				array.Name, array.ActiveDisks, array.RaidDisks, array.FailedDevices)
+		default:
+			health, detail = apiV1.HealthGood, fmt.Sprintf("MD array %s is active", array.Name)
+		}
+		if vol.Spec.Health == health {
+			continue
+		}
+
+		ll.Infof("Setting health %s to volume %s, previous health %s: %s", health, vol.Name, vol.Spec.Health, detail)
+		prevHealthState := vol.Spec.Health
+		vol.Spec.Health = health
+		if err = m.k8sClient.UpdateCR(ctx, vol); err != nil {
+			ll.Errorf("Failed to update volume CR's %s health status: %v", vol.Name, err)
+			continue
+		}
+
+		eventType := eventing.WarningType
+		switch health {
+		case apiV1.HealthGood:
+			eventType, reason = eventing.InfoType, eventing.VolumeGoodHealth
+		case apiV1.HealthSuspect:
+			reason = eventing.VolumeSuspectHealth
+		case apiV1.HealthBad:
+			eventType, reason = eventing.ErrorType, eventing.VolumeBadHealth
+		default:
+			reason = eventing.VolumeUnknownHealth
+		}
+		m.recorder.Eventf(vol, eventType, reason, "Volume health transitioned from %s to %s. %s",
+			prevHealthState, health, detail)
+	}
+}
+
+// discoverThinPoolsUsage sets health of thin pool LVGs according to data and metadata usage of their thin pools:
+// thin pool which usage reaches thinPoolUsageSuspectThreshold is SUSPECT, thinPoolUsageBadThreshold - BAD,
+// event is sent on LVG when health of its thin pool is changed, LVG health is the worst among its drives and thin pool
+func (m *VolumeManager) discoverThinPoolsUsage(ctx context.Context) {
+	ll := m.log.WithField("method", "discoverThinPoolsUsage")
+
+	for _, lvg := range m.crHelper.GetLVGCRs(m.nodeID) {
+		lvg := lvg
+		if !lvg.Spec.ThinPool || lvg.Spec.Status != apiV1.Created {
+			continue
+		}
+
+		data, metadata, err := m.lvmOps.GetThinPoolUsage(lvg.Spec.Name, lvm.ThinPoolName)
+		if err != nil {
+			ll.Errorf("Unable to read usage of thin pool in LVG %s: %v", lvg.Name, err)
+			continue
+		}
+		var (
+			usage                   = math.Max(data, metadata)
+			health                  = apiV1.HealthGood
+			eventType, reason       = eventing.InfoType, eventing.ThinPoolUsageNormal
+			prevHealth, isKnownPool = m.thinPoolsHealth[lvg.Name]
+		)
+		switch {
+		case usage >= thinPoolUsageBadThreshold:
+			health, eventType, reason = apiV1.HealthBad, eventing.ErrorType, eventing.ThinPoolFull
+		case usage >= thinPoolUsageSuspectThreshold:
+			health, eventType, reason = apiV1.HealthSuspect, eventing.WarningType, eventing.ThinPoolUsageHigh
+		}
+		m.thinPoolsHealth[lvg.Name] = health
+		if prevHealth != health && (isKnownPool || health != apiV1.HealthGood) {
+			m.recorder.Eventf(&lvg, eventType, reason,
+				"Thin pool health transitioned from %s to %s. Data usage %.2f%%, metadata usage %.2f%%",
+				prevHealth, health, data, metadata)
+		}
+
+		for _, location := range lvg.Spec.Locations {
+			if d := m.crHelper.GetDriveCRByUUID(location); d != nil {
+				health = healthpolicy.Worst(health, d.Spec.Health)
+			}
+		}
+		if lvg.Spec.Health != health {
+			m.setLVGHealth(ctx, &lvg, health, fmt.Sprintf("thin pool with %.2f%% usage", usage))
+		}
+	}
+}
+
+// applyIOLimits writes I/O limits of the volume for its device into cgroup of the pod which uses volume,
+// nothing is done if volume doesn't have limits. Returns error if owner isn't a pod UID (see getVolumeOwner)
+func (m *VolumeManager) applyIOLimits(vol *api.Volume, owner string) error {
+	limits := getIOLimits(vol)
+	if limits.IsEmpty() {
+		return nil
+	}
+	if strings.Contains(owner, "/") {
+		return fmt.Errorf("unable to determine pod from target path %s", owner)
+	}
+
+	device, err := m.getProvisionerForVolume(vol).GetVolumePath(*vol)
+	if err != nil {
+		return err
+	}
+	return m.cgroupOps.SetIOLimits(owner, device, limits)
+}
+
+// clearIOLimits removes I/O limits of the volume for its device from cgroup of the pod which used volume
+func (m *VolumeManager) clearIOLimits(vol *api.Volume, owner string) error {
+	if getIOLimits(vol).IsEmpty() {
+		return nil
+	}
+	if strings.Contains(owner, "/") {
+		return fmt.Errorf("unable to determine pod from target path %s", owner)
+	}
+
+	device, err := m.getProvisionerForVolume(vol).GetVolumePath(*vol)
+	if err != nil {
+		return err
+	}
+	return m.cgroupOps.ClearIOLimits(owner, device)
+}
+
+// getIOLimits returns I/O limits of the volume
+func getIOLimits(vol *api.Volume) cgroup.IOLimits {
+	return cgroup.IOLimits{
+		ReadBps:   vol.ReadBps,
+		WriteBps:  vol.WriteBps,
+		ReadIops:  vol.ReadIops,
+		WriteIops: vol.WriteIops,
+	}
+}
+
 // getProvisionerForVolume returns appropriate Provisioner implementation for volume
 func (m *VolumeManager) getProvisionerForVolume(vol *api.Volume) p.Provisioner {
 	if util.IsStorageClassLVG(vol.StorageClass) {
 		return m.provisioners[p.LVMBasedVolumeType]
 	}
+	if util.IsStorageClassRAID(vol.StorageClass) {
+		return m.provisioners[p.MDRaidBasedVolumeType]
+	}
 
 	return m.provisioners[p.DriveBasedVolumeType]
 }
@@ -884,8 +1246,9 @@ func (m *VolumeManager) handleDriveStatusChange(ctx context.Context, drive *api.
 	}
 
 	// Set disk's health status to volume CR
+	// health of the volume on MD RAID array depends on the array state, see discoverMDRaidHealth
 	vol := m.crHelper.GetVolumeByLocation(drive.UUID)
-	if vol != nil {
+	if vol != nil && vol.Spec.LocationType != apiV1.LocationTypeMDRaid {
 		ll.Infof("Setting updated status %s to volume %s", drive.Health, vol.Name)
 		// save previous health state
 		prevHealthState := vol.Spec.Health
@@ -901,8 +1264,134 @@ func (m *VolumeManager) handleDriveStatusChange(ctx context.Context, drive *api.
 	}
 
 	// Handle resources with LVG
-	// This is not work for the current moment because HAL doesn't monitor disks with LVM
-	// TODO: Handle disk health which are used by LVGs - https://github.com/dell/csi-baremetal/issues/88
+	m.handleLVGHealthChange(ctx, drive)
+}
+
+// handleLVGHealthChange propagates health of the drive to LVGs based on it and to the volumes on these LVGs.
+// AC of unhealthy LVG is zeroed to avoid new allocations and restored when LVG becomes healthy again.
+// LVG health is the worst health among its drives.
+// Receives golang context and api.Drive that should be handled
+func (m *VolumeManager) handleLVGHealthChange(ctx context.Context, drive *api.Drive) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":  "handleLVGHealthChange",
+		"driveID": drive.UUID,
+	})
+
+	for _, lvg := range m.crHelper.GetLVGCRs(m.nodeID) {
+		lvg := lvg
+		if !util.ContainsString(lvg.Spec.Locations, drive.UUID) {
+			continue
+		}
+
+		health := drive.Health
+		for _, location := range lvg.Spec.Locations {
+			if location == drive.UUID {
+				continue
+			}
+			if d := m.crHelper.GetDriveCRByUUID(location); d != nil {
+				health = healthpolicy.Worst(health, d.Spec.Health)
+			}
+		}
+		if poolHealth, ok := m.thinPoolsHealth[lvg.Name]; ok {
+			health = healthpolicy.Worst(health, poolHealth)
+		}
+		if lvg.Spec.Health == health {
+			continue
+		}
+
+		ll.Infof("Setting health %s to LVG %s", health, lvg.Name)
+		m.setLVGHealth(ctx, &lvg, health, fmt.Sprintf("%s drive on %s", drive.Health, drive.NodeId))
+	}
+}
+
+// setLVGHealth sets health to LVG and to the volumes on it, AC of unhealthy LVG is zeroed
+// and restored when LVG becomes healthy again. cause describes the origin of the health for volume events.
+// Cached volume takes the worst health among its LVG and cache LVG
+func (m *VolumeManager) setLVGHealth(ctx context.Context, lvg *lvgcrd.LVG, health, cause string) {
+	ll := m.log.WithFields(logrus.Fields{
+		"method":  "setLVGHealth",
+		"lvgName": lvg.Name,
+	})
+
+	ll.Infof("Setting health %s to LVG, previous health %s", health, lvg.Spec.Health)
+	lvg.Spec.Health = health
+	if err := m.k8sClient.UpdateCR(ctx, lvg); err != nil {
+		ll.Errorf("Failed to update LVG CR's %s health: %v", lvg.Name, err)
+		return
+	}
+
+	volumes, err := m.crHelper.GetVolumeCRs(m.nodeID)
+	if err != nil {
+		ll.Errorf("Unable to read volume CRs: %v", err)
+		return
+	}
+
+	if ac := m.crHelper.GetACByLocation(lvg.Name); ac != nil {
+		size := int64(0)
+		switch {
+		case health != apiV1.HealthGood:
+		case lvg.Spec.ThinPool:
+			// thin pool takes the whole VG, free space is what isn't accounted for thin volumes
+			size = lvg.Spec.Size
+			for i := range volumes {
+				if volumes[i].Spec.Location == lvg.Name {
+					size -= capacityplanner.GetVolumeConsumedSize(&volumes[i].Spec, ac)
+				}
+			}
+		case len(lvg.Spec.Locations) > 1:
+			// logical volumes are spread evenly over all PVs, so only the smallest free space is usable on each PV
+			var (
+				pvsFree []int64
+				minFree int64
+			)
+			if pvsFree, err = m.lvmOps.GetPVsFreeSpace(lvg.Spec.Name); err != nil {
+				ll.Errorf("Unable to determine free space of PVs of LVG %s: %v", lvg.Name, err)
+			}
+			for i, free := range pvsFree {
+				if i == 0 || free < minFree {
+					minFree = free
+				}
+			}
+			size = int64(len(pvsFree)) * minFree
+		default:
+			if size, err = m.lvmOps.GetVgFreeSpace(lvg.Spec.Name); err != nil {
+				ll.Errorf("Unable to determine free space of LVG %s: %v", lvg.Name, err)
+			}
+		}
+		ll.Infof("Setting size %d to AC %s based on LVG %s", size, ac.Name, lvg.Name)
+		ac.Spec.Size = size
+		if err := m.k8sClient.UpdateCR(ctx, ac); err != nil {
+			ll.Errorf("Failed to update AC CR's %s size: %v", ac.Name, err)
+		}
+	}
+
+	lvgsHealth := make(map[string]string)
+	for _, l := range m.crHelper.GetLVGCRs(m.nodeID) {
+		lvgsHealth[l.Name] = l.Spec.Health
+	}
+	lvgsHealth[lvg.Name] = health
+
+	for _, vol := range volumes {
+		vol := vol
+		if vol.Spec.Location != lvg.Name && vol.Spec.CacheLocation != lvg.Name {
+			continue
+		}
+		volHealth := lvgsHealth[vol.Spec.Location]
+		if vol.Spec.CacheLocation != "" {
+			volHealth = healthpolicy.Worst(volHealth, lvgsHealth[vol.Spec.CacheLocation])
+		}
+		prevHealthState := vol.Spec.Health
+		vol.Spec.Health = volHealth
+		if err = m.k8sClient.UpdateCR(ctx, &vol); err != nil {
+			ll.Errorf("Failed to update volume CR's %s health status: %v", vol.Name, err)
+			continue
+		}
+		if volHealth == apiV1.HealthBad || volHealth == apiV1.HealthSuspect {
+			m.recorder.Eventf(&vol, eventing.WarningType, eventing.VolumeBadHealth,
+				"Volume health transitioned from %s to %s. Inherited from %s)",
+				prevHealthState, volHealth, cause)
+		}
+	}
 }
 
 // drivesAreTheSame check whether two drive represent same node drive or no
@@ -953,6 +1442,7 @@ func (m *VolumeManager) createEventsForDriveUpdates(updates *driveUpdates) {
 			createdDrive.Spec.SerialNumber, createdDrive.Spec.NodeId)
 		m.createEventForDriveHealthChange(
 			createdDrive, apiV1.HealthUnknown, createdDrive.Spec.Health)
+		m.createEventForDriveEnduranceChange(createdDrive, apiV1.DriveEnduranceUnknown, createdDrive.Spec.Endurance)
 	}
 	for _, updDrive := range updates.Updated {
 		if updDrive.CurrentState.Spec.Health != updDrive.PreviousState.Spec.Health {
@@ -963,7 +1453,35 @@ func (m *VolumeManager) createEventsForDriveUpdates(updates *driveUpdates) {
 			m.createEventForDriveStatusChange(
 				updDrive.CurrentState, updDrive.PreviousState.Spec.Status, updDrive.CurrentState.Spec.Status)
 		}
+		if updDrive.CurrentState.Spec.Endurance != updDrive.PreviousState.Spec.Endurance {
+			m.createEventForDriveEnduranceChange(
+				updDrive.CurrentState, updDrive.PreviousState.Spec.Endurance, updDrive.CurrentState.Spec.Endurance)
+		}
+	}
+}
+
+// createEventForDriveEnduranceChange sends event when endurance of the drive falls to the lowest of crossed
+// thresholds. Negative endurance means that drive doesn't report it, previous unknown endurance is treated as 100 percent
+func (m *VolumeManager) createEventForDriveEnduranceChange(
+	drive *drivecrd.Drive, prevEndurance, currentEndurance int64) {
+	if currentEndurance < 0 {
+		return
+	}
+	if prevEndurance < 0 {
+		prevEndurance = 100
+	}
+	var crossed int64 = -1
+	for _, threshold := range m.enduranceThresholds {
+		if currentEndurance <= threshold && prevEndurance > threshold && (crossed < 0 || threshold < crossed) {
+			crossed = threshold
+		}
+	}
+	if crossed < 0 {
+		return
 	}
+	m.sendEventForDrive(drive, eventing.WarningType, eventing.DriveEnduranceLow,
+		"Drive endurance is %d%%, threshold: %d%%, previous endurance: %d%%.",
+		currentEndurance, crossed, prevEndurance)
 }
 
 func (m *VolumeManager) createEventForDriveHealthChange(
@@ -1016,9 +1534,11 @@ func (m *VolumeManager) sendEventForDrive(drive *drivecrd.Drive, eventtype, reas
 func prepareDriveDescription(drive *drivecrd.Drive) string {
 	return fmt.Sprintf(" Drive Details: SN='%s', Node='%s',"+
 		" Type='%s', Model='%s %s',"+
-		" Size='%d', Firmware='%s'",
+		" Size='%d', Firmware='%s',"+
+		" Enclosure='%s', Slot='%s', Bay='%s'",
 		drive.Spec.SerialNumber, drive.Spec.NodeId, drive.Spec.Type,
-		drive.Spec.VID, drive.Spec.PID, drive.Spec.Size, drive.Spec.Firmware)
+		drive.Spec.VID, drive.Spec.PID, drive.Spec.Size, drive.Spec.Firmware,
+		drive.Spec.Enclosure, drive.Spec.Slot, drive.Spec.Bay)
 }
 
 // isDriveSystem check whether drive is system
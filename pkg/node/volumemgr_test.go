@@ -41,8 +41,11 @@ import (
 	vcrd "github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/cgroup"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/fs"
 	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lsblk"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/lvm"
+	"github.com/dell/csi-baremetal/pkg/base/linuxutils/mdadm"
 	"github.com/dell/csi-baremetal/pkg/base/util"
 	"github.com/dell/csi-baremetal/pkg/eventing"
 	"github.com/dell/csi-baremetal/pkg/mocks"
@@ -490,6 +493,44 @@ func TestVolumeManager_handleCreatingVolumeInLVG(t *testing.T) {
 	res, err = vm.handleCreatingVolumeInLVG(testCtx, &testVol)
 	assert.Nil(t, err)
 	assert.Equal(t, expectedResRequeue, res)
+
+	// cached volume, LVG in created state and cache LVG in creating state
+	vm = prepareSuccessVolumeManager(t)
+	pMock = &mockProv.MockProvisioner{}
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{p.LVMBasedVolumeType: pMock})
+	testLVG = testLVGCR
+	testLVG.Spec.Status = apiV1.Created
+	cacheLVG := testLVGCR
+	cacheLVG.Name = "cache-lvg"
+	cacheLVG.Spec.Name = cacheLVG.Name
+	cacheLVG.Spec.Status = apiV1.Creating
+	testVol = testVolumeLVGCR
+	testVol.Spec.CacheLocation = cacheLVG.Name
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testLVG.Name, &testLVG))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, cacheLVG.Name, &cacheLVG))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, testVol.Name, &testVol))
+
+	res, err = vm.handleCreatingVolumeInLVG(testCtx, &testVol)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedResRequeue, res)
+	pMock.AssertNotCalled(t, "PrepareVolume", mock.Anything)
+
+	// cached volume, both LVGs in created state
+	lvg = &lvgcrd.LVG{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, cacheLVG.Name, lvg))
+	lvg.Spec.Status = apiV1.Created
+	assert.Nil(t, vm.k8sClient.UpdateCR(testCtx, lvg))
+	pMock.On("PrepareVolume", mock.Anything).Return(nil)
+
+	res, err = vm.handleCreatingVolumeInLVG(testCtx, &testVol)
+	assert.Nil(t, err)
+	assert.Equal(t, ctrl.Result{}, res)
+
+	for _, name := range []string{testLVG.Name, cacheLVG.Name} {
+		lvg = &lvgcrd.LVG{}
+		assert.Nil(t, vm.k8sClient.ReadCR(testCtx, name, lvg))
+		assert.True(t, util.ContainsString(lvg.Spec.VolumeRefs, testVol.Spec.Id))
+	}
 }
 
 func TestReconcile_ReconcileDefaultStatus(t *testing.T) {
@@ -543,6 +584,19 @@ func TestVolumeManager_DrivesNotInUse_Success(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(drivesNotInUse))
 	assert.Equal(t, drive2.UUID, drivesNotInUse[0].Spec.UUID)
+
+	// add Volume CR on MD RAID array which drive2 is a member of
+	raidVolumeCR := vm.k8sClient.ConstructVolumeCR("test_raid_name", api.Volume{
+		NodeId:       nodeID,
+		LocationType: apiV1.LocationTypeMDRaid,
+		Location:     drive1.UUID,
+		Locations:    []string{drive1.UUID, drive2.UUID},
+	})
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, raidVolumeCR.Name, raidVolumeCR))
+
+	drivesNotInUse, err = vm.drivesAreNotUsed()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(drivesNotInUse))
 }
 
 func TestVolumeManager_DrivesNotInUse_Fail(t *testing.T) {
@@ -891,6 +945,140 @@ func TestVolumeManager_handleDriveStatusChange(t *testing.T) {
 	assert.Equal(t, apiV1.HealthBad, rVolume.Spec.Health)
 }
 
+func TestVolumeManager_handleDriveStatusChangeLVG(t *testing.T) {
+	var (
+		drive2 = getTestDrive("drive-uuid-2", "hdd2")
+		vm     = prepareSuccessVolumeManagerWithDrives([]*api.Drive{&drive1, drive2}, t)
+		lvmOps = &mocklu.MockWrapLVM{}
+		lvg    = testLVGCR
+		vol    = testVolumeLVGCR
+		ac     = acCR
+	)
+	vm.lvmOps = lvmOps
+	// logical volumes are spread evenly over PVs, only the smallest free space is usable on each of them
+	lvmOps.On("GetPVsFreeSpace", testLVGName).Return([]int64{512, 1024}, nil)
+
+	lvg.Spec.Locations = []string{drive1.UUID, drive2.UUID}
+	lvg.Spec.Health = apiV1.HealthGood
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, lvg.Name, &lvg))
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, vol.Name, &vol))
+	ac.Spec.Location = lvg.Name
+	ac.Spec.StorageClass = apiV1.StorageClassHDDLVG
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, ac.Name, &ac))
+
+	// one of the LVG drives becomes BAD
+	drive := *drive2
+	drive.Health = apiV1.HealthBad
+	vm.handleDriveStatusChange(testCtx, &drive)
+
+	rLVG := &lvgcrd.LVG{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvg.Name, rLVG))
+	assert.Equal(t, apiV1.HealthBad, rLVG.Spec.Health)
+	rVolume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthBad, rVolume.Spec.Health)
+	rAC := &accrd.AvailableCapacity{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, ac.Name, rAC))
+	assert.Equal(t, int64(0), rAC.Spec.Size)
+
+	// drive returns to GOOD, AC is restored
+	drive.Health = apiV1.HealthGood
+	vm.handleDriveStatusChange(testCtx, &drive)
+
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvg.Name, rLVG))
+	assert.Equal(t, apiV1.HealthGood, rLVG.Spec.Health)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthGood, rVolume.Spec.Health)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, ac.Name, rAC))
+	assert.Equal(t, int64(1024), rAC.Spec.Size)
+}
+
+func TestVolumeManager_handleDriveStatusChangeCachedVolume(t *testing.T) {
+	var (
+		drive2    = getTestDrive("drive-uuid-2", "nvme1")
+		vm        = prepareSuccessVolumeManagerWithDrives([]*api.Drive{&drive1, drive2}, t)
+		lvmOps    = &mocklu.MockWrapLVM{}
+		lvg       = testLVGCR
+		cacheLVG  = testLVGCR
+		vol       = testVolumeLVGCR
+		cachedVol = testVolumeLVGCR
+	)
+	vm.lvmOps = lvmOps
+	lvmOps.On("GetVgFreeSpace", mock.Anything).Return(int64(1024), nil)
+
+	lvg.Spec.Locations = []string{drive1.UUID}
+	lvg.Spec.Health = apiV1.HealthGood
+	cacheLVG.Name, cacheLVG.Spec.Name = "cache-lvg", "cache-lvg"
+	cacheLVG.Spec.Locations = []string{drive2.UUID}
+	cacheLVG.Spec.Health = apiV1.HealthGood
+	cachedVol.Name, cachedVol.Spec.Id = "cached-volume", "cached-volume"
+	cachedVol.Spec.CacheLocation = cacheLVG.Name
+	vol.Spec.Health, cachedVol.Spec.Health = apiV1.HealthGood, apiV1.HealthGood
+	for _, obj := range []*lvgcrd.LVG{&lvg, &cacheLVG} {
+		assert.Nil(t, vm.k8sClient.CreateCR(testCtx, obj.Name, obj))
+	}
+	for _, obj := range []*vcrd.Volume{&vol, &cachedVol} {
+		assert.Nil(t, vm.k8sClient.CreateCR(testCtx, obj.Name, obj))
+	}
+
+	// cache drive becomes BAD, cached volume inherits health of the cache LVG
+	drive := *drive2
+	drive.Health = apiV1.HealthBad
+	vm.handleDriveStatusChange(testCtx, &drive)
+
+	rVolume := &vcrd.Volume{}
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, cachedVol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthBad, rVolume.Spec.Health)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthGood, rVolume.Spec.Health)
+
+	// HDD drive becomes SUSPECT, cached volume keeps the worst health
+	hddDrive := drive1
+	hddDrive.Health = apiV1.HealthSuspect
+	vm.handleDriveStatusChange(testCtx, &hddDrive)
+
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, cachedVol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthBad, rVolume.Spec.Health)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthSuspect, rVolume.Spec.Health)
+
+	// cache drive returns to GOOD
+	drive.Health = apiV1.HealthGood
+	vm.handleDriveStatusChange(testCtx, &drive)
+
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, cachedVol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthSuspect, rVolume.Spec.Health)
+}
+
+func Test_discoverIOLimits(t *testing.T) {
+	var (
+		vm         = prepareSuccessVolumeManager(t)
+		pMock      = mockProv.GetMockProvisionerSuccess("/dev/sda1")
+		cgroupOps  = &mocklu.MockWrapCgroup{}
+		limits     = cgroup.IOLimits{ReadBps: 1048576, WriteIops: 100}
+		podUID     = "pod-uid-1"
+		published  = volCR.DeepCopy()
+		notLimited = volCR.DeepCopy()
+	)
+	vm.SetProvisioners(map[p.VolumeType]p.Provisioner{p.DriveBasedVolumeType: pMock})
+	vm.cgroupOps = cgroupOps
+
+	published.Spec.CSIStatus = apiV1.Published
+	published.Spec.Owners = []string{podUID, "/not/kubelet/path"}
+	published.Spec.ReadBps, published.Spec.WriteIops = limits.ReadBps, limits.WriteIops
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, published.Name, published))
+
+	notLimited.Name, notLimited.Spec.Id = "volume-without-limits", "volume-without-limits"
+	notLimited.Spec.CSIStatus = apiV1.Published
+	notLimited.Spec.Owners = []string{podUID}
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, notLimited.Name, notLimited))
+
+	cgroupOps.On("SetIOLimits", podUID, "/dev/sda1", limits).Return(nil).Once()
+	vm.discoverIOLimits()
+	cgroupOps.AssertExpectations(t)
+	cgroupOps.AssertNumberOfCalls(t, "SetIOLimits", 1)
+}
+
 func Test_discoverLVGOnSystemDrive_LVGAlreadyExists(t *testing.T) {
 	var (
 		m     = prepareSuccessVolumeManager(t)
@@ -1061,6 +1249,56 @@ func TestVolumeManager_createEventsForDriveUpdates(t *testing.T) {
 		assert.True(t, expectEvent(drive1CR, eventing.ErrorType, eventing.DriveStatusOffline))
 		assert.True(t, expectEvent(drive1CR, eventing.WarningType, eventing.DriveHealthUnknown))
 	})
+
+	t.Run("Drive endurance crossed thresholds", func(t *testing.T) {
+		init()
+		mgr.SetEnduranceThresholds(DefaultEnduranceThresholds)
+		prevDrive := drive1CR.DeepCopy()
+		prevDrive.Spec.Endurance = 25
+		modifiedDrive := drive1CR.DeepCopy()
+		modifiedDrive.Spec.Endurance = 8
+
+		upd := &driveUpdates{
+			Updated: []updatedDrive{{
+				PreviousState: prevDrive,
+				CurrentState:  modifiedDrive}},
+		}
+		mgr.createEventsForDriveUpdates(upd)
+		assert.True(t, expectEvent(drive1CR, eventing.WarningType, eventing.DriveEnduranceLow))
+		assert.Equal(t, 1, len(rec.Calls))
+		// lowest crossed threshold is reported
+		assert.Equal(t, int64(10), rec.Calls[0].Args[1])
+
+		// threshold isn't crossed
+		init()
+		mgr.SetEnduranceThresholds(DefaultEnduranceThresholds)
+		prevDrive.Spec.Endurance = 9
+		mgr.createEventsForDriveUpdates(upd)
+		assert.Empty(t, rec.Calls)
+
+		// drive doesn't report endurance anymore
+		modifiedDrive.Spec.Endurance = apiV1.DriveEnduranceUnknown
+		mgr.createEventsForDriveUpdates(upd)
+		assert.Empty(t, rec.Calls)
+
+		// drive is worn out
+		modifiedDrive.Spec.Endurance = 0
+		mgr.createEventsForDriveUpdates(upd)
+		assert.True(t, expectEvent(drive1CR, eventing.WarningType, eventing.DriveEnduranceLow))
+		assert.Equal(t, int64(5), rec.Calls[0].Args[1])
+	})
+
+	t.Run("Worn drive discovered", func(t *testing.T) {
+		init()
+		mgr.SetEnduranceThresholds(DefaultEnduranceThresholds)
+		wornDrive := drive1CR.DeepCopy()
+		wornDrive.Spec.Endurance = 15
+		unknownDrive := drive2CR.DeepCopy()
+		unknownDrive.Spec.Endurance = apiV1.DriveEnduranceUnknown
+		mgr.createEventsForDriveUpdates(&driveUpdates{Created: []*drivecrd.Drive{wornDrive, unknownDrive}})
+		assert.True(t, expectEvent(drive1CR, eventing.WarningType, eventing.DriveEnduranceLow))
+		assert.False(t, expectEvent(drive2CR, eventing.WarningType, eventing.DriveEnduranceLow))
+	})
 }
 
 func TestVolumeManager_isShouldBeReconciled(t *testing.T) {
@@ -1090,6 +1328,204 @@ func TestVolumeManager_isDriveIsInLVG(t *testing.T) {
 	assert.False(t, vm.isDriveInLVG(drive2))
 }
 
+func Test_discoverCachedVolumes(t *testing.T) {
+	var (
+		vm       = prepareSuccessVolumeManager(t)
+		lvmOps   = &mocklu.MockWrapLVM{}
+		vol      = volCR.DeepCopy()
+		second   = volCR.DeepCopy()
+		creating = volCR.DeepCopy()
+		cacheVG  = "cache-lvg"
+	)
+	vm.lvmOps = lvmOps
+
+	vol.Spec.Location, vol.Spec.CacheLocation = testLVGName, cacheVG
+	vol.Spec.CSIStatus = apiV1.Published
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, vol.Name, vol))
+	// VGs are activated once for all volumes
+	second.Name, second.Spec.Id = "second-volume", "second-volume"
+	second.Spec.Location, second.Spec.CacheLocation = testLVGName, cacheVG
+	second.Spec.CSIStatus = apiV1.VolumeReady
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, second.Name, second))
+	// VGs of the creating volume aren't activated
+	creating.Name, creating.Spec.Id = "creating-volume", "creating-volume"
+	creating.Spec.Location, creating.Spec.CacheLocation = "creating-lvg", "creating-cache-lvg"
+	creating.Spec.CSIStatus = apiV1.Creating
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, creating.Name, creating))
+
+	var order []string
+	lvmOps.On("VGActivate", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		order = append(order, args.String(0))
+	})
+	vm.discoverCachedVolumes()
+	assert.Equal(t, []string{cacheVG, testLVGName}, order)
+
+	// VG of the volume isn't activated when cache VG fails
+	vm = prepareSuccessVolumeManager(t)
+	lvmOps = &mocklu.MockWrapLVM{}
+	vm.lvmOps = lvmOps
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, vol.Name, vol))
+	lvmOps.On("VGActivate", cacheVG).Return(errors.New("error")).Once()
+	vm.discoverCachedVolumes()
+	lvmOps.AssertNotCalled(t, "VGActivate", testLVGName)
+}
+
+func Test_discoverMDRaidHealth(t *testing.T) {
+	var (
+		vm       = prepareSuccessVolumeManager(t)
+		mdOps    = &mocklu.MockWrapMDADM{}
+		vol      = volCR.DeepCopy()
+		creating = volCR.DeepCopy()
+		rVolume  = &vcrd.Volume{}
+		name     = p.GetMDArrayName(vol.Spec.Id)
+		array    = &mdadm.Array{Name: "md127", State: mdadm.ArrayStateActive, RaidDisks: 2, ActiveDisks: 2}
+	)
+	vm.mdOps = mdOps
+
+	vol.Spec.StorageClass = apiV1.StorageClassHDDRAID1
+	vol.Spec.LocationType = apiV1.LocationTypeMDRaid
+	vol.Spec.Locations = []string{drive1UUID, drive2UUID}
+	vol.Spec.Location = drive1UUID
+	vol.Spec.CSIStatus = apiV1.Published
+	vol.Spec.Health = apiV1.HealthGood
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, vol.Name, vol))
+	// array of the creating volume isn't inspected
+	creating.Name, creating.Spec.Id = "creating-volume", "creating-volume"
+	creating.Spec.LocationType = apiV1.LocationTypeMDRaid
+	creating.Spec.CSIStatus = apiV1.Creating
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, creating.Name, creating))
+
+	// one of the members failed
+	degraded := *array
+	degraded.ActiveDisks, degraded.FailedDevices = 1, []string{"sdb"}
+	mdOps.On("GetArray", name).Return(&degraded, nil).Once()
+	vm.discoverMDRaidHealth(testCtx)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthSuspect, rVolume.Spec.Health)
+
+	// array was rebuilt
+	mdOps.On("GetArray", name).Return(array, nil).Once()
+	vm.discoverMDRaidHealth(testCtx)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthGood, rVolume.Spec.Health)
+
+	// array is inactive
+	inactive := *array
+	inactive.State = "inactive"
+	mdOps.On("GetArray", name).Return(&inactive, nil).Once()
+	vm.discoverMDRaidHealth(testCtx)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthBad, rVolume.Spec.Health)
+
+	// array isn't found
+	mdOps.On("GetArray", name).Return(nil, testErr).Once()
+	vm.discoverMDRaidHealth(testCtx)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthUnknown, rVolume.Spec.Health)
+
+	mdOps.AssertNumberOfCalls(t, "GetArray", 4)
+	mdOps.AssertExpectations(t)
+
+	// health of the volume on MD RAID array doesn't depend on the health of its drive
+	drive := drive1
+	drive.Health = apiV1.HealthBad
+	vm.handleDriveStatusChange(testCtx, &drive)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthUnknown, rVolume.Spec.Health)
+
+	// array isn't running after reboot and is assembled from the present members
+	listBlk := &mocklu.MockWrapLsblk{}
+	vm.listBlk = listBlk
+	drive1CR := vm.k8sClient.ConstructDriveCR(drive1UUID, drive1)
+	addDriveCRs(vm.k8sClient, drive1CR)
+	listBlk.On("SearchDrivePath", mock.Anything).Return("/dev/sdb", nil)
+	mdOps.On("GetArray", name).Return(nil, testErr).Once()
+	mdOps.On("AssembleArray", name, []string{"/dev/sdb"}).Return(nil).Once()
+	mdOps.On("GetArray", name).Return(&degraded, nil).Once()
+	vm.discoverMDRaidHealth(testCtx)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVolume))
+	assert.Equal(t, apiV1.HealthSuspect, rVolume.Spec.Health)
+	mdOps.AssertExpectations(t)
+}
+
+func Test_discoverThinPoolsUsage(t *testing.T) {
+	var (
+		vm     = prepareSuccessVolumeManager(t)
+		lvmOps = &mocklu.MockWrapLVM{}
+		rec    = &mocks.NoOpRecorder{}
+		lvg    = testLVGCR.DeepCopy()
+		vol    = testVolumeLVGCR.DeepCopy()
+		ac     = acCR.DeepCopy()
+		rLVG   = &lvgcrd.LVG{}
+		rVol   = &vcrd.Volume{}
+		rAC    = &accrd.AvailableCapacity{}
+	)
+	vm.lvmOps = lvmOps
+	vm.recorder = rec
+
+	// returns reasons of the events sent on LVG
+	lvgEvents := func() []string {
+		var reasons []string
+		for _, c := range rec.Calls {
+			if _, ok := c.Object.(*lvgcrd.LVG); ok {
+				reasons = append(reasons, c.Reason)
+			}
+		}
+		return reasons
+	}
+
+	lvg.Spec.ThinPool = true
+	lvg.Spec.Health = apiV1.HealthGood
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, lvg.Name, lvg))
+	vol.Spec.StorageClass = apiV1.StorageClassHDDLVGThin
+	vol.Spec.CSIStatus = apiV1.Published
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, vol.Name, vol))
+	ac.Spec.Location = lvg.Name
+	ac.Spec.StorageClass = apiV1.StorageClassHDDLVGThin
+	ac.Spec.OvercommitRatio = 2
+	assert.Nil(t, vm.k8sClient.CreateCR(testCtx, ac.Name, ac))
+
+	// usage is normal, there is nothing to report
+	lvmOps.On("GetThinPoolUsage", lvg.Name, lvm.ThinPoolName).Return(12.5, 3.2, nil).Once()
+	vm.discoverThinPoolsUsage(testCtx)
+	assert.Empty(t, rec.Calls)
+
+	// metadata usage is high
+	lvmOps.On("GetThinPoolUsage", lvg.Name, lvm.ThinPoolName).Return(12.5, 85.0, nil).Once()
+	vm.discoverThinPoolsUsage(testCtx)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvg.Name, rLVG))
+	assert.Equal(t, apiV1.HealthSuspect, rLVG.Spec.Health)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, vol.Name, rVol))
+	assert.Equal(t, apiV1.HealthSuspect, rVol.Spec.Health)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, ac.Name, rAC))
+	assert.Equal(t, int64(0), rAC.Spec.Size)
+	assert.Equal(t, []string{eventing.ThinPoolUsageHigh}, lvgEvents())
+
+	// data usage is close to full
+	lvmOps.On("GetThinPoolUsage", lvg.Name, lvm.ThinPoolName).Return(96.0, 85.0, nil).Once()
+	vm.discoverThinPoolsUsage(testCtx)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvg.Name, rLVG))
+	assert.Equal(t, apiV1.HealthBad, rLVG.Spec.Health)
+	assert.Equal(t, []string{eventing.ThinPoolUsageHigh, eventing.ThinPoolFull}, lvgEvents())
+
+	// usage isn't read, health remains the same
+	lvmOps.On("GetThinPoolUsage", lvg.Name, lvm.ThinPoolName).Return(0.0, 0.0, testErr).Once()
+	vm.discoverThinPoolsUsage(testCtx)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvg.Name, rLVG))
+	assert.Equal(t, apiV1.HealthBad, rLVG.Spec.Health)
+
+	// space was freed, AC is restored for the physical part of the thin volume
+	lvmOps.On("GetThinPoolUsage", lvg.Name, lvm.ThinPoolName).Return(40.0, 20.0, nil).Once()
+	vm.discoverThinPoolsUsage(testCtx)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, lvg.Name, rLVG))
+	assert.Equal(t, apiV1.HealthGood, rLVG.Spec.Health)
+	assert.Nil(t, vm.k8sClient.ReadCR(testCtx, ac.Name, rAC))
+	assert.Equal(t, lvg.Spec.Size-vol.Spec.Size/2, rAC.Spec.Size)
+	assert.Equal(t, []string{eventing.ThinPoolUsageHigh, eventing.ThinPoolFull, eventing.ThinPoolUsageNormal},
+		lvgEvents())
+	lvmOps.AssertExpectations(t)
+}
+
 func prepareSuccessVolumeManager(t *testing.T) *VolumeManager {
 	c := mocks.NewMockDriveMgrClient(nil)
 	// create map of commands which must be mocked
@@ -1164,3 +1600,16 @@ func TestVolumeManager_isDriveSystem(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, isSystem, false)
 }
+
+func Test_prepareDriveDescription(t *testing.T) {
+	drive := &drivecrd.Drive{Spec: api.Drive{
+		SerialNumber: "SN1",
+		NodeId:       "node1",
+		Enclosure:    "0x5000cca04a000000",
+		Slot:         "7",
+		Bay:          "Slot07",
+	}}
+	description := prepareDriveDescription(drive)
+	assert.Contains(t, description, "SN='SN1'")
+	assert.Contains(t, description, "Enclosure='0x5000cca04a000000', Slot='7', Bay='Slot07'")
+}
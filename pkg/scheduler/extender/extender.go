@@ -21,7 +21,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 	"sync"
 
 	"github.com/google/uuid"
@@ -209,7 +208,8 @@ func (e *Extender) BindHandler(w http.ResponseWriter, req *http.Request) {
 }
 
 // gatherVolumesByProvisioner search all volumes in pod' spec that should be provisioned
-// by provisioner e.provisioner and construct genV1.Volume struct for each of such volume
+// by provisioner e.provisioner and construct genV1.Volume struct for each of such volume.
+// Logical volume type and cache of PVC volumes are filled from storage class parameters the same way as in CreateVolume
 func (e *Extender) gatherVolumesByProvisioner(ctx context.Context, pod *coreV1.Pod) ([]*genV1.Volume, error) {
 	ll := e.logger.WithFields(logrus.Fields{
 		"sessionUUID": ctx.Value(k8s.RequestUUID),
@@ -217,7 +217,7 @@ func (e *Extender) gatherVolumesByProvisioner(ctx context.Context, pod *coreV1.P
 		"pod":         pod.Name,
 	})
 
-	scs, err := e.scNameStorageTypeMapping(ctx)
+	scs, err := e.scNameParametersMapping(ctx)
 	if err != nil {
 		ll.Errorf("Unable to collect storage classes: %v", err)
 		return nil, err
@@ -255,7 +255,7 @@ func (e *Extender) gatherVolumesByProvisioner(ctx context.Context, pod *coreV1.P
 			if pvc.Status.Phase == coreV1.ClaimBound || pvc.Status.Phase == coreV1.ClaimLost {
 				continue
 			}
-			if storageType, ok := scs[*pvc.Spec.StorageClassName]; ok {
+			if params, ok := scs[*pvc.Spec.StorageClassName]; ok {
 				storageReq, ok := pvc.Spec.Resources.Requests[coreV1.ResourceStorage]
 				if !ok {
 					ll.Errorf("There is no key for storage resource for PVC %s", pvc.Name)
@@ -267,13 +267,24 @@ func (e *Extender) gatherVolumesByProvisioner(ctx context.Context, pod *coreV1.P
 					mode = string(*pvc.Spec.VolumeMode)
 				}
 
-				volumes = append(volumes, &genV1.Volume{
+				volume := &genV1.Volume{
 					Id:           pvc.Name,
-					StorageClass: util.ConvertStorageClass(storageType),
+					StorageClass: util.ConvertStorageClass(params[base.StorageTypeKey]),
 					Size:         storageReq.Value(),
 					Mode:         mode,
 					Ephemeral:    false,
-				})
+				}
+				if err = util.FillLVType(volume, params); err == nil {
+					err = util.FillCache(volume, params)
+				}
+				if err == nil {
+					err = util.FillOvercommitRatio(volume, params)
+				}
+				if err != nil {
+					ll.Errorf("Unable to construct API Volume for PVC %s: %v", pvc.Name, err)
+					return nil, err
+				}
+				volumes = append(volumes, volume)
 			}
 		}
 	}
@@ -323,7 +334,9 @@ func (e *Extender) filter(ctx context.Context, nodes []coreV1.Node, volumes []*g
 	acReader := capacityplanner.NewACReader(e.k8sClient, e.logger, true)
 	acrReader := capacityplanner.NewACRReader(e.k8sClient, e.logger, true)
 	reservedCapReader := capacityplanner.NewUnreservedACReader(e.logger, acReader, acrReader)
-	capManager := e.capacityManagerBuilder.GetCapacityManager(e.logger, reservedCapReader)
+	driveReader := capacityplanner.NewDriveReader(e.k8sClient, e.logger, true)
+	lvgReader := capacityplanner.NewLVGReader(e.k8sClient, e.logger, true)
+	capManager := e.capacityManagerBuilder.GetCapacityManager(e.logger, reservedCapReader, driveReader, lvgReader)
 
 	placingPlan, err := capManager.PlanVolumesPlacing(ctx, volumes)
 	if err != nil {
@@ -419,25 +432,25 @@ func nodePrioritize(nodeMapping map[string][]volcrd.Volume) (map[string]int, int
 	return nrank, maxCount
 }
 
-// scNameStorageTypeMapping reads k8s storage class resources and collect map with key storage class name
+// scNameParametersMapping reads k8s storage class resources and collect map with key storage class name
 // and value .parameters.storageType for that sc, collect only sc that have provisioner e.provisioner
-func (e *Extender) scNameStorageTypeMapping(ctx context.Context) (map[string]string, error) {
+func (e *Extender) scNameParametersMapping(ctx context.Context) (map[string]map[string]string, error) {
 	scs := storageV1.StorageClassList{}
 
 	if err := e.k8sClient.List(ctx, &scs); err != nil {
 		return nil, err
 	}
 
-	scNameTypeMap := map[string]string{}
+	scNameParamsMap := map[string]map[string]string{}
 	for _, sc := range scs.Items {
 		if sc.Provisioner == e.provisioner {
-			scNameTypeMap[sc.Name] = strings.ToUpper(sc.Parameters[base.StorageTypeKey])
+			scNameParamsMap[sc.Name] = sc.Parameters
 		}
 	}
-	if len(scNameTypeMap) == 0 {
+	if len(scNameParamsMap) == 0 {
 		return nil, fmt.Errorf("there are no any storage classes with provisioner %s", e.provisioner)
 	}
-	return scNameTypeMap, nil
+	return scNameParamsMap, nil
 }
 
 // getNodeID returns node ID, it could be a k8s node UID or value of annotation
@@ -151,6 +151,60 @@ func TestExtender_gatherVolumesByProvisioner_Success(t *testing.T) {
 	assert.Equal(t, 2, len(volumes))
 }
 
+func TestExtender_gatherVolumesByProvisioner_SCParameters(t *testing.T) {
+	e := setup(t)
+	pod := testPod
+	pod.Spec.Volumes = append(pod.Spec.Volumes, coreV1.Volume{
+		VolumeSource: coreV1.VolumeSource{
+			PersistentVolumeClaim: &coreV1.PersistentVolumeClaimVolumeSource{
+				ClaimName: testPVC1Name,
+			},
+		},
+	})
+
+	// cached volume
+	sc := testSC1
+	sc.Parameters = map[string]string{
+		base.StorageTypeKey:      v1.StorageClassHDDLVG,
+		base.CacheStorageTypeKey: v1.StorageClassSSDLVG,
+		base.CacheSizeKey:        "10Mi",
+	}
+	applyObjs(t, e.k8sClient, &testPVC1, &sc)
+
+	volumes, err := e.gatherVolumesByProvisioner(testCtx, &pod)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(volumes))
+	assert.Equal(t, v1.StorageClassHDDLVG, volumes[0].StorageClass)
+	assert.Equal(t, v1.StorageClassSSDLVG, volumes[0].CacheStorageClass)
+	assert.NotZero(t, volumes[0].CacheSize)
+
+	// striped volume
+	sc.Parameters = map[string]string{
+		base.StorageTypeKey: v1.StorageClassHDDLVG,
+		base.LVTypeKey:      v1.LVTypeStriped,
+		base.StripesKey:     "3",
+	}
+	assert.Nil(t, e.k8sClient.UpdateCR(testCtx, &sc))
+
+	volumes, err = e.gatherVolumesByProvisioner(testCtx, &pod)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(volumes))
+	assert.Equal(t, v1.LVTypeStriped, volumes[0].LVType)
+	assert.Equal(t, int32(3), volumes[0].Stripes)
+	assert.Empty(t, volumes[0].CacheStorageClass)
+
+	// invalid parameters
+	sc.Parameters = map[string]string{
+		base.StorageTypeKey: v1.StorageClassHDD,
+		base.LVTypeKey:      v1.LVTypeStriped,
+	}
+	assert.Nil(t, e.k8sClient.UpdateCR(testCtx, &sc))
+
+	volumes, err = e.gatherVolumesByProvisioner(testCtx, &pod)
+	assert.Nil(t, volumes)
+	assert.NotNil(t, err)
+}
+
 func TestExtender_gatherVolumesByProvisioner_Fail(t *testing.T) {
 	e := setup(t)
 
@@ -415,21 +469,21 @@ func TestExtender_filterSuccess(t *testing.T) {
 	}
 }
 
-func TestExtender_getSCNameStorageType_Success(t *testing.T) {
+func TestExtender_getSCNameParameters_Success(t *testing.T) {
 	e := setup(t)
 	// create 2 storage classes
 	applyObjs(t, e.k8sClient, &testSC1, &testSC2)
 
-	m, err := e.scNameStorageTypeMapping(testCtx)
+	m, err := e.scNameParametersMapping(testCtx)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(m))
-	assert.Equal(t, m[testSCName1], testStorageType)
+	assert.Equal(t, testStorageType, m[testSCName1][base.StorageTypeKey])
 }
 
-func TestExtender_getSCNameStorageType_Fail(t *testing.T) {
+func TestExtender_getSCNameParameters_Fail(t *testing.T) {
 	e := setup(t)
 
-	m, err := e.scNameStorageTypeMapping(testCtx)
+	m, err := e.scNameParametersMapping(testCtx)
 	assert.Nil(t, m)
 	assert.NotNil(t, err)
 }
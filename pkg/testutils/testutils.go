@@ -21,6 +21,7 @@ import (
 	"time"
 
 	accrd "github.com/dell/csi-baremetal/api/v1/availablecapacitycrd"
+	"github.com/dell/csi-baremetal/api/v1/snapshotcrd"
 	"github.com/dell/csi-baremetal/api/v1/volumecrd"
 	"github.com/dell/csi-baremetal/pkg/base/k8s"
 )
@@ -65,3 +66,22 @@ func ReadVolumeAndChangeStatus(k8sClient *k8s.KubeClient, volumeID string, newSt
 	}
 	return nil
 }
+
+// SnapshotReconcileImitation looking for snapshot CR with name snapshotID and sets it's status to newStatus
+func SnapshotReconcileImitation(k8sClient *k8s.KubeClient, snapshotID string, newStatus string) {
+	var (
+		s        = &snapshotcrd.Snapshot{}
+		attempts = 10
+		ctx      = context.WithValue(context.Background(), k8s.RequestUUID, snapshotID)
+	)
+	for {
+		<-time.After(200 * time.Millisecond)
+		if err := k8sClient.ReadCRWithAttempts(snapshotID, s, attempts); err != nil {
+			return
+		}
+		s.Spec.CSIStatus = newStatus
+		if err := k8sClient.UpdateCRWithAttempts(ctx, s, attempts); err != nil {
+			return
+		}
+	}
+}
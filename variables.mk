@@ -19,9 +19,11 @@ FULL_VERSION     := ${PRODUCT_VERSION}-${RELEASE_STR}
 TAG              := ${FULL_VERSION}
 
 ### third-party components version
-CSI_PROVISIONER_TAG := v1.2.2
+CSI_PROVISIONER_TAG := v2.0.4
 CSI_REGISTRAR_TAG   := v1.0.1-gke.0
 CSI_ATTACHER_TAG    := v1.0.1
+CSI_RESIZER_TAG     := v1.0.1
+CSI_SNAPSHOTTER_TAG := v2.1.1
 LIVENESS_PROBE_TAG  := v2.1.0
 BUSYBOX_TAG         := 1.29
 
@@ -43,12 +45,15 @@ PLUGIN           := plugin
 
 BASE_DRIVE_MGR     := basemgr
 LOOPBACK_DRIVE_MGR := loopbackmgr
+REDFISH_DRIVE_MGR  := redfishmgr
 DRIVE_MANAGER_TYPE := ${BASE_DRIVE_MGR}
 
 # external components
 CSI_PROVISIONER := csi-provisioner
 CSI_REGISTRAR   := csi-node-driver-registrar
 CSI_ATTACHER    := csi-attacher
+CSI_RESIZER     := csi-resizer
+CSI_SNAPSHOTTER := csi-snapshotter
 LIVENESS_PROBE  := livenessprobe
 BUSYBOX         := busybox
 